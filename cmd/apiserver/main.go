@@ -242,6 +242,36 @@ func main() {
 				Required: true,
 				Sources:  cli.EnvVars("NEXAPI_URL"),
 			},
+			&cli.IntFlag{
+				Name:    "quota-vpcs",
+				Usage:   "Default maximum number of VPCs of an organization, 0 is unlimited",
+				Value:   100,
+				Sources: cli.EnvVars("NEXAPI_QUOTA_VPCS"),
+			},
+			&cli.IntFlag{
+				Name:    "quota-devices",
+				Usage:   "Default maximum number of devices of an organization, 0 is unlimited",
+				Value:   1000,
+				Sources: cli.EnvVars("NEXAPI_QUOTA_DEVICES"),
+			},
+			&cli.IntFlag{
+				Name:    "quota-reg-keys",
+				Usage:   "Default maximum number of registration keys of an organization, 0 is unlimited",
+				Value:   1000,
+				Sources: cli.EnvVars("NEXAPI_QUOTA_REG_KEYS"),
+			},
+			&cli.IntFlag{
+				Name:    "quota-security-groups",
+				Usage:   "Default maximum number of security groups of an organization, 0 is unlimited",
+				Value:   500,
+				Sources: cli.EnvVars("NEXAPI_QUOTA_SECURITY_GROUPS"),
+			},
+			&cli.IntFlag{
+				Name:    "quota-metadata-keys",
+				Usage:   "Default maximum number of device metadata keys of an organization, 0 is unlimited",
+				Value:   10000,
+				Sources: cli.EnvVars("NEXAPI_QUOTA_METADATA_KEYS"),
+			},
 
 			&cli.StringFlag{
 				Name:     "smtp-host-port",
@@ -340,6 +370,13 @@ func main() {
 
 				api.URL = command.String("url")
 				api.FrontendURL = command.StringSlice("origins")[0]
+				api.Quotas = handlers.Quotas{
+					Vpcs:           command.Int("quota-vpcs"),
+					Devices:        command.Int("quota-devices"),
+					RegKeys:        command.Int("quota-reg-keys"),
+					SecurityGroups: command.Int("quota-security-groups"),
+					MetadataKeys:   command.Int("quota-metadata-keys"),
+				}
 				api.URLParsed, err = url.Parse(api.URL)
 				if err != nil {
 					log.Fatal(fmt.Errorf("invalid url: %w", err))
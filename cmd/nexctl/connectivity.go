@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -80,27 +79,11 @@ func cmdConnStatus(ctx context.Context, command *cli.Command, family string) err
 	return nil
 }
 
-// callNexdKeepalives call the Connectivity ctl methods in nexd agent
+// callNexdKeepalives call the PingPeers ctl method in nexd agent
 func callNexdKeepalives(family string) (api.PingPeersResponse, error) {
 	var result api.PingPeersResponse
-	var keepaliveJson string
-	var err error
-
-	if family == v6 {
-		keepaliveJson, err = callNexd("ConnectivityV6", "")
-		if err != nil {
-			return result, fmt.Errorf("Failed to get nexd connectivity status: %w\n", err)
-		}
-	} else {
-		keepaliveJson, err = callNexd("ConnectivityV4", "")
-		if err != nil {
-			return result, fmt.Errorf("Failed to get nexd connectivity status: %w\n", err)
-		}
-	}
-
-	err = json.Unmarshal([]byte(keepaliveJson), &result)
-	if err != nil {
-		return result, fmt.Errorf("Failed to get unmarshall connecitivty results: %w\n", err)
+	if err := callNexdV1("PingPeers", api.PingPeersRequest{Family: family}, &result); err != nil {
+		return result, fmt.Errorf("Failed to get nexd connectivity status: %w\n", err)
 	}
 
 	return result, nil
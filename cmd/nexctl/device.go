@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"github.com/nexodus-io/nexodus/internal/client"
+	"math"
 	"strings"
 	"time"
 
@@ -68,14 +70,45 @@ func createDeviceCommand() *cli.Command {
 						Name:     "device-id",
 						Required: true,
 					},
-					&cli.StringFlag{
+					&cli.StringSliceFlag{
 						Name:     "security-group-id",
+						Usage:    "Security group ID to assign to the device, can be repeated to assign several security groups. Replaces the current security groups of the device",
 						Required: false,
 					},
 					&cli.StringFlag{
 						Name:     "hostname",
 						Required: false,
 					},
+					&cli.StringSliceFlag{
+						Name:     "exit-node-include",
+						Usage:    "CIDR or domain name to send through the exit node, can be repeated. Other destinations bypass the exit node",
+						Required: false,
+					},
+					&cli.StringSliceFlag{
+						Name:     "exit-node-exclude",
+						Usage:    "CIDR or domain name that bypasses the exit node, can be repeated",
+						Required: false,
+					},
+					&cli.BoolFlag{
+						Name:     "exit-node-clear-policy",
+						Usage:    "Remove the exit node policy so that all traffic is sent through the exit node",
+						Required: false,
+					},
+					&cli.UintFlag{
+						Name:     "bandwidth-limit",
+						Usage:    "Bandwidth in bits per second above which the peers drop the traffic of the device. Replaces the current traffic limits",
+						Required: false,
+					},
+					&cli.UintFlag{
+						Name:     "packet-rate-limit",
+						Usage:    "Packets per second above which the peers drop the traffic of the device. Replaces the current traffic limits",
+						Required: false,
+					},
+					&cli.BoolFlag{
+						Name:     "clear-traffic-limits",
+						Usage:    "Remove the traffic limits of the device",
+						Required: false,
+					},
 				},
 				Action: func(ctx context.Context, command *cli.Command) error {
 
@@ -90,11 +123,40 @@ func createDeviceCommand() *cli.Command {
 						update.Hostname = client.PtrString(value)
 					}
 					if command.IsSet("security-group-id") {
-						value, err := getUUID(command, "security-group-id")
+						values, err := getUUIDs(command, "security-group-id")
 						if err != nil {
 							return err
 						}
-						update.SecurityGroupId = client.PtrString(value)
+						update.SecurityGroupIds = values
+					}
+					if command.IsSet("exit-node-include") || command.IsSet("exit-node-exclude") {
+						if command.Bool("exit-node-clear-policy") {
+							return fmt.Errorf("--exit-node-clear-policy cannot be combined with --exit-node-include or --exit-node-exclude")
+						}
+						update.ExitNodePolicy = &client.ModelsExitNodePolicy{
+							Include: command.StringSlice("exit-node-include"),
+							Exclude: command.StringSlice("exit-node-exclude"),
+						}
+					} else if command.Bool("exit-node-clear-policy") {
+						update.ExitNodePolicy = &client.ModelsExitNodePolicy{}
+					}
+					if command.IsSet("bandwidth-limit") || command.IsSet("packet-rate-limit") {
+						if command.Bool("clear-traffic-limits") {
+							return fmt.Errorf("--clear-traffic-limits cannot be combined with --bandwidth-limit or --packet-rate-limit")
+						}
+						limits := &client.ModelsTrafficLimits{}
+						if command.IsSet("bandwidth-limit") {
+							limits.SetBitsPerSecond(int64(command.Uint("bandwidth-limit")))
+						}
+						if command.IsSet("packet-rate-limit") {
+							if command.Uint("packet-rate-limit") > math.MaxInt32 {
+								return fmt.Errorf("--packet-rate-limit must be at most %d", math.MaxInt32)
+							}
+							limits.SetPacketsPerSecond(int32(command.Uint("packet-rate-limit")))
+						}
+						update.TrafficLimits = limits
+					} else if command.Bool("clear-traffic-limits") {
+						update.TrafficLimits = &client.ModelsTrafficLimits{}
 					}
 					return updateDevice(ctx, command, devID, update)
 				},
@@ -166,7 +228,13 @@ func deviceTableFields(command *cli.Command) []TableField {
 		}})
 		fields = append(fields, TableField{Header: "SYMMETRIC NAT", Field: "SymmetricNat"})
 		fields = append(fields, TableField{Header: "OS", Field: "Os"})
-		fields = append(fields, TableField{Header: "SECURITY GROUP ID", Field: "SecurityGroupId"})
+		fields = append(fields, TableField{Header: "SECURITY GROUP IDS", Formatter: func(item interface{}) string {
+			dev := item.(client.ModelsDevice)
+			if dev.SecurityGroupIds == nil {
+				return dev.GetSecurityGroupId()
+			}
+			return strings.Join(dev.SecurityGroupIds, ", ")
+		}})
 		fields = append(fields, TableField{Header: "ONLINE", Field: "Online"})
 		fields = append(fields, TableField{Header: "ONLINE SINCE", Formatter: func(item interface{}) string {
 			d := item.(client.ModelsDevice)
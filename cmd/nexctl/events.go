@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nexodus-io/nexodus/internal/api"
+	"github.com/urfave/cli/v3"
+)
+
+func cmdNexdEvents(ctx context.Context, command *cli.Command) error {
+	if err := checkVersion(); err != nil {
+		return err
+	}
+
+	conn, err := dialNexd()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	arg := ""
+	if command.Bool("follow") {
+		arg = api.SubscribeFollow
+	}
+	// nexd answers the subscribe request with a stream of events rather than a JSON-RPC response
+	if err := json.NewEncoder(conn).Encode(map[string]interface{}{"method": api.SubscribeMethod, "params": []string{arg}, "id": 0}); err != nil {
+		return fmt.Errorf("Failed to subscribe to nexd events: %w\n", err)
+	}
+
+	encodeOut := command.String("output")
+	decoder := json.NewDecoder(conn)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			// nexd closes the stream once the recent events are sent, or when nexctl is interrupted
+			if ctx.Err() != nil || !command.Bool("follow") {
+				return nil
+			}
+			return fmt.Errorf("Lost the connection to nexd: %w\n", err)
+		}
+
+		// older nexd versions answer with a JSON-RPC error
+		var rpcErr struct {
+			Error *string `json:"error"`
+		}
+		if json.Unmarshal(raw, &rpcErr) == nil && rpcErr.Error != nil {
+			return fmt.Errorf("Failed to subscribe to nexd events: %s\n", *rpcErr.Error)
+		}
+
+		if encodeOut == encodeJsonRaw || encodeOut == encodeJsonPretty {
+			fmt.Println(string(raw))
+			continue
+		}
+
+		var event api.Event
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return fmt.Errorf("Failed to decode nexd event: %w\n", err)
+		}
+		fmt.Println(formatNexdEvent(event))
+	}
+}
+
+func formatNexdEvent(event api.Event) string {
+	details := []string{}
+	if event.Hostname != "" {
+		details = append(details, event.Hostname)
+	}
+	if event.PublicKey != "" {
+		details = append(details, "key="+event.PublicKey)
+	}
+	if event.PeeringMethod != "" {
+		if event.PreviousPeeringMethod != "" {
+			details = append(details, fmt.Sprintf("method=%s->%s", event.PreviousPeeringMethod, event.PeeringMethod))
+		} else {
+			details = append(details, "method="+event.PeeringMethod)
+		}
+	}
+	if len(event.SecurityGroupIds) > 0 {
+		details = append(details, "security-groups="+strings.Join(event.SecurityGroupIds, ","))
+	}
+	if event.Status != "" {
+		details = append(details, "status="+event.Status)
+	}
+	if event.Message != "" {
+		details = append(details, strings.TrimSpace(event.Message))
+	}
+	return fmt.Sprintf("%s  %-24s %s", event.Time.Local().Format(LocalTimeFormat), event.Type, strings.Join(details, " "))
+}
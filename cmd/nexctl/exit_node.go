@@ -2,79 +2,98 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+
+	"github.com/nexodus-io/nexodus/internal/api"
 	"github.com/urfave/cli/v3"
 )
 
-type exitNodeOrigin struct {
-	PublicKey           string
-	Endpoint            string
-	AllowedIPs          []string
-	PersistentKeepAlive string
-}
-
 func enableExitNodeClient(ctx context.Context, command *cli.Command) error {
 	if err := checkVersion(); err != nil {
 		return err
 	}
 
-	result, err := callNexd("EnableExitNodeClient", "")
-	if err != nil {
-		return fmt.Errorf("Failed to enable exit node client: %w\n", err)
+	if err := callNexdV1("ExitNodeClient", api.ExitNodeClientRequest{Enabled: true}, &api.Empty{}); err != nil {
+		fmt.Printf("Error encountered while enabling exit node client: %s\n", err)
+		return nil
+	}
+
+	showNexd(command, api.ExitNodeClientRequest{Enabled: true}, "Successfully enabled exit node client on this device\n")
+	return nil
+}
+
+func disableExitNodeClient(ctx context.Context, command *cli.Command) error {
+	if err := checkVersion(); err != nil {
+		return err
 	}
 
-	if result == "null" {
-		fmt.Printf("Successfully enabled exit node client on this device\n")
+	if err := callNexdV1("ExitNodeClient", api.ExitNodeClientRequest{Enabled: false}, &api.Empty{}); err != nil {
+		fmt.Printf("Error encountered while disabling exit node client: %s\n", err)
 		return nil
 	}
-	fmt.Printf("Error encountered  while enabing exit node client: %s\n", result)
 
+	showNexd(command, api.ExitNodeClientRequest{Enabled: false}, "Successfully disabled exit node client on this device\n")
 	return nil
 }
 
-func disableExitNodeClient(ctx context.Context, command *cli.Command) error {
+func useExitNode(ctx context.Context, command *cli.Command) error {
 	if err := checkVersion(); err != nil {
 		return err
 	}
 
-	result, err := callNexd("DisableExitNodeClient", "")
-	if err != nil {
-		return fmt.Errorf("Failed to disable exit node client: %w\n", err)
+	device := command.Args().First()
+	if command.Bool("auto") {
+		device = ""
+	} else if device == "" {
+		return fmt.Errorf("an exit node device is required, or --auto to use the healthiest exit node")
 	}
 
-	if result == "null" {
-		fmt.Printf("Successfully disabled exit node client on this device\n")
+	var result api.ExitNode
+	if err := callNexdV1("UseExitNode", api.UseExitNodeRequest{Device: device}, &result); err != nil {
+		return fmt.Errorf("Failed to use exit node: %w\n", err)
+	}
+
+	if device == "" {
+		showNexd(command, result, fmt.Sprintf("Automatically using the healthiest exit node, currently: %s\n", result.PublicKey))
 		return nil
 	}
-	fmt.Printf("Error encountered while enabing exit node client: %s\n", result)
+	showNexd(command, result, fmt.Sprintf("Successfully selected the exit node: %s\n", result.PublicKey))
 
 	return nil
 }
 
 func exitNodeTableFields(command *cli.Command) []TableField {
 	var fields []TableField
+	fields = append(fields, TableField{Header: "HOSTNAME", Field: "Hostname"})
 	fields = append(fields, TableField{Header: "ENDPOINT ADDRESS", Field: "Endpoint"})
 	fields = append(fields, TableField{Header: "PUBLIC KEY", Field: "PublicKey"})
+	fields = append(fields, TableField{Header: "HEALTHY", Field: "Healthy"})
+	fields = append(fields, TableField{Header: "STATE", Formatter: func(item interface{}) string {
+		origin := item.(api.ExitNode)
+		switch {
+		case origin.Local:
+			return "local"
+		case origin.Active && origin.Selected:
+			return "active (selected)"
+		case origin.Active:
+			return "active"
+		case origin.Selected:
+			return "selected"
+		}
+		return ""
+	}})
 	return fields
 }
 func listExitNodes(ctx context.Context, command *cli.Command, encodeOut string) error {
-	var err error
-	var exitNodes []exitNodeOrigin
-	if err = checkVersion(); err != nil {
+	if err := checkVersion(); err != nil {
 		return err
 	}
 
-	result, err := callNexd("ListExitNodes", "")
-	if err != nil {
+	var result api.ExitNodesResponse
+	if err := callNexdV1("ExitNodes", api.Empty{}, &result); err != nil {
 		return fmt.Errorf("Failed to list exit nodes: %w\n", err)
 	}
 
-	err = json.Unmarshal([]byte(result), &exitNodes)
-	if err != nil {
-		return fmt.Errorf("Failed to marshall exit node results: %w\n", err)
-	}
-
-	show(command, exitNodeTableFields(command), exitNodes)
+	show(command, exitNodeTableFields(command), result.ExitNodes)
 	return nil
 }
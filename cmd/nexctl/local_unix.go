@@ -4,10 +4,13 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/rpc/jsonrpc"
+	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/nexodus-io/nexodus/internal/api"
 	"github.com/urfave/cli/v3"
@@ -26,6 +29,14 @@ func init() {
 				DefaultText: api.UnixSocketPath,
 				Required:    false,
 			},
+			&cli.StringFlag{
+				Name:        "ctl-token-file",
+				Usage:       "Path to the token file of the nexd loopback control port, used when the unix socket is not available",
+				Value:       api.CtlTokenPath,
+				Destination: &api.CtlTokenPath,
+				DefaultText: api.CtlTokenPath,
+				Required:    false,
+			},
 		},
 		Before: func(ctx context.Context, command *cli.Command) error {
 			if err := hasPrivileges(); err != nil {
@@ -60,21 +71,19 @@ func init() {
 							},
 						},
 						Action: func(ctx context.Context, command *cli.Command) error {
-							var result string
-							var err error
 							if err := checkVersion(); err != nil {
 								return err
 							}
-							if command.Bool("ipv6") {
-								result, err = callNexd("GetTunnelIPv6", "")
-							} else {
-								result, err = callNexd("GetTunnelIPv4", "")
-							}
-							if err != nil {
+							var result api.TunnelIPsResponse
+							if err := callNexdV1("TunnelIPs", api.Empty{}, &result); err != nil {
 								fmt.Printf("%s\n", err)
 								return err
 							}
-							fmt.Printf("%s\n", result)
+							text := result.IPv4
+							if command.Bool("ipv6") {
+								text = result.IPv6
+							}
+							showNexd(command, result, text+"\n")
 							return nil
 						},
 					},
@@ -85,12 +94,16 @@ func init() {
 							if err := checkVersion(); err != nil {
 								return err
 							}
-							result, err := callNexd("GetDebug", "")
-							if err != nil {
+							var result api.DebugResponse
+							if err := callNexdV1("Debug", api.Empty{}, &result); err != nil {
 								fmt.Printf("%s\n", err)
 								return err
 							}
-							fmt.Printf("%s\n", result)
+							text := "off\n"
+							if result.Debug {
+								text = "on\n"
+							}
+							showNexd(command, result, text)
 							return nil
 						},
 					},
@@ -108,32 +121,14 @@ func init() {
 								Name:  "on",
 								Usage: "Turn debug logging on",
 								Action: func(ctx context.Context, command *cli.Command) error {
-									if err := checkVersion(); err != nil {
-										return err
-									}
-									result, err := callNexd("SetDebugOn", "")
-									if err != nil {
-										fmt.Printf("%s\n", err)
-										return err
-									}
-									fmt.Printf("%s\n", result)
-									return nil
+									return cmdSetDebug(ctx, command, true)
 								},
 							},
 							{
 								Name:  "off",
 								Usage: "Turn debug logging off",
 								Action: func(ctx context.Context, command *cli.Command) error {
-									if err := checkVersion(); err != nil {
-										return err
-									}
-									result, err := callNexd("SetDebugOff", "")
-									if err != nil {
-										fmt.Printf("%s\n", err)
-										return err
-									}
-									fmt.Printf("%s\n", result)
-									return nil
+									return cmdSetDebug(ctx, command, false)
 								},
 							},
 						},
@@ -151,12 +146,20 @@ func init() {
 							if err := checkVersion(); err != nil {
 								return err
 							}
-							result, err := callNexd("ProxyList", "")
-							if err != nil {
+							var result api.ProxyRulesResponse
+							if err := callNexdV1("ProxyRules", api.Empty{}, &result); err != nil {
 								fmt.Printf("%s\n", err)
 								return err
 							}
-							fmt.Printf("%s", result)
+							text := ""
+							for _, rule := range result.Rules {
+								text += fmt.Sprintf("--%s %s", rule.Type, rule.Rule)
+								if rule.LoadBalancing != "" && rule.LoadBalancing != "round-robin" {
+									text += fmt.Sprintf(" --lb %s", rule.LoadBalancing)
+								}
+								text += "\n"
+							}
+							showNexd(command, result.Rules, text)
 							return nil
 						},
 					},
@@ -174,6 +177,11 @@ func init() {
 								Usage:    "Forward connections from a locally accessible network made to [port] on this proxy instance to port [destination_port] at [destination_ip] via the Nexodus network using a `value` in the form: protocol:port:destination_ip:destination_port. All fields are required.",
 								Required: false,
 							},
+							&cli.StringFlag{
+								Name:     "lb",
+								Usage:    "Set the `policy` picking the destination of the connections made to a port with several rules: round-robin, least-conn, source-hash (by client IP) or failover (to the rules in the order they were added). TCP destinations failing their health checks are skipped. Adding an existing rule with --lb only changes the policy.",
+								Required: false,
+							},
 						},
 						Action: func(ctx context.Context, command *cli.Command) error {
 							return proxyAddRemove(ctx, command, true)
@@ -235,6 +243,21 @@ func init() {
 					},
 				},
 			},
+			{
+				Name:  "events",
+				Usage: "Display the recent nexd events: peers added or removed, peering method and health changes, security groups applied and status changes",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "follow",
+						Aliases: []string{"f"},
+						Usage:   "keep displaying new events as they happen",
+						Value:   false,
+					},
+				},
+				Action: func(ctx context.Context, command *cli.Command) error {
+					return cmdNexdEvents(ctx, command)
+				},
+			},
 			{
 				Name:  "exit-node",
 				Usage: "Commands for interacting nexd exit node configuration",
@@ -254,6 +277,21 @@ func init() {
 							return enableExitNodeClient(ctx, command)
 						},
 					},
+					{
+						Name:      "use",
+						Usage:     "Pick the exit node this device sends its traffic to, by hostname, device id, public key or tunnel ip. If the exit node goes offline, traffic fails over to the healthiest remaining exit node until it is back.",
+						ArgsUsage: "<device>",
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:     "auto",
+								Usage:    "go back to automatically using the healthiest exit node",
+								Required: false,
+							},
+						},
+						Action: func(ctx context.Context, command *cli.Command) error {
+							return useExitNode(ctx, command)
+						},
+					},
 					{
 						Name:  "disable",
 						Usage: "Disable the device from using an exit node. Traffic will return to using the device's default gateway and direct peers in the nexodus peer network.",
@@ -274,13 +312,73 @@ func init() {
 	})
 }
 
-func callNexd(method string, arg string) (string, error) {
+// dialNexd connects to the unix socket of nexd, or to its loopback control port when nexd left a token file in
+// its state directory, as it does on Windows
+func dialNexd() (net.Conn, error) {
 	conn, err := net.Dial("unix", api.UnixSocketPath)
+	if err == nil {
+		return conn, nil
+	}
+	conn, err = net.Dial("unix", filepath.Base(api.UnixSocketPath))
+	if err == nil {
+		return conn, nil
+	}
+	if _, statErr := os.Stat(api.CtlTokenPath); statErr == nil {
+		return dialNexdTCP()
+	}
+	return nil, fmt.Errorf("Failed to connect to nexd: %w\n", err)
+}
+
+func dialNexdTCP() (net.Conn, error) {
+	data, err := os.ReadFile(api.CtlTokenPath)
 	if err != nil {
-		conn, err = net.Dial("unix", filepath.Base(api.UnixSocketPath))
-		if err != nil {
-			return "", fmt.Errorf("Failed to connect to nexd: %w\n", err)
-		}
+		return nil, fmt.Errorf("Failed to read the nexd control token: %w\n", err)
+	}
+	ctlToken := api.CtlToken{}
+	if err := json.Unmarshal(data, &ctlToken); err != nil {
+		return nil, fmt.Errorf("Failed to parse the nexd control token file '%s': %w\n", api.CtlTokenPath, err)
+	}
+
+	conn, err := net.DialTimeout("tcp", ctlToken.Address, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to nexd at '%s': %w\n", ctlToken.Address, err)
+	}
+	if err := json.NewEncoder(conn).Encode(api.CtlAuth{Token: ctlToken.Token}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Failed to authenticate to nexd: %w\n", err)
+	}
+	return conn, nil
+}
+
+// callNexdV1 calls a method of the typed nexd control service
+func callNexdV1(method string, request any, result any) error {
+	conn, err := dialNexd()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := jsonrpc.NewClient(conn)
+	if err := client.Call(api.CtlServiceV1+"."+method, request, result); err != nil {
+		return fmt.Errorf("Failed to execute method (%s): %w\n", method, err)
+	}
+	return nil
+}
+
+// showNexd prints the text for the human readable output formats, and the result for the others
+func showNexd(command *cli.Command, result any, text string) {
+	switch command.String("output") {
+	case encodeColumn, encodeNoHeader:
+		fmt.Print(text)
+	default:
+		show(command, nil, result)
+	}
+}
+
+func callNexd(method string, arg string) (string, error) {
+	conn, err := dialNexd()
+	if err != nil {
+		return "", err
 	}
 	defer conn.Close()
 
@@ -325,16 +423,33 @@ func cmdLocalStatus(ctx context.Context, command *cli.Command) error {
 		return err
 	}
 
-	result, err := callNexd("Status", "")
-	if err != nil {
+	var result api.StatusResponse
+	if err := callNexdV1("Status", api.Empty{}, &result); err != nil {
 		return err
 	}
 
-	fmt.Printf("%s", result)
+	showNexd(command, result, fmt.Sprintf("Status: %s\n%s", result.Status, result.Message))
 
 	return nil
 }
 
+func cmdSetDebug(ctx context.Context, command *cli.Command, debug bool) error {
+	if err := checkVersion(); err != nil {
+		return err
+	}
+	var result api.DebugResponse
+	if err := callNexdV1("SetDebug", api.DebugRequest{Debug: debug}, &result); err != nil {
+		fmt.Printf("%s\n", err)
+		return err
+	}
+	text := "Debug logging disabled\n"
+	if result.Debug {
+		text = "Debug logging enabled\n"
+	}
+	showNexd(command, result, text)
+	return nil
+}
+
 func proxyAddRemove(ctx context.Context, command *cli.Command, add bool) error {
 	if err := checkVersion(); err != nil {
 		return err
@@ -345,34 +460,37 @@ func proxyAddRemove(ctx context.Context, command *cli.Command, add bool) error {
 		return fmt.Errorf("No rules provided")
 	}
 
-	var method string
-	addStr := "adding"
+	method := "ProxyRemove"
+	addStr := "removing"
+	doneStr := "Removed"
 	if add {
-		method = "ProxyAddIngress"
-	} else {
-		method = "ProxyRemoveIngress"
-		addStr = "removing"
-	}
-	for _, rule := range ingress {
-		result, err := callNexd(method, rule)
-		if err != nil {
-			fmt.Printf("Error %s ingress rule (%s): %s\n", addStr, rule, err)
-			continue
-		}
-		fmt.Printf("%s", result)
+		method = "ProxyAdd"
+		addStr = "adding"
+		doneStr = "Added"
 	}
+	lbPolicy := ""
 	if add {
-		method = "ProxyAddEgress"
-	} else {
-		method = "ProxyRemoveEgress"
+		lbPolicy = command.String("lb")
+	}
+	var requests []api.ProxyRuleRequest
+	for _, rule := range ingress {
+		requests = append(requests, api.ProxyRuleRequest{Type: "ingress", Rule: rule, LoadBalancing: lbPolicy})
 	}
 	for _, rule := range egress {
-		result, err := callNexd(method, rule)
-		if err != nil {
-			fmt.Printf("Error %s egress rule (%s): %s\n", addStr, rule, err)
+		requests = append(requests, api.ProxyRuleRequest{Type: "egress", Rule: rule, LoadBalancing: lbPolicy})
+	}
+
+	rules := []api.ProxyRule{}
+	text := ""
+	for _, request := range requests {
+		var result api.ProxyRule
+		if err := callNexdV1(method, request, &result); err != nil {
+			fmt.Printf("Error %s %s rule (%s): %s\n", addStr, request.Type, request.Rule, err)
 			continue
 		}
-		fmt.Printf("%s", result)
+		rules = append(rules, result)
+		text += fmt.Sprintf("%s %s proxy rule: %s\n", doneStr, result.Type, request.Rule)
 	}
+	showNexd(command, rules, text)
 	return nil
 }
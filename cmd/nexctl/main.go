@@ -75,6 +75,12 @@ func main() {
 				Usage:      "Password",
 				Persistent: true,
 			},
+			&cli.StringFlag{
+				Name:       "token",
+				Usage:      "Api token to authenticate with instead of logging in",
+				Sources:    cli.EnvVars("NEXCTL_TOKEN"),
+				Persistent: true,
+			},
 			&cli.StringFlag{
 				Name:       "output",
 				Value:      encodeColumn,
@@ -108,6 +114,8 @@ func main() {
 			createServiceNetworkCommand(),
 			createSiteCommand(),
 			createInvitationCommand(),
+			createServiceAccountCommand(),
+			createTokenCommand(),
 		},
 	}
 
@@ -175,6 +183,9 @@ func createClientOptions(command *cli.Command) []client.Option {
 		),
 		client.WithUserAgent(fmt.Sprintf("nexctl/%s (%s; %s)", Version, runtime.GOOS, runtime.GOARCH)),
 	}
+	if command.String("token") != "" {
+		options = append(options, client.WithBearerToken(command.String("token")))
+	}
 	if command.Bool("insecure-skip-tls-verify") { // #nosec G402
 		options = append(options, client.WithTLSConfig(&tls.Config{
 			InsecureSkipVerify: true,
@@ -362,12 +373,28 @@ func getUUID(command *cli.Command, name string) (string, error) {
 	return value, nil
 }
 
+func getUUIDs(command *cli.Command, name string) ([]string, error) {
+	values := command.StringSlice(name)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		_, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --%s flag: %w", name, err)
+		}
+		ids = append(ids, value)
+	}
+	return ids, nil
+}
+
 func getExpiration(command *cli.Command, name string) string {
 	value := command.Duration(name)
 	if value == 0 {
 		return ""
 	}
-	return time.Now().Add(value).String()
+	return time.Now().Add(value).Format(time.RFC3339)
 }
 
 func getJsonMap(command *cli.Command, name string) (map[string]interface{}, error) {
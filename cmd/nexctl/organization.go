@@ -2,6 +2,10 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/nexodus-io/nexodus/internal/client"
 	"github.com/urfave/cli/v3"
 )
@@ -35,6 +39,33 @@ func createOrganizationCommand() *cli.Command {
 							return listOrganizationUsers(ctx, command, organizationID)
 						},
 					},
+					{
+						Name:  "update",
+						Usage: "Change the roles of a user in an organization",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "user-id",
+								Required: true,
+							},
+							&cli.StringSliceFlag{
+								Name:     "role",
+								Usage:    "Role of the user in the organization, can be repeated",
+								Required: true,
+							},
+						},
+						Action: func(ctx context.Context, command *cli.Command) error {
+							organizationID, err := getUUID(command, "organization-id")
+							if err != nil {
+								return err
+							}
+							userID, err := getUUID(command, "user-id")
+							if err != nil {
+								return err
+							}
+
+							return updateOrganizationUser(ctx, command, organizationID, userID, command.StringSlice("role"))
+						},
+					},
 					{
 						Name:  "delete",
 						Usage: "Delete a user from an organization",
@@ -59,6 +90,47 @@ func createOrganizationCommand() *cli.Command {
 					},
 				},
 			},
+			{
+				Name:  "audit",
+				Usage: "List the changes made to the resources of an organization",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "organization-id",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  "verify",
+						Usage: "Verify the hash chain of the audit log instead of listing it",
+					},
+				},
+				Action: func(ctx context.Context, command *cli.Command) error {
+					organizationID, err := getUUID(command, "organization-id")
+					if err != nil {
+						return err
+					}
+					if command.Bool("verify") {
+						return verifyOrganizationAudit(ctx, command, organizationID)
+					}
+					return listOrganizationAudit(ctx, command, organizationID)
+				},
+			},
+			{
+				Name:  "usage",
+				Usage: "Show the usage of the quotas of an organization",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "organization-id",
+						Required: true,
+					},
+				},
+				Action: func(ctx context.Context, command *cli.Command) error {
+					organizationID, err := getUUID(command, "organization-id")
+					if err != nil {
+						return err
+					}
+					return showOrganizationUsage(ctx, command, organizationID)
+				},
+			},
 			{
 				Name:  "list",
 				Usage: "List organizations",
@@ -183,6 +255,10 @@ func orgUsersTableFields() []TableField {
 		usr := item.(client.ModelsUserOrganization)
 		return usr.User.GetFullName()
 	}})
+	fields = append(fields, TableField{Header: "ROLES", Formatter: func(item interface{}) string {
+		usr := item.(client.ModelsUserOrganization)
+		return strings.Join(usr.Roles, ", ")
+	}})
 	return fields
 }
 func listOrganizationUsers(ctx context.Context, command *cli.Command, orgId string) error {
@@ -193,6 +269,18 @@ func listOrganizationUsers(ctx context.Context, command *cli.Command, orgId stri
 	show(command, orgUsersTableFields(), res)
 	return nil
 }
+func updateOrganizationUser(ctx context.Context, command *cli.Command, orgId string, userId string, roles []string) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.OrganizationsApi.
+		UpdateOrganizationUser(ctx, orgId, userId).
+		Update(client.ModelsUpdateUserOrganization{
+			Roles: roles,
+		}).
+		Execute())
+	show(command, orgUsersTableFields(), res)
+	showSuccessfully(command, "updated")
+	return nil
+}
 func deleteOrganizationUser(ctx context.Context, command *cli.Command, orgId string, userId string) error {
 	c := createClient(ctx, command)
 	res := apiResponse(c.OrganizationsApi.
@@ -202,3 +290,82 @@ func deleteOrganizationUser(ctx context.Context, command *cli.Command, orgId str
 	showSuccessfully(command, "deleted")
 	return nil
 }
+
+func orgAuditTableFields() []TableField {
+	var fields []TableField
+	fields = append(fields, TableField{Header: "SEQUENCE", Field: "Sequence"})
+	fields = append(fields, TableField{Header: "TIME", Field: "CreatedAt"})
+	fields = append(fields, TableField{Header: "ACTOR", Formatter: func(item interface{}) string {
+		entry := item.(client.ModelsAuditEntry)
+		return fmt.Sprintf("%s %s", entry.GetActorKind(), entry.GetActorId())
+	}})
+	fields = append(fields, TableField{Header: "SOURCE IP", Field: "SourceIp"})
+	fields = append(fields, TableField{Header: "ACTION", Field: "Action"})
+	fields = append(fields, TableField{Header: "RESOURCE", Formatter: func(item interface{}) string {
+		entry := item.(client.ModelsAuditEntry)
+		return fmt.Sprintf("%s/%s", entry.GetResourceKind(), entry.GetResourceId())
+	}})
+	fields = append(fields, TableField{Header: "CHANGED FIELDS", Formatter: func(item interface{}) string {
+		entry := item.(client.ModelsAuditEntry)
+		var names []string
+		for name := range entry.GetDiff() {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}})
+	return fields
+}
+
+func listOrganizationAudit(ctx context.Context, command *cli.Command, orgId string) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.OrganizationsApi.
+		ListOrganizationAudit(ctx, orgId).
+		Execute())
+	show(command, orgAuditTableFields(), res)
+	return nil
+}
+
+func verifyOrganizationAudit(ctx context.Context, command *cli.Command, orgId string) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.OrganizationsApi.
+		VerifyOrganizationAudit(ctx, orgId).
+		Execute())
+	var fields []TableField
+	fields = append(fields, TableField{Header: "VERIFIED", Field: "Verified"})
+	fields = append(fields, TableField{Header: "ENTRIES", Field: "Entries"})
+	fields = append(fields, TableField{Header: "HEAD SEQUENCE", Field: "HeadSequence"})
+	fields = append(fields, TableField{Header: "HEAD HASH", Field: "HeadHash"})
+	fields = append(fields, TableField{Header: "INVALID SEQUENCE", Field: "InvalidSequence"})
+	fields = append(fields, TableField{Header: "ERROR", Field: "Error"})
+	show(command, fields, res)
+	if !res.GetVerified() {
+		return fmt.Errorf("the audit log does not match its hash chain at entry %d: %s", res.GetInvalidSequence(), res.GetError())
+	}
+	return nil
+}
+
+func quotaUsageField(header string, get func(*client.ModelsOrganizationUsage) client.ModelsQuotaUsage) TableField {
+	return TableField{Header: header, Formatter: func(item interface{}) string {
+		usage := get(item.(*client.ModelsOrganizationUsage))
+		if usage.GetLimit() == 0 {
+			return fmt.Sprintf("%d/unlimited", usage.GetUsed())
+		}
+		return fmt.Sprintf("%d/%d", usage.GetUsed(), usage.GetLimit())
+	}}
+}
+
+func showOrganizationUsage(ctx context.Context, command *cli.Command, orgId string) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.OrganizationsApi.
+		GetOrganizations(ctx, orgId).
+		Execute())
+	var fields []TableField
+	fields = append(fields, quotaUsageField("VPCS", (*client.ModelsOrganizationUsage).GetVpcs))
+	fields = append(fields, quotaUsageField("DEVICES", (*client.ModelsOrganizationUsage).GetDevices))
+	fields = append(fields, quotaUsageField("REG KEYS", (*client.ModelsOrganizationUsage).GetRegKeys))
+	fields = append(fields, quotaUsageField("SECURITY GROUPS", (*client.ModelsOrganizationUsage).GetSecurityGroups))
+	fields = append(fields, quotaUsageField("METADATA KEYS", (*client.ModelsOrganizationUsage).GetMetadataKeys))
+	show(command, fields, res.Usage)
+	return nil
+}
@@ -2,14 +2,11 @@ package main
 
 import (
 	"fmt"
-	"github.com/nexodus-io/nexodus/internal/api"
-	"net"
 	"os"
-	"path/filepath"
 	"runtime"
 )
 
-// hasPrivileges checks to see if we can access nexd over the admin interface (unix socket)
+// hasPrivileges checks to see if we can access nexd over the admin interface (unix socket or loopback port)
 func hasPrivileges() error {
 
 	// the true test if we have privileges is if we can open the socket file.
@@ -72,12 +69,9 @@ func isWindowsAdmin() bool {
 }
 
 func canAccessSocketAPI() error {
-	conn, err := net.Dial("unix", api.UnixSocketPath)
+	conn, err := dialNexd()
 	if err != nil {
-		conn, err = net.Dial("unix", filepath.Base(api.UnixSocketPath))
-		if err != nil {
-			return fmt.Errorf("failed to connect to nexd at '%s': %w\n", api.UnixSocketPath, err)
-		}
+		return err
 	}
 	conn.Close()
 	return nil
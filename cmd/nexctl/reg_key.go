@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/nexodus-io/nexodus/internal/client"
 	"github.com/urfave/cli/v3"
+	"strings"
 )
 
 func createRegKeyCommand() *cli.Command {
@@ -36,8 +37,9 @@ func createRegKeyCommand() *cli.Command {
 						Name:     "vpc-id",
 						Required: false,
 					},
-					&cli.StringFlag{
+					&cli.StringSliceFlag{
 						Name:     "security-group-id",
+						Usage:    "Security group ID to assign to the devices registered with the key, can be repeated to assign several security groups",
 						Required: false,
 					},
 					&cli.StringFlag{
@@ -68,13 +70,18 @@ func createRegKeyCommand() *cli.Command {
 						settings = nil
 					}
 
+					securityGroupIds, err := getUUIDs(command, "security-group-id")
+					if err != nil {
+						return err
+					}
+
 					return createRegKey(ctx, command, client.ModelsAddRegKey{
-						VpcId:           client.PtrOptionalString(command.String("vpc-id")),
-						Description:     client.PtrOptionalString(command.String("description")),
-						ExpiresAt:       client.PtrOptionalString(getExpiration(command, "expiration")),
-						SingleUse:       client.PtrBool(command.Bool("single-use")),
-						SecurityGroupId: client.PtrOptionalString(command.String("security-group-id")),
-						Settings:        settings,
+						VpcId:            client.PtrOptionalString(command.String("vpc-id")),
+						Description:      client.PtrOptionalString(command.String("description")),
+						ExpiresAt:        client.PtrOptionalString(getExpiration(command, "expiration")),
+						SingleUse:        client.PtrBool(command.Bool("single-use")),
+						SecurityGroupIds: securityGroupIds,
+						Settings:         settings,
 					})
 				},
 			},
@@ -86,8 +93,9 @@ func createRegKeyCommand() *cli.Command {
 						Name:     "reg-key-id",
 						Required: true,
 					},
-					&cli.StringFlag{
+					&cli.StringSliceFlag{
 						Name:     "security-group-id",
+						Usage:    "Security group ID to assign to the devices registered with the key, can be repeated to assign several security groups",
 						Required: false,
 					},
 					&cli.StringFlag{
@@ -114,11 +122,16 @@ func createRegKeyCommand() *cli.Command {
 						settings = nil
 					}
 
+					securityGroupIds, err := getUUIDs(command, "security-group-id")
+					if err != nil {
+						return err
+					}
+
 					return updateRegKey(ctx, command, command.String("reg-key-id"), client.ModelsUpdateRegKey{
-						Description:     client.PtrOptionalString(command.String("description")),
-						ExpiresAt:       client.PtrOptionalString(getExpiration(command, "expiration")),
-						SecurityGroupId: client.PtrOptionalString(command.String("security-group-id")),
-						Settings:        settings,
+						Description:      client.PtrOptionalString(command.String("description")),
+						ExpiresAt:        client.PtrOptionalString(getExpiration(command, "expiration")),
+						SecurityGroupIds: securityGroupIds,
+						Settings:         settings,
 					})
 				},
 			},
@@ -153,7 +166,13 @@ func regTokenTableFields(command *cli.Command) []TableField {
 	}})
 	if command.Bool("full") {
 		fields = append(fields, TableField{Header: "VPC ID", Field: "VpcId"})
-		fields = append(fields, TableField{Header: "SECURITY GROUP ID", Field: "SecurityGroupId"})
+		fields = append(fields, TableField{Header: "SECURITY GROUP IDS", Formatter: func(item interface{}) string {
+			key := item.(client.ModelsRegKey)
+			if key.SecurityGroupIds == nil {
+				return key.GetSecurityGroupId()
+			}
+			return strings.Join(key.SecurityGroupIds, ", ")
+		}})
 		fields = append(fields, TableField{Header: "SINGLE USE", Formatter: func(item interface{}) string {
 			key := item.(client.ModelsRegKey)
 			if key.GetDeviceId() == "" {
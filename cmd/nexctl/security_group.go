@@ -39,6 +39,24 @@ func createSecurityGroupCommand() *cli.Command {
 					return deleteSecurityGroup(ctx, command, encodeOut, sgID)
 				},
 			},
+			{
+				Name:  "stats",
+				Usage: "Show the packet and byte counters of the rules of a security group",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "security-group-id",
+						Required: true,
+					},
+				},
+				Action: func(ctx context.Context, command *cli.Command) error {
+					sgID, err := getUUID(command, "security-group-id")
+					if err != nil {
+						return err
+					}
+
+					return securityGroupStats(ctx, command, sgID)
+				},
+			},
 			{
 				Name:  "create",
 				Usage: "create a security group",
@@ -88,6 +106,107 @@ func createSecurityGroupCommand() *cli.Command {
 					return createSecurityGroup(ctx, command, description, vpcId, inboundRules, outboundRules)
 				},
 			},
+			{
+				Name:  "test",
+				Usage: "Test whether traffic between two devices would be allowed, optionally with proposed rules",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "security-group-id",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "src-device-id",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "dst-device-id",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "protocol",
+						Usage:    "tcp, udp, icmp or icmpv6",
+						Required: true,
+					},
+					&cli.IntFlag{
+						Name:  "port",
+						Usage: "destination port of tcp and udp traffic",
+					},
+					&cli.IntFlag{
+						Name:  "icmp-type",
+						Usage: "icmp type, defaults to an echo request",
+					},
+					&cli.IntFlag{
+						Name:  "icmp-code",
+						Usage: "icmp code",
+					},
+					&cli.IntFlag{
+						Name:  "ip-version",
+						Usage: "4 or 6, only icmpv6 defaults to 6",
+					},
+					&cli.StringFlag{
+						Name:  "inbound-rules",
+						Usage: "proposed inbound rules, the current rules are used if not set",
+					},
+					&cli.StringFlag{
+						Name:  "outbound-rules",
+						Usage: "proposed outbound rules, the current rules are used if not set",
+					},
+				},
+				Action: func(ctx context.Context, command *cli.Command) error {
+					id, err := getUUID(command, "security-group-id")
+					if err != nil {
+						return err
+					}
+					srcID, err := getUUID(command, "src-device-id")
+					if err != nil {
+						return err
+					}
+					dstID, err := getUUID(command, "dst-device-id")
+					if err != nil {
+						return err
+					}
+
+					simulation := client.ModelsSecurityGroupSimulation{
+						SourceDeviceId:      client.PtrString(srcID),
+						DestinationDeviceId: client.PtrString(dstID),
+						IpProtocol:          client.PtrString(command.String("protocol")),
+						Update:              &client.ModelsUpdateSecurityGroup{},
+					}
+					if command.IsSet("port") {
+						simulation.Port = client.PtrInt32(int32(command.Int("port")))
+					}
+					if command.IsSet("icmp-type") {
+						simulation.IcmpType = client.PtrInt32(int32(command.Int("icmp-type")))
+					}
+					if command.IsSet("icmp-code") {
+						simulation.IcmpCode = client.PtrInt32(int32(command.Int("icmp-code")))
+					}
+					if command.IsSet("ip-version") {
+						simulation.IpVersion = client.PtrInt32(int32(command.Int("ip-version")))
+					}
+					if command.IsSet("inbound-rules") {
+						rules, err := jsonStringToSecurityRules(command.String("inbound-rules"))
+						if err != nil {
+							return fmt.Errorf("failed to convert inbound rules string to security rules: %w", err)
+						}
+						simulation.Update.InboundRules = rules
+					}
+					if command.IsSet("outbound-rules") {
+						rules, err := jsonStringToSecurityRules(command.String("outbound-rules"))
+						if err != nil {
+							return fmt.Errorf("failed to convert outbound rules string to security rules: %w", err)
+						}
+						simulation.Update.OutboundRules = rules
+					}
+
+					err = checkICMPRules(simulation.Update.InboundRules, simulation.Update.OutboundRules)
+					if err != nil {
+						return fmt.Errorf("test security group failed: %w", err)
+					}
+
+					return testSecurityGroup(ctx, command, id, simulation)
+				},
+			},
 			{
 				Name:  "update",
 				Usage: "update a security group",
@@ -211,6 +330,82 @@ func deleteSecurityGroup(ctx context.Context, command *cli.Command, encodeOut, s
 	return nil
 }
 
+// testSecurityGroup shows whether the simulated traffic is allowed and which rules decided it.
+func testSecurityGroup(ctx context.Context, command *cli.Command, secGroupID string, simulation client.ModelsSecurityGroupSimulation) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.SecurityGroupApi.
+		SimulateSecurityGroup(ctx, secGroupID).
+		Simulation(simulation).
+		Execute())
+
+	verdict := func(v client.ModelsSecurityRuleVerdict) string {
+		result := "deny"
+		if v.GetAllowed() {
+			result = "allow"
+		}
+		if v.HasRule() {
+			if len(v.SecurityGroupIds) > 1 {
+				// the rule index is only meaningful together with the group of the rule
+				return fmt.Sprintf("%s (rule %d of %s)", result, v.GetRule(), v.GetSecurityGroupId())
+			}
+			return fmt.Sprintf("%s (rule %d)", result, v.GetRule())
+		}
+		return fmt.Sprintf("%s (%s)", result, v.GetReason())
+	}
+	var fields []TableField
+	fields = append(fields, TableField{Header: "ALLOWED", Formatter: func(item interface{}) string {
+		result := item.(*client.ModelsSecurityGroupSimulationResult)
+		return fmt.Sprintf("%v", result.GetAllowed())
+	}})
+	fields = append(fields, TableField{Header: "OUTBOUND", Formatter: func(item interface{}) string {
+		result := item.(*client.ModelsSecurityGroupSimulationResult)
+		return verdict(result.GetOutbound())
+	}})
+	fields = append(fields, TableField{Header: "INBOUND", Formatter: func(item interface{}) string {
+		result := item.(*client.ModelsSecurityGroupSimulationResult)
+		return verdict(result.GetInbound())
+	}})
+	show(command, fields, res)
+	return nil
+}
+
+// securityRuleStatsRow is a rule counter as shown in the table output of the stats command
+type securityRuleStatsRow struct {
+	Direction string
+	Rule      int32
+	Packets   int64
+	Bytes     int64
+}
+
+// securityGroupStats shows the rule counters of a security group, the table output lists one rule per line.
+func securityGroupStats(ctx context.Context, command *cli.Command, secGroupID string) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.SecurityGroupApi.
+		GetSecurityGroupStats(ctx, secGroupID).
+		Execute())
+
+	output := command.String("output")
+	if output != encodeColumn && output != encodeNoHeader {
+		show(command, nil, res)
+		return nil
+	}
+
+	var rows []securityRuleStatsRow
+	for _, stats := range res.InboundRules {
+		rows = append(rows, securityRuleStatsRow{Direction: "inbound", Rule: stats.GetRule(), Packets: stats.GetPackets(), Bytes: stats.GetBytes()})
+	}
+	for _, stats := range res.OutboundRules {
+		rows = append(rows, securityRuleStatsRow{Direction: "outbound", Rule: stats.GetRule(), Packets: stats.GetPackets(), Bytes: stats.GetBytes()})
+	}
+	var fields []TableField
+	fields = append(fields, TableField{Header: "DIRECTION", Field: "Direction"})
+	fields = append(fields, TableField{Header: "RULE", Field: "Rule"})
+	fields = append(fields, TableField{Header: "PACKETS", Field: "Packets"})
+	fields = append(fields, TableField{Header: "BYTES", Field: "Bytes"})
+	show(command, fields, rows)
+	return nil
+}
+
 func jsonStringToSecurityRules(jsonString string) ([]client.ModelsSecurityRule, error) {
 	var rules []client.ModelsSecurityRule
 	err := json.Unmarshal([]byte(jsonString), &rules)
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/urfave/cli/v3"
+	"strings"
+)
+
+func createServiceAccountCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "service-account",
+		Usage: "Commands relating to service accounts",
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List service accounts",
+				Action: func(ctx context.Context, command *cli.Command) error {
+					return listServiceAccounts(ctx, command)
+				},
+			},
+			{
+				Name:  "create",
+				Usage: "Create a service account",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "name",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "organization-id",
+						Required: false,
+					},
+					&cli.StringFlag{
+						Name:     "description",
+						Required: false,
+					},
+					&cli.StringSliceFlag{
+						Name:        "role",
+						Usage:       "Role of the service account in the organization, can be repeated",
+						Required:    false,
+						DefaultText: "member",
+						Value:       []string{"member"},
+					},
+				},
+				Action: func(ctx context.Context, command *cli.Command) error {
+					organizationId, err := getUUID(command, "organization-id")
+					if err != nil {
+						return err
+					}
+					return createServiceAccount(ctx, command, client.ModelsAddServiceAccount{
+						OrganizationId: client.PtrOptionalString(organizationId),
+						Name:           client.PtrString(command.String("name")),
+						Description:    client.PtrOptionalString(command.String("description")),
+						Roles:          command.StringSlice("role"),
+					})
+				},
+			},
+			{
+				Name:  "delete",
+				Usage: "Delete a service account and revoke its tokens",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "service-account-id",
+						Required: true,
+					},
+				},
+				Action: func(ctx context.Context, command *cli.Command) error {
+					id, err := getUUID(command, "service-account-id")
+					if err != nil {
+						return err
+					}
+					return deleteServiceAccount(ctx, command, id)
+				},
+			},
+		},
+	}
+}
+
+func serviceAccountTableFields() []TableField {
+	var fields []TableField
+	fields = append(fields, TableField{Header: "SERVICE ACCOUNT ID", Field: "Id"})
+	fields = append(fields, TableField{Header: "NAME", Field: "Name"})
+	fields = append(fields, TableField{Header: "ORGANIZATION ID", Field: "OrganizationId"})
+	fields = append(fields, TableField{Header: "ROLES", Formatter: func(item interface{}) string {
+		serviceAccount := item.(client.ModelsServiceAccount)
+		return strings.Join(serviceAccount.Roles, ", ")
+	}})
+	fields = append(fields, TableField{Header: "DESCRIPTION", Field: "Description"})
+	return fields
+}
+
+func listServiceAccounts(ctx context.Context, command *cli.Command) error {
+	c := createClient(ctx, command)
+	rows := apiResponse(c.ServiceAccountApi.
+		ListServiceAccounts(ctx).
+		Execute())
+	show(command, serviceAccountTableFields(), rows)
+	return nil
+}
+
+func createServiceAccount(ctx context.Context, command *cli.Command, serviceAccount client.ModelsAddServiceAccount) error {
+	c := createClient(ctx, command)
+	if serviceAccount.GetOrganizationId() == "" {
+		serviceAccount.OrganizationId = client.PtrString(getDefaultOrgId(ctx, c))
+	}
+	res := apiResponse(c.ServiceAccountApi.
+		CreateServiceAccount(ctx).
+		ServiceAccount(serviceAccount).
+		Execute())
+	show(command, serviceAccountTableFields(), res)
+	return nil
+}
+
+func deleteServiceAccount(ctx context.Context, command *cli.Command, id string) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.ServiceAccountApi.
+		DeleteServiceAccount(ctx, id).
+		Execute())
+	show(command, serviceAccountTableFields(), res)
+	showSuccessfully(command, "deleted")
+	return nil
+}
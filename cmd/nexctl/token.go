@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/urfave/cli/v3"
+	"strings"
+)
+
+func createTokenCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "token",
+		Usage: "Commands relating to api tokens",
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List api tokens",
+				Action: func(ctx context.Context, command *cli.Command) error {
+					return listApiTokens(ctx, command)
+				},
+			},
+			{
+				Name:  "create",
+				Usage: "Create an api token, the token is only displayed once",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:     "scope",
+						Usage:    "Scope of the token like read:devices or write:organizations, can be repeated",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "service-account-id",
+						Usage:    "Create the token for a service account instead of the current user",
+						Required: false,
+					},
+					&cli.StringFlag{
+						Name:     "description",
+						Required: false,
+					},
+					&cli.DurationFlag{
+						Name:     "expiration",
+						Required: false,
+					},
+				},
+				Action: func(ctx context.Context, command *cli.Command) error {
+					serviceAccountId, err := getUUID(command, "service-account-id")
+					if err != nil {
+						return err
+					}
+					return createApiToken(ctx, command, client.ModelsAddApiToken{
+						ServiceAccountId: client.PtrOptionalString(serviceAccountId),
+						Description:      client.PtrOptionalString(command.String("description")),
+						ExpiresAt:        client.PtrOptionalString(getExpiration(command, "expiration")),
+						Scopes:           command.StringSlice("scope"),
+					})
+				},
+			},
+			{
+				Name:  "revoke",
+				Usage: "Revoke an api token",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "token-id",
+						Required: true,
+					},
+				},
+				Action: func(ctx context.Context, command *cli.Command) error {
+					id, err := getUUID(command, "token-id")
+					if err != nil {
+						return err
+					}
+					return revokeApiToken(ctx, command, id)
+				},
+			},
+		},
+	}
+}
+
+func apiTokenTableFields(command *cli.Command) []TableField {
+	var fields []TableField
+	fields = append(fields, TableField{Header: "TOKEN ID", Field: "Id"})
+	fields = append(fields, TableField{Header: "SERVICE ACCOUNT ID", Field: "ServiceAccountId"})
+	fields = append(fields, TableField{Header: "SCOPES", Formatter: func(item interface{}) string {
+		token := item.(client.ModelsApiToken)
+		return strings.Join(token.Scopes, ", ")
+	}})
+	fields = append(fields, TableField{Header: "EXPIRES AT", Field: "ExpiresAt"})
+	fields = append(fields, TableField{Header: "DESCRIPTION", Field: "Description"})
+	if command.Name == "create" {
+		fields = append(fields, TableField{Header: "ACCESS TOKEN", Field: "AccessToken"})
+	}
+	return fields
+}
+
+func listApiTokens(ctx context.Context, command *cli.Command) error {
+	c := createClient(ctx, command)
+	rows := apiResponse(c.TokenApi.
+		ListApiTokens(ctx).
+		Execute())
+	show(command, apiTokenTableFields(command), rows)
+	return nil
+}
+
+func createApiToken(ctx context.Context, command *cli.Command, token client.ModelsAddApiToken) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.TokenApi.
+		CreateApiToken(ctx).
+		ApiToken(token).
+		Execute())
+	show(command, apiTokenTableFields(command), res)
+	return nil
+}
+
+func revokeApiToken(ctx context.Context, command *cli.Command, id string) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.TokenApi.
+		DeleteApiToken(ctx, id).
+		Execute())
+	show(command, apiTokenTableFields(command), res)
+	showSuccessfully(command, "revoked")
+	return nil
+}
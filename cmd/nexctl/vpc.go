@@ -97,6 +97,11 @@ func createVpcCommand() *cli.Command {
 				Usage:    "Commands relating to device metadata across the vpc",
 				Commands: vpcMetadataSubcommands,
 			},
+			{
+				Name:     "peering",
+				Usage:    "Commands relating to the peerings between vpcs",
+				Commands: vpcPeeringSubcommands,
+			},
 		},
 	}
 }
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/urfave/cli/v3"
+)
+
+var vpcPeeringSubcommands []*cli.Command
+
+func init() {
+	vpcPeeringSubcommands = []*cli.Command{
+		{
+			Name:  "list",
+			Usage: "List the peerings of the vpcs",
+			Action: func(ctx context.Context, command *cli.Command) error {
+				return listVPCPeerings(ctx, command)
+			},
+		},
+		{
+			Name:  "create",
+			Usage: "Request a peering with a vpc, which may belong to another organization",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "vpc-id",
+					Usage:    "VPC requesting the peering",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     "peer-vpc-id",
+					Usage:    "VPC to peer with",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     "description",
+					Required: false,
+				},
+			},
+			Action: func(ctx context.Context, command *cli.Command) error {
+				vpcID, err := getUUID(command, "vpc-id")
+				if err != nil {
+					return err
+				}
+				peerVpcID, err := getUUID(command, "peer-vpc-id")
+				if err != nil {
+					return err
+				}
+				return createVPCPeering(ctx, command, client.ModelsAddVPCPeering{
+					VpcId:       client.PtrString(vpcID),
+					PeerVpcId:   client.PtrString(peerVpcID),
+					Description: client.PtrOptionalString(command.String("description")),
+				})
+			},
+		},
+		{
+			Name:  "accept",
+			Usage: "Accept a pending peering requested with a vpc",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "peering-id",
+					Required: true,
+				},
+			},
+			Action: func(ctx context.Context, command *cli.Command) error {
+				id, err := getUUID(command, "peering-id")
+				if err != nil {
+					return err
+				}
+				return acceptVPCPeering(ctx, command, id)
+			},
+		},
+		{
+			Name:  "delete",
+			Usage: "Delete a vpc peering",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "peering-id",
+					Required: true,
+				},
+			},
+			Action: func(ctx context.Context, command *cli.Command) error {
+				id, err := getUUID(command, "peering-id")
+				if err != nil {
+					return err
+				}
+				return deleteVPCPeering(ctx, command, id)
+			},
+		},
+	}
+}
+
+func vpcPeeringTableFields() []TableField {
+	var fields []TableField
+	fields = append(fields, TableField{Header: "PEERING ID", Field: "Id"})
+	fields = append(fields, TableField{Header: "REQUESTER VPC ID", Field: "RequesterVpcId"})
+	fields = append(fields, TableField{Header: "ACCEPTER VPC ID", Field: "AccepterVpcId"})
+	fields = append(fields, TableField{Header: "STATUS", Field: "Status"})
+	fields = append(fields, TableField{Header: "DESCRIPTION", Field: "Description"})
+	return fields
+}
+
+func listVPCPeerings(ctx context.Context, command *cli.Command) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.VPCApi.
+		ListVPCPeerings(ctx).
+		Execute())
+	show(command, vpcPeeringTableFields(), res)
+	return nil
+}
+
+func createVPCPeering(ctx context.Context, command *cli.Command, resource client.ModelsAddVPCPeering) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.VPCApi.
+		CreateVPCPeering(ctx).
+		VPCPeering(resource).
+		Execute())
+	show(command, vpcPeeringTableFields(), res)
+	return nil
+}
+
+func acceptVPCPeering(ctx context.Context, command *cli.Command, id string) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.VPCApi.
+		AcceptVPCPeering(ctx, id).
+		Execute())
+	show(command, vpcPeeringTableFields(), res)
+	showSuccessfully(command, "accepted")
+	return nil
+}
+
+func deleteVPCPeering(ctx context.Context, command *cli.Command, id string) error {
+	c := createClient(ctx, command)
+	res := apiResponse(c.VPCApi.
+		DeleteVPCPeering(ctx, id).
+		Execute())
+	show(command, vpcPeeringTableFields(), res)
+	showSuccessfully(command, "deleted")
+	return nil
+}
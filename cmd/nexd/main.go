@@ -10,6 +10,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -202,18 +203,22 @@ func nexdRun(ctx context.Context, command *cli.Command, logger *zap.Logger, logL
 		Relay:                   relayNode,
 		RelayDerp:               relayDerpNode,
 		RelayOnly:               command.Bool("relay-only"),
+		MagicDns:                command.Bool("magic-dns"),
+		MagicDnsUpstreams:       command.StringSlice("magic-dns-upstream"),
+		MetricsListen:           command.String("metrics-listen"),
 		NetworkRouter:           command.Bool("network-router"),
 		NetworkRouterDisableNAT: command.Bool("disable-nat"),
 		ExitNodeClientEnabled:   command.Bool("exit-node-client"),
 		ExitNodeOriginEnabled:   command.Bool("exit-node"),
 		InsecureSkipTlsVerify:   command.Bool("insecure-skip-tls-verify"),
+		GatewayListen:           command.String("gateway-listen"),
 		Version:                 Version,
 		UserspaceMode:           userspaceMode,
 		StateStore:              stateStore,
 		StateDir:                stateDir,
 		Context:                 ctx,
 		VpcId:                   parseUUIDFlag(command, "vpc-id"),
-		SecurityGroupId:         parseUUIDFlag(command, "security-group-id"),
+		SecurityGroupIds:        parseUUIDSliceFlag(command, "security-group-id"),
 	}
 
 	if relayDerpNode {
@@ -273,6 +278,21 @@ func parseUUIDFlag(command *cli.Command, flagName string) string {
 	return uuid.String()
 }
 
+func parseUUIDSliceFlag(command *cli.Command, flagName string) []string {
+	if !command.IsSet(flagName) {
+		return nil
+	}
+	var ids []string
+	for _, value := range command.StringSlice(flagName) {
+		uuid, err := uuid.Parse(value)
+		if err != nil {
+			log.Fatalf("invalid flag --%s: %s", flagName, err)
+		}
+		ids = append(ids, uuid.String())
+	}
+	return ids
+}
+
 var additionalPlatformFlags []cli.Flag = nil
 
 func main() {
@@ -330,6 +350,12 @@ func main() {
 						Usage:    "Forward connections from a locally accessible network made to [port] on this proxy instance to port [destination_port] at [destination_ip] via the Nexodus network using a `value` in the form: protocol:port:destination_ip:destination_port. All fields are required.",
 						Required: false,
 					},
+					&cli.StringFlag{
+						Name:     "gateway-listen",
+						Usage:    "Serve a SOCKS5 and HTTP CONNECT proxy reaching any tunnel IP or peer hostname via the Nexodus network at this `address`, in form \":port\" to listen on 127.0.0.1 or \"ip:port\". The clients are not authenticated, only listen on other addresses if the network is trusted. Disabled when empty",
+						Sources:  cli.EnvVars("NEXD_GATEWAY_LISTEN"),
+						Required: false,
+					},
 				},
 			},
 			{
@@ -341,17 +367,20 @@ func main() {
 							return fmt.Errorf("exit-node support is currently only supported for Linux operating systems")
 						}
 						advertiseCidrs := command.StringSlice("advertise-cidr")
-						// Check if "0.0.0.0/0" already exists in advertise-cidr
-						found := false
-						for _, prefix := range advertiseCidrs {
-							if prefix == "0.0.0.0/0" {
-								found = true
-								break
+						// the IPv6 default route is only advertised when this device can forward IPv6 traffic
+						defaultRoutes := []string{"0.0.0.0/0"}
+						if nexodus.DefaultRouteIPv6Exists() {
+							defaultRoutes = append(defaultRoutes, "::/0")
+						}
+						// Add the default routes that are not already in advertise-cidr
+						updated := false
+						for _, defaultRoute := range defaultRoutes {
+							if !slices.Contains(advertiseCidrs, defaultRoute) {
+								advertiseCidrs = append(advertiseCidrs, defaultRoute)
+								updated = true
 							}
 						}
-						// If not found, add it to advertise-cidr
-						if !found {
-							advertiseCidrs = append(advertiseCidrs, "0.0.0.0/0")
+						if updated {
 							err := command.Set("advertise-cidr", strings.Join(advertiseCidrs, ","))
 							if err != nil {
 								return fmt.Errorf("failed to set advertise-cidr: %w", err)
@@ -631,6 +660,31 @@ func main() {
 				Category:   agentOptions,
 				Persistent: true,
 			},
+			&cli.BoolFlag{
+				Name:       "magic-dns",
+				Usage:      "Serve DNS records for the devices in the VPC as <hostname>.<vpc-id>.nexodus.internal on the tunnel IP",
+				Value:      false,
+				Sources:    cli.EnvVars("NEXD_MAGIC_DNS"),
+				Required:   false,
+				Category:   agentOptions,
+				Persistent: true,
+			},
+			&cli.StringSliceFlag{
+				Name:       "magic-dns-upstream",
+				Usage:      "Upstream DNS `server` used to resolve names outside of nexodus.internal, defaults to the servers in /etc/resolv.conf",
+				Sources:    cli.EnvVars("NEXD_MAGIC_DNS_UPSTREAM"),
+				Required:   false,
+				Category:   agentOptions,
+				Persistent: true,
+			},
+			&cli.StringFlag{
+				Name:       "metrics-listen",
+				Usage:      "Serve Prometheus metrics about the peer connections on /metrics at this `address`, in form \":port\" to listen on 127.0.0.1 or \"ip:port\". The clients are not authenticated, only listen on other addresses if the network is trusted. Disabled when empty",
+				Sources:    cli.EnvVars("NEXD_METRICS_LISTEN"),
+				Required:   false,
+				Category:   agentOptions,
+				Persistent: true,
+			},
 			&cli.StringFlag{
 				Name:       "username",
 				Value:      "",
@@ -699,9 +753,9 @@ func main() {
 				Required:   false,
 				Persistent: true,
 			},
-			&cli.StringFlag{
+			&cli.StringSliceFlag{
 				Name:       "security-group-id",
-				Usage:      "Optional security group ID to use when registering used to secure this device",
+				Usage:      "Optional security group ID to use when registering used to secure this device, can be repeated to add the device to several security groups",
 				Required:   false,
 				Sources:    cli.EnvVars("NEXAPI_SECURITY_GROUP_ID"),
 				Persistent: true,
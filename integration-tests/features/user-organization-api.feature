@@ -187,4 +187,55 @@ Feature: Invitations API
           "roles": ["member"],
           "user": ${russel_user}
       }]
-      """
\ No newline at end of file
+      """
+    # Brent changes the roles of Russel without inviting him again.
+    When I PATCH path "/api/organizations/${brent_organization.id}/users/${russel_user.id}" with json body:
+      """
+      {
+        "roles": ["viewer", "device-operator"]
+      }
+      """
+    Then the response code should be 200
+    And the response should match json:
+      """
+      {
+          "organization_id": "${brent_organization.id}",
+          "user_id": "${russel_user.id}",
+          "roles": ["viewer", "device-operator"],
+          "user": ${russel_user}
+      }
+      """
+
+    When I PATCH path "/api/organizations/${brent_organization.id}/users/${russel_user.id}" with json body:
+      """
+      {
+        "roles": ["admin"]
+      }
+      """
+    Then the response code should be 400
+
+    # The org keeps at least one owner.
+    When I PATCH path "/api/organizations/${brent_organization.id}/users/${brent_user.id}" with json body:
+      """
+      {
+        "roles": ["member"]
+      }
+      """
+    Then the response code should be 400
+    And the response should match json:
+      """
+      {
+        "error": "the organization must keep at least one owner",
+        "field": "roles"
+      }
+      """
+
+    # Russel can't change the roles of the org members.
+    Given I am logged in as "Russel"
+    When I PATCH path "/api/organizations/${brent_organization.id}/users/${russel_user.id}" with json body:
+      """
+      {
+        "roles": ["owner"]
+      }
+      """
+    Then the response code should be 404
@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 type PingPeersResponse struct {
 	RelayPresent  bool                       `json:"relay-present"`
 	RelayRequired bool                       `json:"relay-required"`
@@ -13,3 +15,166 @@ type KeepaliveStatus struct {
 	Latency     string `json:""`
 	Method      string `json:"method"`
 }
+
+// Event types streamed by the NexdCtl.Subscribe method
+const (
+	EventPeerAdded             = "peer-added"
+	EventPeerRemoved           = "peer-removed"
+	EventPeeringMethodChanged  = "peering-method-changed"
+	EventPeerHealthy           = "peer-healthy"
+	EventPeerUnhealthy         = "peer-unhealthy"
+	EventSecurityGroupsApplied = "security-groups-applied"
+	EventStatusChanged         = "status-changed"
+)
+
+const (
+	// SubscribeMethod is the JSON-RPC method that switches a control socket connection to streaming events
+	SubscribeMethod = "NexdCtl.Subscribe"
+	// SubscribeFollow is the argument of SubscribeMethod to keep streaming new events after the recent ones
+	SubscribeFollow = "follow"
+	// SubscribeRecentEvents is the number of recent events sent to new subscribers
+	SubscribeRecentEvents = 100
+)
+
+// Event is a change in the state of nexd, sent as a line of JSON to the subscribers of the control socket
+type Event struct {
+	Time                  time.Time `json:"time"`
+	Type                  string    `json:"type"`
+	PublicKey             string    `json:"public-key,omitempty"`
+	Hostname              string    `json:"hostname,omitempty"`
+	DeviceId              string    `json:"device-id,omitempty"`
+	PeeringMethod         string    `json:"peering-method,omitempty"`
+	PreviousPeeringMethod string    `json:"previous-peering-method,omitempty"`
+	SecurityGroupIds      []string  `json:"security-group-ids,omitempty"`
+	Status                string    `json:"status,omitempty"`
+	Message               string    `json:"message,omitempty"`
+}
+
+// CtlServiceV1 is the JSON-RPC service of the typed nexd control methods. The methods take and return the
+// request and response structs below rather than preformatted strings, so that they can be consumed by scripts.
+const CtlServiceV1 = "NexdCtlV1"
+
+// Empty is the request of the control methods that take no arguments
+type Empty struct{}
+
+type StatusResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Version string `json:"version"`
+}
+
+type TunnelIPsResponse struct {
+	IPv4 string `json:"ipv4"`
+	IPv6 string `json:"ipv6"`
+}
+
+type Peer struct {
+	PublicKey       string    `json:"public-key"`
+	Hostname        string    `json:"hostname,omitempty"`
+	DeviceId        string    `json:"device-id,omitempty"`
+	Endpoint        string    `json:"endpoint"`
+	AllowedIPs      []string  `json:"allowed-ips"`
+	LatestHandshake time.Time `json:"latest-handshake"`
+	Tx              int64     `json:"tx"`
+	Rx              int64     `json:"rx"`
+	Healthy         bool      `json:"healthy"`
+	PeeringMethod   string    `json:"peering-method,omitempty"`
+}
+
+type PeersResponse struct {
+	RelayPresent  bool   `json:"relay-present"`
+	RelayRequired bool   `json:"relay-required"`
+	Peers         []Peer `json:"peers"`
+}
+
+type PingPeersRequest struct {
+	// Family is either v4 or v6
+	Family string `json:"family"`
+}
+
+type ProxyRule struct {
+	// Type is either ingress or egress
+	Type        string `json:"type"`
+	Protocol    string `json:"protocol"`
+	ListenPort  int    `json:"listen-port"`
+	Destination string `json:"destination"`
+	// Rule is the rule in the form passed to nexd --ingress and --egress
+	Rule string `json:"rule"`
+	// Host and PathPrefix are the requests routed to the destination of an http rule, TLS is set when the
+	// listener terminates TLS
+	Host       string `json:"host,omitempty"`
+	PathPrefix string `json:"path-prefix,omitempty"`
+	TLS        bool   `json:"tls,omitempty"`
+	// ProxyProtocol is v1 or v2 when the connections to the destination start with a PROXY protocol header
+	ProxyProtocol string `json:"proxy-protocol,omitempty"`
+	// LoadBalancing is the policy picking the destination of the listener's connections among its rules
+	LoadBalancing string `json:"load-balancing"`
+	// Healthy is cleared while the destination fails its health checks
+	Healthy bool `json:"healthy"`
+}
+
+type ProxyRulesResponse struct {
+	Rules []ProxyRule `json:"rules"`
+}
+
+type ProxyRuleRequest struct {
+	// Type is either ingress or egress
+	Type string `json:"type"`
+	// Rule is in the form protocol:port:destination_ip:destination_port
+	Rule string `json:"rule"`
+	// LoadBalancing optionally sets the policy of the listener the rule is added to: round-robin, least-conn,
+	// source-hash or failover
+	LoadBalancing string `json:"load-balancing,omitempty"`
+}
+
+type ExitNode struct {
+	PublicKey  string   `json:"public-key"`
+	Endpoint   string   `json:"endpoint"`
+	AllowedIPs []string `json:"allowed-ips"`
+	Hostname   string   `json:"hostname"`
+	DeviceId   string   `json:"device-id"`
+	// Healthy is set when the peering with the exit node is up
+	Healthy bool `json:"healthy"`
+	// Active is set on the exit node the default routes are sent to
+	Active bool `json:"active"`
+	// Selected is set on the exit node picked with nexctl nexd exit-node use
+	Selected bool `json:"selected"`
+	// Local is set when this device is the exit node
+	Local bool `json:"local"`
+}
+
+type ExitNodesResponse struct {
+	ExitNodes []ExitNode `json:"exit-nodes"`
+}
+
+type UseExitNodeRequest struct {
+	// Device is the hostname, device id, public key or tunnel ip of the exit node, empty to use the healthiest one
+	Device string `json:"device"`
+}
+
+type ExitNodeClientRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type DebugRequest struct {
+	Debug bool `json:"debug"`
+}
+
+type DebugResponse struct {
+	Debug bool `json:"debug"`
+}
+
+// CtlTokenFileName is the file in the nexd state directory that holds the CtlToken of the loopback control
+// listener. The file is only readable by the user nexd runs as, on windows by SYSTEM and the Administrators.
+const CtlTokenFileName = "ctl-token.json"
+
+// CtlToken tells nexctl where the loopback control listener is and the token to authenticate with
+type CtlToken struct {
+	Address string `json:"address"`
+	Token   string `json:"token"`
+}
+
+// CtlAuth is the first message sent on a loopback control connection, the JSON-RPC requests follow it
+type CtlAuth struct {
+	Token string `json:"token"`
+}
@@ -4,3 +4,5 @@ package api
 
 var UnixSocketPath = "/var/run/nexd.sock"
 var UnixSocketPathExpression = UnixSocketPath
+
+var CtlTokenPath = "/var/lib/nexd/" + CtlTokenFileName
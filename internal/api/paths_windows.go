@@ -4,3 +4,5 @@ package api
 
 var UnixSocketPath = "C://nexd/nexd.sock"
 var UnixSocketPathExpression = UnixSocketPath
+
+var CtlTokenPath = "C:/nexodus/" + CtlTokenFileName
@@ -68,10 +68,14 @@ type APIClient struct {
 
 	SecurityGroupApi *SecurityGroupApiService
 
+	ServiceAccountApi *ServiceAccountApiService
+
 	ServiceNetworkApi *ServiceNetworkApiService
 
 	SitesApi *SitesApiService
 
+	TokenApi *TokenApiService
+
 	UsersApi *UsersApiService
 
 	VPCApi *VPCApiService
@@ -102,8 +106,10 @@ func NewAPIClient(cfg *Configuration) *APIClient {
 	c.OrganizationsApi = (*OrganizationsApiService)(&c.common)
 	c.RegKeyApi = (*RegKeyApiService)(&c.common)
 	c.SecurityGroupApi = (*SecurityGroupApiService)(&c.common)
+	c.ServiceAccountApi = (*ServiceAccountApiService)(&c.common)
 	c.ServiceNetworkApi = (*ServiceNetworkApiService)(&c.common)
 	c.SitesApi = (*SitesApiService)(&c.common)
+	c.TokenApi = (*TokenApiService)(&c.common)
 	c.UsersApi = (*UsersApiService)(&c.common)
 	c.VPCApi = (*VPCApiService)(&c.common)
 
@@ -19,16 +19,19 @@ var _ MappedNullable = &ModelsAddDevice{}
 
 // ModelsAddDevice struct for ModelsAddDevice
 type ModelsAddDevice struct {
-	AdvertiseCidrs  []string         `json:"advertise_cidrs,omitempty"`
-	Endpoints       []ModelsEndpoint `json:"endpoints,omitempty"`
-	Hostname        *string          `json:"hostname,omitempty"`
-	Ipv4TunnelIps   []ModelsTunnelIP `json:"ipv4_tunnel_ips,omitempty"`
-	Os              *string          `json:"os,omitempty"`
-	PublicKey       *string          `json:"public_key,omitempty"`
-	Relay           *bool            `json:"relay,omitempty"`
-	SecurityGroupId *string          `json:"security_group_id,omitempty"`
-	SymmetricNat    *bool            `json:"symmetric_nat,omitempty"`
-	VpcId           *string          `json:"vpc_id,omitempty"`
+	AdvertiseCidrs []string         `json:"advertise_cidrs,omitempty"`
+	Endpoints      []ModelsEndpoint `json:"endpoints,omitempty"`
+	Hostname       *string          `json:"hostname,omitempty"`
+	Ipv4TunnelIps  []ModelsTunnelIP `json:"ipv4_tunnel_ips,omitempty"`
+	Os             *string          `json:"os,omitempty"`
+	PublicKey      *string          `json:"public_key,omitempty"`
+	Relay          *bool            `json:"relay,omitempty"`
+	// SecurityGroupId is only used when SecurityGroupIds is not set, it is kept for older clients.
+	SecurityGroupId *string `json:"security_group_id,omitempty"`
+	// SecurityGroupIds are the security groups of the device, the default security group of the vpc is used if none are set.
+	SecurityGroupIds []string `json:"security_group_ids,omitempty"`
+	SymmetricNat     *bool    `json:"symmetric_nat,omitempty"`
+	VpcId            *string  `json:"vpc_id,omitempty"`
 }
 
 // NewModelsAddDevice instantiates a new ModelsAddDevice object
@@ -304,6 +307,38 @@ func (o *ModelsAddDevice) SetSecurityGroupId(v string) {
 	o.SecurityGroupId = &v
 }
 
+// GetSecurityGroupIds returns the SecurityGroupIds field value if set, zero value otherwise.
+func (o *ModelsAddDevice) GetSecurityGroupIds() []string {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		var ret []string
+		return ret
+	}
+	return o.SecurityGroupIds
+}
+
+// GetSecurityGroupIdsOk returns a tuple with the SecurityGroupIds field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsAddDevice) GetSecurityGroupIdsOk() ([]string, bool) {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		return nil, false
+	}
+	return o.SecurityGroupIds, true
+}
+
+// HasSecurityGroupIds returns a boolean if a field has been set.
+func (o *ModelsAddDevice) HasSecurityGroupIds() bool {
+	if o != nil && !IsNil(o.SecurityGroupIds) {
+		return true
+	}
+
+	return false
+}
+
+// SetSecurityGroupIds gets a reference to the given []string and assigns it to the SecurityGroupIds field.
+func (o *ModelsAddDevice) SetSecurityGroupIds(v []string) {
+	o.SecurityGroupIds = v
+}
+
 // GetSymmetricNat returns the SymmetricNat field value if set, zero value otherwise.
 func (o *ModelsAddDevice) GetSymmetricNat() bool {
 	if o == nil || IsNil(o.SymmetricNat) {
@@ -402,6 +437,9 @@ func (o ModelsAddDevice) ToMap() (map[string]interface{}, error) {
 	if !IsNil(o.SecurityGroupId) {
 		toSerialize["security_group_id"] = o.SecurityGroupId
 	}
+	if !IsNil(o.SecurityGroupIds) {
+		toSerialize["security_group_ids"] = o.SecurityGroupIds
+	}
 	if !IsNil(o.SymmetricNat) {
 		toSerialize["symmetric_nat"] = o.SymmetricNat
 	}
@@ -22,22 +22,29 @@ type ModelsDevice struct {
 	AdvertiseCidrs []string `json:"advertise_cidrs,omitempty"`
 	AllowedIps     []string `json:"allowed_ips,omitempty"`
 	// the token nexd should use to reconcile device state.
-	BearerToken     *string          `json:"bearer_token,omitempty"`
-	Endpoints       []ModelsEndpoint `json:"endpoints,omitempty"`
-	Hostname        *string          `json:"hostname,omitempty"`
-	Id              *string          `json:"id,omitempty"`
-	Ipv4TunnelIps   []ModelsTunnelIP `json:"ipv4_tunnel_ips,omitempty"`
-	Ipv6TunnelIps   []ModelsTunnelIP `json:"ipv6_tunnel_ips,omitempty"`
-	Online          *bool            `json:"online,omitempty"`
-	OnlineAt        *string          `json:"online_at,omitempty"`
-	Os              *string          `json:"os,omitempty"`
-	OwnerId         *string          `json:"owner_id,omitempty"`
-	PublicKey       *string          `json:"public_key,omitempty"`
-	Relay           *bool            `json:"relay,omitempty"`
-	Revision        *int32           `json:"revision,omitempty"`
-	SecurityGroupId *string          `json:"security_group_id,omitempty"`
-	SymmetricNat    *bool            `json:"symmetric_nat,omitempty"`
-	VpcId           *string          `json:"vpc_id,omitempty"`
+	BearerToken *string          `json:"bearer_token,omitempty"`
+	Endpoints   []ModelsEndpoint `json:"endpoints,omitempty"`
+	// ExitNodePolicy selects the traffic this device sends through an exit node, all of it when not set.
+	ExitNodePolicy *ModelsExitNodePolicy `json:"exit_node_policy,omitempty"`
+	Hostname       *string               `json:"hostname,omitempty"`
+	Id             *string               `json:"id,omitempty"`
+	Ipv4TunnelIps  []ModelsTunnelIP      `json:"ipv4_tunnel_ips,omitempty"`
+	Ipv6TunnelIps  []ModelsTunnelIP      `json:"ipv6_tunnel_ips,omitempty"`
+	Online         *bool                 `json:"online,omitempty"`
+	OnlineAt       *string               `json:"online_at,omitempty"`
+	Os             *string               `json:"os,omitempty"`
+	OwnerId        *string               `json:"owner_id,omitempty"`
+	PublicKey      *string               `json:"public_key,omitempty"`
+	Relay          *bool                 `json:"relay,omitempty"`
+	Revision       *int32                `json:"revision,omitempty"`
+	// SecurityGroupId is the first of the SecurityGroupIds, it is kept for older clients.
+	SecurityGroupId *string `json:"security_group_id,omitempty"`
+	// SecurityGroupIds are the security groups of the device, the device is allowed the traffic allowed by any of them.
+	SecurityGroupIds []string `json:"security_group_ids,omitempty"`
+	SymmetricNat     *bool    `json:"symmetric_nat,omitempty"`
+	// TrafficLimits caps the traffic the device sends into the VPC, it is unlimited when not set.
+	TrafficLimits *ModelsTrafficLimits `json:"traffic_limits,omitempty"`
+	VpcId         *string              `json:"vpc_id,omitempty"`
 }
 
 // NewModelsDevice instantiates a new ModelsDevice object
@@ -185,6 +192,38 @@ func (o *ModelsDevice) SetEndpoints(v []ModelsEndpoint) {
 	o.Endpoints = v
 }
 
+// GetExitNodePolicy returns the ExitNodePolicy field value if set, zero value otherwise.
+func (o *ModelsDevice) GetExitNodePolicy() ModelsExitNodePolicy {
+	if o == nil || IsNil(o.ExitNodePolicy) {
+		var ret ModelsExitNodePolicy
+		return ret
+	}
+	return *o.ExitNodePolicy
+}
+
+// GetExitNodePolicyOk returns a tuple with the ExitNodePolicy field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsDevice) GetExitNodePolicyOk() (*ModelsExitNodePolicy, bool) {
+	if o == nil || IsNil(o.ExitNodePolicy) {
+		return nil, false
+	}
+	return o.ExitNodePolicy, true
+}
+
+// HasExitNodePolicy returns a boolean if a field has been set.
+func (o *ModelsDevice) HasExitNodePolicy() bool {
+	if o != nil && !IsNil(o.ExitNodePolicy) {
+		return true
+	}
+
+	return false
+}
+
+// SetExitNodePolicy gets a reference to the given ModelsExitNodePolicy and assigns it to the ExitNodePolicy field.
+func (o *ModelsDevice) SetExitNodePolicy(v ModelsExitNodePolicy) {
+	o.ExitNodePolicy = &v
+}
+
 // GetHostname returns the Hostname field value if set, zero value otherwise.
 func (o *ModelsDevice) GetHostname() string {
 	if o == nil || IsNil(o.Hostname) {
@@ -569,6 +608,38 @@ func (o *ModelsDevice) SetSecurityGroupId(v string) {
 	o.SecurityGroupId = &v
 }
 
+// GetSecurityGroupIds returns the SecurityGroupIds field value if set, zero value otherwise.
+func (o *ModelsDevice) GetSecurityGroupIds() []string {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		var ret []string
+		return ret
+	}
+	return o.SecurityGroupIds
+}
+
+// GetSecurityGroupIdsOk returns a tuple with the SecurityGroupIds field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsDevice) GetSecurityGroupIdsOk() ([]string, bool) {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		return nil, false
+	}
+	return o.SecurityGroupIds, true
+}
+
+// HasSecurityGroupIds returns a boolean if a field has been set.
+func (o *ModelsDevice) HasSecurityGroupIds() bool {
+	if o != nil && !IsNil(o.SecurityGroupIds) {
+		return true
+	}
+
+	return false
+}
+
+// SetSecurityGroupIds gets a reference to the given []string and assigns it to the SecurityGroupIds field.
+func (o *ModelsDevice) SetSecurityGroupIds(v []string) {
+	o.SecurityGroupIds = v
+}
+
 // GetSymmetricNat returns the SymmetricNat field value if set, zero value otherwise.
 func (o *ModelsDevice) GetSymmetricNat() bool {
 	if o == nil || IsNil(o.SymmetricNat) {
@@ -601,6 +672,38 @@ func (o *ModelsDevice) SetSymmetricNat(v bool) {
 	o.SymmetricNat = &v
 }
 
+// GetTrafficLimits returns the TrafficLimits field value if set, zero value otherwise.
+func (o *ModelsDevice) GetTrafficLimits() ModelsTrafficLimits {
+	if o == nil || IsNil(o.TrafficLimits) {
+		var ret ModelsTrafficLimits
+		return ret
+	}
+	return *o.TrafficLimits
+}
+
+// GetTrafficLimitsOk returns a tuple with the TrafficLimits field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsDevice) GetTrafficLimitsOk() (*ModelsTrafficLimits, bool) {
+	if o == nil || IsNil(o.TrafficLimits) {
+		return nil, false
+	}
+	return o.TrafficLimits, true
+}
+
+// HasTrafficLimits returns a boolean if a field has been set.
+func (o *ModelsDevice) HasTrafficLimits() bool {
+	if o != nil && !IsNil(o.TrafficLimits) {
+		return true
+	}
+
+	return false
+}
+
+// SetTrafficLimits gets a reference to the given ModelsTrafficLimits and assigns it to the TrafficLimits field.
+func (o *ModelsDevice) SetTrafficLimits(v ModelsTrafficLimits) {
+	o.TrafficLimits = &v
+}
+
 // GetVpcId returns the VpcId field value if set, zero value otherwise.
 func (o *ModelsDevice) GetVpcId() string {
 	if o == nil || IsNil(o.VpcId) {
@@ -655,6 +758,9 @@ func (o ModelsDevice) ToMap() (map[string]interface{}, error) {
 	if !IsNil(o.Endpoints) {
 		toSerialize["endpoints"] = o.Endpoints
 	}
+	if !IsNil(o.ExitNodePolicy) {
+		toSerialize["exit_node_policy"] = o.ExitNodePolicy
+	}
 	if !IsNil(o.Hostname) {
 		toSerialize["hostname"] = o.Hostname
 	}
@@ -691,9 +797,15 @@ func (o ModelsDevice) ToMap() (map[string]interface{}, error) {
 	if !IsNil(o.SecurityGroupId) {
 		toSerialize["security_group_id"] = o.SecurityGroupId
 	}
+	if !IsNil(o.SecurityGroupIds) {
+		toSerialize["security_group_ids"] = o.SecurityGroupIds
+	}
 	if !IsNil(o.SymmetricNat) {
 		toSerialize["symmetric_nat"] = o.SymmetricNat
 	}
+	if !IsNil(o.TrafficLimits) {
+		toSerialize["traffic_limits"] = o.TrafficLimits
+	}
 	if !IsNil(o.VpcId) {
 		toSerialize["vpc_id"] = o.VpcId
 	}
@@ -22,6 +22,8 @@ type ModelsOrganization struct {
 	Description *string `json:"description,omitempty"`
 	Id          *string `json:"id,omitempty"`
 	Name        *string `json:"name,omitempty"`
+	// Usage of the quotas, only reported when getting a single organization.
+	Usage *ModelsOrganizationUsage `json:"usage,omitempty"`
 }
 
 // NewModelsOrganization instantiates a new ModelsOrganization object
@@ -137,6 +139,38 @@ func (o *ModelsOrganization) SetName(v string) {
 	o.Name = &v
 }
 
+// GetUsage returns the Usage field value if set, zero value otherwise.
+func (o *ModelsOrganization) GetUsage() ModelsOrganizationUsage {
+	if o == nil || IsNil(o.Usage) {
+		var ret ModelsOrganizationUsage
+		return ret
+	}
+	return *o.Usage
+}
+
+// GetUsageOk returns a tuple with the Usage field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsOrganization) GetUsageOk() (*ModelsOrganizationUsage, bool) {
+	if o == nil || IsNil(o.Usage) {
+		return nil, false
+	}
+	return o.Usage, true
+}
+
+// HasUsage returns a boolean if a field has been set.
+func (o *ModelsOrganization) HasUsage() bool {
+	if o != nil && !IsNil(o.Usage) {
+		return true
+	}
+
+	return false
+}
+
+// SetUsage gets a reference to the given ModelsOrganizationUsage and assigns it to the Usage field.
+func (o *ModelsOrganization) SetUsage(v ModelsOrganizationUsage) {
+	o.Usage = &v
+}
+
 func (o ModelsOrganization) MarshalJSON() ([]byte, error) {
 	toSerialize, err := o.ToMap()
 	if err != nil {
@@ -156,6 +190,9 @@ func (o ModelsOrganization) ToMap() (map[string]interface{}, error) {
 	if !IsNil(o.Name) {
 		toSerialize["name"] = o.Name
 	}
+	if !IsNil(o.Usage) {
+		toSerialize["usage"] = o.Usage
+	}
 	return toSerialize, nil
 }
 
@@ -30,8 +30,10 @@ type ModelsRegKey struct {
 	Id        *string `json:"id,omitempty"`
 	// OwnerID is the ID of the user that created the registration key.
 	OwnerId *string `json:"owner_id,omitempty"`
-	// SecurityGroupId is the ID of the security group to assign to the device.
+	// SecurityGroupId is the first of the SecurityGroupIds, it is kept for older clients.
 	SecurityGroupId *string `json:"security_group_id,omitempty"`
+	// SecurityGroupIds are the IDs of the security groups to assign to the device.
+	SecurityGroupIds []string `json:"security_group_ids,omitempty"`
 	// ServiceNetworkID is the ID of the Service Network the device can join.
 	ServiceNetworkId *string `json:"service_network_id,omitempty"`
 	// Settings contains general settings for the device.
@@ -281,6 +283,38 @@ func (o *ModelsRegKey) SetSecurityGroupId(v string) {
 	o.SecurityGroupId = &v
 }
 
+// GetSecurityGroupIds returns the SecurityGroupIds field value if set, zero value otherwise.
+func (o *ModelsRegKey) GetSecurityGroupIds() []string {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		var ret []string
+		return ret
+	}
+	return o.SecurityGroupIds
+}
+
+// GetSecurityGroupIdsOk returns a tuple with the SecurityGroupIds field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsRegKey) GetSecurityGroupIdsOk() ([]string, bool) {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		return nil, false
+	}
+	return o.SecurityGroupIds, true
+}
+
+// HasSecurityGroupIds returns a boolean if a field has been set.
+func (o *ModelsRegKey) HasSecurityGroupIds() bool {
+	if o != nil && !IsNil(o.SecurityGroupIds) {
+		return true
+	}
+
+	return false
+}
+
+// SetSecurityGroupIds gets a reference to the given []string and assigns it to the SecurityGroupIds field.
+func (o *ModelsRegKey) SetSecurityGroupIds(v []string) {
+	o.SecurityGroupIds = v
+}
+
 // GetServiceNetworkId returns the ServiceNetworkId field value if set, zero value otherwise.
 func (o *ModelsRegKey) GetServiceNetworkId() string {
 	if o == nil || IsNil(o.ServiceNetworkId) {
@@ -408,6 +442,9 @@ func (o ModelsRegKey) ToMap() (map[string]interface{}, error) {
 	if !IsNil(o.SecurityGroupId) {
 		toSerialize["security_group_id"] = o.SecurityGroupId
 	}
+	if !IsNil(o.SecurityGroupIds) {
+		toSerialize["security_group_ids"] = o.SecurityGroupIds
+	}
 	if !IsNil(o.ServiceNetworkId) {
 		toSerialize["service_network_id"] = o.ServiceNetworkId
 	}
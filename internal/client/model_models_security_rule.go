@@ -19,10 +19,22 @@ var _ MappedNullable = &ModelsSecurityRule{}
 
 // ModelsSecurityRule struct for ModelsSecurityRule
 type ModelsSecurityRule struct {
-	FromPort   *int32   `json:"from_port,omitempty"`
+	// Action is either allow or deny, rules without an action allow the traffic.
+	Action   *string `json:"action,omitempty"`
+	FromPort *int32  `json:"from_port,omitempty"`
+	// IcmpCode optionally restricts an icmp rule with an icmp_type to a single ICMP code.
+	IcmpCode *int32 `json:"icmp_code,omitempty"`
+	// IcmpType optionally restricts an icmp rule to a single ICMP type.
+	IcmpType   *int32   `json:"icmp_type,omitempty"`
 	IpProtocol *string  `json:"ip_protocol,omitempty"`
 	IpRanges   []string `json:"ip_ranges,omitempty"`
-	ToPort     *int32   `json:"to_port,omitempty"`
+	// Priority orders the rules of a direction, rules with a lower priority are evaluated first.
+	Priority *int32 `json:"priority,omitempty"`
+	// SecurityGroupIds matches the tunnel IPs of the devices in the referenced security groups, in addition to the IpRanges.
+	SecurityGroupIds []string `json:"security_group_ids,omitempty"`
+	ToPort           *int32   `json:"to_port,omitempty"`
+	// VpcIds matches the tunnel IPs of the devices in the referenced VPCs, which are the VPC of the security group or VPCs actively peered with it.
+	VpcIds []string `json:"vpc_ids,omitempty"`
 }
 
 // NewModelsSecurityRule instantiates a new ModelsSecurityRule object
@@ -42,6 +54,38 @@ func NewModelsSecurityRuleWithDefaults() *ModelsSecurityRule {
 	return &this
 }
 
+// GetAction returns the Action field value if set, zero value otherwise.
+func (o *ModelsSecurityRule) GetAction() string {
+	if o == nil || IsNil(o.Action) {
+		var ret string
+		return ret
+	}
+	return *o.Action
+}
+
+// GetActionOk returns a tuple with the Action field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsSecurityRule) GetActionOk() (*string, bool) {
+	if o == nil || IsNil(o.Action) {
+		return nil, false
+	}
+	return o.Action, true
+}
+
+// HasAction returns a boolean if a field has been set.
+func (o *ModelsSecurityRule) HasAction() bool {
+	if o != nil && !IsNil(o.Action) {
+		return true
+	}
+
+	return false
+}
+
+// SetAction gets a reference to the given string and assigns it to the Action field.
+func (o *ModelsSecurityRule) SetAction(v string) {
+	o.Action = &v
+}
+
 // GetFromPort returns the FromPort field value if set, zero value otherwise.
 func (o *ModelsSecurityRule) GetFromPort() int32 {
 	if o == nil || IsNil(o.FromPort) {
@@ -74,6 +118,70 @@ func (o *ModelsSecurityRule) SetFromPort(v int32) {
 	o.FromPort = &v
 }
 
+// GetIcmpCode returns the IcmpCode field value if set, zero value otherwise.
+func (o *ModelsSecurityRule) GetIcmpCode() int32 {
+	if o == nil || IsNil(o.IcmpCode) {
+		var ret int32
+		return ret
+	}
+	return *o.IcmpCode
+}
+
+// GetIcmpCodeOk returns a tuple with the IcmpCode field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsSecurityRule) GetIcmpCodeOk() (*int32, bool) {
+	if o == nil || IsNil(o.IcmpCode) {
+		return nil, false
+	}
+	return o.IcmpCode, true
+}
+
+// HasIcmpCode returns a boolean if a field has been set.
+func (o *ModelsSecurityRule) HasIcmpCode() bool {
+	if o != nil && !IsNil(o.IcmpCode) {
+		return true
+	}
+
+	return false
+}
+
+// SetIcmpCode gets a reference to the given int32 and assigns it to the IcmpCode field.
+func (o *ModelsSecurityRule) SetIcmpCode(v int32) {
+	o.IcmpCode = &v
+}
+
+// GetIcmpType returns the IcmpType field value if set, zero value otherwise.
+func (o *ModelsSecurityRule) GetIcmpType() int32 {
+	if o == nil || IsNil(o.IcmpType) {
+		var ret int32
+		return ret
+	}
+	return *o.IcmpType
+}
+
+// GetIcmpTypeOk returns a tuple with the IcmpType field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsSecurityRule) GetIcmpTypeOk() (*int32, bool) {
+	if o == nil || IsNil(o.IcmpType) {
+		return nil, false
+	}
+	return o.IcmpType, true
+}
+
+// HasIcmpType returns a boolean if a field has been set.
+func (o *ModelsSecurityRule) HasIcmpType() bool {
+	if o != nil && !IsNil(o.IcmpType) {
+		return true
+	}
+
+	return false
+}
+
+// SetIcmpType gets a reference to the given int32 and assigns it to the IcmpType field.
+func (o *ModelsSecurityRule) SetIcmpType(v int32) {
+	o.IcmpType = &v
+}
+
 // GetIpProtocol returns the IpProtocol field value if set, zero value otherwise.
 func (o *ModelsSecurityRule) GetIpProtocol() string {
 	if o == nil || IsNil(o.IpProtocol) {
@@ -138,6 +246,70 @@ func (o *ModelsSecurityRule) SetIpRanges(v []string) {
 	o.IpRanges = v
 }
 
+// GetPriority returns the Priority field value if set, zero value otherwise.
+func (o *ModelsSecurityRule) GetPriority() int32 {
+	if o == nil || IsNil(o.Priority) {
+		var ret int32
+		return ret
+	}
+	return *o.Priority
+}
+
+// GetPriorityOk returns a tuple with the Priority field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsSecurityRule) GetPriorityOk() (*int32, bool) {
+	if o == nil || IsNil(o.Priority) {
+		return nil, false
+	}
+	return o.Priority, true
+}
+
+// HasPriority returns a boolean if a field has been set.
+func (o *ModelsSecurityRule) HasPriority() bool {
+	if o != nil && !IsNil(o.Priority) {
+		return true
+	}
+
+	return false
+}
+
+// SetPriority gets a reference to the given int32 and assigns it to the Priority field.
+func (o *ModelsSecurityRule) SetPriority(v int32) {
+	o.Priority = &v
+}
+
+// GetSecurityGroupIds returns the SecurityGroupIds field value if set, zero value otherwise.
+func (o *ModelsSecurityRule) GetSecurityGroupIds() []string {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		var ret []string
+		return ret
+	}
+	return o.SecurityGroupIds
+}
+
+// GetSecurityGroupIdsOk returns a tuple with the SecurityGroupIds field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsSecurityRule) GetSecurityGroupIdsOk() ([]string, bool) {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		return nil, false
+	}
+	return o.SecurityGroupIds, true
+}
+
+// HasSecurityGroupIds returns a boolean if a field has been set.
+func (o *ModelsSecurityRule) HasSecurityGroupIds() bool {
+	if o != nil && !IsNil(o.SecurityGroupIds) {
+		return true
+	}
+
+	return false
+}
+
+// SetSecurityGroupIds gets a reference to the given []string and assigns it to the SecurityGroupIds field.
+func (o *ModelsSecurityRule) SetSecurityGroupIds(v []string) {
+	o.SecurityGroupIds = v
+}
+
 // GetToPort returns the ToPort field value if set, zero value otherwise.
 func (o *ModelsSecurityRule) GetToPort() int32 {
 	if o == nil || IsNil(o.ToPort) {
@@ -170,6 +342,38 @@ func (o *ModelsSecurityRule) SetToPort(v int32) {
 	o.ToPort = &v
 }
 
+// GetVpcIds returns the VpcIds field value if set, zero value otherwise.
+func (o *ModelsSecurityRule) GetVpcIds() []string {
+	if o == nil || IsNil(o.VpcIds) {
+		var ret []string
+		return ret
+	}
+	return o.VpcIds
+}
+
+// GetVpcIdsOk returns a tuple with the VpcIds field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsSecurityRule) GetVpcIdsOk() ([]string, bool) {
+	if o == nil || IsNil(o.VpcIds) {
+		return nil, false
+	}
+	return o.VpcIds, true
+}
+
+// HasVpcIds returns a boolean if a field has been set.
+func (o *ModelsSecurityRule) HasVpcIds() bool {
+	if o != nil && !IsNil(o.VpcIds) {
+		return true
+	}
+
+	return false
+}
+
+// SetVpcIds gets a reference to the given []string and assigns it to the VpcIds field.
+func (o *ModelsSecurityRule) SetVpcIds(v []string) {
+	o.VpcIds = v
+}
+
 func (o ModelsSecurityRule) MarshalJSON() ([]byte, error) {
 	toSerialize, err := o.ToMap()
 	if err != nil {
@@ -180,18 +384,36 @@ func (o ModelsSecurityRule) MarshalJSON() ([]byte, error) {
 
 func (o ModelsSecurityRule) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
+	if !IsNil(o.Action) {
+		toSerialize["action"] = o.Action
+	}
 	if !IsNil(o.FromPort) {
 		toSerialize["from_port"] = o.FromPort
 	}
+	if !IsNil(o.IcmpCode) {
+		toSerialize["icmp_code"] = o.IcmpCode
+	}
+	if !IsNil(o.IcmpType) {
+		toSerialize["icmp_type"] = o.IcmpType
+	}
 	if !IsNil(o.IpProtocol) {
 		toSerialize["ip_protocol"] = o.IpProtocol
 	}
 	if !IsNil(o.IpRanges) {
 		toSerialize["ip_ranges"] = o.IpRanges
 	}
+	if !IsNil(o.Priority) {
+		toSerialize["priority"] = o.Priority
+	}
+	if !IsNil(o.SecurityGroupIds) {
+		toSerialize["security_group_ids"] = o.SecurityGroupIds
+	}
 	if !IsNil(o.ToPort) {
 		toSerialize["to_port"] = o.ToPort
 	}
+	if !IsNil(o.VpcIds) {
+		toSerialize["vpc_ids"] = o.VpcIds
+	}
 	return toSerialize, nil
 }
 
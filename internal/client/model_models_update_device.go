@@ -19,14 +19,21 @@ var _ MappedNullable = &ModelsUpdateDevice{}
 
 // ModelsUpdateDevice struct for ModelsUpdateDevice
 type ModelsUpdateDevice struct {
-	AdvertiseCidrs  []string         `json:"advertise_cidrs,omitempty"`
-	Endpoints       []ModelsEndpoint `json:"endpoints,omitempty"`
-	Hostname        *string          `json:"hostname,omitempty"`
-	Relay           *bool            `json:"relay,omitempty"`
-	Revision        *int32           `json:"revision,omitempty"`
-	SecurityGroupId *string          `json:"security_group_id,omitempty"`
-	SymmetricNat    *bool            `json:"symmetric_nat,omitempty"`
-	VpcId           *string          `json:"vpc_id,omitempty"`
+	AdvertiseCidrs []string         `json:"advertise_cidrs,omitempty"`
+	Endpoints      []ModelsEndpoint `json:"endpoints,omitempty"`
+	// ExitNodePolicy replaces the exit node policy of the device when set, an empty policy sends all the traffic through the exit node.
+	ExitNodePolicy *ModelsExitNodePolicy `json:"exit_node_policy,omitempty"`
+	Hostname       *string               `json:"hostname,omitempty"`
+	Relay          *bool                 `json:"relay,omitempty"`
+	Revision       *int32                `json:"revision,omitempty"`
+	// SecurityGroupId is only used when SecurityGroupIds is not set, it is kept for older clients.
+	SecurityGroupId *string `json:"security_group_id,omitempty"`
+	// SecurityGroupIds replaces the security groups of the device when set, an empty list removes the device from all security groups.
+	SecurityGroupIds []string `json:"security_group_ids,omitempty"`
+	SymmetricNat     *bool    `json:"symmetric_nat,omitempty"`
+	// TrafficLimits replaces the traffic limits of the device when set, empty limits remove them.
+	TrafficLimits *ModelsTrafficLimits `json:"traffic_limits,omitempty"`
+	VpcId         *string              `json:"vpc_id,omitempty"`
 }
 
 // NewModelsUpdateDevice instantiates a new ModelsUpdateDevice object
@@ -110,6 +117,38 @@ func (o *ModelsUpdateDevice) SetEndpoints(v []ModelsEndpoint) {
 	o.Endpoints = v
 }
 
+// GetExitNodePolicy returns the ExitNodePolicy field value if set, zero value otherwise.
+func (o *ModelsUpdateDevice) GetExitNodePolicy() ModelsExitNodePolicy {
+	if o == nil || IsNil(o.ExitNodePolicy) {
+		var ret ModelsExitNodePolicy
+		return ret
+	}
+	return *o.ExitNodePolicy
+}
+
+// GetExitNodePolicyOk returns a tuple with the ExitNodePolicy field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsUpdateDevice) GetExitNodePolicyOk() (*ModelsExitNodePolicy, bool) {
+	if o == nil || IsNil(o.ExitNodePolicy) {
+		return nil, false
+	}
+	return o.ExitNodePolicy, true
+}
+
+// HasExitNodePolicy returns a boolean if a field has been set.
+func (o *ModelsUpdateDevice) HasExitNodePolicy() bool {
+	if o != nil && !IsNil(o.ExitNodePolicy) {
+		return true
+	}
+
+	return false
+}
+
+// SetExitNodePolicy gets a reference to the given ModelsExitNodePolicy and assigns it to the ExitNodePolicy field.
+func (o *ModelsUpdateDevice) SetExitNodePolicy(v ModelsExitNodePolicy) {
+	o.ExitNodePolicy = &v
+}
+
 // GetHostname returns the Hostname field value if set, zero value otherwise.
 func (o *ModelsUpdateDevice) GetHostname() string {
 	if o == nil || IsNil(o.Hostname) {
@@ -238,6 +277,38 @@ func (o *ModelsUpdateDevice) SetSecurityGroupId(v string) {
 	o.SecurityGroupId = &v
 }
 
+// GetSecurityGroupIds returns the SecurityGroupIds field value if set, zero value otherwise.
+func (o *ModelsUpdateDevice) GetSecurityGroupIds() []string {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		var ret []string
+		return ret
+	}
+	return o.SecurityGroupIds
+}
+
+// GetSecurityGroupIdsOk returns a tuple with the SecurityGroupIds field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsUpdateDevice) GetSecurityGroupIdsOk() ([]string, bool) {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		return nil, false
+	}
+	return o.SecurityGroupIds, true
+}
+
+// HasSecurityGroupIds returns a boolean if a field has been set.
+func (o *ModelsUpdateDevice) HasSecurityGroupIds() bool {
+	if o != nil && !IsNil(o.SecurityGroupIds) {
+		return true
+	}
+
+	return false
+}
+
+// SetSecurityGroupIds gets a reference to the given []string and assigns it to the SecurityGroupIds field.
+func (o *ModelsUpdateDevice) SetSecurityGroupIds(v []string) {
+	o.SecurityGroupIds = v
+}
+
 // GetSymmetricNat returns the SymmetricNat field value if set, zero value otherwise.
 func (o *ModelsUpdateDevice) GetSymmetricNat() bool {
 	if o == nil || IsNil(o.SymmetricNat) {
@@ -270,6 +341,38 @@ func (o *ModelsUpdateDevice) SetSymmetricNat(v bool) {
 	o.SymmetricNat = &v
 }
 
+// GetTrafficLimits returns the TrafficLimits field value if set, zero value otherwise.
+func (o *ModelsUpdateDevice) GetTrafficLimits() ModelsTrafficLimits {
+	if o == nil || IsNil(o.TrafficLimits) {
+		var ret ModelsTrafficLimits
+		return ret
+	}
+	return *o.TrafficLimits
+}
+
+// GetTrafficLimitsOk returns a tuple with the TrafficLimits field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsUpdateDevice) GetTrafficLimitsOk() (*ModelsTrafficLimits, bool) {
+	if o == nil || IsNil(o.TrafficLimits) {
+		return nil, false
+	}
+	return o.TrafficLimits, true
+}
+
+// HasTrafficLimits returns a boolean if a field has been set.
+func (o *ModelsUpdateDevice) HasTrafficLimits() bool {
+	if o != nil && !IsNil(o.TrafficLimits) {
+		return true
+	}
+
+	return false
+}
+
+// SetTrafficLimits gets a reference to the given ModelsTrafficLimits and assigns it to the TrafficLimits field.
+func (o *ModelsUpdateDevice) SetTrafficLimits(v ModelsTrafficLimits) {
+	o.TrafficLimits = &v
+}
+
 // GetVpcId returns the VpcId field value if set, zero value otherwise.
 func (o *ModelsUpdateDevice) GetVpcId() string {
 	if o == nil || IsNil(o.VpcId) {
@@ -318,6 +421,9 @@ func (o ModelsUpdateDevice) ToMap() (map[string]interface{}, error) {
 	if !IsNil(o.Endpoints) {
 		toSerialize["endpoints"] = o.Endpoints
 	}
+	if !IsNil(o.ExitNodePolicy) {
+		toSerialize["exit_node_policy"] = o.ExitNodePolicy
+	}
 	if !IsNil(o.Hostname) {
 		toSerialize["hostname"] = o.Hostname
 	}
@@ -330,9 +436,15 @@ func (o ModelsUpdateDevice) ToMap() (map[string]interface{}, error) {
 	if !IsNil(o.SecurityGroupId) {
 		toSerialize["security_group_id"] = o.SecurityGroupId
 	}
+	if !IsNil(o.SecurityGroupIds) {
+		toSerialize["security_group_ids"] = o.SecurityGroupIds
+	}
 	if !IsNil(o.SymmetricNat) {
 		toSerialize["symmetric_nat"] = o.SymmetricNat
 	}
+	if !IsNil(o.TrafficLimits) {
+		toSerialize["traffic_limits"] = o.TrafficLimits
+	}
 	if !IsNil(o.VpcId) {
 		toSerialize["vpc_id"] = o.VpcId
 	}
@@ -23,8 +23,10 @@ type ModelsUpdateRegKey struct {
 	Description *string `json:"description,omitempty"`
 	// ExpiresAt is optional, if set the registration key is only valid until the ExpiresAt time.
 	ExpiresAt *string `json:"expires_at,omitempty"`
-	// SecurityGroupId is the ID of the security group to assign to the device.
+	// SecurityGroupId is only used when SecurityGroupIds is not set, it is kept for older clients.
 	SecurityGroupId *string `json:"security_group_id,omitempty"`
+	// SecurityGroupIds replaces the security groups to assign to the device when set.
+	SecurityGroupIds []string `json:"security_group_ids,omitempty"`
 	// Settings contains general settings for the device.
 	Settings map[string]interface{} `json:"settings,omitempty"`
 }
@@ -142,6 +144,38 @@ func (o *ModelsUpdateRegKey) SetSecurityGroupId(v string) {
 	o.SecurityGroupId = &v
 }
 
+// GetSecurityGroupIds returns the SecurityGroupIds field value if set, zero value otherwise.
+func (o *ModelsUpdateRegKey) GetSecurityGroupIds() []string {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		var ret []string
+		return ret
+	}
+	return o.SecurityGroupIds
+}
+
+// GetSecurityGroupIdsOk returns a tuple with the SecurityGroupIds field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ModelsUpdateRegKey) GetSecurityGroupIdsOk() ([]string, bool) {
+	if o == nil || IsNil(o.SecurityGroupIds) {
+		return nil, false
+	}
+	return o.SecurityGroupIds, true
+}
+
+// HasSecurityGroupIds returns a boolean if a field has been set.
+func (o *ModelsUpdateRegKey) HasSecurityGroupIds() bool {
+	if o != nil && !IsNil(o.SecurityGroupIds) {
+		return true
+	}
+
+	return false
+}
+
+// SetSecurityGroupIds gets a reference to the given []string and assigns it to the SecurityGroupIds field.
+func (o *ModelsUpdateRegKey) SetSecurityGroupIds(v []string) {
+	o.SecurityGroupIds = v
+}
+
 // GetSettings returns the Settings field value if set, zero value otherwise.
 func (o *ModelsUpdateRegKey) GetSettings() map[string]interface{} {
 	if o == nil || IsNil(o.Settings) {
@@ -193,6 +227,9 @@ func (o ModelsUpdateRegKey) ToMap() (map[string]interface{}, error) {
 	if !IsNil(o.SecurityGroupId) {
 		toSerialize["security_group_id"] = o.SecurityGroupId
 	}
+	if !IsNil(o.SecurityGroupIds) {
+		toSerialize["security_group_ids"] = o.SecurityGroupIds
+	}
 	if !IsNil(o.Settings) {
 		toSerialize["settings"] = o.Settings
 	}
@@ -16,6 +16,13 @@ import (
 	_ "github.com/nexodus-io/nexodus/internal/database/migration_20231211_0000"
 	_ "github.com/nexodus-io/nexodus/internal/database/migration_20240221_0000"
 	_ "github.com/nexodus-io/nexodus/internal/database/migration_20240227_0000"
+	_ "github.com/nexodus-io/nexodus/internal/database/migration_20240305_0000"
+	_ "github.com/nexodus-io/nexodus/internal/database/migration_20240312_0000"
+	_ "github.com/nexodus-io/nexodus/internal/database/migration_20240319_0000"
+	_ "github.com/nexodus-io/nexodus/internal/database/migration_20240326_0000"
+	_ "github.com/nexodus-io/nexodus/internal/database/migration_20240402_0000"
+	_ "github.com/nexodus-io/nexodus/internal/database/migration_20240409_0000"
+	_ "github.com/nexodus-io/nexodus/internal/database/migration_20240416_0000"
 	"sort"
 
 	"github.com/cenkalti/backoff/v4"
@@ -0,0 +1,47 @@
+package migration_20240305_0000
+
+import (
+	"github.com/nexodus-io/nexodus/internal/database/datatype"
+	. "github.com/nexodus-io/nexodus/internal/database/migrations"
+)
+
+type Device struct {
+	SecurityGroupIds datatype.StringArray `json:"security_group_ids" swaggertype:"array,string"`
+}
+type RegKey struct {
+	SecurityGroupIds datatype.StringArray `json:"security_group_ids" swaggertype:"array,string"`
+}
+
+func init() {
+	migrationId := "20240305-0000"
+	CreateMigrationFromActions(migrationId,
+		AddTableColumnsAction(&Device{}),
+		AddTableColumnsAction(&RegKey{}),
+		ExecActionIf(
+			`CREATE INDEX IF NOT EXISTS "idx_devices_security_group_ids" ON "devices" USING GIN ("security_group_ids")`,
+			`DROP INDEX IF EXISTS idx_devices_security_group_ids`,
+			NotOnSqlLite,
+		),
+		// devices and reg keys keep their single security group as the first of their security groups
+		ExecActionIf(
+			`UPDATE devices SET security_group_ids = ARRAY[security_group_id::text] WHERE security_group_id IS NOT NULL AND security_group_id <> '00000000-0000-0000-0000-000000000000'`,
+			``,
+			NotOnSqlLite,
+		),
+		ExecActionIf(
+			`UPDATE reg_keys SET security_group_ids = ARRAY[security_group_id::text] WHERE security_group_id IS NOT NULL AND security_group_id <> '00000000-0000-0000-0000-000000000000'`,
+			``,
+			NotOnSqlLite,
+		),
+		ExecActionIf(
+			`UPDATE devices SET security_group_ids = json_array(security_group_id) WHERE security_group_id IS NOT NULL AND security_group_id <> '00000000-0000-0000-0000-000000000000'`,
+			``,
+			OnSqlLite,
+		),
+		ExecActionIf(
+			`UPDATE reg_keys SET security_group_ids = json_array(security_group_id) WHERE security_group_id IS NOT NULL AND security_group_id <> '00000000-0000-0000-0000-000000000000'`,
+			``,
+			OnSqlLite,
+		),
+	)
+}
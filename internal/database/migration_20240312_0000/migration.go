@@ -0,0 +1,21 @@
+package migration_20240312_0000
+
+import (
+	. "github.com/nexodus-io/nexodus/internal/database/migrations"
+)
+
+type ExitNodePolicy struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+type Device struct {
+	ExitNodePolicy *ExitNodePolicy `gorm:"type:JSONB; serializer:json"`
+}
+
+func init() {
+	migrationId := "20240312-0000"
+	CreateMigrationFromActions(migrationId,
+		AddTableColumnsAction(&Device{}),
+	)
+}
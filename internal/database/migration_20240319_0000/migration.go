@@ -0,0 +1,21 @@
+package migration_20240319_0000
+
+import (
+	. "github.com/nexodus-io/nexodus/internal/database/migrations"
+)
+
+type TrafficLimits struct {
+	BitsPerSecond    uint64 `json:"bits_per_second,omitempty"`
+	PacketsPerSecond uint64 `json:"packets_per_second,omitempty"`
+}
+
+type Device struct {
+	TrafficLimits *TrafficLimits `gorm:"type:JSONB; serializer:json"`
+}
+
+func init() {
+	migrationId := "20240319-0000"
+	CreateMigrationFromActions(migrationId,
+		AddTableColumnsAction(&Device{}),
+	)
+}
@@ -0,0 +1,41 @@
+package migration_20240326_0000
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/nexodus-io/nexodus/internal/database/migrations"
+)
+
+type AuditEntry struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_audit_entries_chain"`
+	Sequence       int64     `gorm:"uniqueIndex:idx_audit_entries_chain"`
+	CreatedAt      time.Time
+	ActorKind      string
+	ActorID        uuid.UUID `gorm:"type:uuid"`
+	UserID         uuid.UUID `gorm:"type:uuid"`
+	SourceIP       string
+	Method         string
+	Path           string
+	Action         string
+	ResourceKind   string
+	ResourceID     string
+	Diff           []byte `gorm:"type:JSONB"`
+	PrevHash       string
+	Hash           string
+}
+
+type AuditHead struct {
+	OrganizationID uuid.UUID `gorm:"type:uuid;primary_key"`
+	Sequence       int64
+	Hash           string
+}
+
+func init() {
+	migrationId := "20240326-0000"
+	CreateMigrationFromActions(migrationId,
+		CreateTableAction(&AuditEntry{}),
+		CreateTableAction(&AuditHead{}),
+	)
+}
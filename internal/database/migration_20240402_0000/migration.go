@@ -0,0 +1,42 @@
+package migration_20240402_0000
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/database/datatype"
+	"github.com/nexodus-io/nexodus/internal/database/migration_20231031_0000"
+	. "github.com/nexodus-io/nexodus/internal/database/migrations"
+)
+
+type ServiceAccount struct {
+	migration_20231031_0000.Base
+	OrganizationID uuid.UUID `gorm:"type:uuid;index"`
+	UserID         uuid.UUID `gorm:"type:uuid;index"`
+	OwnerID        uuid.UUID `gorm:"type:uuid"`
+	Name           string
+	Description    string
+}
+
+type ApiToken struct {
+	migration_20231031_0000.Base
+	OwnerID          uuid.UUID  `gorm:"type:uuid;index"`
+	UserID           uuid.UUID  `gorm:"type:uuid"`
+	ServiceAccountID *uuid.UUID `gorm:"type:uuid;index"`
+	OrganizationID   *uuid.UUID `gorm:"type:uuid;index"`
+	Description      string
+	Scopes           datatype.StringArray
+	ExpiresAt        *time.Time
+}
+
+func init() {
+	migrationId := "20240402-0000"
+	CreateMigrationFromActions(migrationId,
+		CreateTableAction(&ServiceAccount{}),
+		CreateTableAction(&ApiToken{}),
+		ExecAction(
+			`CREATE UNIQUE INDEX IF NOT EXISTS "idx_service_accounts_name" ON "service_accounts" ("organization_id", "name") WHERE deleted_at IS NULL`,
+			`DROP INDEX IF EXISTS idx_service_accounts_name`,
+		),
+	)
+}
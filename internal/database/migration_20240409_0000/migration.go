@@ -0,0 +1,24 @@
+package migration_20240409_0000
+
+import (
+	. "github.com/nexodus-io/nexodus/internal/database/migrations"
+)
+
+type OrganizationQuotas struct {
+	Vpcs           *int64 `json:"vpcs,omitempty"`
+	Devices        *int64 `json:"devices,omitempty"`
+	RegKeys        *int64 `json:"reg_keys,omitempty"`
+	SecurityGroups *int64 `json:"security_groups,omitempty"`
+	MetadataKeys   *int64 `json:"metadata_keys,omitempty"`
+}
+
+type Organization struct {
+	Quotas *OrganizationQuotas `gorm:"type:JSONB; serializer:json"`
+}
+
+func init() {
+	migrationId := "20240409-0000"
+	CreateMigrationFromActions(migrationId,
+		AddTableColumnsAction(&Organization{}),
+	)
+}
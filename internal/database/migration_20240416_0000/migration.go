@@ -0,0 +1,29 @@
+package migration_20240416_0000
+
+import (
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/database/migration_20231031_0000"
+	. "github.com/nexodus-io/nexodus/internal/database/migrations"
+)
+
+type VPCPeering struct {
+	migration_20231031_0000.Base
+	RequesterVpcID          uuid.UUID `gorm:"type:uuid;index"`
+	RequesterOrganizationID uuid.UUID `gorm:"type:uuid;index"`
+	AccepterVpcID           uuid.UUID `gorm:"type:uuid;index"`
+	AccepterOrganizationID  uuid.UUID `gorm:"type:uuid;index"`
+	Status                  string
+	Description             string
+	UnpeeredRevision        uint64
+}
+
+func init() {
+	migrationId := "20240416-0000"
+	CreateMigrationFromActions(migrationId,
+		CreateTableAction(&VPCPeering{}),
+		ExecAction(
+			`CREATE UNIQUE INDEX IF NOT EXISTS "idx_vpc_peerings_vpcs" ON "vpc_peerings" ("requester_vpc_id", "accepter_vpc_id") WHERE deleted_at IS NULL`,
+			`DROP INDEX IF EXISTS idx_vpc_peerings_vpcs`,
+		),
+	)
+}
@@ -409,4 +409,9 @@ func NotOnSqlLite(db *gorm.DB) bool {
 	return db.Raw("SELECT sqlite_version()").Scan(&version).Error != nil
 }
 
+// OnSqlLite is the condition of the SQLite variant of the SQL of an action that is only run NotOnSqlLite
+func OnSqlLite(db *gorm.DB) bool {
+	return !NotOnSqlLite(db)
+}
+
 var List []*gormigrate.Migration = nil
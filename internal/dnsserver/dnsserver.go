@@ -13,6 +13,7 @@ import (
 )
 
 type Server struct {
+	mu       sync.Mutex
 	instance *caddy.Instance
 }
 
@@ -34,20 +35,23 @@ func Start(ctx context.Context, wg *sync.WaitGroup, config string) (*Server, err
 		return nil, err
 	}
 
+	server := &Server{
+		instance: instance,
+	}
 	go func() {
 		<-ctx.Done()
-		instance.ShutdownCallbacks()
+		server.stop()
 	}()
 	util.GoWithWaitGroup(wg, func() {
 		// Twiddle your thumbs
 		instance.Wait()
 	})
-	return &Server{
-		instance: instance,
-	}, nil
+	return server, nil
 }
 
 func (server *Server) Restart(config string) error {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 	i, err := server.instance.Restart(input(config))
 	if err != nil {
 		return err
@@ -56,7 +60,18 @@ func (server *Server) Restart(config string) error {
 	return nil
 }
 
+// stop shuts down the current instance, a Restart replaces the instance, so
+// stopping the one created by Start would leave the listeners running.
+func (server *Server) stop() {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.instance.ShutdownCallbacks()
+	_ = server.instance.Stop()
+}
+
 func (server *Server) Ports() (uppAddress net.Addr, tcpAddress net.Addr, err error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 	listeners := server.instance.Servers()
 	if len(listeners) == 0 {
 		err = errors.New("no listeners")
@@ -90,6 +90,66 @@ const docTemplate = `{
                 }
             }
         },
+        "/private/organizations/{id}/quotas": {
+            "put": {
+                "description": "Overrides the default quotas of an Organization, the quotas that are not set go back to the defaults",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Private"
+                ],
+                "summary": "Update the quotas of an Organization",
+                "operationId": "UpdateOrganizationQuotas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Organization Quotas",
+                        "name": "quotas",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationQuotas"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrganizationUsage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            }
+        },
         "/private/ready": {
             "post": {
                 "description": "Checks if the service is ready to accept requests",
@@ -119,6 +179,15 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "models.BaseError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "something bad"
+                }
+            }
+        },
         "models.InternalServerError": {
             "type": "object",
             "properties": {
@@ -131,6 +200,77 @@ const docTemplate = `{
                 }
             }
         },
+        "models.OrganizationQuotas": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 1000
+                },
+                "metadata_keys": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 10000
+                },
+                "reg_keys": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 1000
+                },
+                "security_groups": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 500
+                },
+                "vpcs": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 10
+                }
+            }
+        },
+        "models.OrganizationUsage": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "$ref": "#/definitions/models.QuotaUsage"
+                },
+                "metadata_keys": {
+                    "description": "MetadataKeys counts the metadata keys of all the devices of the organization.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.QuotaUsage"
+                        }
+                    ]
+                },
+                "reg_keys": {
+                    "$ref": "#/definitions/models.QuotaUsage"
+                },
+                "security_groups": {
+                    "$ref": "#/definitions/models.QuotaUsage"
+                },
+                "vpcs": {
+                    "$ref": "#/definitions/models.QuotaUsage"
+                }
+            }
+        },
+        "models.QuotaUsage": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "Limit is 0 when the quota is unlimited.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 10
+                },
+                "used": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 3
+                }
+            }
+        },
         "models.ValidationError": {
             "type": "object",
             "properties": {
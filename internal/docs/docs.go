@@ -222,6 +222,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuotaExceededError"
+                        }
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -590,6 +596,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.DeviceMetadata"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuotaExceededError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1300,6 +1312,135 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/organizations/{id}/audit": {
+            "get": {
+                "description": "Lists the changes made to the resources of an organization, in the order they were made",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "List Organization Audit Entries",
+                "operationId": "ListOrganizationAudit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/organizations/{id}/audit/verify": {
+            "get": {
+                "description": "Checks that the audit entries of an organization were not changed or removed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Verify Organization Audit Entries",
+                "operationId": "VerifyOrganizationAudit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AuditVerification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            }
+        },
         "/api/organizations/{id}/users": {
             "get": {
                 "description": "Lists all the users of an organization",
@@ -1478,6 +1619,83 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "patch": {
+                "description": "Changes the roles of a user in an organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Update Organization User",
+                "operationId": "UpdateOrganizationUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Organization User Update",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateUserOrganization"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserOrganization"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
             }
         },
         "/api/reg-keys": {
@@ -1561,6 +1779,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuotaExceededError"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1862,6 +2086,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuotaExceededError"
+                        }
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -2068,28 +2298,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/service-networks": {
-            "get": {
-                "description": "Lists all ServiceNetworks",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/security-groups/{id}/simulate": {
+            "post": {
+                "description": "Evaluates the traffic between two devices with a proposed update of a Security Group, the security group is not changed",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ServiceNetwork"
+                    "SecurityGroup"
+                ],
+                "summary": "Simulate Security Group",
+                "operationId": "SimulateSecurityGroup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Security Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Security Group Simulation",
+                        "name": "simulation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SecurityGroupSimulation"
+                        }
+                    }
                 ],
-                "summary": "List ServiceNetworks",
-                "operationId": "ListServiceNetworks",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ServiceNetwork"
-                            }
+                            "$ref": "#/definitions/models.SecurityGroupSimulationResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
                         }
                     },
                     "401": {
@@ -2098,6 +2346,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationError"
+                        }
+                    },
                     "429": {
                         "description": "Too Many Requests",
                         "schema": {
@@ -2111,36 +2371,33 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Creates a named serviceNetwork with the given CIDR",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/security-groups/{id}/stats": {
+            "get": {
+                "description": "Gets the packet and byte counters of the rules of a security group, summed over the devices in the group",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ServiceNetwork"
+                    "SecurityGroup"
                 ],
-                "summary": "Create an ServiceNetwork",
-                "operationId": "CreateServiceNetwork",
+                "summary": "Get SecurityGroup Stats",
+                "operationId": "GetSecurityGroupStats",
                 "parameters": [
                     {
-                        "description": "Add ServiceNetwork",
-                        "name": "ServiceNetwork",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.AddServiceNetwork"
-                        }
+                        "type": "string",
+                        "description": "Security Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ServiceNetwork"
+                            "$ref": "#/definitions/models.SecurityGroupStats"
                         }
                     },
                     "400": {
@@ -2155,18 +2412,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
-                    "405": {
-                        "description": "Method Not Allowed",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/models.ConflictsError"
-                        }
-                    },
                     "429": {
                         "description": "Too Many Requests",
                         "schema": {
@@ -2182,9 +2433,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/service-networks/{id}": {
+        "/api/service-accounts": {
             "get": {
-                "description": "Gets a ServiceNetwork by ServiceNetwork ID",
+                "description": "Lists the service accounts of the organizations of the current user",
                 "consumes": [
                     "application/json"
                 ],
@@ -2192,24 +2443,69 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ServiceNetwork"
+                    "ServiceAccount"
                 ],
-                "summary": "Get ServiceNetworks",
-                "operationId": "GetServiceNetwork",
+                "summary": "List service accounts",
+                "operationId": "ListServiceAccounts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ServiceAccount"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a ServiceAccount in an organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ServiceAccount"
+                ],
+                "summary": "Create a ServiceAccount",
+                "operationId": "CreateServiceAccount",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ServiceNetwork ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Add ServiceAccount",
+                        "name": "ServiceAccount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AddServiceAccount"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.ServiceNetwork"
+                            "$ref": "#/definitions/models.ServiceAccount"
                         }
                     },
                     "400": {
@@ -2218,16 +2514,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/models.BaseError"
+                            "$ref": "#/definitions/models.ConflictsError"
                         }
                     },
                     "429": {
@@ -2243,9 +2539,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes an existing serviceNetwork",
+            }
+        },
+        "/api/service-accounts/{id}": {
+            "get": {
+                "description": "Gets a ServiceAccount by ServiceAccount ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2253,24 +2551,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ServiceNetwork"
+                    "ServiceAccount"
                 ],
-                "summary": "Delete ServiceNetwork",
-                "operationId": "DeleteServiceNetwork",
+                "summary": "Get a ServiceAccount",
+                "operationId": "GetServiceAccount",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ServiceNetwork ID",
+                        "description": "ServiceAccount ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ServiceNetwork"
+                            "$ref": "#/definitions/models.ServiceAccount"
                         }
                     },
                     "400": {
@@ -2279,8 +2577,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
-                    "405": {
-                        "description": "Method Not Allowed",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.BaseError"
                         }
@@ -2299,8 +2603,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "Updates a serviceNetwork by ID",
+            "delete": {
+                "description": "Deletes an existing ServiceAccount and revokes its tokens",
                 "consumes": [
                     "application/json"
                 ],
@@ -2308,33 +2612,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ServiceNetwork"
+                    "ServiceAccount"
                 ],
-                "summary": "Update ServiceNetworks",
-                "operationId": "UpdateServiceNetwork",
+                "summary": "Delete ServiceAccount",
+                "operationId": "DeleteServiceAccount",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ServiceNetwork ID",
+                        "description": "ServiceAccount ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "ServiceNetwork Update",
-                        "name": "update",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateServiceNetwork"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ServiceNetwork"
+                            "$ref": "#/definitions/models.ServiceAccount"
                         }
                     },
                     "400": {
@@ -2343,12 +2638,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/models.BaseError"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -2370,9 +2659,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/sites": {
+        "/api/service-networks": {
             "get": {
-                "description": "Lists all sites",
+                "description": "Lists all ServiceNetworks",
                 "consumes": [
                     "application/json"
                 ],
@@ -2380,17 +2669,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sites"
+                    "ServiceNetwork"
                 ],
-                "summary": "List Sites",
-                "operationId": "ListSites",
+                "summary": "List ServiceNetworks",
+                "operationId": "ListServiceNetworks",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Site"
+                                "$ref": "#/definitions/models.ServiceNetwork"
                             }
                         }
                     },
@@ -2415,7 +2704,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Adds a new site",
+                "description": "Creates a named serviceNetwork with the given CIDR",
                 "consumes": [
                     "application/json"
                 ],
@@ -2423,18 +2712,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sites"
+                    "ServiceNetwork"
                 ],
-                "summary": "Add Sites",
-                "operationId": "CreateSite",
+                "summary": "Create an ServiceNetwork",
+                "operationId": "CreateServiceNetwork",
                 "parameters": [
                     {
-                        "description": "Add Site",
-                        "name": "Site",
+                        "description": "Add ServiceNetwork",
+                        "name": "ServiceNetwork",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.AddSite"
+                            "$ref": "#/definitions/models.AddServiceNetwork"
                         }
                     }
                 ],
@@ -2442,7 +2731,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Site"
+                            "$ref": "#/definitions/models.ServiceNetwork"
                         }
                     },
                     "400": {
@@ -2457,6 +2746,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
+                    "405": {
+                        "description": "Method Not Allowed",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -2478,9 +2773,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/sites/{id}": {
+        "/api/service-networks/{id}": {
             "get": {
-                "description": "Gets a site by ID",
+                "description": "Gets a ServiceNetwork by ServiceNetwork ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2488,14 +2783,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sites"
+                    "ServiceNetwork"
                 ],
-                "summary": "Get Sites",
-                "operationId": "GetSite",
+                "summary": "Get ServiceNetworks",
+                "operationId": "GetServiceNetwork",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Site ID",
+                        "description": "ServiceNetwork ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2505,7 +2800,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Site"
+                            "$ref": "#/definitions/models.ServiceNetwork"
                         }
                     },
                     "400": {
@@ -2541,7 +2836,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Deletes an existing site and associated IPAM lease",
+                "description": "Deletes an existing serviceNetwork",
                 "consumes": [
                     "application/json"
                 ],
@@ -2549,14 +2844,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sites"
+                    "ServiceNetwork"
                 ],
-                "summary": "Delete Site",
-                "operationId": "DeleteSite",
+                "summary": "Delete ServiceNetwork",
+                "operationId": "DeleteServiceNetwork",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Site ID",
+                        "description": "ServiceNetwork ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2566,7 +2861,7 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content",
                         "schema": {
-                            "$ref": "#/definitions/models.Site"
+                            "$ref": "#/definitions/models.ServiceNetwork"
                         }
                     },
                     "400": {
@@ -2575,6 +2870,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
+                    "405": {
+                        "description": "Method Not Allowed",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
                     "429": {
                         "description": "Too Many Requests",
                         "schema": {
@@ -2590,7 +2891,7 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Updates a site by ID",
+                "description": "Updates a serviceNetwork by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2598,25 +2899,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sites"
+                    "ServiceNetwork"
                 ],
-                "summary": "Update Sites",
-                "operationId": "UpdateSite",
+                "summary": "Update ServiceNetworks",
+                "operationId": "UpdateServiceNetwork",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Site ID",
+                        "description": "ServiceNetwork ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Site Update",
+                        "description": "ServiceNetwork Update",
                         "name": "update",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateSite"
+                            "$ref": "#/definitions/models.UpdateServiceNetwork"
                         }
                     }
                 ],
@@ -2624,7 +2925,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Site"
+                            "$ref": "#/definitions/models.ServiceNetwork"
                         }
                     },
                     "400": {
@@ -2660,28 +2961,804 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users": {
+        "/api/sites": {
             "get": {
-                "description": "Lists all users",
+                "description": "Lists all sites",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "tags": [
-                    "Users"
+                "tags": [
+                    "Sites"
+                ],
+                "summary": "List Sites",
+                "operationId": "ListSites",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Site"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a new site",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sites"
+                ],
+                "summary": "Add Sites",
+                "operationId": "CreateSite",
+                "parameters": [
+                    {
+                        "description": "Add Site",
+                        "name": "Site",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AddSite"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Site"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ConflictsError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sites/{id}": {
+            "get": {
+                "description": "Gets a site by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sites"
+                ],
+                "summary": "Get Sites",
+                "operationId": "GetSite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Site"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes an existing site and associated IPAM lease",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sites"
+                ],
+                "summary": "Delete Site",
+                "operationId": "DeleteSite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "$ref": "#/definitions/models.Site"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates a site by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sites"
+                ],
+                "summary": "Update Sites",
+                "operationId": "UpdateSite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Site Update",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSite"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Site"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tokens": {
+            "get": {
+                "description": "Lists the api tokens of the current user and of the service accounts of the organizations it owns",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "List api tokens",
+                "operationId": "ListApiTokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ApiToken"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create an ApiToken for the current user or for a service account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "Create an ApiToken",
+                "operationId": "CreateApiToken",
+                "parameters": [
+                    {
+                        "description": "Add ApiToken",
+                        "name": "ApiToken",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AddApiToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tokens/{id}": {
+            "get": {
+                "description": "Gets an ApiToken by ApiToken ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "Get an ApiToken",
+                "operationId": "GetApiToken",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ApiToken ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Revokes an existing ApiToken",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "Revoke ApiToken",
+                "operationId": "DeleteApiToken",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ApiToken ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApiToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "get": {
+                "description": "Lists all users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List Users",
+                "operationId": "ListUsers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}": {
+            "get": {
+                "description": "Gets a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get User",
+                "operationId": "GetUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete User",
+                "operationId": "DeleteUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotAllowedError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/organizations/{organization}": {
+            "delete": {
+                "description": "Deletes an existing organization associated to a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Remove a User from an Organization",
+                "operationId": "DeleteUserFromOrganization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/vpc-peerings": {
+            "get": {
+                "description": "Lists the peerings of the VPCs of the organizations of the current user, including the pending peerings requested with them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VPC"
+                ],
+                "summary": "List VPC Peerings",
+                "operationId": "ListVPCPeerings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.VPCPeering"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalServerError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Requests a peering between a VPC and a peer VPC, which may belong to another organization. The peering stays pending until it is accepted for the peer VPC.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VPC"
+                ],
+                "summary": "Request a VPC Peering",
+                "operationId": "CreateVPCPeering",
+                "parameters": [
+                    {
+                        "description": "Add VPC Peering",
+                        "name": "VPCPeering",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AddVPCPeering"
+                        }
+                    }
                 ],
-                "summary": "List Users",
-                "operationId": "ListUsers",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
-                            }
+                            "$ref": "#/definitions/models.VPCPeering"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationError"
                         }
                     },
                     "401": {
@@ -2690,6 +3767,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ConflictsError"
+                        }
+                    },
                     "429": {
                         "description": "Too Many Requests",
                         "schema": {
@@ -2705,9 +3794,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/{id}": {
+        "/api/vpc-peerings/{id}": {
             "get": {
-                "description": "Gets a user",
+                "description": "Gets a VPC peering by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2715,14 +3804,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "VPC"
                 ],
-                "summary": "Get User",
-                "operationId": "GetUser",
+                "summary": "Get VPC Peering",
+                "operationId": "GetVPCPeering",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "VPC Peering ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2732,7 +3821,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.VPCPeering"
                         }
                     },
                     "400": {
@@ -2768,7 +3857,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a user",
+                "description": "Deletes a pending or active VPC peering, the devices of the VPCs stop seeing each other",
                 "consumes": [
                     "application/json"
                 ],
@@ -2776,14 +3865,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "VPC"
                 ],
-                "summary": "Delete User",
-                "operationId": "DeleteUser",
+                "summary": "Delete VPC Peering",
+                "operationId": "DeleteVPCPeering",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "VPC Peering ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2793,13 +3882,25 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.VPCPeering"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.NotAllowedError"
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
                         }
                     },
                     "429": {
@@ -2817,9 +3918,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/{id}/organizations/{organization}": {
-            "delete": {
-                "description": "Deletes an existing organization associated to a user",
+        "/api/vpc-peerings/{id}/accept": {
+            "post": {
+                "description": "Accepts a pending VPC peering for the peer VPC, the devices of both VPCs then see each other",
                 "consumes": [
                     "application/json"
                 ],
@@ -2827,31 +3928,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "VPC"
                 ],
-                "summary": "Remove a User from an Organization",
-                "operationId": "DeleteUserFromOrganization",
+                "summary": "Accept VPC Peering",
+                "operationId": "AcceptVPCPeering",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "VPC Peering ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.VPCPeering"
                         }
                     },
                     "400": {
@@ -2860,6 +3954,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.BaseError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3031,6 +4143,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.BaseError"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuotaExceededError"
+                        }
+                    },
                     "405": {
                         "description": "Method Not Allowed",
                         "schema": {
@@ -3248,7 +4366,7 @@ const docTemplate = `{
         },
         "/api/vpcs/{id}/devices": {
             "get": {
-                "description": "Lists all devices for this VPC",
+                "description": "Lists all devices for this VPC, and the devices of the VPCs actively peered with it",
                 "consumes": [
                     "application/json"
                 ],
@@ -3699,6 +4817,30 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "models.AddApiToken": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description of the token.",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is optional, if set the token is only valid until the ExpiresAt time.",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Scopes limit the API endpoints the token can access, like read:devices or write:organizations.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "service_account_id": {
+                    "description": "ServiceAccountID is the ID of the service account the token belongs to, the token belongs to the current user when not set.",
+                    "type": "string"
+                }
+            }
+        },
         "models.AddDevice": {
             "type": "object",
             "properties": {
@@ -3737,8 +4879,16 @@ const docTemplate = `{
                     "type": "boolean"
                 },
                 "security_group_id": {
+                    "description": "SecurityGroupId is only used when SecurityGroupIds is not set, it is kept for older clients.",
                     "type": "string"
                 },
+                "security_group_ids": {
+                    "description": "SecurityGroupIds are the security groups of the device, the default security group of the vpc is used if none are set.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "symmetric_nat": {
                     "type": "boolean"
                 },
@@ -3795,9 +4945,16 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "security_group_id": {
-                    "description": "SecurityGroupId is the ID of the security group to assign to the device.",
+                    "description": "SecurityGroupId is only used when SecurityGroupIds is not set, it is kept for older clients.",
                     "type": "string"
                 },
+                "security_group_ids": {
+                    "description": "SecurityGroupIds are the IDs of the security groups to assign to the device.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "service_network_id": {
                     "description": "ServiceNetworkID is the ID of the Service Network the device can join.",
                     "type": "string"
@@ -3841,6 +4998,30 @@ const docTemplate = `{
                 }
             }
         },
+        "models.AddServiceAccount": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description of the service account.",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the service account, unique in the organization.",
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "OrganizationID is the ID of the organization the service account is a member of.",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "Roles of the service account in the organization, defaults to member.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "models.AddServiceNetwork": {
             "type": "object",
             "properties": {
@@ -3878,18 +5059,197 @@ const docTemplate = `{
                     "type": "string",
                     "example": "The Red Zone"
                 },
-                "ipv4_cidr": {
+                "ipv4_cidr": {
+                    "type": "string",
+                    "example": "172.16.42.0/24"
+                },
+                "ipv6_cidr": {
+                    "type": "string",
+                    "example": "0200::/8"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "private_cidr": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.AddVPCPeering": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description of the peering.",
+                    "type": "string",
+                    "example": "web to database"
+                },
+                "peer_vpc_id": {
+                    "description": "PeerVpcID is the ID of the VPC to peer with, it may belong to another organization.",
+                    "type": "string"
+                },
+                "vpc_id": {
+                    "description": "VpcID is the ID of the VPC requesting the peering.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ApiToken": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "description": "AccessToken is the bearer token to send to the API, it is only returned when the token is created.",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description of the token.",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is optional, if set the token is only valid until the ExpiresAt time.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "aa22666c-0f57-45cb-a449-16efecc04f2e"
+                },
+                "organization_id": {
+                    "description": "OrganizationID is denormalized from the service account record for performance",
+                    "type": "string"
+                },
+                "owner_id": {
+                    "description": "OwnerID is the ID of the user that created the token.",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Scopes limit the API endpoints the token can access.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "service_account_id": {
+                    "description": "ServiceAccountID is set when the token belongs to a service account.",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user the token acts as, the owner or the user of the service account.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is create, update or delete",
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor_id": {
+                    "description": "ActorID is the ID of the user, registration key, device or api token that made the change",
+                    "type": "string"
+                },
+                "actor_kind": {
+                    "description": "ActorKind is user, reg-key, device or api-token",
+                    "type": "string",
+                    "example": "user"
+                },
+                "created_at": {
+                    "description": "CreatedAt is when the change was made",
+                    "type": "string"
+                },
+                "diff": {
+                    "description": "Diff maps the changed fields of the resource to their before and after values",
+                    "type": "object"
+                },
+                "hash": {
+                    "description": "Hash is the HMAC-SHA256 of the entry and PrevHash",
+                    "type": "string",
+                    "example": "9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f0c9a0a1f5e3b0c6e8a1d2b7c4f5e6a"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "aa22666c-0f57-45cb-a449-16efecc04f2e"
+                },
+                "method": {
+                    "description": "Method of the request that made the change",
+                    "type": "string",
+                    "example": "PATCH"
+                },
+                "organization_id": {
+                    "description": "OrganizationID is the organization of the changed resource, nil for resources outside organizations",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path of the request that made the change",
+                    "type": "string",
+                    "example": "/api/devices/aa22666c-0f57-45cb-a449-16efecc04f2e"
+                },
+                "prev_hash": {
+                    "description": "PrevHash is the hash of the previous entry of the chain, empty for the first entry",
+                    "type": "string",
+                    "example": "3f0c9a0a1f5e3b0c6e8a1d2b7c4f5e6a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e"
+                },
+                "resource_id": {
+                    "description": "ResourceID is the ID of the changed resource, IDs made of several fields are joined with '/'",
+                    "type": "string",
+                    "example": "aa22666c-0f57-45cb-a449-16efecc04f2e"
+                },
+                "resource_kind": {
+                    "description": "ResourceKind is the kind of the changed resource",
+                    "type": "string",
+                    "example": "devices"
+                },
+                "sequence": {
+                    "description": "Sequence is the position of the entry in the chain of the organization, starting at 1",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 1
+                },
+                "source_ip": {
+                    "description": "SourceIP is the client address of the request",
+                    "type": "string",
+                    "example": "192.0.2.10"
+                },
+                "user_id": {
+                    "description": "UserID is the user the actor acts for",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditVerification": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Entries is the number of entries checked",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 42
+                },
+                "error": {
+                    "description": "Error describes why the entry does not match",
                     "type": "string",
-                    "example": "172.16.42.0/24"
+                    "example": "hash does not match"
                 },
-                "ipv6_cidr": {
+                "head_hash": {
+                    "description": "HeadHash is the hash of the last entry of the chain",
                     "type": "string",
-                    "example": "0200::/8"
-                },
-                "organization_id": {
-                    "type": "string"
-                },
-                "private_cidr": {
+                    "example": "9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f0c9a0a1f5e3b0c6e8a1d2b7c4f5e6a"
+                },
+                "head_sequence": {
+                    "description": "HeadSequence is the sequence of the last entry of the chain",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 42
+                },
+                "invalid_sequence": {
+                    "description": "InvalidSequence is the sequence of the first entry that does not match the chain",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 7
+                },
+                "verified": {
+                    "description": "Verified is true when every entry of the chain is intact",
                     "type": "boolean"
                 }
             }
@@ -3981,6 +5341,14 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.Endpoint"
                     }
                 },
+                "exit_node_policy": {
+                    "description": "ExitNodePolicy selects the traffic this device sends through an exit node, all of it when not set.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ExitNodePolicy"
+                        }
+                    ]
+                },
                 "hostname": {
                     "type": "string"
                 },
@@ -4022,11 +5390,27 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "security_group_id": {
+                    "description": "SecurityGroupId is the first of the SecurityGroupIds, it is kept for older clients.",
                     "type": "string"
                 },
+                "security_group_ids": {
+                    "description": "SecurityGroupIds are the security groups of the device, the device is allowed the traffic allowed by any of them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "symmetric_nat": {
                     "type": "boolean"
                 },
+                "traffic_limits": {
+                    "description": "TrafficLimits caps the traffic the device sends into the VPC, it is unlimited when not set.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TrafficLimits"
+                        }
+                    ]
+                },
                 "vpc_id": {
                     "type": "string",
                     "example": "694aa002-5d19-495e-980b-3d8fd508ea10"
@@ -4082,6 +5466,31 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ExitNodePolicy": {
+            "type": "object",
+            "properties": {
+                "exclude": {
+                    "description": "Exclude are the destinations that bypass the exit node, they take precedence over Include",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "192.168.0.0/16"
+                    ]
+                },
+                "include": {
+                    "description": "Include are the destinations sent through the exit node, all destinations are when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "0.0.0.0/0"
+                    ]
+                }
+            }
+        },
         "models.InternalServerError": {
             "type": "object",
             "properties": {
@@ -4207,6 +5616,73 @@ const docTemplate = `{
                 "name": {
                     "type": "string",
                     "example": "zone-red"
+                },
+                "usage": {
+                    "description": "Usage of the quotas, only reported when getting a single organization.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.OrganizationUsage"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.OrganizationUsage": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "$ref": "#/definitions/models.QuotaUsage"
+                },
+                "metadata_keys": {
+                    "description": "MetadataKeys counts the metadata keys of all the devices of the organization.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.QuotaUsage"
+                        }
+                    ]
+                },
+                "reg_keys": {
+                    "$ref": "#/definitions/models.QuotaUsage"
+                },
+                "security_groups": {
+                    "$ref": "#/definitions/models.QuotaUsage"
+                },
+                "vpcs": {
+                    "$ref": "#/definitions/models.QuotaUsage"
+                }
+            }
+        },
+        "models.QuotaExceededError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "something bad"
+                },
+                "limit": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 10
+                },
+                "quota": {
+                    "type": "string",
+                    "example": "vpcs"
+                }
+            }
+        },
+        "models.QuotaUsage": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "Limit is 0 when the quota is unlimited.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 10
+                },
+                "used": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 3
                 }
             }
         },
@@ -4238,9 +5714,16 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "security_group_id": {
-                    "description": "SecurityGroupId is the ID of the security group to assign to the device.",
+                    "description": "SecurityGroupId is the first of the SecurityGroupIds, it is kept for older clients.",
                     "type": "string"
                 },
+                "security_group_ids": {
+                    "description": "SecurityGroupIds are the IDs of the security groups to assign to the device.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "service_network_id": {
                     "description": "ServiceNetworkID is the ID of the Service Network the device can join.",
                     "type": "string"
@@ -4286,12 +5769,118 @@ const docTemplate = `{
                 }
             }
         },
+        "models.SecurityGroupSimulation": {
+            "type": "object",
+            "properties": {
+                "destination_device_id": {
+                    "type": "string"
+                },
+                "icmp_code": {
+                    "description": "IcmpCode is the code of icmp traffic, it defaults to 0.",
+                    "type": "integer"
+                },
+                "icmp_type": {
+                    "description": "IcmpType is the type of icmp traffic, it defaults to an echo request.",
+                    "type": "integer"
+                },
+                "ip_protocol": {
+                    "description": "IpProtocol is the protocol of the traffic.",
+                    "type": "string",
+                    "enum": [
+                        "tcp",
+                        "udp",
+                        "icmp",
+                        "icmpv6"
+                    ]
+                },
+                "ip_version": {
+                    "description": "IpVersion selects the tunnel addresses of the devices the traffic is sent between, 4 or 6. Only icmpv6 defaults to 6.",
+                    "type": "integer",
+                    "enum": [
+                        4,
+                        6
+                    ]
+                },
+                "port": {
+                    "description": "Port is the destination port of tcp and udp traffic.",
+                    "type": "integer"
+                },
+                "source_device_id": {
+                    "type": "string"
+                },
+                "update": {
+                    "description": "Update holds the proposed rules, the current rules of the security group are used for the directions that are not set.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.UpdateSecurityGroup"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.SecurityGroupSimulationResult": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean"
+                },
+                "inbound": {
+                    "$ref": "#/definitions/models.SecurityRuleVerdict"
+                },
+                "outbound": {
+                    "$ref": "#/definitions/models.SecurityRuleVerdict"
+                }
+            }
+        },
+        "models.SecurityGroupStats": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "description": "Devices is the number of devices that reported counters for the current revision of the security group",
+                    "type": "integer"
+                },
+                "inbound_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SecurityRuleStats"
+                    }
+                },
+                "outbound_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SecurityRuleStats"
+                    }
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "security_group_id": {
+                    "type": "string"
+                }
+            }
+        },
         "models.SecurityRule": {
             "type": "object",
             "properties": {
+                "action": {
+                    "description": "Action is either allow or deny, rules without an action allow the traffic.",
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ]
+                },
                 "from_port": {
                     "type": "integer"
                 },
+                "icmp_code": {
+                    "description": "IcmpCode optionally restricts an icmp rule with an icmp_type to a single ICMP code.",
+                    "type": "integer"
+                },
+                "icmp_type": {
+                    "description": "IcmpType optionally restricts an icmp rule to a single ICMP type.",
+                    "type": "integer"
+                },
                 "ip_protocol": {
                     "type": "string"
                 },
@@ -4301,8 +5890,108 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "priority": {
+                    "description": "Priority orders the rules of a direction, rules with a lower priority are evaluated first.",
+                    "type": "integer"
+                },
+                "security_group_ids": {
+                    "description": "SecurityGroupIds matches the tunnel IPs of the devices in the referenced security groups, in addition to the IpRanges.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "to_port": {
                     "type": "integer"
+                },
+                "vpc_ids": {
+                    "description": "VpcIds matches the tunnel IPs of the devices in the referenced VPCs, which are the VPC of the security group or VPCs actively peered with it.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.SecurityRuleStats": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "packets": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "rule": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SecurityRuleVerdict": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean"
+                },
+                "matched_rule": {
+                    "$ref": "#/definitions/models.SecurityRule"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "Rule is the index of the rule that matched the traffic in its security group, it is not set when no rule matched.",
+                    "type": "integer"
+                },
+                "security_group_id": {
+                    "description": "SecurityGroupId is the security group of the rule that matched the traffic, it is the nil uuid when no rule matched.",
+                    "type": "string"
+                },
+                "security_group_ids": {
+                    "description": "SecurityGroupIds are the security groups of the device the rules belong to, it is empty if the device has no security group.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ServiceAccount": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description of the service account.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "aa22666c-0f57-45cb-a449-16efecc04f2e"
+                },
+                "name": {
+                    "description": "Name of the service account, unique in the organization.",
+                    "type": "string",
+                    "example": "ci-pipeline"
+                },
+                "organization_id": {
+                    "description": "OrganizationID is the ID of the organization the service account is a member of.",
+                    "type": "string"
+                },
+                "owner_id": {
+                    "description": "OwnerID is the ID of the user that created the service account.",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "Roles of the service account in the organization.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "description": "UserID is the ID of the user record the service account acts as.",
+                    "type": "string"
                 }
             }
         },
@@ -4378,6 +6067,22 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TrafficLimits": {
+            "type": "object",
+            "properties": {
+                "bits_per_second": {
+                    "description": "BitsPerSecond limits the bandwidth used by the device",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 100000000
+                },
+                "packets_per_second": {
+                    "description": "PacketsPerSecond limits the packet rate of the device",
+                    "type": "integer",
+                    "example": 10000
+                }
+            }
+        },
         "models.TunnelIP": {
             "type": "object",
             "properties": {
@@ -4411,6 +6116,14 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.Endpoint"
                     }
                 },
+                "exit_node_policy": {
+                    "description": "ExitNodePolicy replaces the exit node policy of the device when set, an empty policy sends all the traffic through the exit node.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ExitNodePolicy"
+                        }
+                    ]
+                },
                 "hostname": {
                     "type": "string",
                     "example": "myhost"
@@ -4422,11 +6135,27 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "security_group_id": {
+                    "description": "SecurityGroupId is only used when SecurityGroupIds is not set, it is kept for older clients.",
                     "type": "string"
                 },
+                "security_group_ids": {
+                    "description": "SecurityGroupIds replaces the security groups of the device when set, an empty list removes the device from all security groups.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "symmetric_nat": {
                     "type": "boolean"
                 },
+                "traffic_limits": {
+                    "description": "TrafficLimits replaces the traffic limits of the device when set, empty limits remove them.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TrafficLimits"
+                        }
+                    ]
+                },
                 "vpc_id": {
                     "type": "string",
                     "example": "694aa002-5d19-495e-980b-3d8fd508ea10"
@@ -4445,9 +6174,16 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "security_group_id": {
-                    "description": "SecurityGroupId is the ID of the security group to assign to the device.",
+                    "description": "SecurityGroupId is only used when SecurityGroupIds is not set, it is kept for older clients.",
                     "type": "string"
                 },
+                "security_group_ids": {
+                    "description": "SecurityGroupIds replaces the security groups to assign to the device when set.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "settings": {
                     "description": "Settings contains general settings for the device.",
                     "type": "object",
@@ -4502,6 +6238,17 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdateUserOrganization": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "models.UpdateVPC": {
             "type": "object",
             "properties": {
@@ -4605,6 +6352,45 @@ const docTemplate = `{
                 }
             }
         },
+        "models.VPCPeering": {
+            "type": "object",
+            "properties": {
+                "accepter_organization_id": {
+                    "description": "AccepterOrganizationID is the ID of the organization of the accepter VPC.",
+                    "type": "string"
+                },
+                "accepter_vpc_id": {
+                    "description": "AccepterVpcID is the ID of the VPC that has to accept the peering.",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description of the peering.",
+                    "type": "string",
+                    "example": "web to database"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "aa22666c-0f57-45cb-a449-16efecc04f2e"
+                },
+                "requester_organization_id": {
+                    "description": "RequesterOrganizationID is the ID of the organization of the requester VPC.",
+                    "type": "string"
+                },
+                "requester_vpc_id": {
+                    "description": "RequesterVpcID is the ID of the VPC that requested the peering.",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is pending until the peering is accepted, and active after.",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "active"
+                    ],
+                    "example": "pending"
+                }
+            }
+        },
         "models.ValidationError": {
             "type": "object",
             "properties": {
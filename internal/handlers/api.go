@@ -16,11 +16,13 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/nexodus-io/nexodus/internal/database"
 	"github.com/open-policy-agent/opa/storage"
 
 	"github.com/nexodus-io/nexodus/internal/util"
+	"github.com/nexodus-io/nexodus/internal/util/cache"
 
 	"github.com/google/uuid"
 	"github.com/nexodus-io/nexodus/internal/fflags"
@@ -59,6 +61,8 @@ type API struct {
 	SmtpFrom       string
 	caKeyPair      CertificateKeyPair
 	FrontendURL    string
+	apiTokens      *cache.MemoizeCache[string, uuid.UUID]
+	Quotas         Quotas
 }
 
 func NewAPI(
@@ -121,12 +125,17 @@ func NewAPI(
 		fetchManager:   fetchManager,
 		onlineTracker:  onlineTracker,
 		caKeyPair:      caKeyPair,
+		apiTokens:      cache.NewMemoizeCache[string, uuid.UUID](time.Second*5, time.Second*5),
 	}
 
 	if err := api.populateStore(ctx); err != nil {
 		return nil, err
 	}
 
+	if err := api.registerAuditCallbacks(); err != nil {
+		return nil, err
+	}
+
 	err = api.createDefaultIPamNamespace(ctx)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/models"
+	"github.com/nexodus-io/nexodus/internal/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/maps"
+	"gorm.io/gorm"
+)
+
+// ApiTokenScope is in the scope of the JWTs minted for api tokens, next to the scopes the token was created with.
+const ApiTokenScope = "api-token"
+
+var apiTokenScopes = map[string]struct{}{
+	"read:organizations":  {},
+	"write:organizations": {},
+	"read:devices":        {},
+	"write:devices":       {},
+	"read:users":          {},
+	"write:users":         {},
+}
+
+// CreateApiToken creates an ApiToken
+// @Summary      Create an ApiToken
+// @Description  Create an ApiToken for the current user or for a service account
+// @Id           CreateApiToken
+// @Tags         Token
+// @Accept       json
+// @Produce      json
+// @Param        ApiToken  body     models.AddApiToken  true  "Add ApiToken"
+// @Success      201  {object}  models.ApiToken
+// @Failure      400  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/tokens [post]
+func (api *API) CreateApiToken(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "CreateApiToken")
+	defer span.End()
+
+	var request models.AddApiToken
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPayloadError(err))
+		return
+	}
+
+	if len(request.Scopes) == 0 {
+		c.JSON(http.StatusBadRequest, models.NewFieldNotPresentError("scopes"))
+		return
+	}
+	if len(util.FilterOutAllowed(request.Scopes, apiTokenScopes)) > 0 {
+		c.JSON(http.StatusBadRequest, models.NewFieldValidationError("scopes", "allowed values are: "+strings.Join(maps.Keys(apiTokenScopes), ", ")))
+		return
+	}
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, models.NewFieldValidationError("expires_at", "must be in the future"))
+		return
+	}
+
+	userId := api.GetCurrentUserID(c)
+
+	record := models.ApiToken{
+		OwnerID:     userId,
+		UserID:      userId,
+		Description: request.Description,
+		Scopes:      request.Scopes,
+		ExpiresAt:   request.ExpiresAt,
+	}
+	err := api.transaction(ctx, func(tx *gorm.DB) error {
+
+		// Only the owners of the organization can create tokens for its service accounts
+		if request.ServiceAccountID != nil {
+			var serviceAccount models.ServiceAccount
+			if res := api.ServiceAccountIsOwnedByCurrentUser(c, tx).
+				First(&serviceAccount, "id = ?", *request.ServiceAccountID); res.Error != nil {
+				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("service account"))
+			}
+			record.UserID = serviceAccount.UserID
+			record.ServiceAccountID = &serviceAccount.ID
+			record.OrganizationID = &serviceAccount.OrganizationID
+		}
+
+		var user models.User
+		if res := tx.First(&user, "id = ?", record.UserID); res.Error != nil {
+			return res.Error
+		}
+
+		if res := tx.Create(&record); res.Error != nil {
+			return res.Error
+		}
+
+		accessToken, err := api.signApiToken(record, user)
+		if err != nil {
+			return err
+		}
+		record.AccessToken = accessToken
+		return nil
+	})
+
+	if err != nil {
+		var apiResponseError *ApiResponseError
+		if errors.As(err, &apiResponseError) {
+			c.JSON(apiResponseError.Status, apiResponseError.Body)
+		} else {
+			api.SendInternalServerError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// signApiToken mints the JWT of an api token, it is verified against the same JWKS as the reg key and device tokens.
+func (api *API) signApiToken(token models.ApiToken, user models.User) (string, error) {
+	claims := models.NexodusClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:   api.URL,
+			ID:       token.ID.String(),
+			Subject:  user.IdpID,
+			IssuedAt: jwt.NewNumericDate(token.CreatedAt),
+		},
+		Scope: strings.Join(append([]string{ApiTokenScope}, token.Scopes...), " "),
+	}
+	if token.ExpiresAt != nil {
+		claims.ExpiresAt = jwt.NewNumericDate(*token.ExpiresAt)
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(api.PrivateKey)
+}
+
+// ApiTokenUserID returns the ID of the user an api token acts as. The JWT of a token stays valid after the token is
+// revoked, so every request made with it checks that the token still exists. The lookups are cached for a few seconds.
+func (api *API) ApiTokenUserID(ctx context.Context, tokenID string) (uuid.UUID, error) {
+	return api.apiTokens.MemoizeCanErr(tokenID, func() (uuid.UUID, error) {
+		id, err := uuid.Parse(tokenID)
+		if err != nil {
+			return uuid.Nil, NewApiResponseError(http.StatusUnauthorized, models.NewBaseError("invalid api token"))
+		}
+		var token models.ApiToken
+		res := api.db.WithContext(ctx).
+			Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+			First(&token, "id = ?", id)
+		if res.Error != nil {
+			if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+				return uuid.Nil, NewApiResponseError(http.StatusUnauthorized, models.NewBaseError("api token was revoked or has expired"))
+			}
+			return uuid.Nil, res.Error
+		}
+		return token.UserID, nil
+	})
+}
+
+func (api *API) ApiTokenIsForCurrentUserOrOrgOwner(c *gin.Context, db *gorm.DB) *gorm.DB {
+	userId := api.GetCurrentUserID(c)
+	return db.Where(
+		db.Where("owner_id = ?", userId).
+			Or(api.CurrentUserHasRole(c, db, "organization_id", OwnerRoles)),
+	)
+}
+
+// ListApiTokens lists api tokens
+// @Summary      List api tokens
+// @Description  Lists the api tokens of the current user and of the service accounts of the organizations it owns
+// @Id           ListApiTokens
+// @Tags         Token
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  []models.ApiToken
+// @Failure		 401  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/tokens [get]
+func (api *API) ListApiTokens(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ListApiTokens")
+	defer span.End()
+	records := []models.ApiToken{}
+	db := api.db.WithContext(ctx)
+	db = api.ApiTokenIsForCurrentUserOrOrgOwner(c, db)
+	db = FilterAndPaginate(db, &models.ApiToken{}, c, "id")
+	result := db.Find(&records)
+	if result.Error != nil {
+		api.SendInternalServerError(c, fmt.Errorf("error fetching api tokens from db: %w", result.Error))
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+// GetApiToken gets a specific ApiToken
+// @Summary      Get an ApiToken
+// @Description  Gets an ApiToken by ApiToken ID
+// @Id           GetApiToken
+// @Tags         Token
+// @Accept       json
+// @Produce      json
+// @Param		 id   path      string true "ApiToken ID"
+// @Success      200  {object}  models.ApiToken
+// @Failure      400  {object}  models.BaseError
+// @Failure		 401  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/tokens/{id} [get]
+func (api *API) GetApiToken(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "GetApiToken",
+		trace.WithAttributes(
+			attribute.String("id", c.Param("id")),
+		))
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	var record models.ApiToken
+	db := api.db.WithContext(ctx)
+	result := api.ApiTokenIsForCurrentUserOrOrgOwner(c, db).
+		First(&record, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.NewNotFoundError("api token"))
+		} else {
+			api.SendInternalServerError(c, result.Error)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// DeleteApiToken handles revoking an ApiToken
+// @Summary      Revoke ApiToken
+// @Description  Revokes an existing ApiToken
+// @Id           DeleteApiToken
+// @Tags         Token
+// @Accept		 json
+// @Produce      json
+// @Param		 id   path      string true "ApiToken ID"
+// @Success      200  {object}  models.ApiToken
+// @Failure      400  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/tokens/{id} [delete]
+func (api *API) DeleteApiToken(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "DeleteApiToken",
+		trace.WithAttributes(
+			attribute.String("id", c.Param("id")),
+		))
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	var record models.ApiToken
+	err = api.transaction(ctx, func(tx *gorm.DB) error {
+		res := api.ApiTokenIsForCurrentUserOrOrgOwner(c, tx).
+			First(&record, "id = ?", id)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		res = tx.Delete(&models.ApiToken{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		return nil
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.NewNotFoundError("api token"))
+		return
+	} else if err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/models"
+	"github.com/nexodus-io/nexodus/internal/util/cache"
+	"gorm.io/gorm"
+)
+
+func (suite *HandlerTestSuite) TestApiTokens() {
+	require := suite.Require()
+	api := suite.api
+	db := api.db
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(err)
+	// the revoked tokens are checked right away instead of after the cache expires
+	url, key, tokens := api.URL, api.PrivateKey, api.apiTokens
+	api.URL, api.PrivateKey, api.apiTokens = "https://api.example.com", privateKey, cache.NewMemoizeCache[string, uuid.UUID](0, 0)
+	defer func() {
+		api.URL, api.PrivateKey, api.apiTokens = url, key, tokens
+	}()
+
+	owner := models.User{Base: models.Base{ID: uuid.New()}, IdpID: uuid.NewString(), UserName: "owner"}
+	require.NoError(db.Create(&owner).Error)
+	org := models.Organization{Name: "automation"}
+	require.NoError(db.Create(&org).Error)
+	require.NoError(db.Create(&models.UserOrganization{UserID: owner.ID, OrganizationID: org.ID, Roles: []string{"owner"}}).Error)
+
+	serve := func(handler gin.HandlerFunc, userID uuid.UUID, body interface{}, params ...gin.Param) *httptest.ResponseRecorder {
+		path, uri := "/", "/"
+		if len(params) > 0 {
+			path, uri = "/:id", "/"+params[0].Value
+		}
+		_, res, err := suite.ServeRequestAsUser(userID, http.MethodPost, path, uri, handler, bytes.NewBuffer(suite.jsonMarshal(body)))
+		require.NoError(err)
+		return res
+	}
+
+	res := serve(api.CreateServiceAccount, owner.ID, models.AddServiceAccount{OrganizationID: org.ID, Name: "ci", Roles: []string{"admin"}})
+	require.Equal(http.StatusBadRequest, res.Code)
+
+	res = serve(api.CreateServiceAccount, owner.ID, models.AddServiceAccount{OrganizationID: org.ID, Name: "ci"})
+	require.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var serviceAccount models.ServiceAccount
+	require.NoError(json.Unmarshal(res.Body.Bytes(), &serviceAccount))
+	require.Equal(models.StringArray{"member"}, serviceAccount.Roles)
+
+	res = serve(api.CreateServiceAccount, owner.ID, models.AddServiceAccount{OrganizationID: org.ID, Name: "ci"})
+	require.Equal(http.StatusConflict, res.Code)
+
+	// the service account is a member of the organization
+	var membership models.UserOrganization
+	require.NoError(db.First(&membership, "user_id = ? AND organization_id = ?", serviceAccount.UserID, org.ID).Error)
+
+	res = serve(api.CreateApiToken, owner.ID, models.AddApiToken{ServiceAccountID: &serviceAccount.ID, Scopes: []string{"reg-token"}})
+	require.Equal(http.StatusBadRequest, res.Code)
+
+	// only the owners of the organization can create tokens for its service accounts
+	res = serve(api.CreateApiToken, serviceAccount.UserID, models.AddApiToken{ServiceAccountID: &serviceAccount.ID, Scopes: []string{"read:devices"}})
+	require.Equal(http.StatusNotFound, res.Code)
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	res = serve(api.CreateApiToken, owner.ID, models.AddApiToken{ServiceAccountID: &serviceAccount.ID, Scopes: []string{"read:devices", "write:organizations"}, ExpiresAt: &expiresAt})
+	require.Equal(http.StatusCreated, res.Code, res.Body.String())
+	var token models.ApiToken
+	require.NoError(json.Unmarshal(res.Body.Bytes(), &token))
+	require.Equal(serviceAccount.UserID, token.UserID)
+	require.Equal(owner.ID, token.OwnerID)
+	require.Equal(&org.ID, token.OrganizationID)
+
+	claims := models.NexodusClaims{}
+	_, err = jwt.ParseWithClaims(token.AccessToken, &claims, func(*jwt.Token) (interface{}, error) {
+		return &privateKey.PublicKey, nil
+	})
+	require.NoError(err)
+	require.Equal(token.ID.String(), claims.ID)
+	require.Equal("service-account:"+serviceAccount.ID.String(), claims.Subject)
+	require.Equal("api-token read:devices write:organizations", claims.Scope)
+	require.Equal(expiresAt.Unix(), claims.ExpiresAt.Unix())
+
+	userID, err := api.ApiTokenUserID(context.Background(), claims.ID)
+	require.NoError(err)
+	require.Equal(serviceAccount.UserID, userID)
+
+	// the owner sees the token of the service account, the token is not returned again
+	res = serve(api.GetApiToken, owner.ID, nil, gin.Param{Key: "id", Value: token.ID.String()})
+	require.Equal(http.StatusOK, res.Code)
+	var fetched models.ApiToken
+	require.NoError(json.Unmarshal(res.Body.Bytes(), &fetched))
+	require.Empty(fetched.AccessToken)
+
+	res = serve(api.DeleteApiToken, owner.ID, nil, gin.Param{Key: "id", Value: token.ID.String()})
+	require.Equal(http.StatusOK, res.Code)
+	_, err = api.ApiTokenUserID(context.Background(), claims.ID)
+	var apiResponseError *ApiResponseError
+	require.ErrorAs(err, &apiResponseError)
+	require.Equal(http.StatusUnauthorized, apiResponseError.Status)
+
+	// deleting the service account revokes its tokens and removes its membership
+	res = serve(api.CreateApiToken, owner.ID, models.AddApiToken{ServiceAccountID: &serviceAccount.ID, Scopes: []string{"read:devices"}})
+	require.Equal(http.StatusCreated, res.Code)
+	require.NoError(json.Unmarshal(res.Body.Bytes(), &token))
+	res = serve(api.DeleteServiceAccount, owner.ID, nil, gin.Param{Key: "id", Value: serviceAccount.ID.String()})
+	require.Equal(http.StatusOK, res.Code)
+	_, err = api.ApiTokenUserID(context.Background(), token.ID.String())
+	require.ErrorAs(err, &apiResponseError)
+	require.ErrorIs(db.First(&membership, "user_id = ?", serviceAccount.UserID).Error, gorm.ErrRecordNotFound)
+}
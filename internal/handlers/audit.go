@@ -0,0 +1,667 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/database"
+	"github.com/nexodus-io/nexodus/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	auditActorUser     = "user"
+	auditActorRegKey   = "reg-key"
+	auditActorDevice   = "device"
+	auditActorApiToken = "api-token"
+
+	auditCallback         = "nexodus:audit"
+	auditSnapshotCallback = "nexodus:audit_snapshot"
+
+	// auditVerifyBatchSize is the number of entries loaded at a time when verifying a chain
+	auditVerifyBatchSize = 500
+)
+
+// auditRedactedFields are secrets, the audit log only records that they changed
+var auditRedactedFields = map[string]bool{
+	"bearer_token": true,
+	"link_secret":  true,
+}
+
+// auditIgnoredFields are left out of the diffs, the revision is bumped by the database on every write
+var auditIgnoredFields = map[string]bool{
+	"revision": true,
+}
+
+var (
+	auditEntryType = reflect.TypeOf(models.AuditEntry{})
+	auditHeadType  = reflect.TypeOf(models.AuditHead{})
+)
+
+type auditActorKey struct{}
+
+// auditActor is who makes the changes of a request, it is carried by the request context down to the database callbacks
+type auditActor struct {
+	kind     string
+	id       uuid.UUID
+	userID   uuid.UUID
+	sourceIP string
+	method   string
+	path     string
+}
+
+// AuditMutations attaches the actor of the POST, PATCH, PUT and DELETE requests to their context. The changes the
+// handlers then make to the database are recorded in the audit log by the audit callbacks.
+func (api *API) AuditMutations(c *gin.Context) {
+	switch c.Request.Method {
+	case http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete:
+	default:
+		c.Next()
+		return
+	}
+
+	userID, _ := c.Get(gin.AuthUserKey)
+	actor := &auditActor{
+		kind:     auditActorUser,
+		sourceIP: c.ClientIP(),
+		method:   c.Request.Method,
+		path:     c.Request.URL.Path,
+	}
+	if id, ok := userID.(uuid.UUID); ok {
+		actor.id = id
+		actor.userID = id
+	}
+	if claims, err := NxodusClaims(c, nil); err == nil {
+		switch claims.Scope {
+		case "reg-token":
+			actor.kind = auditActorRegKey
+			actor.id, _ = uuid.Parse(claims.ID)
+		case "device-token":
+			actor.kind = auditActorDevice
+			actor.id, _ = uuid.Parse(claims.ID)
+		default:
+			if strings.HasPrefix(claims.Scope, ApiTokenScope+" ") {
+				actor.kind = auditActorApiToken
+				actor.id, _ = uuid.Parse(claims.ID)
+			}
+		}
+	}
+
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), auditActorKey{}, actor))
+	c.Next()
+}
+
+// registerAuditCallbacks records the changes made by the statements of the audited requests. Updates and deletes
+// snapshot the rows they change first. The entries are written in the transaction of the change, so a change is
+// never committed without its entry.
+func (api *API) registerAuditCallbacks() error {
+	callbacks := api.db.Callback()
+	if callbacks.Create().Get(auditCallback) != nil {
+		return nil
+	}
+	if err := callbacks.Create().After("gorm:create").Register(auditCallback, api.auditCreate); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register(auditSnapshotCallback, api.auditSnapshot); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register(auditCallback, api.auditUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register(auditSnapshotCallback, api.auditSnapshot); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Register(auditCallback, api.auditDelete)
+}
+
+// auditActorOf returns the actor of the statement, or nil if the statement is not audited
+func auditActorOf(db *gorm.DB) *auditActor {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || stmt.Context == nil {
+		return nil
+	}
+	if stmt.Schema.ModelType == auditEntryType || stmt.Schema.ModelType == auditHeadType {
+		return nil
+	}
+	actor, _ := stmt.Context.Value(auditActorKey{}).(*auditActor)
+	return actor
+}
+
+func (api *API) auditCreate(db *gorm.DB) {
+	actor := auditActorOf(db)
+	if actor == nil {
+		return
+	}
+	rows := reflect.Indirect(db.Statement.ReflectValue)
+	switch rows.Kind() {
+	case reflect.Struct:
+		api.auditRecord(db, actor, "create", reflect.Value{}, rows)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rows.Len(); i++ {
+			api.auditRecord(db, actor, "create", reflect.Value{}, reflect.Indirect(rows.Index(i)))
+		}
+	}
+}
+
+func (api *API) auditSnapshot(db *gorm.DB) {
+	if auditActorOf(db) == nil {
+		return
+	}
+	stmt := db.Statement
+	var conditions []clause.Expression
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			conditions = append(conditions, where.Exprs...)
+		}
+	}
+	if condition := auditPrimaryKeyCondition(db, stmt.ReflectValue); condition != nil {
+		conditions = append(conditions, condition)
+	}
+	if len(conditions) == 0 {
+		// gorm refuses updates and deletes without conditions
+		return
+	}
+	rows, err := auditLoadRows(db, stmt.Unscoped, conditions)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+	db.InstanceSet(auditSnapshotCallback, rows)
+}
+
+func (api *API) auditUpdate(db *gorm.DB) {
+	actor := auditActorOf(db)
+	if actor == nil {
+		return
+	}
+	before, ok := auditSnapshotOf(db)
+	if !ok {
+		return
+	}
+	after, err := auditLoadRows(db, true, []clause.Expression{auditPrimaryKeyCondition(db, before)})
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+	updated := map[string]reflect.Value{}
+	for i := 0; i < after.Len(); i++ {
+		updated[auditResourceID(db, after.Index(i))] = after.Index(i)
+	}
+	for i := 0; i < before.Len(); i++ {
+		row := before.Index(i)
+		api.auditRecord(db, actor, "update", row, updated[auditResourceID(db, row)])
+	}
+}
+
+func (api *API) auditDelete(db *gorm.DB) {
+	actor := auditActorOf(db)
+	if actor == nil {
+		return
+	}
+	before, ok := auditSnapshotOf(db)
+	if !ok {
+		return
+	}
+	for i := 0; i < before.Len(); i++ {
+		api.auditRecord(db, actor, "delete", before.Index(i), reflect.Value{})
+	}
+}
+
+func auditSnapshotOf(db *gorm.DB) (reflect.Value, bool) {
+	value, ok := db.InstanceGet(auditSnapshotCallback)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	rows := value.(reflect.Value)
+	return rows, rows.Len() > 0
+}
+
+// auditPrimaryKeyCondition matches the primary keys of the rows of value, it returns nil when the keys are not set
+func auditPrimaryKeyCondition(db *gorm.DB, value reflect.Value) clause.Expression {
+	stmt := db.Statement
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array:
+	default:
+		return nil
+	}
+	_, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, value, stmt.Schema.PrimaryFields)
+	column, values := schema.ToQueryValues(stmt.Schema.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+	if len(values) == 0 {
+		return nil
+	}
+	return clause.IN{Column: column, Values: values}
+}
+
+// auditLoadRows loads the rows of the statement's model matching the conditions, in the transaction of the statement
+func auditLoadRows(db *gorm.DB, unscoped bool, conditions []clause.Expression) (reflect.Value, error) {
+	modelType := db.Statement.Schema.ModelType
+	rows := reflect.New(reflect.SliceOf(modelType))
+	tx := db.Session(&gorm.Session{NewDB: true}).Model(reflect.New(modelType).Interface())
+	if unscoped {
+		tx = tx.Unscoped()
+	}
+	if err := tx.Clauses(clause.Where{Exprs: conditions}).Find(rows.Interface()).Error; err != nil {
+		return reflect.Value{}, err
+	}
+	return rows.Elem(), nil
+}
+
+// auditRecord appends the change of a row to the audit log, unchanged rows are skipped
+func (api *API) auditRecord(db *gorm.DB, actor *auditActor, action string, before reflect.Value, after reflect.Value) {
+	diff, err := auditDiff(before, after)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+	if diff == nil {
+		return
+	}
+	row := after
+	if !row.IsValid() {
+		row = before
+	}
+	entry := models.AuditEntry{
+		ID:             uuid.New(),
+		OrganizationID: auditOrganizationID(db, row),
+		CreatedAt:      time.Now().UTC().Truncate(time.Microsecond),
+		ActorKind:      actor.kind,
+		ActorID:        actor.id,
+		UserID:         actor.userID,
+		SourceIP:       actor.sourceIP,
+		Method:         actor.method,
+		Path:           actor.path,
+		Action:         action,
+		ResourceKind:   db.Statement.Schema.Table,
+		ResourceID:     auditResourceID(db, row),
+		Diff:           diff,
+	}
+	if err := api.auditAppend(db.Session(&gorm.Session{NewDB: true}), &entry); err != nil {
+		_ = db.AddError(fmt.Errorf("failed to record the audit entry: %w", err))
+	}
+}
+
+// auditAppend links the entry to the head of the chain of its organization and stores it as the new head
+func (api *API) auditAppend(tx *gorm.DB, entry *models.AuditEntry) error {
+	key, err := api.auditKey()
+	if err != nil {
+		return err
+	}
+	if api.dialect == database.DialectPostgreSQL {
+		// serializes the appends to the chain of the organization until the transaction ends,
+		// sqlite serializes all the writes and CockroachDB retries the conflicting transactions
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", entry.OrganizationID.String()).Error; err != nil {
+			return err
+		}
+	}
+	head := models.AuditHead{OrganizationID: entry.OrganizationID}
+	if err := tx.Where("organization_id = ?", entry.OrganizationID).Limit(1).Find(&head).Error; err != nil {
+		return err
+	}
+	entry.Sequence = head.Sequence + 1
+	entry.PrevHash = head.Hash
+	entry.Hash, err = auditEntryHash(key, entry)
+	if err != nil {
+		return err
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return err
+	}
+	head.Sequence = entry.Sequence
+	head.Hash = entry.Hash
+	return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&head).Error
+}
+
+// auditDiff maps the fields that differ between the JSON representations of the rows to their values,
+// an invalid row is a resource that does not exist. It returns nil when nothing changed.
+func auditDiff(before reflect.Value, after reflect.Value) (json.RawMessage, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+	diff := map[string]models.AuditChange{}
+	for name, value := range beforeFields {
+		if afterValue, found := afterFields[name]; !found || !reflect.DeepEqual(value, afterValue) {
+			diff[name] = models.AuditChange{Before: value, After: afterValue}
+		}
+	}
+	for name, value := range afterFields {
+		if _, found := beforeFields[name]; !found {
+			diff[name] = models.AuditChange{After: value}
+		}
+	}
+	if len(diff) == 0 {
+		return nil, nil
+	}
+	for name, change := range diff {
+		if auditRedactedFields[name] {
+			if change.Before != nil {
+				change.Before = "<redacted>"
+			}
+			if change.After != nil {
+				change.After = "<redacted>"
+			}
+			diff[name] = change
+		}
+	}
+	return json.Marshal(diff)
+}
+
+func auditFields(row reflect.Value) (map[string]interface{}, error) {
+	if !row.IsValid() {
+		return nil, nil
+	}
+	data, err := json.Marshal(row.Interface())
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	for name := range auditIgnoredFields {
+		delete(fields, name)
+	}
+	return fields, nil
+}
+
+// auditOrganizationID returns the organization of a row, from its organization field or the one of its device
+func auditOrganizationID(db *gorm.DB, row reflect.Value) uuid.UUID {
+	stmt := db.Statement
+	fields := []string{"OrganizationID", "SNOrganizationID"}
+	if stmt.Schema.ModelType == reflect.TypeOf(models.Organization{}) {
+		fields = []string{"ID"}
+	}
+	for _, name := range fields {
+		if id := auditUUIDField(db, row, name); id != uuid.Nil {
+			return id
+		}
+	}
+	if deviceID := auditUUIDField(db, row, "DeviceID"); deviceID != uuid.Nil {
+		var device models.Device
+		if db.Session(&gorm.Session{NewDB: true}).Unscoped().Select("organization_id").Limit(1).Find(&device, "id = ?", deviceID).Error == nil {
+			return device.OrganizationID
+		}
+	}
+	return uuid.Nil
+}
+
+func auditUUIDField(db *gorm.DB, row reflect.Value, name string) uuid.UUID {
+	field := db.Statement.Schema.LookUpField(name)
+	if field == nil {
+		return uuid.Nil
+	}
+	value, zero := field.ValueOf(db.Statement.Context, row)
+	if zero {
+		return uuid.Nil
+	}
+	switch id := value.(type) {
+	case uuid.UUID:
+		return id
+	case *uuid.UUID:
+		return *id
+	}
+	return uuid.Nil
+}
+
+// auditResourceID returns the primary key of a row, the fields of composite keys are joined with '/'
+func auditResourceID(db *gorm.DB, row reflect.Value) string {
+	var parts []string
+	for _, field := range db.Statement.Schema.PrimaryFields {
+		value, _ := field.ValueOf(db.Statement.Context, row)
+		parts = append(parts, fmt.Sprint(value))
+	}
+	return strings.Join(parts, "/")
+}
+
+// auditKey is the HMAC key of the audit entries. It is derived from the private key of the api server, so that
+// having access to the database is not enough to rewrite the entries and compute their hashes again.
+func (api *API) auditKey() ([]byte, error) {
+	if api.PrivateKey == nil {
+		return nil, fmt.Errorf("the api server has no private key to sign the audit entries with")
+	}
+	mac := hmac.New(sha256.New, x509.MarshalPKCS1PrivateKey(api.PrivateKey))
+	mac.Write([]byte("nexodus audit log"))
+	return mac.Sum(nil), nil
+}
+
+// auditEntryHash computes the HMAC of the fields of the entry along with the hash of the previous entry. The diff
+// is hashed in a canonical form, since the database is free to reformat the JSON it stores.
+func auditEntryHash(key []byte, entry *models.AuditEntry) (string, error) {
+	diff, err := canonicalJSON(entry.Diff)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(struct {
+		PrevHash       string          `json:"prev_hash"`
+		OrganizationID uuid.UUID       `json:"organization_id"`
+		Sequence       int64           `json:"sequence"`
+		CreatedAt      string          `json:"created_at"`
+		ActorKind      string          `json:"actor_kind"`
+		ActorID        uuid.UUID       `json:"actor_id"`
+		UserID         uuid.UUID       `json:"user_id"`
+		SourceIP       string          `json:"source_ip"`
+		Method         string          `json:"method"`
+		Path           string          `json:"path"`
+		Action         string          `json:"action"`
+		ResourceKind   string          `json:"resource_kind"`
+		ResourceID     string          `json:"resource_id"`
+		Diff           json.RawMessage `json:"diff"`
+	}{
+		PrevHash:       entry.PrevHash,
+		OrganizationID: entry.OrganizationID,
+		Sequence:       entry.Sequence,
+		CreatedAt:      entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		ActorKind:      entry.ActorKind,
+		ActorID:        entry.ActorID,
+		UserID:         entry.UserID,
+		SourceIP:       entry.SourceIP,
+		Method:         entry.Method,
+		Path:           entry.Path,
+		Action:         entry.Action,
+		ResourceKind:   entry.ResourceKind,
+		ResourceID:     entry.ResourceID,
+		Diff:           diff,
+	})
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// canonicalJSON re-encodes JSON with sorted keys and no spaces, numbers are kept as written
+func canonicalJSON(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return []byte("null"), nil
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// verifyAuditChain checks the entries of the organization in sequence order up to its head, and stops at the first
+// one that does not match the chain
+func verifyAuditChain(db *gorm.DB, key []byte, orgID uuid.UUID) (models.AuditVerification, error) {
+	var head models.AuditHead
+	if err := db.Where("organization_id = ?", orgID).Limit(1).Find(&head).Error; err != nil {
+		return models.AuditVerification{}, err
+	}
+	result := models.AuditVerification{Verified: true, HeadSequence: head.Sequence, HeadHash: head.Hash}
+	invalid := func(sequence int64, err string) models.AuditVerification {
+		result.Verified = false
+		result.InvalidSequence = &sequence
+		result.Error = err
+		return result
+	}
+
+	prevHash := ""
+	var sequence int64
+	for {
+		// the entries appended after the head was loaded are left for the next check
+		var entries []models.AuditEntry
+		err := db.Where("organization_id = ? AND sequence > ? AND sequence <= ?", orgID, sequence, head.Sequence).
+			Order("sequence").
+			Limit(auditVerifyBatchSize).
+			Find(&entries).Error
+		if err != nil {
+			return result, err
+		}
+		for _, entry := range entries {
+			sequence++
+			if entry.Sequence != sequence {
+				return invalid(sequence, "entry is missing"), nil
+			}
+			if entry.PrevHash != prevHash {
+				return invalid(sequence, "previous hash does not match"), nil
+			}
+			hash, err := auditEntryHash(key, &entry)
+			if err != nil {
+				return result, err
+			}
+			if hash != entry.Hash {
+				return invalid(sequence, "hash does not match"), nil
+			}
+			result.Entries++
+			prevHash = entry.Hash
+		}
+		if len(entries) < auditVerifyBatchSize {
+			break
+		}
+	}
+	if sequence < head.Sequence {
+		return invalid(sequence+1, "entry is missing"), nil
+	}
+	if prevHash != head.Hash {
+		return invalid(sequence, "hash does not match the head"), nil
+	}
+	return result, nil
+}
+
+// ListOrganizationAudit lists the audit log of an organization
+// @Summary      List Organization Audit Entries
+// @Description  Lists the changes made to the resources of an organization, in the order they were made
+// @Id 			 ListOrganizationAudit
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param		 id   path      string true "Organization ID"
+// @Success      200  {object}  []models.AuditEntry
+// @Failure      400  {object}  models.BaseError
+// @Failure		 401  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/organizations/{id}/audit [get]
+func (api *API) ListOrganizationAudit(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ListOrganizationAudit",
+		trace.WithAttributes(
+			attribute.String("id", c.Param("id")),
+		))
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	owner, err := api.IsOwnerOfOrg(c, id)
+	if err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+	if !owner {
+		c.JSON(http.StatusNotFound, models.NewNotFoundError("organization"))
+		return
+	}
+
+	db := api.db.WithContext(ctx)
+	db = db.Where("organization_id = ?", id)
+	db = FilterAndPaginate(db, &models.AuditEntry{}, c, "sequence")
+
+	entries := []models.AuditEntry{}
+	if result := db.Find(&entries); result.Error != nil {
+		api.SendInternalServerError(c, result.Error)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// VerifyOrganizationAudit verifies the hash chain of the audit log of an organization up to its head
+// @Summary      Verify Organization Audit Entries
+// @Description  Checks that the audit entries of an organization were not changed or removed
+// @Id 			 VerifyOrganizationAudit
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param		 id   path      string true "Organization ID"
+// @Success      200  {object}  models.AuditVerification
+// @Failure      400  {object}  models.BaseError
+// @Failure		 401  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/organizations/{id}/audit/verify [get]
+func (api *API) VerifyOrganizationAudit(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "VerifyOrganizationAudit",
+		trace.WithAttributes(
+			attribute.String("id", c.Param("id")),
+		))
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	owner, err := api.IsOwnerOfOrg(c, id)
+	if err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+	if !owner {
+		c.JSON(http.StatusNotFound, models.NewNotFoundError("organization"))
+		return
+	}
+
+	key, err := api.auditKey()
+	if err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+	result, err := verifyAuditChain(api.db.WithContext(ctx), key, id)
+	if err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/models"
+)
+
+func (suite *HandlerTestSuite) TestAuditLog() {
+	require := suite.Require()
+	db := suite.api.db
+
+	orgID := uuid.New()
+	userID := uuid.New()
+	actor := &auditActor{kind: auditActorUser, id: userID, userID: userID, sourceIP: "192.0.2.10", method: "PATCH", path: "/api/vpcs"}
+	audited := db.WithContext(context.WithValue(context.Background(), auditActorKey{}, actor))
+
+	// changes made outside of the audited requests are not recorded
+	vpc := models.VPC{OrganizationID: orgID, Description: "unaudited", Ipv4Cidr: "100.64.0.0/10"}
+	require.NoError(db.Create(&vpc).Error)
+
+	require.NoError(audited.Model(&vpc).Update("description", "blue").Error)
+	require.NoError(audited.Model(&vpc).Update("description", "blue").Error)
+	key := models.RegKey{OrganizationID: &orgID, VpcID: &vpc.ID, BearerToken: "RK:secret", SecurityGroupIds: models.StringArray{}}
+	require.NoError(audited.Create(&key).Error)
+	require.NoError(audited.Where("organization_id = ?", orgID).Delete(&models.RegKey{}).Error)
+
+	var entries []models.AuditEntry
+	require.NoError(db.Where("organization_id = ?", orgID).Order("sequence").Find(&entries).Error)
+	require.Len(entries, 3)
+
+	require.Equal("update", entries[0].Action)
+	require.Equal("vpcs", entries[0].ResourceKind)
+	require.Equal(vpc.ID.String(), entries[0].ResourceID)
+	require.Equal(auditActorUser, entries[0].ActorKind)
+	require.Equal(userID, entries[0].ActorID)
+	require.Equal("192.0.2.10", entries[0].SourceIP)
+	require.JSONEq(`{"description":{"before":"unaudited","after":"blue"}}`, string(entries[0].Diff))
+	require.Equal("", entries[0].PrevHash)
+
+	require.Equal("create", entries[1].Action)
+	require.Equal(key.ID.String(), entries[1].ResourceID)
+	diff := map[string]models.AuditChange{}
+	require.NoError(json.Unmarshal(entries[1].Diff, &diff))
+	require.Equal(models.AuditChange{After: "<redacted>"}, diff["bearer_token"])
+	require.Equal(entries[0].Hash, entries[1].PrevHash)
+
+	require.Equal("delete", entries[2].Action)
+	require.Equal(key.ID.String(), entries[2].ResourceID)
+	require.Equal(int64(3), entries[2].Sequence)
+
+	hmacKey, err := suite.api.auditKey()
+	require.NoError(err)
+	result, err := verifyAuditChain(db, hmacKey, orgID)
+	require.NoError(err)
+	require.Equal(models.AuditVerification{Verified: true, Entries: 3, HeadSequence: 3, HeadHash: entries[2].Hash}, result)
+
+	// removing the last entries breaks the chain at the head, which is stored apart from the entries
+	require.NoError(db.Delete(&entries[2]).Error)
+	result, err = verifyAuditChain(db, hmacKey, orgID)
+	require.NoError(err)
+	require.False(result.Verified)
+	require.Equal(int64(2), result.Entries)
+	require.Equal(int64(3), *result.InvalidSequence)
+	require.Equal("entry is missing", result.Error)
+	require.NoError(db.Create(&entries[2]).Error)
+
+	// the hashes can't be computed again without the key of the api server
+	forged := entries[2]
+	forged.SourceIP = "198.51.100.7"
+	forged.Hash, err = auditEntryHash([]byte("guessed"), &forged)
+	require.NoError(err)
+	require.NoError(db.Save(&forged).Error)
+	require.NoError(db.Model(&models.AuditHead{}).Where("organization_id = ?", orgID).Update("hash", forged.Hash).Error)
+	result, err = verifyAuditChain(db, hmacKey, orgID)
+	require.NoError(err)
+	require.False(result.Verified)
+	require.Equal(int64(3), *result.InvalidSequence)
+	require.Equal("hash does not match", result.Error)
+	require.NoError(db.Save(&entries[2]).Error)
+	require.NoError(db.Model(&models.AuditHead{}).Where("organization_id = ?", orgID).Update("hash", entries[2].Hash).Error)
+
+	// rewriting an entry breaks the chain at that entry
+	require.NoError(db.Model(&entries[1]).Update("source_ip", "198.51.100.7").Error)
+	result, err = verifyAuditChain(db, hmacKey, orgID)
+	require.NoError(err)
+	require.False(result.Verified)
+	require.Equal(int64(1), result.Entries)
+	require.Equal(int64(2), *result.InvalidSequence)
+	require.Equal("hash does not match", result.Error)
+
+	// so does removing one
+	require.NoError(db.Delete(&entries[1]).Error)
+	result, err = verifyAuditChain(db, hmacKey, orgID)
+	require.NoError(err)
+	require.False(result.Verified)
+	require.Equal("entry is missing", result.Error)
+}
@@ -124,6 +124,16 @@ func (api *API) DeviceIsOwnedByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB
 	return db.Where("owner_id = ?", userId)
 }
 
+// DeviceIsManagedByCurrentUser matches the devices of the current user, and the devices of the organizations
+// where the current user is a device operator.
+func (api *API) DeviceIsManagedByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
+	userId := api.GetCurrentUserID(c)
+	return db.Where(
+		db.Where("owner_id = ?", userId).
+			Or(api.CurrentUserHasRole(c, db, "organization_id", DeviceOperatorRoles)),
+	)
+}
+
 // GetDevice gets a device by ID
 // @Summary      Get Devices
 // @Description  Gets a device by ID
@@ -157,7 +167,7 @@ func (api *API) GetDevice(c *gin.Context) {
 	var device models.Device
 
 	db := api.db.WithContext(ctx)
-	db = api.DeviceIsOwnedByCurrentUser(c, db)
+	db = api.DeviceIsManagedByCurrentUser(c, db)
 	result := db.First(&device, "id = ?", k)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		c.Status(http.StatusNotFound)
@@ -213,12 +223,24 @@ func (api *API) UpdateDevice(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, models.NewBadPayloadError(err))
 		return
 	}
+	if request.ExitNodePolicy != nil {
+		if field, err := validateExitNodePolicy(*request.ExitNodePolicy); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewFieldValidationError(field, err.Error()))
+			return
+		}
+	}
+	if request.TrafficLimits != nil {
+		if field, err := validateTrafficLimits(*request.TrafficLimits); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewFieldValidationError(field, err.Error()))
+			return
+		}
+	}
 
 	var device models.Device
 	var tokenClaims *models.NexodusClaims
 	err = api.transaction(ctx, func(tx *gorm.DB) error {
 
-		db := api.DeviceIsOwnedByCurrentUser(c, tx)
+		db := api.DeviceIsManagedByCurrentUser(c, tx)
 		db = FilterAndPaginate(db, &models.Device{}, c, "hostname")
 
 		result := db.First(&device, "id = ?", deviceId)
@@ -243,6 +265,10 @@ func (api *API) UpdateDevice(c *gin.Context) {
 					return NewApiResponseError(http.StatusForbidden, models.NewApiError(errors.New("reg key does not have access")))
 				}
 			}
+			// a device must not be able to lift its own limits
+			if request.TrafficLimits != nil && (tokenClaims.Scope == "reg-token" || tokenClaims.Scope == "device-token") {
+				return NewApiResponseError(http.StatusForbidden, models.NewApiError(errors.New("traffic limits can only be changed by users")))
+			}
 		}
 
 		var vpc models.VPC
@@ -268,7 +294,7 @@ func (api *API) UpdateDevice(c *gin.Context) {
 		if request.VpcID != nil && *request.VpcID != device.OrganizationID {
 
 			var newVpc models.VPC
-			if result := api.VPCIsReadableByCurrentUser(c, tx).
+			if result := api.VPCIsJoinableByCurrentUser(c, tx).
 				Preload("Organization").
 				First(&newVpc, "id = ?", request.VpcID); result.Error != nil {
 				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("vpc_id"))
@@ -337,13 +363,29 @@ func (api *API) UpdateDevice(c *gin.Context) {
 			device.Relay = *request.Relay
 		}
 
-		if request.SecurityGroupId != nil {
-			var sg models.SecurityGroup
-			if result := api.SecurityGroupIsReadableByCurrentUser(c, tx).
-				First(&sg, "id = ?", *request.SecurityGroupId); result.Error != nil {
-				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("security_group_id"))
+		if request.ExitNodePolicy != nil {
+			device.ExitNodePolicy = request.ExitNodePolicy
+			if len(request.ExitNodePolicy.Include) == 0 && len(request.ExitNodePolicy.Exclude) == 0 {
+				device.ExitNodePolicy = nil
+			}
+		}
+
+		if request.TrafficLimits != nil {
+			device.TrafficLimits = request.TrafficLimits
+			if *request.TrafficLimits == (models.TrafficLimits{}) {
+				device.TrafficLimits = nil
+			}
+		}
+
+		if ids, field, ok := requestedSecurityGroupIds(request.SecurityGroupIds, request.SecurityGroupId); ok {
+			device.SecurityGroupIds, err = api.lookupSecurityGroupIds(c, tx, device.VpcID, field, ids)
+			if err != nil {
+				return err
+			}
+			device.SecurityGroupId = uuid.Nil
+			if id := firstSecurityGroupId(device.SecurityGroupIds); id != nil {
+				device.SecurityGroupId = *id
 			}
-			device.SecurityGroupId = *request.SecurityGroupId
 		}
 
 		// check if the updated device advertised CIDRs match the existing device advertised CIDRs
@@ -400,7 +442,7 @@ func (api *API) UpdateDevice(c *gin.Context) {
 
 	hideDeviceBearerToken(&device, tokenClaims, api.GetCurrentUserID(c))
 
-	api.signalBus.Notify(fmt.Sprintf("/devices/vpc=%s", device.VpcID.String()))
+	api.notifyDeviceChange(c, device)
 	c.JSON(http.StatusOK, device)
 }
 
@@ -444,6 +486,7 @@ func getAllowedIPs(ip string, ip6 string, relay bool) ([]string, error) {
 // @Success      201  {object}  models.Device
 // @Failure      400  {object}  models.BaseError
 // @Failure		 401  {object}  models.BaseError
+// @Failure      403  {object}  models.QuotaExceededError
 // @Failure      409  {object}  models.ConflictsError
 // @Failure		 429  {object}  models.BaseError
 // @Failure      500  {object}  models.InternalServerError "Internal Server Error"
@@ -478,7 +521,7 @@ func (api *API) CreateDevice(c *gin.Context) {
 	err := api.transaction(ctx, func(tx *gorm.DB) error {
 
 		var vpc models.VPC
-		if result := api.VPCIsReadableByCurrentUser(c, tx).
+		if result := api.VPCIsJoinableByCurrentUser(c, tx).
 			Preload("Organization").
 			First(&vpc, "id = ?", request.VpcID); result.Error != nil {
 			return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("vpc"))
@@ -492,6 +535,10 @@ func (api *API) CreateDevice(c *gin.Context) {
 			return res.Error
 		}
 
+		if err := api.checkQuota(tx, vpc.OrganizationID, deviceQuota); err != nil {
+			return err
+		}
+
 		var err2 *ApiResponseError
 		tokenClaims, err2 = NxodusClaims(c, tx)
 		if err2 != nil {
@@ -584,6 +631,29 @@ func (api *API) CreateDevice(c *gin.Context) {
 			return err
 		}
 
+		// devices join the default security group of the vpc unless the request or the reg key used
+		// to register them picks their security groups.
+		securityGroupIds := models.StringArray{vpc.ID.String()}
+		var requestedSecurityGroupId *uuid.UUID
+		if request.SecurityGroupId != uuid.Nil {
+			requestedSecurityGroupId = &request.SecurityGroupId
+		}
+		if ids, field, ok := requestedSecurityGroupIds(request.SecurityGroupIds, requestedSecurityGroupId); ok {
+			securityGroupIds, err = api.lookupSecurityGroupIds(c, tx, vpc.ID, field, ids)
+			if err != nil {
+				return err
+			}
+		} else if regKeyID != uuid.Nil {
+			var regKey models.RegKey
+			if res := tx.First(&regKey, "id = ?", regKeyID); res.Error == nil && len(regKey.SecurityGroupIds) > 0 {
+				securityGroupIds = regKey.SecurityGroupIds
+			}
+		}
+		securityGroupId := uuid.Nil
+		if id := firstSecurityGroupId(securityGroupIds); id != nil {
+			securityGroupId = *id
+		}
+
 		// lets use a wg private key as the token, since it should be hard to guess.
 		deviceToken, err := wgtypes.GeneratePrivateKey()
 		if err != nil {
@@ -612,14 +682,15 @@ func (api *API) CreateDevice(c *gin.Context) {
 					CIDR:    vpc.Ipv6Cidr,
 				},
 			},
-			AdvertiseCidrs:  request.AdvertiseCidrs,
-			Relay:           request.Relay,
-			SymmetricNat:    request.SymmetricNat,
-			Hostname:        request.Hostname,
-			Os:              request.Os,
-			SecurityGroupId: vpc.ID,
-			RegKeyID:        regKeyID,
-			BearerToken:     "DT:" + deviceToken.String(),
+			AdvertiseCidrs:   request.AdvertiseCidrs,
+			Relay:            request.Relay,
+			SymmetricNat:     request.SymmetricNat,
+			Hostname:         request.Hostname,
+			Os:               request.Os,
+			SecurityGroupId:  securityGroupId,
+			SecurityGroupIds: securityGroupIds,
+			RegKeyID:         regKeyID,
+			BearerToken:      "DT:" + deviceToken.String(),
 		}
 
 		if res := tx.
@@ -644,7 +715,7 @@ func (api *API) CreateDevice(c *gin.Context) {
 	}
 
 	hideDeviceBearerToken(&device, tokenClaims, userId)
-	api.signalBus.Notify(fmt.Sprintf("/devices/vpc=%s", device.VpcID.String()))
+	api.notifyDeviceChange(c, device)
 	c.JSON(http.StatusCreated, device)
 }
 
@@ -677,7 +748,7 @@ func (api *API) DeleteDevice(c *gin.Context) {
 
 	device := models.Device{}
 	db := api.db.WithContext(ctx)
-	if res := api.DeviceIsOwnedByCurrentUser(c, db).
+	if res := api.DeviceIsManagedByCurrentUser(c, db).
 		First(&device, "id = ?", deviceID); res.Error != nil {
 
 		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
@@ -718,7 +789,7 @@ func (api *API) DeleteDevice(c *gin.Context) {
 		return
 	}
 
-	api.signalBus.Notify(fmt.Sprintf("/devices/vpc=%s", device.VpcID.String()))
+	api.notifyDeviceChange(c, device)
 
 	if ipamAddress != "" && orgPrefix != "" {
 		if err := api.ipam.ReleaseToPool(c.Request.Context(), ipamNamespace, ipamAddress, orgPrefix); err != nil {
@@ -764,9 +835,38 @@ func advertiseCidrEquals(existingPrefix, newPrefix []string) bool {
 	return true
 }
 
+// validateExitNodePolicy checks that the entries of the policy are CIDRs or domain names, the
+// field of the first invalid entry is returned along with the error.
+func validateExitNodePolicy(policy models.ExitNodePolicy) (string, error) {
+	lists := []struct {
+		field   string
+		entries []string
+	}{
+		{"exit_node_policy.include", policy.Include},
+		{"exit_node_policy.exclude", policy.Exclude},
+	}
+	for _, list := range lists {
+		for i, entry := range list.entries {
+			if !util.IsValidPrefix(entry) && !util.IsValidDomainName(entry) {
+				return fmt.Sprintf("%s[%d]", list.field, i), fmt.Errorf("%q is not a CIDR or a domain name", entry)
+			}
+		}
+	}
+	return "", nil
+}
+
+// validateTrafficLimits checks that the limits can be enforced, the field of the invalid limit is returned along with the error.
+func validateTrafficLimits(limits models.TrafficLimits) (string, error) {
+	// the bandwidth is enforced in bytes per second
+	if limits.BitsPerSecond > 0 && limits.BitsPerSecond < 8 {
+		return "traffic_limits.bits_per_second", fmt.Errorf("must be at least 8 bits per second")
+	}
+	return "", nil
+}
+
 // ListDevicesInVPC lists all devices in an VPC
 // @Summary      List Devices
-// @Description  Lists all devices for this VPC
+// @Description  Lists all devices for this VPC, and the devices of the VPCs actively peered with it
 // @Id           ListDevicesInVPC
 // @Tags         VPC
 // @Accept       json
@@ -823,7 +923,7 @@ func (api *API) ListDevicesInVPC(c *gin.Context) {
 	}
 
 	api.sendList(c, ctx, func(db *gorm.DB) (fetchmgr.ResourceList, error) {
-		db = db.Where("vpc_id = ?", vpcId.String())
+		db = devicesInVPC(db, vpcId)
 		db = FilterAndPaginateWithQuery(db, &models.Device{}, c, query, "hostname")
 
 		var items deviceList
@@ -49,7 +49,7 @@ func (api *API) ListDeviceMetadata(c *gin.Context) {
 
 	var device models.Device
 	db := api.db.WithContext(ctx)
-	result := api.DeviceIsOwnedByCurrentUser(c, db).
+	result := api.DeviceIsManagedByCurrentUser(c, db).
 		First(&device, "id = ?", deviceId)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -222,7 +222,7 @@ func (api *API) GetDeviceMetadataKey(c *gin.Context) {
 	err = api.transaction(ctx, func(tx *gorm.DB) error {
 		var device models.Device
 		db := api.db.WithContext(ctx)
-		result := api.DeviceIsOwnedByCurrentUser(c, db).
+		result := api.DeviceIsManagedByCurrentUser(c, db).
 			First(&device, "id = ?", deviceId)
 		if result.Error != nil {
 			return result.Error
@@ -257,6 +257,7 @@ func (api *API) GetDeviceMetadataKey(c *gin.Context) {
 // @Accept	     json
 // @Produce      json
 // @Success      200  {object}  models.DeviceMetadata
+// @Failure      403  {object}  models.QuotaExceededError
 // @Failure      500  {object}  models.InternalServerError "Internal Server Error"
 // @Router       /api/devices/{id}/metadata/{key} [put]
 func (api *API) UpdateDeviceMetadataKey(c *gin.Context) {
@@ -287,12 +288,25 @@ func (api *API) UpdateDeviceMetadataKey(c *gin.Context) {
 
 	var device models.Device
 	err = api.transaction(ctx, func(tx *gorm.DB) error {
-		result := api.DeviceIsOwnedByCurrentUser(c, tx).
+		result := api.DeviceIsManagedByCurrentUser(c, tx).
 			First(&device, "id = ?", deviceId)
 		if result.Error != nil {
 			return result.Error
 		}
 
+		// only new keys count against the quota
+		var count int64
+		if result = tx.Model(&models.DeviceMetadata{}).
+			Where("device_id = ? AND key = ?", deviceId, key).
+			Count(&count); result.Error != nil {
+			return result.Error
+		}
+		if count == 0 {
+			if err := api.checkQuota(tx, device.OrganizationID, metadataKeyQuota); err != nil {
+				return err
+			}
+		}
+
 		result = tx.
 			Clauses(clause.Returning{Columns: []clause.Column{{Name: "revision"}}}).
 			Save(&metadataInstance)
@@ -304,6 +318,11 @@ func (api *API) UpdateDeviceMetadataKey(c *gin.Context) {
 			c.Status(http.StatusNotFound)
 			return
 		}
+		var apiResponseError *ApiResponseError
+		if errors.As(err, &apiResponseError) {
+			c.JSON(apiResponseError.Status, apiResponseError.Body)
+			return
+		}
 		api.SendInternalServerError(c, fmt.Errorf("error updating metadata: %w", err))
 		return
 	}
@@ -337,7 +356,7 @@ func (api *API) DeleteDeviceMetadata(c *gin.Context) {
 
 	var device models.Device
 	err = api.transaction(ctx, func(tx *gorm.DB) error {
-		result := api.DeviceIsOwnedByCurrentUser(c, tx).
+		result := api.DeviceIsManagedByCurrentUser(c, tx).
 			First(&device, "id = ?", deviceId)
 		if result.Error != nil {
 			return result.Error
@@ -385,7 +404,7 @@ func (api *API) DeleteDeviceMetadataKey(c *gin.Context) {
 
 	var device models.Device
 	err = api.transaction(ctx, func(tx *gorm.DB) error {
-		result := api.DeviceIsOwnedByCurrentUser(c, tx).
+		result := api.DeviceIsManagedByCurrentUser(c, tx).
 			First(&device, "id = ?", deviceId)
 		if result.Error != nil {
 			return result.Error
@@ -41,6 +41,9 @@ func (suite *HandlerTestSuite) TestCreateGetDevice() {
 
 	require.Equal(newDevice.PublicKey, actual.PublicKey)
 	require.Equal(suite.testUserID, actual.OwnerID)
+	// devices join the default security group of the vpc
+	require.Equal(models.StringArray{suite.testUserID.String()}, actual.SecurityGroupIds)
+	require.Equal(suite.testUserID, actual.SecurityGroupId)
 
 	_, res, err = suite.ServeRequest(
 		http.MethodGet, "/:id", fmt.Sprintf("/%s", actual.ID),
@@ -100,3 +103,31 @@ func TestAdvertiseCidrEquals(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateExitNodePolicy(t *testing.T) {
+	field, err := validateExitNodePolicy(models.ExitNodePolicy{
+		Include: []string{"0.0.0.0/0", "::/0", "vpn.example.com"},
+		Exclude: []string{"10.0.0.0/8", "192.168.0.0/16"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "", field)
+
+	field, err = validateExitNodePolicy(models.ExitNodePolicy{
+		Exclude: []string{"10.0.0.0/8", "not a cidr"},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "exit_node_policy.exclude[1]", field)
+}
+
+func TestValidateTrafficLimits(t *testing.T) {
+	field, err := validateTrafficLimits(models.TrafficLimits{BitsPerSecond: 100_000_000, PacketsPerSecond: 10_000})
+	assert.NoError(t, err)
+	assert.Equal(t, "", field)
+
+	_, err = validateTrafficLimits(models.TrafficLimits{PacketsPerSecond: 1})
+	assert.NoError(t, err)
+
+	field, err = validateTrafficLimits(models.TrafficLimits{BitsPerSecond: 4})
+	assert.Error(t, err)
+	assert.Equal(t, "traffic_limits.bits_per_second", field)
+}
@@ -156,15 +156,9 @@ func (api *API) WatchEvents(c *gin.Context) {
 			}
 
 			fetcher := api.fetchManager.Open("org-devices:"+vpcId.String(), deviceCacheSize, func(db *gorm.DB, gtRevision uint64) (fetchmgr.ResourceList, error) {
-				var items deviceList
-				db = db.Unscoped().Limit(100).Order("revision")
-				if gtRevision != 0 {
-					db = db.Where("revision > ?", gtRevision)
-				}
-				db = db.Where("vpc_id = ?", vpcId.String())
-				result := db.Find(&items)
-				if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
-					return nil, result.Error
+				items, err := fetchDevicesInVPC(db, vpcId, gtRevision)
+				if err != nil {
+					return nil, err
 				}
 
 				for i := range items {
@@ -525,15 +519,9 @@ func (api *API) WatchEventsInVPC(c *gin.Context) {
 			}
 
 			fetcher := api.fetchManager.Open("org-devices:"+vpcId.String(), deviceCacheSize, func(db *gorm.DB, gtRevision uint64) (fetchmgr.ResourceList, error) {
-				var items deviceList
-				db = db.Unscoped().Limit(100).Order("revision")
-				if gtRevision != 0 {
-					db = db.Where("revision > ?", gtRevision)
-				}
-				db = db.Where("vpc_id = ?", vpcId.String())
-				result := db.Find(&items)
-				if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
-					return nil, result.Error
+				items, err := fetchDevicesInVPC(db, vpcId, gtRevision)
+				if err != nil {
+					return nil, err
 				}
 
 				for i := range items {
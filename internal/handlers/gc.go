@@ -86,6 +86,15 @@ func (api *API) GarbageCollect(c *gin.Context) {
 		return
 	}
 
+	err = db.Unscoped().
+		Debug().
+		Where("deleted_at < ?", time.Now().Add(-d)).
+		Delete(&models.VPCPeering{}).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, err)
+		return
+	}
+
 	err = db.Unscoped().
 		Debug().
 		Where("deleted_at < ?", time.Now().Add(-d)).
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"encoding/json"
 	"errors"
 	"github.com/redis/go-redis/v9"
@@ -78,6 +80,11 @@ func (suite *HandlerTestSuite) SetupSuite() {
 	if err != nil {
 		suite.T().Fatal(err)
 	}
+	// signs the audit entries
+	suite.api.PrivateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
 }
 
 func (suite *HandlerTestSuite) BeforeTest(_, _ string) {
@@ -95,10 +102,14 @@ func (suite *HandlerTestSuite) BeforeTest(_, _ string) {
 }
 
 func (suite *HandlerTestSuite) ServeRequest(method, path string, uri string, handler func(*gin.Context), body io.Reader) (*http.Request, *httptest.ResponseRecorder, error) {
+	return suite.ServeRequestAsUser(suite.testUserID, method, path, uri, handler, body)
+}
+
+func (suite *HandlerTestSuite) ServeRequestAsUser(userID uuid.UUID, method, path string, uri string, handler func(*gin.Context), body io.Reader) (*http.Request, *httptest.ResponseRecorder, error) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
 	r.Use(func(c *gin.Context) {
-		c.Set(gin.AuthUserKey, suite.testUserID)
+		c.Set(gin.AuthUserKey, userID)
 		c.Next()
 	})
 
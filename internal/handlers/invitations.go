@@ -16,11 +16,6 @@ import (
 	"gorm.io/gorm"
 )
 
-var allowedRoles = map[string]struct{}{
-	"member": {},
-	"owner":  {},
-}
-
 // CreateInvitation creates an invitation
 // @Summary      Create an invitation
 // @Description  Create an invitation to an organization
@@ -14,9 +14,6 @@ import (
 	"time"
 )
 
-var OwnerRoles = []string{"owner"}
-var MemberRoles = []string{"owner", "member"}
-
 type errDuplicateOrganization struct {
 	ID string
 }
@@ -247,6 +244,12 @@ func (api *API) GetOrganizations(c *gin.Context) {
 		return
 	}
 
+	org.Usage, err = api.organizationUsage(db, org)
+	if err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, org)
 }
 
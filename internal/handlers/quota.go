@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Quotas are the default quotas of the organizations, a quota of 0 is unlimited.
+type Quotas struct {
+	Vpcs           int64
+	Devices        int64
+	RegKeys        int64
+	SecurityGroups int64
+	MetadataKeys   int64
+}
+
+// quota counts the resources of a kind of an organization
+type quota struct {
+	name  string
+	limit func(Quotas) int64
+	count func(tx *gorm.DB, orgID uuid.UUID) (int64, error)
+}
+
+func countWhere(model interface{}, query string) func(tx *gorm.DB, orgID uuid.UUID) (int64, error) {
+	return func(tx *gorm.DB, orgID uuid.UUID) (int64, error) {
+		var count int64
+		err := tx.Model(model).Where(query, map[string]interface{}{"org": orgID}).Count(&count).Error
+		return count, err
+	}
+}
+
+var (
+	vpcQuota = quota{
+		name:  "vpcs",
+		limit: func(q Quotas) int64 { return q.Vpcs },
+		count: countWhere(&models.VPC{}, "organization_id = @org"),
+	}
+	deviceQuota = quota{
+		name:  "devices",
+		limit: func(q Quotas) int64 { return q.Devices },
+		count: countWhere(&models.Device{}, "organization_id = @org"),
+	}
+	regKeyQuota = quota{
+		name:  "reg_keys",
+		limit: func(q Quotas) int64 { return q.RegKeys },
+		count: countWhere(&models.RegKey{}, "organization_id = @org OR sn_organization_id = @org"),
+	}
+	securityGroupQuota = quota{
+		name:  "security_groups",
+		limit: func(q Quotas) int64 { return q.SecurityGroups },
+		count: countWhere(&models.SecurityGroup{}, "organization_id = @org"),
+	}
+	metadataKeyQuota = quota{
+		name:  "metadata_keys",
+		limit: func(q Quotas) int64 { return q.MetadataKeys },
+		count: countWhere(&models.DeviceMetadata{}, "device_id IN (SELECT id FROM devices WHERE organization_id = @org AND deleted_at IS NULL)"),
+	}
+)
+
+// organizationQuotas applies the overrides of the organization to the default quotas
+func (api *API) organizationQuotas(org models.Organization) Quotas {
+	quotas := api.Quotas
+	if overrides := org.Quotas; overrides != nil {
+		if overrides.Vpcs != nil {
+			quotas.Vpcs = *overrides.Vpcs
+		}
+		if overrides.Devices != nil {
+			quotas.Devices = *overrides.Devices
+		}
+		if overrides.RegKeys != nil {
+			quotas.RegKeys = *overrides.RegKeys
+		}
+		if overrides.SecurityGroups != nil {
+			quotas.SecurityGroups = *overrides.SecurityGroups
+		}
+		if overrides.MetadataKeys != nil {
+			quotas.MetadataKeys = *overrides.MetadataKeys
+		}
+	}
+	return quotas
+}
+
+// checkQuota fails with a quota exceeded error when the organization cannot have another resource of the quota's kind.
+// The organization stays locked until the transaction ends, so concurrent requests cannot both take its last slot.
+func (api *API) checkQuota(tx *gorm.DB, orgID uuid.UUID, q quota) error {
+	var org models.Organization
+	if res := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&org, "id = ?", orgID); res.Error != nil {
+		return res.Error
+	}
+	limit := q.limit(api.organizationQuotas(org))
+	if limit <= 0 {
+		return nil
+	}
+	used, err := q.count(tx, orgID)
+	if err != nil {
+		return err
+	}
+	if used >= limit {
+		return NewApiResponseError(http.StatusForbidden, models.NewQuotaExceededError(q.name, limit))
+	}
+	return nil
+}
+
+// organizationUsage counts the resources of the organization against its quotas
+func (api *API) organizationUsage(db *gorm.DB, org models.Organization) (*models.OrganizationUsage, error) {
+	quotas := api.organizationQuotas(org)
+	usage := &models.OrganizationUsage{}
+	for _, item := range []struct {
+		quota quota
+		usage *models.QuotaUsage
+	}{
+		{vpcQuota, &usage.Vpcs},
+		{deviceQuota, &usage.Devices},
+		{regKeyQuota, &usage.RegKeys},
+		{securityGroupQuota, &usage.SecurityGroups},
+		{metadataKeyQuota, &usage.MetadataKeys},
+	} {
+		used, err := item.quota.count(db, org.ID)
+		if err != nil {
+			return nil, err
+		}
+		item.usage.Used = used
+		item.usage.Limit = item.quota.limit(quotas)
+	}
+	return usage, nil
+}
+
+// UpdateOrganizationQuotas overrides the default quotas of an Organization
+// @Summary      Update the quotas of an Organization
+// @Description  Overrides the default quotas of an Organization, the quotas that are not set go back to the defaults
+// @Id           UpdateOrganizationQuotas
+// @Tags         Private
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string  true "Organization ID"
+// @Param        quotas  body      models.OrganizationQuotas  true "Organization Quotas"
+// @Success      200  {object}  models.OrganizationUsage
+// @Failure      400  {object}  models.ValidationError
+// @Failure      404  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /private/organizations/{id}/quotas [put]
+func (api *API) UpdateOrganizationQuotas(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "UpdateOrganizationQuotas",
+		trace.WithAttributes(
+			attribute.String("id", c.Param("id")),
+		))
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	var request models.OrganizationQuotas
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPayloadError(err))
+		return
+	}
+	for field, value := range map[string]*int64{
+		"vpcs":            request.Vpcs,
+		"devices":         request.Devices,
+		"reg_keys":        request.RegKeys,
+		"security_groups": request.SecurityGroups,
+		"metadata_keys":   request.MetadataKeys,
+	} {
+		if value != nil && *value < 0 {
+			c.JSON(http.StatusBadRequest, models.NewFieldValidationError(field, "must not be negative"))
+			return
+		}
+	}
+
+	var usage *models.OrganizationUsage
+	err = api.transaction(ctx, func(tx *gorm.DB) error {
+		var org models.Organization
+		if res := tx.First(&org, "id = ?", id); res.Error != nil {
+			return res.Error
+		}
+		org.Quotas = &request
+		if res := tx.Model(&org).Select("quotas").Updates(&org); res.Error != nil {
+			return res.Error
+		}
+		var err error
+		usage, err = api.organizationUsage(tx, org)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.NewNotFoundError("organization"))
+		} else {
+			api.SendInternalServerError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/models"
+	"gorm.io/gorm"
+)
+
+func (suite *HandlerTestSuite) TestQuotas() {
+	require := suite.Require()
+	api := suite.api
+	db := api.db
+
+	quotas := api.Quotas
+	api.Quotas = Quotas{Vpcs: 1, Devices: 0, MetadataKeys: 2}
+	defer func() {
+		api.Quotas = quotas
+	}()
+
+	org := models.Organization{Name: "quotas"}
+	require.NoError(db.Create(&org).Error)
+	vpc := models.VPC{OrganizationID: org.ID, Ipv4Cidr: "100.64.0.0/10", Ipv6Cidr: "200::/64"}
+	require.NoError(db.Create(&vpc).Error)
+	device := models.Device{VpcID: vpc.ID, OrganizationID: org.ID, PublicKey: "device"}
+	require.NoError(db.Create(&device).Error)
+	require.NoError(db.Create(&models.DeviceMetadata{DeviceID: device.ID, Key: "tags", Value: "a"}).Error)
+
+	checkQuota := func(q quota) error {
+		return api.transaction(context.Background(), func(tx *gorm.DB) error {
+			return api.checkQuota(tx, org.ID, q)
+		})
+	}
+
+	// the organization already has its only vpc
+	err := checkQuota(vpcQuota)
+	var apiResponseError *ApiResponseError
+	require.True(errors.As(err, &apiResponseError))
+	require.Equal(http.StatusForbidden, apiResponseError.Status)
+	require.Equal(models.NewQuotaExceededError("vpcs", 1), apiResponseError.Body)
+
+	// a quota of 0 is unlimited
+	require.NoError(checkQuota(deviceQuota))
+	require.NoError(checkQuota(metadataKeyQuota))
+
+	serve := func(id string, body string) *httptest.ResponseRecorder {
+		_, res, err := suite.ServeRequest(http.MethodPut,
+			"/organizations/:id/quotas", fmt.Sprintf("/organizations/%s/quotas", id),
+			api.UpdateOrganizationQuotas, bytes.NewBufferString(body))
+		require.NoError(err)
+		return res
+	}
+
+	res := serve(uuid.NewString(), `{}`)
+	require.Equal(http.StatusNotFound, res.Code)
+
+	res = serve(org.ID.String(), `{"vpcs": -1}`)
+	require.Equal(http.StatusBadRequest, res.Code)
+
+	res = serve(org.ID.String(), `{"vpcs": 2, "metadata_keys": 1}`)
+	require.Equal(http.StatusOK, res.Code, res.Body.String())
+	var usage models.OrganizationUsage
+	require.NoError(json.Unmarshal(res.Body.Bytes(), &usage))
+	require.Equal(models.QuotaUsage{Used: 1, Limit: 2}, usage.Vpcs)
+	require.Equal(models.QuotaUsage{Used: 1, Limit: 0}, usage.Devices)
+	require.Equal(models.QuotaUsage{Used: 1, Limit: 1}, usage.MetadataKeys)
+
+	require.NoError(checkQuota(vpcQuota))
+	require.Error(checkQuota(metadataKeyQuota))
+
+	// the quotas that are left out go back to the defaults
+	res = serve(org.ID.String(), `{}`)
+	require.Equal(http.StatusOK, res.Code, res.Body.String())
+	require.NoError(json.Unmarshal(res.Body.Bytes(), &usage))
+	require.Equal(models.QuotaUsage{Used: 1, Limit: 1}, usage.Vpcs)
+	require.Equal(models.QuotaUsage{Used: 1, Limit: 2}, usage.MetadataKeys)
+
+	// deleted resources stop counting
+	require.NoError(db.Delete(&vpc).Error)
+	require.NoError(checkQuota(vpcQuota))
+}
@@ -29,6 +29,7 @@ import (
 // @Param        RegKey  body     models.AddRegKey  true  "Add RegKey"
 // @Success      201  {object}  models.RegKey
 // @Failure      400  {object}  models.BaseError
+// @Failure      403  {object}  models.QuotaExceededError
 // @Failure      404  {object}  models.BaseError
 // @Failure		 429  {object}  models.BaseError
 // @Failure      500  {object}  models.InternalServerError "Internal Server Error"
@@ -76,7 +77,7 @@ func (api *API) CreateRegKey(c *gin.Context) {
 		// User needs to be a member of the VPC's org
 		if request.VpcID != nil {
 			var vpc models.VPC
-			if res := api.VPCIsReadableByCurrentUser(c, tx).
+			if res := api.VPCIsJoinableByCurrentUser(c, tx).
 				First(&vpc, "id = ?", request.VpcID.String()); res.Error != nil {
 				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("vpc"))
 			}
@@ -86,20 +87,32 @@ func (api *API) CreateRegKey(c *gin.Context) {
 		// User needs to be a member of the ServiceNetwork's org
 		if request.ServiceNetworkID != nil {
 			var sn models.ServiceNetwork
-			if res := api.ServiceNetworkIsReadableByCurrentUser(c, tx).
+			if res := api.ServiceNetworkIsJoinableByCurrentUser(c, tx).
 				First(&sn, "id = ?", request.ServiceNetworkID.String()); res.Error != nil {
 				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("service_network"))
 			}
 			record.SNOrganizationID = &sn.OrganizationID
 		}
 
-		if request.SecurityGroupId != nil {
-			var sg models.SecurityGroup
-			if res := api.SecurityGroupIsReadableByCurrentUser(c, tx).
-				First(&sg, "id = ?", *request.SecurityGroupId); res.Error != nil {
-				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("security_group_id"))
+		orgID := record.SNOrganizationID
+		if record.OrganizationID != nil {
+			orgID = record.OrganizationID
+		}
+		if err := api.checkQuota(tx, *orgID, regKeyQuota); err != nil {
+			return err
+		}
+
+		if ids, field, ok := requestedSecurityGroupIds(request.SecurityGroupIds, request.SecurityGroupId); ok {
+			vpcId := uuid.Nil
+			if request.VpcID != nil {
+				vpcId = *request.VpcID
 			}
-			record.SecurityGroupId = request.SecurityGroupId
+			securityGroupIds, err := api.lookupSecurityGroupIds(c, tx, vpcId, field, ids)
+			if err != nil {
+				return err
+			}
+			record.SecurityGroupIds = securityGroupIds
+			record.SecurityGroupId = firstSecurityGroupId(securityGroupIds)
 		}
 
 		if request.SingleUse {
@@ -165,19 +178,23 @@ func (api *API) UpdateRegKey(c *gin.Context) {
 	var regKey models.RegKey
 	err = api.transaction(ctx, func(tx *gorm.DB) error {
 
-		result := api.RegKeyIsForCurrentUser(c, tx).
+		result := api.RegKeyIsForCurrentUserOrDeviceOperator(c, tx).
 			First(&regKey, "id = ?", k)
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("reg key"))
 		}
 
-		if request.SecurityGroupId != nil {
-			var sg models.SecurityGroup
-			if res := api.SecurityGroupIsReadableByCurrentUser(c, tx).
-				First(&sg, "id = ?", *request.SecurityGroupId); res.Error != nil {
-				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("security_group_id"))
+		if ids, field, ok := requestedSecurityGroupIds(request.SecurityGroupIds, request.SecurityGroupId); ok {
+			vpcId := uuid.Nil
+			if regKey.VpcID != nil {
+				vpcId = *regKey.VpcID
+			}
+			securityGroupIds, err := api.lookupSecurityGroupIds(c, tx, vpcId, field, ids)
+			if err != nil {
+				return err
 			}
-			regKey.SecurityGroupId = request.SecurityGroupId
+			regKey.SecurityGroupIds = securityGroupIds
+			regKey.SecurityGroupId = firstSecurityGroupId(securityGroupIds)
 		}
 		if request.Description != nil {
 			regKey.Description = *request.Description
@@ -242,7 +259,7 @@ func (api *API) ListRegKeys(c *gin.Context) {
 	defer span.End()
 	records := []models.RegKey{}
 	db := api.db.WithContext(ctx)
-	db = api.RegKeyIsForCurrentUserOrOrgOwner(c, db)
+	db = api.RegKeyIsForCurrentUserOrDeviceOperator(c, db)
 	db = FilterAndPaginate(db, &models.RegKey{}, c, "id")
 	result := db.Find(&records)
 	if result.Error != nil {
@@ -283,7 +300,7 @@ func (api *API) GetRegKey(c *gin.Context) {
 
 	var record models.RegKey
 	db := api.db.WithContext(ctx)
-	db = api.RegKeyIsForCurrentUserOrOrgOwner(c, db)
+	db = api.RegKeyIsForCurrentUserOrDeviceOperator(c, db)
 
 	if tokenClaims != nil && tokenClaims.Scope == "reg-token" {
 		db = db.Where("id = ?", tokenClaims.ID)
@@ -311,19 +328,12 @@ func (api *API) GetRegKey(c *gin.Context) {
 	c.JSON(http.StatusOK, record)
 }
 
-func (api *API) RegKeyIsForCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
-	userId := api.GetCurrentUserID(c)
-
-	// this could potentially be driven by rego output
-	return db.Where("owner_id = ?", userId)
-}
-
-func (api *API) RegKeyIsForCurrentUserOrOrgOwner(c *gin.Context, db *gorm.DB) *gorm.DB {
+func (api *API) RegKeyIsForCurrentUserOrDeviceOperator(c *gin.Context, db *gorm.DB) *gorm.DB {
 	userId := api.GetCurrentUserID(c)
 	return db.Where(
 		db.Where("owner_id = ?", userId).
-			Or(api.CurrentUserHasRole(c, db, "organization_id", OwnerRoles)).
-			Or(api.CurrentUserHasRole(c, db, "sn_organization_id", OwnerRoles)),
+			Or(api.CurrentUserHasRole(c, db, "organization_id", DeviceOperatorRoles)).
+			Or(api.CurrentUserHasRole(c, db, "sn_organization_id", DeviceOperatorRoles)),
 	)
 }
 
@@ -356,7 +366,7 @@ func (api *API) DeleteRegKey(c *gin.Context) {
 
 	var record models.RegKey
 	err = api.transaction(ctx, func(tx *gorm.DB) error {
-		res := api.RegKeyIsForCurrentUser(c, tx).
+		res := api.RegKeyIsForCurrentUserOrDeviceOperator(c, tx).
 			First(&record, "id = ?", id)
 		if res.Error != nil {
 			return res.Error
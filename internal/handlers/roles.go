@@ -0,0 +1,44 @@
+package handlers
+
+// The built-in roles of the members of an organization.
+const (
+	RoleOwner          = "owner"
+	RoleMember         = "member"
+	RoleViewer         = "viewer"
+	RoleNetworkAdmin   = "network-admin"
+	RoleSecurityAdmin  = "security-admin"
+	RoleDeviceOperator = "device-operator"
+)
+
+// The permission matrix of the roles: each list holds the roles that are granted the permission.
+var (
+	// OwnerRoles manage the organization, its members, invitations and service accounts.
+	OwnerRoles = []string{RoleOwner}
+
+	// MemberRoles can read all the resources of the organization.
+	MemberRoles = []string{RoleOwner, RoleMember, RoleViewer, RoleNetworkAdmin, RoleSecurityAdmin, RoleDeviceOperator}
+
+	// NetworkCreatorRoles can create VPCs and service networks.
+	NetworkCreatorRoles = []string{RoleOwner, RoleMember, RoleNetworkAdmin}
+
+	// NetworkAdminRoles can update and delete VPCs and service networks.
+	NetworkAdminRoles = []string{RoleOwner, RoleNetworkAdmin}
+
+	// SecurityAdminRoles can create, update and delete security groups.
+	SecurityAdminRoles = []string{RoleOwner, RoleSecurityAdmin}
+
+	// DeviceCreatorRoles can create registration keys and register devices and sites.
+	DeviceCreatorRoles = []string{RoleOwner, RoleMember, RoleNetworkAdmin, RoleDeviceOperator}
+
+	// DeviceOperatorRoles can manage the devices, sites and registration keys of the other members.
+	DeviceOperatorRoles = []string{RoleOwner, RoleDeviceOperator}
+)
+
+// allowedRoles are the roles that can be given to the members of an organization.
+var allowedRoles = func() map[string]struct{} {
+	roles := map[string]struct{}{}
+	for _, role := range MemberRoles {
+		roles[role] = struct{}{}
+	}
+	return roles
+}()
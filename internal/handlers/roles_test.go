@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/models"
+	"gorm.io/gorm"
+)
+
+func (suite *HandlerTestSuite) TestOrganizationRoles() {
+	require := suite.Require()
+	api := suite.api
+	db := api.db
+
+	org := models.Organization{Name: "roles"}
+	require.NoError(db.Create(&org).Error)
+	users := map[string]uuid.UUID{}
+	for _, role := range MemberRoles {
+		user := models.User{Base: models.Base{ID: uuid.New()}, IdpID: uuid.NewString(), UserName: role}
+		require.NoError(db.Create(&user).Error)
+		require.NoError(db.Create(&models.UserOrganization{UserID: user.ID, OrganizationID: org.ID, Roles: []string{role}}).Error)
+		users[role] = user.ID
+	}
+
+	vpc := models.VPC{OrganizationID: org.ID, Ipv4Cidr: "100.64.0.0/10", Ipv6Cidr: "200::/64"}
+	require.NoError(db.Create(&vpc).Error)
+	sg := models.SecurityGroup{OrganizationID: org.ID, VpcId: vpc.ID}
+	require.NoError(db.Create(&sg).Error)
+	device := models.Device{VpcID: vpc.ID, OrganizationID: org.ID, OwnerID: users[RoleMember], PublicKey: "device"}
+	require.NoError(db.Create(&device).Error)
+
+	permitted := func(scope func(*gin.Context, *gorm.DB) *gorm.DB, model interface{}, id uuid.UUID) []string {
+		var roles []string
+		for _, role := range MemberRoles {
+			var count int64
+			_, _, err := suite.ServeRequestAsUser(users[role], http.MethodGet, "/", "/", func(c *gin.Context) {
+				require.NoError(scope(c, db.WithContext(c)).Model(model).Where("id = ?", id).Count(&count).Error)
+			}, nil)
+			require.NoError(err)
+			if count > 0 {
+				roles = append(roles, role)
+			}
+		}
+		return roles
+	}
+
+	require.Equal(MemberRoles, permitted(api.VPCIsReadableByCurrentUser, &models.VPC{}, vpc.ID))
+	require.Equal([]string{RoleOwner, RoleNetworkAdmin}, permitted(api.VPCIsWriteableByCurrentUser, &models.VPC{}, vpc.ID))
+	require.Equal([]string{RoleOwner, RoleMember, RoleNetworkAdmin, RoleDeviceOperator}, permitted(api.VPCIsJoinableByCurrentUser, &models.VPC{}, vpc.ID))
+	require.Equal([]string{RoleOwner, RoleSecurityAdmin}, permitted(api.SecurityGroupIsWriteableByCurrentUser, &models.SecurityGroup{}, sg.ID))
+	require.Equal([]string{RoleOwner, RoleMember, RoleDeviceOperator}, permitted(api.DeviceIsManagedByCurrentUser, &models.Device{}, device.ID))
+
+	serve := func(userID uuid.UUID, uid uuid.UUID, roles ...string) *httptest.ResponseRecorder {
+		_, res, err := suite.ServeRequestAsUser(userID, http.MethodPatch,
+			"/organizations/:id/users/:uid", fmt.Sprintf("/organizations/%s/users/%s", org.ID, uid),
+			api.UpdateOrganizationUser, bytes.NewBuffer(suite.jsonMarshal(models.UpdateUserOrganization{Roles: roles})))
+		require.NoError(err)
+		return res
+	}
+
+	// only the owners can change the roles
+	res := serve(users[RoleNetworkAdmin], users[RoleViewer], RoleDeviceOperator)
+	require.Equal(http.StatusNotFound, res.Code)
+
+	res = serve(users[RoleOwner], users[RoleViewer], "admin")
+	require.Equal(http.StatusBadRequest, res.Code)
+
+	// the organization keeps at least one owner
+	res = serve(users[RoleOwner], users[RoleOwner], RoleMember)
+	require.Equal(http.StatusBadRequest, res.Code, res.Body.String())
+
+	res = serve(users[RoleOwner], users[RoleViewer], RoleViewer, RoleDeviceOperator)
+	require.Equal(http.StatusOK, res.Code, res.Body.String())
+	var membership models.UserOrganization
+	require.NoError(json.Unmarshal(res.Body.Bytes(), &membership))
+	require.Equal(models.StringArray{RoleViewer, RoleDeviceOperator}, membership.Roles)
+	require.Equal([]string{RoleOwner, RoleMember, RoleViewer, RoleDeviceOperator}, permitted(api.DeviceIsManagedByCurrentUser, &models.Device{}, device.ID))
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -57,7 +58,7 @@ func (api *API) SecurityGroupIsReadableByCurrentUser(c *gin.Context, db *gorm.DB
 }
 
 func (api *API) SecurityGroupIsWriteableByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
-	return api.CurrentUserHasRole(c, db, "organization_id", OwnerRoles)
+	return api.CurrentUserHasRole(c, db, "organization_id", SecurityAdminRoles)
 }
 
 // ListSecurityGroups lists all Security Groups
@@ -202,6 +203,122 @@ func (api *API) GetSecurityGroup(c *gin.Context) {
 	c.JSON(http.StatusOK, securityGroup)
 }
 
+// GetSecurityGroupStats handles getting the rule counters of a SecurityGroup
+// @Summary      Get SecurityGroup Stats
+// @Description  Gets the packet and byte counters of the rules of a security group, summed over the devices in the group
+// @Id  		 GetSecurityGroupStats
+// @Tags         SecurityGroup
+// @Accepts		 json
+// @Produce      json
+// @Param        id   path      string  true "Security Group ID"
+// @Success      200  {object}  models.SecurityGroupStats
+// @Failure		 401  {object}  models.BaseError
+// @Failure      400  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/security-groups/{id}/stats [get]
+func (api *API) GetSecurityGroupStats(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "GetSecurityGroupStats", trace.WithAttributes(
+		attribute.String("id", c.Param("id")),
+	))
+	defer span.End()
+	k, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	db := api.db.WithContext(ctx)
+	var securityGroup models.SecurityGroup
+	result := api.SecurityGroupIsReadableByCurrentUser(c, db).
+		First(&securityGroup, "id = ?", k)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.NewNotFoundError("security group"))
+		} else {
+			api.SendInternalServerError(c, result.Error)
+		}
+		return
+	}
+
+	var metadata []models.DeviceMetadata
+	result = db.Model(&models.DeviceMetadata{}).
+		Joins("inner join devices on devices.id=device_metadata.device_id").
+		Scopes(api.hasSecurityGroup("devices.security_group_ids", securityGroup.ID)).
+		Where("devices.deleted_at IS NULL").
+		Where("device_metadata.key = ?", models.SecurityGroupStatsMetadataKey).
+		Find(&metadata)
+	if result.Error != nil {
+		api.SendInternalServerError(c, result.Error)
+		return
+	}
+
+	var reports []models.SecurityGroupDeviceStats
+	for _, item := range metadata {
+		deviceStats, err := parseDeviceSecurityGroupStats(item.Value)
+		if err != nil {
+			api.logger.Debugf("ignoring invalid security group stats of device %s: %v", item.DeviceID, err)
+			continue
+		}
+		for _, report := range deviceStats.SecurityGroups {
+			if report.SecurityGroupId == securityGroup.ID {
+				reports = append(reports, report)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, sumSecurityGroupStats(securityGroup, reports))
+}
+
+// parseDeviceSecurityGroupStats decodes the value of the security-group-stats device metadata
+func parseDeviceSecurityGroupStats(value interface{}) (models.DeviceSecurityGroupStats, error) {
+	report := models.DeviceSecurityGroupStats{}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return report, err
+	}
+	err = json.Unmarshal(data, &report)
+	return report, err
+}
+
+// sumSecurityGroupStats adds up the counters the devices reported for the current revision of the security
+// group. Every rule of the group is listed so that rules that never matched show up with zero counters.
+func sumSecurityGroupStats(sg models.SecurityGroup, reports []models.SecurityGroupDeviceStats) models.SecurityGroupStats {
+	stats := models.SecurityGroupStats{
+		SecurityGroupId: sg.ID,
+		Revision:        sg.Revision,
+		InboundRules:    make([]models.SecurityRuleStats, len(sg.InboundRules)),
+		OutboundRules:   make([]models.SecurityRuleStats, len(sg.OutboundRules)),
+	}
+	for i := range stats.InboundRules {
+		stats.InboundRules[i].Rule = i
+	}
+	for i := range stats.OutboundRules {
+		stats.OutboundRules[i].Rule = i
+	}
+
+	add := func(totals []models.SecurityRuleStats, counters []models.SecurityRuleStats) {
+		for _, counter := range counters {
+			if counter.Rule < 0 || counter.Rule >= len(totals) {
+				continue
+			}
+			totals[counter.Rule].Packets += counter.Packets
+			totals[counter.Rule].Bytes += counter.Bytes
+		}
+	}
+	for _, report := range reports {
+		// counters of an older revision may belong to rules that were changed or reordered since
+		if report.SecurityGroupId != sg.ID || report.Revision != sg.Revision {
+			continue
+		}
+		stats.Devices++
+		add(stats.InboundRules, report.InboundRules)
+		add(stats.OutboundRules, report.OutboundRules)
+	}
+	return stats
+}
+
 // CreateSecurityGroup handles adding a new SecurityGroup
 // @Summary      Add SecurityGroup
 // @Id  		 CreateSecurityGroup
@@ -213,6 +330,7 @@ func (api *API) GetSecurityGroup(c *gin.Context) {
 // @Success      201  {object}  models.SecurityGroup
 // @Failure      400  {object}  models.BaseError
 // @Failure      401  {object}  models.BaseError
+// @Failure      403  {object}  models.QuotaExceededError
 // @Failure      409  {object}  models.ConflictsError
 // @Failure      422  {object}  models.ValidationError
 // @Failure      429  {object}  models.BaseError
@@ -235,16 +353,7 @@ func (api *API) CreateSecurityGroup(c *gin.Context) {
 
 	// Validate security group rules for any invalid fields in ports/ip_ranges/protocol
 	if err := ValidateCreateSecurityGroupRules(request); err != nil {
-		switch {
-		case strings.Contains(err.Error(), "invalid protocol"):
-			c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("protocol", err.Error()))
-		case strings.Contains(err.Error(), "invalid port range"):
-			c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("port_range", err.Error()))
-		case strings.Contains(err.Error(), "invalid IP range"):
-			c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("ip_range", err.Error()))
-		default:
-			c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("rule", "invalid rule"))
-		}
+		sendSecurityRuleValidationError(c, err)
 		return
 	}
 
@@ -256,11 +365,19 @@ func (api *API) CreateSecurityGroup(c *gin.Context) {
 	var sg models.SecurityGroup
 	err := api.transaction(ctx, func(tx *gorm.DB) error {
 		var vpc models.VPC
-		if res := api.VPCIsOwnedByCurrentUser(c, tx).
+		if res := api.CurrentUserHasRole(c, tx, "organization_id", SecurityAdminRoles).
 			First(&vpc, "id = ?", request.VpcId); res.Error != nil {
 			return res.Error
 		}
 
+		if err := validateSecurityGroupReferences(tx, vpc.ID, request.InboundRules, request.OutboundRules); err != nil {
+			return err
+		}
+
+		if err := api.checkQuota(tx, vpc.OrganizationID, securityGroupQuota); err != nil {
+			return err
+		}
+
 		sg = models.SecurityGroup{
 			VpcId:          vpc.ID,
 			OrganizationID: vpc.OrganizationID,
@@ -280,8 +397,11 @@ func (api *API) CreateSecurityGroup(c *gin.Context) {
 	})
 
 	if err != nil {
+		var apiResponseError *ApiResponseError
 		if errors.Is(err, errUserNotFound) {
 			c.JSON(http.StatusNotFound, models.NewApiError(err))
+		} else if errors.As(err, &apiResponseError) {
+			c.JSON(apiResponseError.Status, apiResponseError.Body)
 		} else {
 			api.SendInternalServerError(c, err)
 		}
@@ -353,7 +473,7 @@ func (api *API) DeleteSecurityGroup(c *gin.Context) {
 		}
 
 		var count int64
-		res := tx.Model(&models.Device{}).Where("security_group_id = ?", secGroupID).Count(&count)
+		res := tx.Model(&models.Device{}).Scopes(api.hasSecurityGroup("security_group_ids", secGroupID)).Count(&count)
 		if res.Error != nil {
 			return res.Error
 		}
@@ -432,16 +552,7 @@ func (api *API) UpdateSecurityGroup(c *gin.Context) {
 
 	// Validate security group rules for any invalid fields in ports/ip_ranges/protocol
 	if err := ValidateUpdateSecurityGroupRules(request); err != nil {
-		switch {
-		case strings.Contains(err.Error(), "invalid protocol"):
-			c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("protocol", err.Error()))
-		case strings.Contains(err.Error(), "invalid port range"):
-			c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("port_range", err.Error()))
-		case strings.Contains(err.Error(), "invalid IP range"):
-			c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("ip_range", err.Error()))
-		default:
-			c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("rule", "invalid rule"))
-		}
+		sendSecurityRuleValidationError(c, err)
 		return
 	}
 
@@ -464,6 +575,10 @@ func (api *API) UpdateSecurityGroup(c *gin.Context) {
 			securityGroup.OutboundRules = request.OutboundRules
 		}
 
+		if err := validateSecurityGroupReferences(tx, securityGroup.VpcId, request.InboundRules, request.OutboundRules); err != nil {
+			return err
+		}
+
 		if res := tx.
 			Clauses(clause.Returning{Columns: []clause.Column{{Name: "revision"}}}).
 			Save(&securityGroup); res.Error != nil {
@@ -474,10 +589,13 @@ func (api *API) UpdateSecurityGroup(c *gin.Context) {
 	})
 
 	if err != nil {
+		var apiResponseError *ApiResponseError
 		if errors.Is(err, errSecurityGroupNotFound) {
 			c.JSON(http.StatusNotFound, models.NewNotFoundError("security_group"))
 		} else if errors.Is(err, errOrgNotFound) {
 			c.JSON(http.StatusNotFound, err)
+		} else if errors.As(err, &apiResponseError) {
+			c.JSON(apiResponseError.Status, apiResponseError.Body)
 		} else {
 			api.SendInternalServerError(c, err)
 		}
@@ -516,6 +634,128 @@ func (api *API) createDefaultSecurityGroup(ctx context.Context, db *gorm.DB, vpc
 	return nil
 }
 
+// sendSecurityRuleValidationError maps a rule validation error to the field that failed validation
+func sendSecurityRuleValidationError(c *gin.Context, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid protocol"):
+		c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("protocol", err.Error()))
+	case strings.Contains(err.Error(), "invalid port range"):
+		c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("port_range", err.Error()))
+	case strings.Contains(err.Error(), "invalid IP range"):
+		c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("ip_range", err.Error()))
+	case strings.Contains(err.Error(), "invalid action"):
+		c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("action", err.Error()))
+	case strings.Contains(err.Error(), "invalid priority"):
+		c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("priority", err.Error()))
+	case strings.Contains(err.Error(), "invalid icmp type"):
+		c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("icmp_type", err.Error()))
+	case strings.Contains(err.Error(), "invalid security group reference"):
+		c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("security_group_ids", err.Error()))
+	case strings.Contains(err.Error(), "invalid vpc reference"):
+		c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("vpc_ids", err.Error()))
+	default:
+		c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("rule", "invalid rule"))
+	}
+}
+
+// validateSecurityGroupReferences verifies that the security groups referenced by the rules exist in the vpc, and
+// that the referenced VPCs are the vpc itself or VPCs actively peered with it
+func validateSecurityGroupReferences(tx *gorm.DB, vpcId uuid.UUID, rules ...[]models.SecurityRule) error {
+	ids := map[uuid.UUID]struct{}{}
+	vpcIds := map[uuid.UUID]struct{}{}
+	for _, list := range rules {
+		for _, rule := range list {
+			for _, id := range rule.SecurityGroupIds {
+				ids[id] = struct{}{}
+			}
+			for _, id := range rule.VpcIds {
+				vpcIds[id] = struct{}{}
+			}
+		}
+	}
+	if len(vpcIds) > 0 {
+		peers, err := activePeerVpcIds(tx, vpcId)
+		if err != nil {
+			return err
+		}
+		for id := range vpcIds {
+			if id != vpcId && !peers[id] {
+				return NewApiResponseError(http.StatusUnprocessableEntity,
+					models.NewFieldValidationError("vpc_ids", fmt.Sprintf("invalid vpc reference: %s is not the vpc of the security group or a vpc peered with it", id)))
+			}
+		}
+	}
+	for id := range ids {
+		var count int64
+		result := tx.Model(&models.SecurityGroup{}).
+			Where("id = ? AND vpc_id = ?", id, vpcId).
+			Count(&count)
+		if result.Error != nil {
+			return result.Error
+		}
+		if count == 0 {
+			return NewApiResponseError(http.StatusUnprocessableEntity,
+				models.NewFieldValidationError("security_group_ids", fmt.Sprintf("invalid security group reference: %s is not a security group of the vpc", id)))
+		}
+	}
+	return nil
+}
+
+// requestedSecurityGroupIds returns the security groups set in a device or reg key request. The deprecated
+// single security group id is only used when the list is not set, ok is false when neither is set.
+func requestedSecurityGroupIds(ids []uuid.UUID, id *uuid.UUID) (requested []uuid.UUID, field string, ok bool) {
+	if ids != nil {
+		return ids, "security_group_ids", true
+	}
+	if id != nil {
+		if *id == uuid.Nil {
+			return []uuid.UUID{}, "security_group_id", true
+		}
+		return []uuid.UUID{*id}, "security_group_id", true
+	}
+	return nil, "", false
+}
+
+// lookupSecurityGroupIds verifies that the security groups are readable by the current user and, when vpcId
+// is set, that they belong to the vpc. The ids are returned in the order they were requested without duplicates.
+func (api *API) lookupSecurityGroupIds(c *gin.Context, tx *gorm.DB, vpcId uuid.UUID, field string, ids []uuid.UUID) (models.StringArray, error) {
+	result := models.StringArray{}
+	seen := map[uuid.UUID]struct{}{}
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		db := api.SecurityGroupIsReadableByCurrentUser(c, tx)
+		if vpcId != uuid.Nil {
+			db = db.Where("vpc_id = ?", vpcId)
+		}
+		var sg models.SecurityGroup
+		if res := db.First(&sg, "id = ?", id); res.Error != nil {
+			if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+				return nil, NewApiResponseError(http.StatusNotFound, models.NewNotFoundError(field))
+			}
+			return nil, res.Error
+		}
+		result = append(result, id.String())
+	}
+	return result, nil
+}
+
+// firstSecurityGroupId returns the first of the security groups, it is stored in the deprecated security_group_id
+// columns so that older versions of nexd keep enforcing at least that group.
+func firstSecurityGroupId(ids models.StringArray) *uuid.UUID {
+	if len(ids) == 0 {
+		return nil
+	}
+	id, err := uuid.Parse(ids[0])
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
 // ValidateUpdateSecurityGroupRules validates rules for updating the security group
 func ValidateUpdateSecurityGroupRules(sg models.UpdateSecurityGroup) error {
 	for _, rule := range append(sg.InboundRules, sg.OutboundRules...) {
@@ -554,6 +794,44 @@ func ValidateRule(rule models.SecurityRule) error {
 		return fmt.Errorf("invalid port range: from %d to %d", rule.FromPort, rule.ToPort)
 	}
 
+	// Validate Action
+	if rule.Action != "" && rule.Action != models.SecurityRuleActionAllow && rule.Action != models.SecurityRuleActionDeny {
+		return fmt.Errorf("invalid action: %s", rule.Action)
+	}
+
+	// Validate Priority
+	if rule.Priority < 0 || rule.Priority > 65535 {
+		return fmt.Errorf("invalid priority: %d is not in the range of 0-65535", rule.Priority)
+	}
+
+	// Validate ICMP type and code, they are only valid on icmp rules
+	if rule.IcmpType != nil || rule.IcmpCode != nil {
+		if rule.IpProtocol != protoICMP && rule.IpProtocol != protoICMPv4 && rule.IpProtocol != protoICMPv6 {
+			return fmt.Errorf("invalid icmp type: icmp_type and icmp_code are only supported on icmp rules")
+		}
+		if rule.IcmpType == nil {
+			return fmt.Errorf("invalid icmp type: icmp_code requires an icmp_type")
+		}
+		if *rule.IcmpType < 0 || *rule.IcmpType > 255 {
+			return fmt.Errorf("invalid icmp type: %d is not in the range of 0-255", *rule.IcmpType)
+		}
+		if rule.IcmpCode != nil && (*rule.IcmpCode < 0 || *rule.IcmpCode > 255) {
+			return fmt.Errorf("invalid icmp type: code %d is not in the range of 0-255", *rule.IcmpCode)
+		}
+	}
+
+	// Validate Security Group references
+	for _, id := range rule.SecurityGroupIds {
+		if id == uuid.Nil {
+			return fmt.Errorf("invalid security group reference: %s", id)
+		}
+	}
+	for _, id := range rule.VpcIds {
+		if id == uuid.Nil {
+			return fmt.Errorf("invalid vpc reference: %s", id)
+		}
+	}
+
 	// Validate IP Ranges
 	for _, ipRange := range rule.IpRanges {
 		if ipRange == "" { // Wildcard case
@@ -572,3 +850,13 @@ func ValidateRule(rule models.SecurityRule) error {
 
 	return nil
 }
+
+// hasSecurityGroup filters the rows whose security group ids field contains the security group.
+func (api *API) hasSecurityGroup(field string, securityGroupId uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if api.dialect == database.DialectSqlLite {
+			return db.Where(fmt.Sprintf("EXISTS (SELECT * FROM json_each(%s) AS sg WHERE sg.value = ?)", field), securityGroupId.String())
+		}
+		return db.Where(fmt.Sprintf("%s && ?", field), models.StringArray{securityGroupId.String()})
+	}
+}
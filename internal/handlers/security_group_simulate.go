@@ -0,0 +1,460 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/models"
+	"github.com/nexodus-io/nexodus/internal/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// simulatedPacket is the first packet of the simulated traffic
+type simulatedPacket struct {
+	ipVersion int64
+	protocol  string
+	port      int64
+	icmpType  int64
+	icmpCode  int64
+	// remote is the address of the other device, the source for the inbound rules and the destination for the outbound rules
+	remote netip.Addr
+}
+
+// SimulateSecurityGroup evaluates a proposed update of a Security Group
+// @Summary      Simulate Security Group
+// @Description  Evaluates the traffic between two devices with a proposed update of a Security Group, the security group is not changed
+// @Id           SimulateSecurityGroup
+// @Tags         SecurityGroup
+// @Accepts      json
+// @Produce      json
+// @Param        id path      string  true "Security Group ID"
+// @Param        simulation body       models.SecurityGroupSimulation true "Security Group Simulation"
+// @Success      200  {object}     models.SecurityGroupSimulationResult
+// @Failure      400  {object}     models.BaseError
+// @Failure      401  {object}     models.BaseError
+// @Failure      404  {object}     models.BaseError
+// @Failure      422  {object}     models.ValidationError
+// @Failure      429  {object}     models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/security-groups/{id}/simulate [post]
+func (api *API) SimulateSecurityGroup(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "SimulateSecurityGroup", trace.WithAttributes(
+		attribute.String("id", c.Param("id")),
+	))
+	defer span.End()
+
+	if !api.FlagCheck(c, "security-groups") {
+		return
+	}
+
+	k, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	var request models.SecurityGroupSimulation
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPayloadError(err))
+		return
+	}
+
+	// Validate the proposed rules the same way an update does
+	if err := ValidateUpdateSecurityGroupRules(request.Update); err != nil {
+		sendSecurityRuleValidationError(c, err)
+		return
+	}
+	packet, err := simulatedPacketOf(request)
+	if err != nil {
+		var apiResponseError *ApiResponseError
+		if errors.As(err, &apiResponseError) {
+			c.JSON(apiResponseError.Status, apiResponseError.Body)
+		} else {
+			api.SendInternalServerError(c, err)
+		}
+		return
+	}
+
+	db := api.db.WithContext(ctx)
+	var securityGroup models.SecurityGroup
+	result := api.SecurityGroupIsReadableByCurrentUser(c, db).
+		First(&securityGroup, "id = ?", k)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.NewNotFoundError("security group"))
+		} else {
+			api.SendInternalServerError(c, result.Error)
+		}
+		return
+	}
+	if err := validateSecurityGroupReferences(db, securityGroup.VpcId, request.Update.InboundRules, request.Update.OutboundRules); err != nil {
+		var apiResponseError *ApiResponseError
+		if errors.As(err, &apiResponseError) {
+			c.JSON(apiResponseError.Status, apiResponseError.Body)
+		} else {
+			api.SendInternalServerError(c, err)
+		}
+		return
+	}
+	if request.Update.InboundRules != nil {
+		securityGroup.InboundRules = request.Update.InboundRules
+	}
+	if request.Update.OutboundRules != nil {
+		securityGroup.OutboundRules = request.Update.OutboundRules
+	}
+
+	// the devices of the vpc and of its peered VPCs are needed to resolve the security group and vpc references of the rules
+	var devices []models.Device
+	result = devicesInVPC(db.Select("id", "vpc_id", "security_group_ids", "ipv4_tunnel_ips", "ipv6_tunnel_ips"), securityGroup.VpcId).
+		Find(&devices)
+	if result.Error != nil {
+		api.SendInternalServerError(c, result.Error)
+		return
+	}
+	var src, dst *models.Device
+	for i := range devices {
+		if devices[i].ID == request.SourceDeviceId {
+			src = &devices[i]
+		}
+		if devices[i].ID == request.DestinationDeviceId {
+			dst = &devices[i]
+		}
+	}
+	if src == nil {
+		c.JSON(http.StatusNotFound, models.NewNotFoundError("source device"))
+		return
+	}
+	if dst == nil {
+		c.JSON(http.StatusNotFound, models.NewNotFoundError("destination device"))
+		return
+	}
+	srcAddr, ok := deviceTunnelAddr(*src, packet.ipVersion)
+	if !ok {
+		c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("source_device_id", fmt.Sprintf("the device has no ipv%d tunnel address", packet.ipVersion)))
+		return
+	}
+	dstAddr, ok := deviceTunnelAddr(*dst, packet.ipVersion)
+	if !ok {
+		c.JSON(http.StatusUnprocessableEntity, models.NewFieldValidationError("destination_device_id", fmt.Sprintf("the device has no ipv%d tunnel address", packet.ipVersion)))
+		return
+	}
+
+	// the security groups of the devices other than the simulated one are evaluated with their current rules
+	deviceSecurityGroups := func(d *models.Device) ([]models.SecurityGroup, error) {
+		var groups []models.SecurityGroup
+		for _, value := range d.SecurityGroupIds {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				continue
+			}
+			if id == securityGroup.ID {
+				groups = append(groups, securityGroup)
+				continue
+			}
+			var sg models.SecurityGroup
+			result := db.First(&sg, "id = ? AND vpc_id = ?", id, d.VpcID)
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				continue
+			}
+			if result.Error != nil {
+				return nil, result.Error
+			}
+			groups = append(groups, sg)
+		}
+		return groups, nil
+	}
+	srcGroups, err := deviceSecurityGroups(src)
+	if err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+	dstGroups, err := deviceSecurityGroups(dst)
+	if err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+
+	members := ruleMemberAddrs(devices)
+	response := models.SecurityGroupSimulationResult{}
+	packet.remote = dstAddr
+	if len(srcGroups) > 0 {
+		response.Outbound = simulateDeviceSecurityRules(srcGroups, true, members, packet)
+	} else {
+		response.Outbound = models.SecurityRuleVerdict{Allowed: true, Reason: "the source device has no security group"}
+	}
+	packet.remote = srcAddr
+	if len(dstGroups) > 0 {
+		response.Inbound = simulateDeviceSecurityRules(dstGroups, false, members, packet)
+	} else {
+		response.Inbound = models.SecurityRuleVerdict{Allowed: true, Reason: "the destination device has no security group"}
+	}
+	response.Allowed = response.Outbound.Allowed && response.Inbound.Allowed
+
+	c.JSON(http.StatusOK, response)
+}
+
+// simulatedPacketOf validates the traffic of the simulation request
+func simulatedPacketOf(request models.SecurityGroupSimulation) (simulatedPacket, error) {
+	packet := simulatedPacket{
+		ipVersion: request.IpVersion,
+		protocol:  strings.ToLower(request.IpProtocol),
+		port:      request.Port,
+	}
+	invalid := func(field, reason string) error {
+		return NewApiResponseError(http.StatusUnprocessableEntity, models.NewFieldValidationError(field, reason))
+	}
+
+	if packet.ipVersion == 0 {
+		packet.ipVersion = 4
+		if packet.protocol == protoICMPv6 {
+			packet.ipVersion = 6
+		}
+	}
+	if packet.ipVersion != 4 && packet.ipVersion != 6 {
+		return packet, invalid("ip_version", "the ip version must be 4 or 6")
+	}
+
+	switch packet.protocol {
+	case protoTCP, protoUDP:
+		if packet.port < 1 || packet.port > 65535 {
+			return packet, invalid("port", fmt.Sprintf("the port must be in the range of 1-65535 for %s traffic", packet.protocol))
+		}
+	case protoICMP, protoICMPv4, protoICMPv6:
+		if packet.protocol == protoICMPv4 {
+			packet.protocol = protoICMP
+		}
+		if (packet.protocol == protoICMPv6) != (packet.ipVersion == 6) {
+			return packet, invalid("ip_version", fmt.Sprintf("%s traffic is not supported over ipv%d", packet.protocol, packet.ipVersion))
+		}
+		// an echo request, the message sent by ping
+		packet.icmpType = 8
+		if packet.protocol == protoICMPv6 {
+			packet.icmpType = 128
+		}
+		if request.IcmpType != nil {
+			packet.icmpType = *request.IcmpType
+		}
+		if request.IcmpCode != nil {
+			packet.icmpCode = *request.IcmpCode
+		}
+		if packet.icmpType < 0 || packet.icmpType > 255 || packet.icmpCode < 0 || packet.icmpCode > 255 {
+			return packet, invalid("icmp_type", "the icmp type and code must be in the range of 0-255")
+		}
+	default:
+		return packet, invalid("ip_protocol", fmt.Sprintf("invalid protocol: %s, the traffic must be tcp, udp, icmp or icmpv6", request.IpProtocol))
+	}
+	return packet, nil
+}
+
+// deviceTunnelAddr returns the first tunnel address of the device in the ip version
+func deviceTunnelAddr(device models.Device, ipVersion int64) (netip.Addr, bool) {
+	tunnelIPs := device.IPv4TunnelIPs
+	if ipVersion == 6 {
+		tunnelIPs = device.IPv6TunnelIPs
+	}
+	for _, ip := range tunnelIPs {
+		if addr, err := netip.ParseAddr(ip.Address); err == nil {
+			return addr.Unmap(), true
+		}
+	}
+	return netip.Addr{}, false
+}
+
+// ruleMembers holds the tunnel addresses of the devices that the security group and vpc references of the rules
+// match. They are kept apart since the default security group of a vpc has the same id as the vpc.
+type ruleMembers struct {
+	securityGroups map[uuid.UUID][]netip.Addr
+	vpcs           map[uuid.UUID][]netip.Addr
+}
+
+// ruleMemberAddrs maps the security group and vpc ids to the tunnel addresses of the devices in them
+func ruleMemberAddrs(devices []models.Device) ruleMembers {
+	members := ruleMembers{
+		securityGroups: map[uuid.UUID][]netip.Addr{},
+		vpcs:           map[uuid.UUID][]netip.Addr{},
+	}
+	for _, device := range devices {
+		var addrs []netip.Addr
+		for _, ip := range append(append([]models.TunnelIP{}, device.IPv4TunnelIPs...), device.IPv6TunnelIPs...) {
+			if addr, err := netip.ParseAddr(ip.Address); err == nil {
+				addrs = append(addrs, addr.Unmap())
+			}
+		}
+		members.vpcs[device.VpcID] = append(members.vpcs[device.VpcID], addrs...)
+		for _, value := range device.SecurityGroupIds {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				continue
+			}
+			members.securityGroups[id] = append(members.securityGroups[id], addrs...)
+		}
+	}
+	return members
+}
+
+// simulateDeviceSecurityRules evaluates the security groups of a device the way nexd applies them: the rules of
+// every group are evaluated on their own and the traffic is allowed if any of the groups allows it. The verdict
+// of the first group that allows the traffic is returned, otherwise the one of the first group whose deny rule
+// matched the traffic or the one of the first group.
+func simulateDeviceSecurityRules(groups []models.SecurityGroup, outbound bool, members ruleMembers, packet simulatedPacket) models.SecurityRuleVerdict {
+	var ids []uuid.UUID
+	var result *models.SecurityRuleVerdict
+	for _, sg := range groups {
+		ids = append(ids, sg.ID)
+		rules := sg.InboundRules
+		if outbound {
+			rules = sg.OutboundRules
+		}
+		verdict := simulateSecurityRules(rules, members, packet)
+		if verdict.Rule != nil {
+			verdict.SecurityGroupId = sg.ID
+		}
+		if result == nil || verdict.Allowed && !result.Allowed || !result.Allowed && result.Rule == nil && verdict.Rule != nil {
+			result = &verdict
+		}
+	}
+	if result == nil {
+		return models.SecurityRuleVerdict{Allowed: true, Reason: "the device has no security group"}
+	}
+	result.SecurityGroupIds = ids
+	return *result
+}
+
+// simulateSecurityRules evaluates the rules of a security group in one direction the way nexd applies them: the
+// rules are ordered by priority, keeping the order of the rules with the same priority, and the first matching rule
+// decides. When no rule matches, the traffic is dropped if the group has allow rules and allowed if it only has
+// deny rules or no rules at all.
+func simulateSecurityRules(rules []models.SecurityRule, members ruleMembers, packet simulatedPacket) models.SecurityRuleVerdict {
+	order := make([]int, len(rules))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return rules[order[i]].Priority < rules[order[j]].Priority
+	})
+
+	hasAllow := false
+	for _, rule := range rules {
+		if rule.Action != models.SecurityRuleActionDeny {
+			hasAllow = true
+		}
+	}
+
+	for _, i := range order {
+		rule := rules[i]
+		if len(rule.SecurityGroupIds) > 0 || len(rule.VpcIds) > 0 {
+			count := len(rule.IpRanges)
+			for _, id := range rule.SecurityGroupIds {
+				count += len(members.securityGroups[id])
+			}
+			for _, id := range rule.VpcIds {
+				count += len(members.vpcs[id])
+			}
+			if count == 0 {
+				// nexd skips the rules that only reference security groups or VPCs without members
+				continue
+			}
+		}
+		if !securityRuleMatches(rule, members, packet) {
+			continue
+		}
+		deny := rule.Action == models.SecurityRuleActionDeny
+		index := int64(i)
+		verdict := models.SecurityRuleVerdict{
+			Allowed:     !deny,
+			Rule:        &index,
+			MatchedRule: &rule,
+			Reason:      "the traffic matched an allow rule",
+		}
+		if deny {
+			verdict.Reason = "the traffic matched a deny rule"
+		}
+		return verdict
+	}
+
+	if hasAllow {
+		return models.SecurityRuleVerdict{Allowed: false, Reason: "no rule matched the traffic, it is dropped since the security group has allow rules"}
+	}
+	return models.SecurityRuleVerdict{Allowed: true, Reason: "no rule matched the traffic, it is allowed since the security group has no allow rules"}
+}
+
+// securityRuleMatches returns true if the rule matches the first packet of the traffic
+func securityRuleMatches(rule models.SecurityRule, members ruleMembers, packet simulatedPacket) bool {
+	hasPorts := rule.FromPort > 0 && rule.ToPort > 0
+	proto := strings.ToLower(rule.IpProtocol)
+	switch proto {
+	case "", protoIPv4, protoIPv6:
+		if proto == protoIPv4 && packet.ipVersion != 4 || proto == protoIPv6 && packet.ipVersion != 6 {
+			return false
+		}
+		// ports without a transport protocol match both tcp and udp
+		if hasPorts && packet.protocol != protoTCP && packet.protocol != protoUDP {
+			return false
+		}
+	case protoTCP, protoUDP:
+		if packet.protocol != proto {
+			return false
+		}
+	case protoICMP:
+		// icmp types are ICMPv4 types, an icmp rule with a type does not match ICMPv6
+		if packet.protocol != protoICMP && (packet.protocol != protoICMPv6 || rule.IcmpType != nil) {
+			return false
+		}
+	case protoICMPv4:
+		if packet.protocol != protoICMP {
+			return false
+		}
+	case protoICMPv6:
+		if packet.protocol != protoICMPv6 {
+			return false
+		}
+	default:
+		return false
+	}
+	if hasPorts && (packet.protocol == protoTCP || packet.protocol == protoUDP) &&
+		(packet.port < rule.FromPort || packet.port > rule.ToPort) {
+		return false
+	}
+	if rule.IcmpType != nil && *rule.IcmpType != packet.icmpType {
+		return false
+	}
+	if rule.IcmpCode != nil && *rule.IcmpCode != packet.icmpCode {
+		return false
+	}
+
+	if len(rule.IpRanges) == 0 && len(rule.SecurityGroupIds) == 0 && len(rule.VpcIds) == 0 {
+		return true
+	}
+	for _, ipRange := range rule.IpRanges {
+		if ipRange == "" {
+			// the wildcard range matches any address
+			return true
+		}
+		if contains, err := util.IPRangeContains(ipRange, packet.remote); err == nil && contains {
+			return true
+		}
+	}
+	for _, id := range rule.SecurityGroupIds {
+		for _, addr := range members.securityGroups[id] {
+			if addr == packet.remote {
+				return true
+			}
+		}
+	}
+	for _, id := range rule.VpcIds {
+		for _, addr := range members.vpcs[id] {
+			if addr == packet.remote {
+				return true
+			}
+		}
+	}
+	return false
+}
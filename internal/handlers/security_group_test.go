@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/netip"
+	"testing"
 
 	"github.com/nexodus-io/nexodus/internal/util"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/nexodus-io/nexodus/internal/models"
+	"github.com/stretchr/testify/require"
 )
 
 func (suite *HandlerTestSuite) TestCreateGetSecurityGroups() {
@@ -425,3 +429,482 @@ func (suite *HandlerTestSuite) TestInvalidUpdateSecurityGroup() {
 	// Should be http.StatusStatusUnprocessableEntity.
 	require.Equal(http.StatusUnprocessableEntity, res.Code)
 }
+
+func (suite *HandlerTestSuite) TestSecurityGroupRuleActionsAndReferences() {
+	require := suite.Require()
+
+	createGroup := func(group models.AddSecurityGroup) (int, []byte) {
+		resBody, err := json.Marshal(group)
+		require.NoError(err)
+
+		_, res, err := suite.ServeRequest(
+			http.MethodPost,
+			"/security-groups", "/security-groups",
+			func(c *gin.Context) {
+				c.Set("nexodus.fflag.security-groups", true)
+				suite.api.CreateSecurityGroup(c)
+			},
+			bytes.NewBuffer(resBody),
+		)
+		require.NoError(err)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(err)
+		return res.Code, body
+	}
+
+	code, body := createGroup(models.AddSecurityGroup{
+		Description:  "web servers",
+		VpcId:        suite.testUserID,
+		InboundRules: []models.SecurityRule{{IpProtocol: "tcp", FromPort: 443, ToPort: 443}},
+	})
+	require.Equal(http.StatusCreated, code, "HTTP error: %s", string(body))
+
+	var webGroup models.SecurityGroup
+	require.NoError(json.Unmarshal(body, &webGroup))
+
+	icmpType := int64(8)
+	icmpCode := int64(0)
+	code, body = createGroup(models.AddSecurityGroup{
+		Description: "databases",
+		VpcId:       suite.testUserID,
+		InboundRules: []models.SecurityRule{
+			{IpProtocol: "tcp", FromPort: 5432, ToPort: 5432, IpRanges: []string{"100.64.0.99"}, Action: models.SecurityRuleActionDeny, Priority: 10},
+			{IpProtocol: "tcp", FromPort: 5432, ToPort: 5432, SecurityGroupIds: []uuid.UUID{webGroup.ID}, Priority: 20},
+			{IpProtocol: "icmp", IcmpType: &icmpType, IcmpCode: &icmpCode},
+		},
+	})
+	require.Equal(http.StatusCreated, code, "HTTP error: %s", string(body))
+
+	var dbGroup models.SecurityGroup
+	require.NoError(json.Unmarshal(body, &dbGroup))
+	require.Equal(models.SecurityRuleActionDeny, dbGroup.InboundRules[0].Action)
+	require.Equal(int64(20), dbGroup.InboundRules[1].Priority)
+	require.Equal([]uuid.UUID{webGroup.ID}, dbGroup.InboundRules[1].SecurityGroupIds)
+	require.Equal(&icmpType, dbGroup.InboundRules[2].IcmpType)
+
+	invalidRules := map[string]models.SecurityRule{
+		"action":             {IpProtocol: "tcp", Action: "reject"},
+		"priority":           {IpProtocol: "tcp", Priority: 70000},
+		"icmp_type":          {IpProtocol: "tcp", IcmpType: &icmpType},
+		"security_group_ids": {IpProtocol: "tcp", SecurityGroupIds: []uuid.UUID{uuid.New()}},
+	}
+	for field, rule := range invalidRules {
+		code, body = createGroup(models.AddSecurityGroup{
+			Description:  "invalid",
+			VpcId:        suite.testUserID,
+			InboundRules: []models.SecurityRule{rule},
+		})
+		require.Equal(http.StatusUnprocessableEntity, code, "HTTP error: %s", string(body))
+
+		var validationErr models.ValidationError
+		require.NoError(json.Unmarshal(body, &validationErr))
+		require.Equal(field, validationErr.Field)
+	}
+}
+
+func (suite *HandlerTestSuite) TestGetSecurityGroupStats() {
+	require := suite.Require()
+
+	resBody, err := json.Marshal(models.AddSecurityGroup{
+		Description: "stats",
+		VpcId:       suite.testUserID,
+		InboundRules: []models.SecurityRule{
+			{IpProtocol: "tcp", FromPort: 22, ToPort: 22},
+			{IpProtocol: "tcp", FromPort: 443, ToPort: 443},
+		},
+		OutboundRules: []models.SecurityRule{
+			{IpProtocol: "udp", FromPort: 53, ToPort: 53},
+		},
+	})
+	require.NoError(err)
+	_, res, err := suite.ServeRequest(
+		http.MethodPost,
+		"/security-groups", "/security-groups",
+		func(c *gin.Context) {
+			c.Set("nexodus.fflag.security-groups", true)
+			suite.api.CreateSecurityGroup(c)
+		},
+		bytes.NewBuffer(resBody),
+	)
+	require.NoError(err)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(err)
+	require.Equal(http.StatusCreated, res.Code, "HTTP error: %s", string(body))
+
+	var group models.SecurityGroup
+	require.NoError(json.Unmarshal(body, &group))
+
+	for i, report := range []models.SecurityGroupDeviceStats{
+		{
+			SecurityGroupId: group.ID,
+			Revision:        group.Revision,
+			InboundRules:    []models.SecurityRuleStats{{Rule: 0, Packets: 2, Bytes: 120}},
+			OutboundRules:   []models.SecurityRuleStats{{Rule: 0, Packets: 1, Bytes: 60}},
+		},
+		{
+			SecurityGroupId: group.ID,
+			Revision:        group.Revision,
+			InboundRules:    []models.SecurityRuleStats{{Rule: 0, Packets: 3, Bytes: 180}},
+		},
+		{
+			// reported for an older revision of the group and ignored
+			SecurityGroupId: group.ID,
+			Revision:        group.Revision - 1,
+			InboundRules:    []models.SecurityRuleStats{{Rule: 1, Packets: 100, Bytes: 6000}},
+		},
+	} {
+		resBody, err := json.Marshal(models.AddDevice{
+			VpcID:           suite.testUserID,
+			PublicKey:       fmt.Sprintf("stats-device-%d", i),
+			SecurityGroupId: group.ID,
+		})
+		require.NoError(err)
+		_, res, err := suite.ServeRequest(
+			http.MethodPost,
+			"/", "/",
+			suite.api.CreateDevice, bytes.NewBuffer(resBody),
+		)
+		require.NoError(err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(err)
+		require.Equal(http.StatusCreated, res.Code, "HTTP error: %s", string(body))
+
+		var device models.Device
+		require.NoError(json.Unmarshal(body, &device))
+		require.NoError(suite.api.db.Create(&models.DeviceMetadata{
+			DeviceID: device.ID,
+			Key:      models.SecurityGroupStatsMetadataKey,
+			Value: models.DeviceSecurityGroupStats{
+				SecurityGroups: []models.SecurityGroupDeviceStats{report},
+			},
+		}).Error)
+	}
+
+	_, res, err = suite.ServeRequest(
+		http.MethodGet, "/security-groups/:id/stats", fmt.Sprintf("/security-groups/%s/stats", group.ID),
+		suite.api.GetSecurityGroupStats, nil,
+	)
+	require.NoError(err)
+	body, err = io.ReadAll(res.Body)
+	require.NoError(err)
+	require.Equal(http.StatusOK, res.Code, "HTTP error: %s", string(body))
+
+	var stats models.SecurityGroupStats
+	require.NoError(json.Unmarshal(body, &stats))
+	require.Equal(models.SecurityGroupStats{
+		SecurityGroupId: group.ID,
+		Revision:        group.Revision,
+		Devices:         2,
+		InboundRules: []models.SecurityRuleStats{
+			{Rule: 0, Packets: 5, Bytes: 300},
+			{Rule: 1},
+		},
+		OutboundRules: []models.SecurityRuleStats{
+			{Rule: 0, Packets: 1, Bytes: 60},
+		},
+	}, stats)
+
+	_, res, err = suite.ServeRequest(
+		http.MethodGet, "/security-groups/:id/stats", fmt.Sprintf("/security-groups/%s/stats", uuid.New()),
+		suite.api.GetSecurityGroupStats, nil,
+	)
+	require.NoError(err)
+	require.Equal(http.StatusNotFound, res.Code)
+}
+
+func (suite *HandlerTestSuite) TestSimulateSecurityGroup() {
+	require := suite.Require()
+
+	createGroup := func(group models.AddSecurityGroup) models.SecurityGroup {
+		resBody, err := json.Marshal(group)
+		require.NoError(err)
+		_, res, err := suite.ServeRequest(
+			http.MethodPost,
+			"/security-groups", "/security-groups",
+			func(c *gin.Context) {
+				c.Set("nexodus.fflag.security-groups", true)
+				suite.api.CreateSecurityGroup(c)
+			},
+			bytes.NewBuffer(resBody),
+		)
+		require.NoError(err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(err)
+		require.Equal(http.StatusCreated, res.Code, "HTTP error: %s", string(body))
+		var result models.SecurityGroup
+		require.NoError(json.Unmarshal(body, &result))
+		return result
+	}
+	createDevice := func(publicKey string, securityGroupId uuid.UUID) models.Device {
+		resBody, err := json.Marshal(models.AddDevice{
+			VpcID:           suite.testUserID,
+			PublicKey:       publicKey,
+			SecurityGroupId: securityGroupId,
+		})
+		require.NoError(err)
+		_, res, err := suite.ServeRequest(
+			http.MethodPost,
+			"/", "/",
+			suite.api.CreateDevice, bytes.NewBuffer(resBody),
+		)
+		require.NoError(err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(err)
+		require.Equal(http.StatusCreated, res.Code, "HTTP error: %s", string(body))
+		var result models.Device
+		require.NoError(json.Unmarshal(body, &result))
+		return result
+	}
+
+	web := createGroup(models.AddSecurityGroup{Description: "web", VpcId: suite.testUserID})
+	db := createGroup(models.AddSecurityGroup{
+		Description: "db",
+		VpcId:       suite.testUserID,
+		InboundRules: []models.SecurityRule{
+			{IpProtocol: "tcp", FromPort: 5432, ToPort: 5432, SecurityGroupIds: []uuid.UUID{web.ID}},
+		},
+	})
+	webDevice := createDevice("simulate-web", web.ID)
+	dbDevice := createDevice("simulate-db", db.ID)
+
+	simulate := func(id uuid.UUID, simulation models.SecurityGroupSimulation) (int, models.SecurityGroupSimulationResult) {
+		resBody, err := json.Marshal(simulation)
+		require.NoError(err)
+		_, res, err := suite.ServeRequest(
+			http.MethodPost,
+			"/security-groups/:id/simulate", fmt.Sprintf("/security-groups/%s/simulate", id),
+			func(c *gin.Context) {
+				c.Set("nexodus.fflag.security-groups", true)
+				suite.api.SimulateSecurityGroup(c)
+			},
+			bytes.NewBuffer(resBody),
+		)
+		require.NoError(err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(err)
+		var result models.SecurityGroupSimulationResult
+		if res.Code == http.StatusOK {
+			require.NoError(json.Unmarshal(body, &result))
+		}
+		return res.Code, result
+	}
+
+	// the current rules allow postgres from the web servers
+	code, result := simulate(db.ID, models.SecurityGroupSimulation{
+		SourceDeviceId:      webDevice.ID,
+		DestinationDeviceId: dbDevice.ID,
+		IpProtocol:          "tcp",
+		Port:                5432,
+	})
+	require.Equal(http.StatusOK, code)
+	require.True(result.Allowed)
+	require.True(result.Outbound.Allowed)
+	require.Equal([]uuid.UUID{web.ID}, result.Outbound.SecurityGroupIds)
+	require.Nil(result.Outbound.Rule)
+	require.Equal([]uuid.UUID{db.ID}, result.Inbound.SecurityGroupIds)
+	require.Equal(db.ID, result.Inbound.SecurityGroupId)
+	require.NotNil(result.Inbound.Rule)
+	require.Equal(int64(0), *result.Inbound.Rule)
+
+	// other ports hit the implicit drop
+	code, result = simulate(db.ID, models.SecurityGroupSimulation{
+		SourceDeviceId:      webDevice.ID,
+		DestinationDeviceId: dbDevice.ID,
+		IpProtocol:          "tcp",
+		Port:                22,
+	})
+	require.Equal(http.StatusOK, code)
+	require.False(result.Allowed)
+	require.Nil(result.Inbound.Rule)
+
+	// a proposed deny rule with a lower priority takes precedence
+	code, result = simulate(db.ID, models.SecurityGroupSimulation{
+		Update: models.UpdateSecurityGroup{
+			InboundRules: []models.SecurityRule{
+				{IpProtocol: "tcp", FromPort: 5432, ToPort: 5432, SecurityGroupIds: []uuid.UUID{web.ID}, Priority: 20},
+				{IpProtocol: "tcp", FromPort: 5000, ToPort: 6000, SecurityGroupIds: []uuid.UUID{web.ID}, Priority: 10, Action: models.SecurityRuleActionDeny},
+			},
+		},
+		SourceDeviceId:      webDevice.ID,
+		DestinationDeviceId: dbDevice.ID,
+		IpProtocol:          "tcp",
+		Port:                5432,
+	})
+	require.Equal(http.StatusOK, code)
+	require.False(result.Allowed)
+	require.NotNil(result.Inbound.Rule)
+	require.Equal(int64(1), *result.Inbound.Rule)
+	require.Equal(models.SecurityRuleActionDeny, result.Inbound.MatchedRule.Action)
+
+	// the simulation does not change the security group
+	var current models.SecurityGroup
+	require.NoError(suite.api.db.First(&current, "id = ?", db.ID).Error)
+	require.Len(current.InboundRules, 1)
+
+	code, _ = simulate(db.ID, models.SecurityGroupSimulation{
+		SourceDeviceId:      uuid.New(),
+		DestinationDeviceId: dbDevice.ID,
+		IpProtocol:          "tcp",
+		Port:                5432,
+	})
+	require.Equal(http.StatusNotFound, code)
+
+	code, _ = simulate(db.ID, models.SecurityGroupSimulation{
+		SourceDeviceId:      webDevice.ID,
+		DestinationDeviceId: dbDevice.ID,
+		IpProtocol:          "sctp",
+	})
+	require.Equal(http.StatusUnprocessableEntity, code)
+
+	// a device in several security groups is allowed the traffic any of its groups allows
+	admin := createGroup(models.AddSecurityGroup{
+		Description: "admin",
+		VpcId:       suite.testUserID,
+		InboundRules: []models.SecurityRule{
+			{IpProtocol: "tcp", FromPort: 22, ToPort: 22},
+		},
+	})
+	resBody, err := json.Marshal(models.UpdateDevice{SecurityGroupIds: []uuid.UUID{db.ID, admin.ID}})
+	require.NoError(err)
+	_, res, err := suite.ServeRequest(
+		http.MethodPatch, "/:id", fmt.Sprintf("/%s", dbDevice.ID),
+		suite.api.UpdateDevice, bytes.NewBuffer(resBody),
+	)
+	require.NoError(err)
+	require.Equal(http.StatusOK, res.Code)
+
+	code, result = simulate(db.ID, models.SecurityGroupSimulation{
+		SourceDeviceId:      webDevice.ID,
+		DestinationDeviceId: dbDevice.ID,
+		IpProtocol:          "tcp",
+		Port:                22,
+	})
+	require.Equal(http.StatusOK, code)
+	require.True(result.Allowed)
+	require.Equal([]uuid.UUID{db.ID, admin.ID}, result.Inbound.SecurityGroupIds)
+	require.Equal(admin.ID, result.Inbound.SecurityGroupId)
+	require.NotNil(result.Inbound.Rule)
+	require.Equal(int64(0), *result.Inbound.Rule)
+
+	// a deny rule of one group does not drop the traffic another group allows
+	code, result = simulate(db.ID, models.SecurityGroupSimulation{
+		Update: models.UpdateSecurityGroup{
+			InboundRules: []models.SecurityRule{
+				{IpProtocol: "tcp", FromPort: 22, ToPort: 22, Action: models.SecurityRuleActionDeny},
+				{IpProtocol: "tcp", FromPort: 5432, ToPort: 5432},
+			},
+		},
+		SourceDeviceId:      webDevice.ID,
+		DestinationDeviceId: dbDevice.ID,
+		IpProtocol:          "tcp",
+		Port:                22,
+	})
+	require.Equal(http.StatusOK, code)
+	require.True(result.Allowed)
+	require.Equal(admin.ID, result.Inbound.SecurityGroupId)
+
+	// a group without inbound rules allows all the inbound traffic
+	resBody, err = json.Marshal(models.UpdateDevice{SecurityGroupIds: []uuid.UUID{db.ID, web.ID}})
+	require.NoError(err)
+	_, res, err = suite.ServeRequest(
+		http.MethodPatch, "/:id", fmt.Sprintf("/%s", dbDevice.ID),
+		suite.api.UpdateDevice, bytes.NewBuffer(resBody),
+	)
+	require.NoError(err)
+	require.Equal(http.StatusOK, res.Code)
+
+	code, result = simulate(db.ID, models.SecurityGroupSimulation{
+		SourceDeviceId:      webDevice.ID,
+		DestinationDeviceId: dbDevice.ID,
+		IpProtocol:          "tcp",
+		Port:                80,
+	})
+	require.Equal(http.StatusOK, code)
+	require.True(result.Allowed)
+	require.Equal([]uuid.UUID{db.ID, web.ID}, result.Inbound.SecurityGroupIds)
+	require.Nil(result.Inbound.Rule)
+}
+
+func TestSimulateSecurityRules(t *testing.T) {
+	require := require.New(t)
+
+	web := uuid.New()
+	members := ruleMembers{securityGroups: map[uuid.UUID][]netip.Addr{
+		web: {netip.MustParseAddr("100.64.0.2"), netip.MustParseAddr("200::2")},
+	}}
+	icmpType := int64(8)
+	rules := []models.SecurityRule{
+		{IpProtocol: "tcp", FromPort: 22, ToPort: 22, SecurityGroupIds: []uuid.UUID{web}, Priority: 20},
+		{IpProtocol: "tcp", IpRanges: []string{"100.64.0.2"}, Priority: 10, Action: models.SecurityRuleActionDeny},
+		{IpProtocol: "icmp", IcmpType: &icmpType},
+		{IpProtocol: "ipv6", FromPort: 80, ToPort: 90, IpRanges: []string{"200::/64"}},
+		// a reference to a group without members is skipped
+		{IpProtocol: "udp", SecurityGroupIds: []uuid.UUID{uuid.New()}},
+	}
+	tcp := func(remote string, port int64) simulatedPacket {
+		addr := netip.MustParseAddr(remote)
+		version := int64(4)
+		if addr.Is6() {
+			version = 6
+		}
+		return simulatedPacket{ipVersion: version, protocol: protoTCP, port: port, remote: addr}
+	}
+	matched := func(verdict models.SecurityRuleVerdict) int64 {
+		if verdict.Rule == nil {
+			return -1
+		}
+		return *verdict.Rule
+	}
+
+	tests := []struct {
+		packet  simulatedPacket
+		allowed bool
+		rule    int64
+	}{
+		// the deny rule has a lower priority than the security group reference
+		{tcp("100.64.0.2", 22), false, 1},
+		{tcp("200::2", 22), true, 0},
+		{tcp("200::3", 22), false, -1},
+		{tcp("200::3", 85), true, 3},
+		{tcp("100.64.0.3", 85), false, -1},
+		{simulatedPacket{ipVersion: 4, protocol: protoICMP, icmpType: 8, remote: netip.MustParseAddr("100.64.0.3")}, true, 2},
+		{simulatedPacket{ipVersion: 4, protocol: protoICMP, icmpType: 13, remote: netip.MustParseAddr("100.64.0.3")}, false, -1},
+		{simulatedPacket{ipVersion: 6, protocol: protoICMPv6, icmpType: 8, remote: netip.MustParseAddr("200::3")}, false, -1},
+		{simulatedPacket{ipVersion: 4, protocol: protoUDP, port: 53, remote: netip.MustParseAddr("100.64.0.3")}, false, -1},
+	}
+	for _, test := range tests {
+		verdict := simulateSecurityRules(rules, members, test.packet)
+		require.Equal(test.allowed, verdict.Allowed, "%+v: %s", test.packet, verdict.Reason)
+		require.Equal(test.rule, matched(verdict), "%+v", test.packet)
+	}
+
+	// a direction with only deny rules allows everything else
+	verdict := simulateSecurityRules(rules[1:2], members, tcp("100.64.0.3", 22))
+	require.True(verdict.Allowed)
+	require.Nil(verdict.Rule)
+	require.True(simulateSecurityRules(nil, members, tcp("100.64.0.3", 22)).Allowed)
+
+	// a device is allowed the traffic any of its security groups allows
+	ssh := models.SecurityGroup{Base: models.Base{ID: uuid.New()}, InboundRules: []models.SecurityRule{
+		{IpProtocol: "tcp", FromPort: 22, ToPort: 22},
+	}}
+	db := models.SecurityGroup{Base: models.Base{ID: uuid.New()}, InboundRules: []models.SecurityRule{
+		{IpProtocol: "tcp", IpRanges: []string{"100.64.0.2"}, Priority: 10, Action: models.SecurityRuleActionDeny},
+		{IpProtocol: "tcp", FromPort: 5432, ToPort: 5432, Priority: 20},
+	}}
+	verdict = simulateDeviceSecurityRules([]models.SecurityGroup{db, ssh}, false, members, tcp("100.64.0.2", 22))
+	require.True(verdict.Allowed)
+	require.Equal(ssh.ID, verdict.SecurityGroupId)
+	require.Equal([]uuid.UUID{db.ID, ssh.ID}, verdict.SecurityGroupIds)
+	// the deny rule is reported when no group allows the traffic
+	verdict = simulateDeviceSecurityRules([]models.SecurityGroup{ssh, db}, false, members, tcp("100.64.0.2", 5432))
+	require.False(verdict.Allowed)
+	require.Equal(db.ID, verdict.SecurityGroupId)
+	require.Equal(int64(0), *verdict.Rule)
+	require.True(simulateDeviceSecurityRules([]models.SecurityGroup{ssh, db}, false, members, tcp("100.64.0.3", 5432)).Allowed)
+	// a group without rules allows all the traffic
+	verdict = simulateDeviceSecurityRules([]models.SecurityGroup{db, {Base: models.Base{ID: uuid.New()}}}, false, members, tcp("100.64.0.2", 80))
+	require.True(verdict.Allowed)
+	require.Nil(verdict.Rule)
+}
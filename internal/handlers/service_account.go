@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/database"
+	"github.com/nexodus-io/nexodus/internal/models"
+	"github.com/nexodus-io/nexodus/internal/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/maps"
+	"gorm.io/gorm"
+)
+
+// serviceAccountIdpPrefix prefixes the IdpID of the users of service accounts, no identity provider issues these.
+const serviceAccountIdpPrefix = "service-account:"
+
+// CreateServiceAccount creates a ServiceAccount
+// @Summary      Create a ServiceAccount
+// @Description  Create a ServiceAccount in an organization
+// @Id           CreateServiceAccount
+// @Tags         ServiceAccount
+// @Accept       json
+// @Produce      json
+// @Param        ServiceAccount  body     models.AddServiceAccount  true  "Add ServiceAccount"
+// @Success      201  {object}  models.ServiceAccount
+// @Failure      400  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure      409  {object}  models.ConflictsError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/service-accounts [post]
+func (api *API) CreateServiceAccount(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "CreateServiceAccount")
+	defer span.End()
+
+	var request models.AddServiceAccount
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPayloadError(err))
+		return
+	}
+
+	if request.OrganizationID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, models.NewFieldNotPresentError("organization_id"))
+		return
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		c.JSON(http.StatusBadRequest, models.NewFieldNotPresentError("name"))
+		return
+	}
+	if len(util.FilterOutAllowed(request.Roles, allowedRoles)) > 0 {
+		c.JSON(http.StatusBadRequest, models.NewFieldValidationError("roles", "allowed values are: "+strings.Join(maps.Keys(allowedRoles), ", ")))
+		return
+	}
+	if len(request.Roles) == 0 {
+		request.Roles = []string{"member"}
+	}
+
+	userId := api.GetCurrentUserID(c)
+
+	var record models.ServiceAccount
+	err := api.transaction(ctx, func(tx *gorm.DB) error {
+
+		// Only allow org owners to create service accounts...
+		var org models.Organization
+		if res := api.OrganizationIsOwnedByCurrentUser(c, tx).
+			First(&org, "id = ?", request.OrganizationID); res.Error != nil {
+			return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("organization"))
+		}
+
+		var existing models.ServiceAccount
+		if res := tx.Where("organization_id = ? AND name = ?", org.ID, request.Name).
+			Limit(1).Find(&existing); res.Error != nil {
+			return res.Error
+		}
+		if existing.ID != uuid.Nil {
+			return NewApiResponseError(http.StatusConflict, models.NewConflictsError(existing.ID.String()))
+		}
+
+		record = models.ServiceAccount{
+			Base:           models.Base{ID: uuid.New()},
+			OrganizationID: org.ID,
+			OwnerID:        userId,
+			Name:           request.Name,
+			Description:    request.Description,
+		}
+
+		// the service account acts through its own user record, so that the membership checks
+		// of the other handlers apply to it like they do to people.
+		user := models.User{
+			Base:     models.Base{ID: uuid.New()},
+			IdpID:    serviceAccountIdpPrefix + record.ID.String(),
+			UserName: request.Name,
+			FullName: request.Description,
+		}
+		if res := tx.Create(&user); res.Error != nil {
+			return res.Error
+		}
+		if res := tx.Create(&models.UserIdentity{
+			Kind:   "service-account",
+			Value:  record.ID.String(),
+			UserID: user.ID,
+		}); res.Error != nil {
+			return res.Error
+		}
+		record.UserID = user.ID
+
+		if res := tx.Create(&record); res.Error != nil {
+			if database.IsDuplicateError(res.Error) {
+				return NewApiResponseError(http.StatusConflict, models.NewConflictsError(record.ID.String()))
+			}
+			return res.Error
+		}
+
+		if res := tx.Create(&models.UserOrganization{
+			UserID:         user.ID,
+			OrganizationID: org.ID,
+			Roles:          request.Roles,
+		}); res.Error != nil {
+			return res.Error
+		}
+		record.Roles = request.Roles
+
+		span.SetAttributes(attribute.String("id", record.ID.String()))
+		return nil
+	})
+
+	if err != nil {
+		var apiResponseError *ApiResponseError
+		if errors.As(err, &apiResponseError) {
+			c.JSON(apiResponseError.Status, apiResponseError.Body)
+		} else {
+			api.SendInternalServerError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+func (api *API) ServiceAccountIsReadableByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
+	return api.CurrentUserHasRole(c, db, "organization_id", MemberRoles)
+}
+
+func (api *API) ServiceAccountIsOwnedByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
+	return api.CurrentUserHasRole(c, db, "organization_id", OwnerRoles)
+}
+
+// populateServiceAccountRoles fills the Roles of the service accounts from their organization memberships.
+func populateServiceAccountRoles(db *gorm.DB, records []models.ServiceAccount) error {
+	if len(records) == 0 {
+		return nil
+	}
+	userIds := make([]uuid.UUID, len(records))
+	for i, record := range records {
+		userIds[i] = record.UserID
+	}
+	var memberships []models.UserOrganization
+	if res := db.Where("user_id IN ?", userIds).Find(&memberships); res.Error != nil {
+		return res.Error
+	}
+	roles := map[string]models.StringArray{}
+	for _, membership := range memberships {
+		roles[membership.UserID.String()+"/"+membership.OrganizationID.String()] = membership.Roles
+	}
+	for i, record := range records {
+		records[i].Roles = roles[record.UserID.String()+"/"+record.OrganizationID.String()]
+		if records[i].Roles == nil {
+			records[i].Roles = models.StringArray{}
+		}
+	}
+	return nil
+}
+
+// ListServiceAccounts lists service accounts
+// @Summary      List service accounts
+// @Description  Lists the service accounts of the organizations of the current user
+// @Id           ListServiceAccounts
+// @Tags         ServiceAccount
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  []models.ServiceAccount
+// @Failure		 401  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/service-accounts [get]
+func (api *API) ListServiceAccounts(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ListServiceAccounts")
+	defer span.End()
+	records := []models.ServiceAccount{}
+	db := api.db.WithContext(ctx)
+	db = api.ServiceAccountIsReadableByCurrentUser(c, db)
+	db = FilterAndPaginate(db, &models.ServiceAccount{}, c, "name")
+	result := db.Find(&records)
+	if result.Error != nil {
+		api.SendInternalServerError(c, fmt.Errorf("error fetching service accounts from db: %w", result.Error))
+		return
+	}
+	if err := populateServiceAccountRoles(api.db.WithContext(ctx), records); err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+// GetServiceAccount gets a specific ServiceAccount
+// @Summary      Get a ServiceAccount
+// @Description  Gets a ServiceAccount by ServiceAccount ID
+// @Id           GetServiceAccount
+// @Tags         ServiceAccount
+// @Accept       json
+// @Produce      json
+// @Param		 id   path      string true "ServiceAccount ID"
+// @Success      200  {object}  models.ServiceAccount
+// @Failure      400  {object}  models.BaseError
+// @Failure		 401  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/service-accounts/{id} [get]
+func (api *API) GetServiceAccount(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "GetServiceAccount",
+		trace.WithAttributes(
+			attribute.String("id", c.Param("id")),
+		))
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	var record models.ServiceAccount
+	db := api.db.WithContext(ctx)
+	result := api.ServiceAccountIsReadableByCurrentUser(c, db).
+		First(&record, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.NewNotFoundError("service account"))
+		} else {
+			api.SendInternalServerError(c, result.Error)
+		}
+		return
+	}
+	records := []models.ServiceAccount{record}
+	if err := populateServiceAccountRoles(db, records); err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, records[0])
+}
+
+// DeleteServiceAccount handles deleting a ServiceAccount
+// @Summary      Delete ServiceAccount
+// @Description  Deletes an existing ServiceAccount and revokes its tokens
+// @Id           DeleteServiceAccount
+// @Tags         ServiceAccount
+// @Accept		 json
+// @Produce      json
+// @Param		 id   path      string true "ServiceAccount ID"
+// @Success      200  {object}  models.ServiceAccount
+// @Failure      400  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/service-accounts/{id} [delete]
+func (api *API) DeleteServiceAccount(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "DeleteServiceAccount",
+		trace.WithAttributes(
+			attribute.String("id", c.Param("id")),
+		))
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	var record models.ServiceAccount
+	err = api.transaction(ctx, func(tx *gorm.DB) error {
+		res := api.ServiceAccountIsOwnedByCurrentUser(c, tx).
+			First(&record, "id = ?", id)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res := tx.Where("service_account_id = ?", record.ID).
+			Delete(&models.ApiToken{}); res.Error != nil {
+			return res.Error
+		}
+		if res := tx.Where("user_id = ?", record.UserID).
+			Delete(&models.UserOrganization{}); res.Error != nil {
+			return res.Error
+		}
+		if res := tx.Where("kind = 'service-account' AND value = ?", record.ID.String()).
+			Delete(&models.UserIdentity{}); res.Error != nil {
+			return res.Error
+		}
+		if res := tx.Delete(&models.User{}, record.UserID); res.Error != nil {
+			return res.Error
+		}
+		if res := tx.Delete(&record); res.Error != nil {
+			return res.Error
+		}
+		return nil
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.NewNotFoundError("service account"))
+		return
+	} else if err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
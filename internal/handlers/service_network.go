@@ -51,7 +51,7 @@ func (api *API) CreateServiceNetwork(c *gin.Context) {
 	err := api.transaction(ctx, func(tx *gorm.DB) error {
 
 		var org models.Organization
-		if res := api.OrganizationIsReadableByCurrentUser(c, tx).
+		if res := api.CurrentUserHasRole(c, tx, "id", NetworkCreatorRoles).
 			First(&org, "id = ?", request.OrganizationID.String()); res.Error != nil {
 			return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("organization"))
 		}
@@ -89,8 +89,13 @@ func (api *API) ServiceNetworkIsReadableByCurrentUser(c *gin.Context, db *gorm.D
 	return api.CurrentUserHasRole(c, db, "organization_id", MemberRoles)
 }
 
-func (api *API) ServiceNetworkIsOwnedByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
-	return api.CurrentUserHasRole(c, db, "organization_id", OwnerRoles)
+func (api *API) ServiceNetworkIsWriteableByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
+	return api.CurrentUserHasRole(c, db, "organization_id", NetworkAdminRoles)
+}
+
+// ServiceNetworkIsJoinableByCurrentUser matches the service networks the current user can register sites in
+func (api *API) ServiceNetworkIsJoinableByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
+	return api.CurrentUserHasRole(c, db, "organization_id", DeviceCreatorRoles)
 }
 
 // ListServiceNetworks lists all ServiceNetworks
@@ -199,7 +204,7 @@ func (api *API) DeleteServiceNetwork(c *gin.Context) {
 
 	var serviceNetwork models.ServiceNetwork
 	db := api.db.WithContext(ctx)
-	result := api.ServiceNetworkIsOwnedByCurrentUser(c, db).
+	result := api.ServiceNetworkIsWriteableByCurrentUser(c, db).
 		First(&serviceNetwork, "id = ?", id)
 
 	if result.Error != nil {
@@ -278,7 +283,7 @@ func (api *API) UpdateServiceNetwork(c *gin.Context) {
 	var serviceNetwork models.ServiceNetwork
 	err = api.transaction(ctx, func(tx *gorm.DB) error {
 
-		result := api.ServiceNetworkIsOwnedByCurrentUser(c, tx).First(&serviceNetwork, "id = ?", id)
+		result := api.ServiceNetworkIsWriteableByCurrentUser(c, tx).First(&serviceNetwork, "id = ?", id)
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("service_network"))
 		}
@@ -115,6 +115,16 @@ func (api *API) SiteIsOwnedByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
 	return db.Where("owner_id = ?", userId)
 }
 
+// SiteIsManagedByCurrentUser matches the sites of the current user, and the sites of the organizations
+// where the current user is a device operator.
+func (api *API) SiteIsManagedByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
+	userId := api.GetCurrentUserID(c)
+	return db.Where(
+		db.Where("owner_id = ?", userId).
+			Or(api.CurrentUserHasRole(c, db, "organization_id", DeviceOperatorRoles)),
+	)
+}
+
 // GetSite gets a site by ID
 // @Summary      Get Sites
 // @Description  Gets a site by ID
@@ -148,7 +158,7 @@ func (api *API) GetSite(c *gin.Context) {
 	var site models.Site
 
 	db := api.db.WithContext(ctx)
-	db = api.SiteIsOwnedByCurrentUser(c, db)
+	db = api.SiteIsManagedByCurrentUser(c, db)
 	result := db.First(&site, "id = ?", k)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		c.Status(http.StatusNotFound)
@@ -210,7 +220,7 @@ func (api *API) UpdateSite(c *gin.Context) {
 	var tokenClaims *models.NexodusClaims
 	err = api.transaction(ctx, func(tx *gorm.DB) error {
 
-		db := api.SiteIsOwnedByCurrentUser(c, tx)
+		db := api.SiteIsManagedByCurrentUser(c, tx)
 		db = FilterAndPaginate(db, &models.Site{}, c, "hostname")
 
 		result := db.First(&site, "id = ?", siteId)
@@ -321,7 +331,7 @@ func (api *API) CreateSite(c *gin.Context) {
 	err := api.transaction(ctx, func(tx *gorm.DB) error {
 
 		var ServiceNetwork models.ServiceNetwork
-		if result := api.ServiceNetworkIsReadableByCurrentUser(c, tx).
+		if result := api.ServiceNetworkIsJoinableByCurrentUser(c, tx).
 			Preload("Organization").
 			First(&ServiceNetwork, "id = ?", request.ServiceNetworkID); result.Error != nil {
 			return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("service_network"))
@@ -449,7 +459,7 @@ func (api *API) DeleteSite(c *gin.Context) {
 
 	site := models.Site{}
 	db := api.db.WithContext(ctx)
-	if res := api.SiteIsOwnedByCurrentUser(c, db).
+	if res := api.SiteIsManagedByCurrentUser(c, db).
 		First(&site, "id = ?", siteID); res.Error != nil {
 
 		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
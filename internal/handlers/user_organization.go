@@ -2,13 +2,18 @@ package handlers
 
 import (
 	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/nexodus-io/nexodus/internal/models"
+	"github.com/nexodus-io/nexodus/internal/util"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/maps"
 	"gorm.io/gorm"
-	"net/http"
 )
 
 // IsMemberOfOrg checks if the current user is a member of the organization, returns true if he is.
@@ -159,6 +164,110 @@ func (api *API) GetOrganizationUser(c *gin.Context) {
 	c.JSON(http.StatusOK, model)
 }
 
+// UpdateOrganizationUser changes the roles of a user in an organization
+// @Summary      Update Organization User
+// @Description  Changes the roles of a user in an organization
+// @Id 			 UpdateOrganizationUser
+// @Tags         Organizations
+// @Accept       json
+// @Produce      json
+// @Param		 id   path      string true "Organization ID"
+// @Param		 uid  path      string true "User ID"
+// @Param		 update body models.UpdateUserOrganization true "Organization User Update"
+// @Success      200  {object}  models.UserOrganization
+// @Failure      400  {object}  models.BaseError
+// @Failure		 401  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/organizations/{id}/users/{uid} [patch]
+func (api *API) UpdateOrganizationUser(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "UpdateOrganizationUser",
+		trace.WithAttributes(
+			attribute.String("id", c.Param("id")),
+			attribute.String("uid", c.Param("uid")),
+		))
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+	uid, err := uuid.Parse(c.Param("uid"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("uid"))
+		return
+	}
+
+	var request models.UpdateUserOrganization
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPayloadError(err))
+		return
+	}
+	if len(request.Roles) == 0 {
+		c.JSON(http.StatusBadRequest, models.NewFieldNotPresentError("roles"))
+		return
+	}
+	if len(util.FilterOutAllowed(request.Roles, allowedRoles)) > 0 {
+		c.JSON(http.StatusBadRequest, models.NewFieldValidationError("roles", "allowed values are: "+strings.Join(maps.Keys(allowedRoles), ", ")))
+		return
+	}
+
+	var model models.UserOrganization
+	err = api.transaction(ctx, func(tx *gorm.DB) error {
+
+		// Only allow org owners to change the roles of the members...
+		var org models.Organization
+		if res := api.OrganizationIsOwnedByCurrentUser(c, tx).
+			First(&org, "id = ?", id); res.Error != nil {
+			return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("organization"))
+		}
+
+		result := tx.Joins("User").
+			Where("organization_id=? AND user_id=?", id, uid).
+			First(&model)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("user"))
+			}
+			return result.Error
+		}
+
+		// don't allow removing the last owner of the org...
+		if slices.Contains(model.Roles, RoleOwner) && !slices.Contains(request.Roles, RoleOwner) {
+			var members []models.UserOrganization
+			if res := tx.Where("organization_id=? AND user_id<>?", id, uid).Find(&members); res.Error != nil {
+				return res.Error
+			}
+			if !slices.ContainsFunc(members, func(member models.UserOrganization) bool {
+				return slices.Contains(member.Roles, RoleOwner)
+			}) {
+				return NewApiResponseError(http.StatusBadRequest, models.NewFieldValidationError("roles", "the organization must keep at least one owner"))
+			}
+		}
+
+		result = tx.Model(&models.UserOrganization{UserID: uid, OrganizationID: id}).
+			Update("roles", models.StringArray(request.Roles))
+		if result.Error != nil {
+			return result.Error
+		}
+		model.Roles = request.Roles
+		return nil
+	})
+	if err != nil {
+		var apiResponseError *ApiResponseError
+		if errors.As(err, &apiResponseError) {
+			c.JSON(apiResponseError.Status, apiResponseError.Body)
+		} else {
+			api.SendInternalServerError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, model)
+}
+
 // DeleteOrganizationUser handles deleting a user from an organization
 // @Summary      Delete a Organization User
 // @Description  Deletes an existing organization user
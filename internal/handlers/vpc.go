@@ -34,6 +34,7 @@ const (
 // @Success      201  {object}  models.VPC
 // @Failure      400  {object}  models.BaseError
 // @Failure		 401  {object}  models.BaseError
+// @Failure      403  {object}  models.QuotaExceededError
 // @Failure		 405  {object}  models.BaseError
 // @Failure      409  {object}  models.ConflictsError
 // @Failure		 429  {object}  models.BaseError
@@ -92,11 +93,15 @@ func (api *API) CreateVPC(c *gin.Context) {
 	err := api.transaction(ctx, func(tx *gorm.DB) error {
 
 		var org models.Organization
-		if res := api.OrganizationIsReadableByCurrentUser(c, tx).
+		if res := api.CurrentUserHasRole(c, tx, "id", NetworkCreatorRoles).
 			First(&org, "id = ?", request.OrganizationID.String()); res.Error != nil {
 			return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("organization"))
 		}
 
+		if err := api.checkQuota(tx, org.ID, vpcQuota); err != nil {
+			return err
+		}
+
 		vpc = models.VPC{
 			OrganizationID: request.OrganizationID,
 			Description:    request.Description,
@@ -158,8 +163,13 @@ func (api *API) VPCIsReadableByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB
 	return api.CurrentUserHasRole(c, db, "organization_id", MemberRoles)
 }
 
-func (api *API) VPCIsOwnedByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
-	return api.CurrentUserHasRole(c, db, "organization_id", OwnerRoles)
+func (api *API) VPCIsWriteableByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
+	return api.CurrentUserHasRole(c, db, "organization_id", NetworkAdminRoles)
+}
+
+// VPCIsJoinableByCurrentUser matches the VPCs the current user can register devices in
+func (api *API) VPCIsJoinableByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
+	return api.CurrentUserHasRole(c, db, "organization_id", DeviceCreatorRoles)
 }
 
 // ListVPCs lists all VPCs
@@ -268,7 +278,7 @@ func (api *API) DeleteVPC(c *gin.Context) {
 
 	var vpc models.VPC
 	db := api.db.WithContext(ctx)
-	result := api.VPCIsOwnedByCurrentUser(c, db).
+	result := api.VPCIsWriteableByCurrentUser(c, db).
 		First(&vpc, "id = ?", id)
 
 	if result.Error != nil {
@@ -305,6 +315,23 @@ func (api *API) DeleteVPC(c *gin.Context) {
 		api.SendInternalServerError(c, res.Error)
 		return
 	}
+	var unpeered []models.VPCPeering
+	err = api.transaction(ctx, func(tx *gorm.DB) error {
+		var peerings []models.VPCPeering
+		if res := tx.Where("requester_vpc_id = ? OR accepter_vpc_id = ?", id, id).Find(&peerings); res.Error != nil {
+			return res.Error
+		}
+		var err error
+		unpeered, err = deleteVPCPeerings(tx, peerings)
+		return err
+	})
+	if err != nil {
+		api.SendInternalServerError(c, err)
+		return
+	}
+	for _, peering := range unpeered {
+		api.notifyVPCPeeringChange(peering)
+	}
 
 	result = db.Delete(&vpc)
 	if result.Error != nil {
@@ -370,7 +397,7 @@ func (api *API) UpdateVPC(c *gin.Context) {
 	var vpc models.VPC
 	err = api.transaction(ctx, func(tx *gorm.DB) error {
 
-		result := api.VPCIsOwnedByCurrentUser(c, tx).First(&vpc, "id = ?", id)
+		result := api.VPCIsWriteableByCurrentUser(c, tx).First(&vpc, "id = ?", id)
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("vpc"))
 		}
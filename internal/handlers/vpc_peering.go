@@ -0,0 +1,482 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/database"
+	"github.com/nexodus-io/nexodus/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// peerVpcIdsQuery selects the VPCs that have an active peering with the @vpc VPC.
+const peerVpcIdsQuery = "SELECT accepter_vpc_id FROM vpc_peerings WHERE requester_vpc_id = @vpc AND status = @status AND deleted_at IS NULL" +
+	" UNION SELECT requester_vpc_id FROM vpc_peerings WHERE accepter_vpc_id = @vpc AND status = @status AND deleted_at IS NULL"
+
+// unpeeredDevicesQuery matches the devices of the VPCs whose active peering with the @vpc VPC was deleted, as long as
+// they did not change after the peering was deleted.
+const unpeeredDevicesQuery = "SELECT 1 FROM vpc_peerings WHERE status = @status AND deleted_at IS NOT NULL AND devices.revision <= unpeered_revision" +
+	" AND ((requester_vpc_id = @vpc AND accepter_vpc_id = devices.vpc_id) OR (accepter_vpc_id = @vpc AND requester_vpc_id = devices.vpc_id))"
+
+// devicesInVPC limits the devices to the ones in the vpc or in the VPCs actively peered with it
+func devicesInVPC(db *gorm.DB, vpcId uuid.UUID) *gorm.DB {
+	return db.Where("vpc_id = @vpc OR vpc_id IN ("+peerVpcIdsQuery+")", map[string]interface{}{
+		"vpc":    vpcId,
+		"status": models.VPCPeeringStatusActive,
+	})
+}
+
+// activePeerVpcIds returns the VPCs actively peered with the vpc
+func activePeerVpcIds(db *gorm.DB, vpcId uuid.UUID) (map[uuid.UUID]bool, error) {
+	var peerings []models.VPCPeering
+	result := db.Where("(requester_vpc_id = ? OR accepter_vpc_id = ?) AND status = ?", vpcId, vpcId, models.VPCPeeringStatusActive).
+		Find(&peerings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	peers := map[uuid.UUID]bool{}
+	for _, peering := range peerings {
+		if peering.RequesterVpcID == vpcId {
+			peers[peering.AccepterVpcID] = true
+		} else {
+			peers[peering.RequesterVpcID] = true
+		}
+	}
+	return peers, nil
+}
+
+// fetchDevicesInVPC fetches the device changes watched in a vpc, which include the devices of its peered VPCs. The
+// devices of the VPCs whose peering was removed are returned as deleted, up to the revisions they were bumped to when
+// the peering was removed, so that the watchers drop those peers once.
+func fetchDevicesInVPC(db *gorm.DB, vpcId uuid.UUID, gtRevision uint64) (deviceList, error) {
+	peers, err := activePeerVpcIds(db, vpcId)
+	if err != nil {
+		return nil, err
+	}
+
+	var items deviceList
+	db = db.Unscoped().Limit(100).Order("revision")
+	if gtRevision != 0 {
+		db = db.Where("revision > ?", gtRevision)
+	}
+	db = db.Where("vpc_id = @vpc OR vpc_id IN ("+peerVpcIdsQuery+") OR EXISTS ("+unpeeredDevicesQuery+")", map[string]interface{}{
+		"vpc":    vpcId,
+		"status": models.VPCPeeringStatusActive,
+	})
+	result := db.Find(&items)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, result.Error
+	}
+
+	for _, item := range items {
+		if item.VpcID != vpcId && !peers[item.VpcID] && !item.DeletedAt.Valid {
+			item.DeletedAt = gorm.DeletedAt{Time: item.UpdatedAt, Valid: true}
+		}
+	}
+	return items, nil
+}
+
+// touchDevicesInVPCs bumps the revision of the devices of the VPCs, so that the watchers of the VPCs peered with
+// them fetch the devices again after the peering changed. It returns the highest revision of the devices.
+func touchDevicesInVPCs(tx *gorm.DB, vpcIds ...uuid.UUID) (uint64, error) {
+	if res := tx.Model(&models.Device{}).Where("vpc_id IN ?", vpcIds).Update("updated_at", time.Now()); res.Error != nil {
+		return 0, res.Error
+	}
+	var revision uint64
+	if res := tx.Model(&models.Device{}).Unscoped().Where("vpc_id IN ?", vpcIds).
+		Select("COALESCE(MAX(revision), 0)").Scan(&revision); res.Error != nil {
+		return 0, res.Error
+	}
+	return revision, nil
+}
+
+// deleteVPCPeerings deletes the peerings. The devices of the VPCs of the active ones are touched, so that the
+// watchers of each VPC drop the devices of the other one. It returns the active peerings, whose VPCs have to be
+// notified with notifyVPCPeeringChange once the transaction is committed.
+func deleteVPCPeerings(tx *gorm.DB, peerings []models.VPCPeering) ([]models.VPCPeering, error) {
+	var active []models.VPCPeering
+	for _, peering := range peerings {
+		if peering.Status == models.VPCPeeringStatusActive {
+			revision, err := touchDevicesInVPCs(tx, peering.RequesterVpcID, peering.AccepterVpcID)
+			if err != nil {
+				return nil, err
+			}
+			if res := tx.Model(&peering).Update("unpeered_revision", revision); res.Error != nil {
+				return nil, res.Error
+			}
+			active = append(active, peering)
+		}
+		if res := tx.Delete(&peering); res.Error != nil {
+			return nil, res.Error
+		}
+	}
+	return active, nil
+}
+
+// notifyDeviceChange wakes up the watchers of the device's vpc and of the VPCs actively peered with it.
+func (api *API) notifyDeviceChange(c *gin.Context, device models.Device) {
+	api.signalBus.Notify(fmt.Sprintf("/devices/vpc=%s", device.VpcID.String()))
+	peers, err := activePeerVpcIds(api.db.WithContext(c), device.VpcID)
+	if err != nil {
+		api.logger.Warnf("failed to notify the vpcs peered with vpc %s: %v", device.VpcID, err)
+		return
+	}
+	for peer := range peers {
+		api.signalBus.Notify(fmt.Sprintf("/devices/vpc=%s", peer.String()))
+	}
+}
+
+func (api *API) notifyVPCPeeringChange(peering models.VPCPeering) {
+	api.signalBus.Notify(fmt.Sprintf("/devices/vpc=%s", peering.RequesterVpcID.String()))
+	api.signalBus.Notify(fmt.Sprintf("/devices/vpc=%s", peering.AccepterVpcID.String()))
+}
+
+// validateVPCPeeringCidrs verifies that the tunnel addresses of the devices of the VPCs cannot collide. The
+// VPCs that do not use a private CIDR allocate their addresses from the same IPAM pool, so they never collide.
+func validateVPCPeeringCidrs(vpc models.VPC, peer models.VPC) error {
+	if !vpc.PrivateCidr && !peer.PrivateCidr {
+		return nil
+	}
+	for _, cidrs := range [][2]string{{vpc.Ipv4Cidr, peer.Ipv4Cidr}, {vpc.Ipv6Cidr, peer.Ipv6Cidr}} {
+		prefix, err := netip.ParsePrefix(cidrs[0])
+		if err != nil {
+			return fmt.Errorf("invalid cidr of vpc %s: %w", vpc.ID, err)
+		}
+		peerPrefix, err := netip.ParsePrefix(cidrs[1])
+		if err != nil {
+			return fmt.Errorf("invalid cidr of vpc %s: %w", peer.ID, err)
+		}
+		if prefix.Overlaps(peerPrefix) {
+			return NewApiResponseError(http.StatusBadRequest, models.NewFieldValidationError("peer_vpc_id",
+				fmt.Sprintf("the cidr %s of the peer vpc overlaps the cidr %s of the vpc", peerPrefix, prefix)))
+		}
+	}
+	return nil
+}
+
+// VPCPeeringIsReadableByCurrentUser matches the peerings of the VPCs of the organizations the current user is a member of
+func (api *API) VPCPeeringIsReadableByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
+	return api.vpcPeeringOfCurrentUser(c, db, MemberRoles)
+}
+
+// VPCPeeringIsWriteableByCurrentUser matches the peerings that the current user administers the network of either side of
+func (api *API) VPCPeeringIsWriteableByCurrentUser(c *gin.Context, db *gorm.DB) *gorm.DB {
+	return api.vpcPeeringOfCurrentUser(c, db, NetworkAdminRoles)
+}
+
+func (api *API) vpcPeeringOfCurrentUser(c *gin.Context, db *gorm.DB, roles []string) *gorm.DB {
+	either := db.Session(&gorm.Session{NewDB: true})
+	return db.Where(api.CurrentUserHasRole(c, either, "requester_organization_id", roles).
+		Or(api.CurrentUserHasRole(c, either, "accepter_organization_id", roles)))
+}
+
+// CreateVPCPeering requests a peering between two VPCs
+// @Summary      Request a VPC Peering
+// @Description  Requests a peering between a VPC and a peer VPC, which may belong to another organization. The peering stays pending until it is accepted for the peer VPC.
+// @Id           CreateVPCPeering
+// @Tags         VPC
+// @Accept       json
+// @Produce      json
+// @Param        VPCPeering  body     models.AddVPCPeering  true  "Add VPC Peering"
+// @Success      201  {object}  models.VPCPeering
+// @Failure      400  {object}  models.ValidationError
+// @Failure		 401  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure      409  {object}  models.ConflictsError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/vpc-peerings [post]
+func (api *API) CreateVPCPeering(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "CreateVPCPeering")
+	defer span.End()
+
+	var request models.AddVPCPeering
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPayloadError(err))
+		return
+	}
+	if request.VpcID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, models.NewFieldNotPresentError("vpc_id"))
+		return
+	}
+	if request.PeerVpcID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, models.NewFieldNotPresentError("peer_vpc_id"))
+		return
+	}
+	if request.PeerVpcID == request.VpcID {
+		c.JSON(http.StatusBadRequest, models.NewFieldValidationError("peer_vpc_id", "a vpc cannot be peered with itself"))
+		return
+	}
+
+	var peering models.VPCPeering
+	err := api.transaction(ctx, func(tx *gorm.DB) error {
+		var vpc models.VPC
+		if res := api.VPCIsWriteableByCurrentUser(c, tx).
+			First(&vpc, "id = ?", request.VpcID); res.Error != nil {
+			if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("vpc"))
+			}
+			return res.Error
+		}
+
+		// the peer vpc may belong to any organization, its owners decide whether to accept the peering
+		var peer models.VPC
+		if res := tx.First(&peer, "id = ?", request.PeerVpcID); res.Error != nil {
+			if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("peer vpc"))
+			}
+			return res.Error
+		}
+
+		if err := validateVPCPeeringCidrs(vpc, peer); err != nil {
+			return err
+		}
+
+		var existing models.VPCPeering
+		res := tx.Where("(requester_vpc_id = @vpc AND accepter_vpc_id = @peer) OR (requester_vpc_id = @peer AND accepter_vpc_id = @vpc)",
+			map[string]interface{}{"vpc": vpc.ID, "peer": peer.ID}).
+			First(&existing)
+		if res.Error == nil {
+			return NewApiResponseError(http.StatusConflict, models.NewConflictsError(existing.ID.String()))
+		}
+		if !errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return res.Error
+		}
+
+		peering = models.VPCPeering{
+			RequesterVpcID:          vpc.ID,
+			RequesterOrganizationID: vpc.OrganizationID,
+			AccepterVpcID:           peer.ID,
+			AccepterOrganizationID:  peer.OrganizationID,
+			Status:                  models.VPCPeeringStatusPending,
+			Description:             request.Description,
+		}
+		if res := tx.Create(&peering); res.Error != nil {
+			if database.IsDuplicateError(res.Error) {
+				return NewApiResponseError(http.StatusConflict, models.NewConflictsError(peering.ID.String()))
+			}
+			return fmt.Errorf("failed to create vpc peering: %w", res.Error)
+		}
+		span.SetAttributes(attribute.String("id", peering.ID.String()))
+		return nil
+	})
+
+	if err != nil {
+		var apiResponseError *ApiResponseError
+		if errors.As(err, &apiResponseError) {
+			c.JSON(apiResponseError.Status, apiResponseError.Body)
+		} else {
+			api.SendInternalServerError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, peering)
+}
+
+// ListVPCPeerings lists the VPC peerings
+// @Summary      List VPC Peerings
+// @Description  Lists the peerings of the VPCs of the organizations of the current user, including the pending peerings requested with them
+// @Id           ListVPCPeerings
+// @Tags         VPC
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  []models.VPCPeering
+// @Failure		 401  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/vpc-peerings [get]
+func (api *API) ListVPCPeerings(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ListVPCPeerings")
+	defer span.End()
+	peerings := []models.VPCPeering{}
+
+	db := api.db.WithContext(ctx)
+	db = api.VPCPeeringIsReadableByCurrentUser(c, db)
+	db = FilterAndPaginate(db, &models.VPCPeering{}, c, "description")
+	result := db.Find(&peerings)
+	if result.Error != nil {
+		api.SendInternalServerError(c, result.Error)
+		return
+	}
+
+	c.JSON(http.StatusOK, peerings)
+}
+
+// GetVPCPeering gets a VPC peering
+// @Summary      Get VPC Peering
+// @Description  Gets a VPC peering by ID
+// @Id           GetVPCPeering
+// @Tags         VPC
+// @Accept       json
+// @Produce      json
+// @Param		 id   path      string true "VPC Peering ID"
+// @Success      200  {object}  models.VPCPeering
+// @Failure      400  {object}  models.BaseError
+// @Failure		 401  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/vpc-peerings/{id} [get]
+func (api *API) GetVPCPeering(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "GetVPCPeering",
+		trace.WithAttributes(
+			attribute.String("id", c.Param("id")),
+		))
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	var peering models.VPCPeering
+	db := api.db.WithContext(ctx)
+	result := api.VPCPeeringIsReadableByCurrentUser(c, db).
+		First(&peering, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.NewNotFoundError("vpc peering"))
+		} else {
+			api.SendInternalServerError(c, result.Error)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, peering)
+}
+
+// AcceptVPCPeering accepts a pending VPC peering
+// @Summary      Accept VPC Peering
+// @Description  Accepts a pending VPC peering for the peer VPC, the devices of both VPCs then see each other
+// @Id           AcceptVPCPeering
+// @Tags         VPC
+// @Accept       json
+// @Produce      json
+// @Param		 id   path      string true "VPC Peering ID"
+// @Success      200  {object}  models.VPCPeering
+// @Failure      400  {object}  models.BaseError
+// @Failure		 401  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/vpc-peerings/{id}/accept [post]
+func (api *API) AcceptVPCPeering(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "AcceptVPCPeering",
+		trace.WithAttributes(
+			attribute.String("id", c.Param("id")),
+		))
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	var peering models.VPCPeering
+	accepted := false
+	err = api.transaction(ctx, func(tx *gorm.DB) error {
+		// only the network admins of the peer vpc can accept the peering
+		if res := api.CurrentUserHasRole(c, tx, "accepter_organization_id", NetworkAdminRoles).
+			First(&peering, "id = ?", id); res.Error != nil {
+			if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("vpc peering"))
+			}
+			return res.Error
+		}
+		if peering.Status == models.VPCPeeringStatusActive {
+			return nil
+		}
+
+		peering.Status = models.VPCPeeringStatusActive
+		if res := tx.Model(&peering).Update("status", peering.Status); res.Error != nil {
+			return res.Error
+		}
+		accepted = true
+		_, err := touchDevicesInVPCs(tx, peering.RequesterVpcID, peering.AccepterVpcID)
+		return err
+	})
+
+	if err != nil {
+		var apiResponseError *ApiResponseError
+		if errors.As(err, &apiResponseError) {
+			c.JSON(apiResponseError.Status, apiResponseError.Body)
+		} else {
+			api.SendInternalServerError(c, err)
+		}
+		return
+	}
+
+	if accepted {
+		api.notifyVPCPeeringChange(peering)
+	}
+	c.JSON(http.StatusOK, peering)
+}
+
+// DeleteVPCPeering deletes a VPC peering
+// @Summary      Delete VPC Peering
+// @Description  Deletes a pending or active VPC peering, the devices of the VPCs stop seeing each other
+// @Id           DeleteVPCPeering
+// @Tags         VPC
+// @Accept       json
+// @Produce      json
+// @Param		 id   path      string true "VPC Peering ID"
+// @Success      200  {object}  models.VPCPeering
+// @Failure      400  {object}  models.BaseError
+// @Failure		 401  {object}  models.BaseError
+// @Failure      404  {object}  models.BaseError
+// @Failure		 429  {object}  models.BaseError
+// @Failure      500  {object}  models.InternalServerError "Internal Server Error"
+// @Router       /api/vpc-peerings/{id} [delete]
+func (api *API) DeleteVPCPeering(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "DeleteVPCPeering",
+		trace.WithAttributes(
+			attribute.String("id", c.Param("id")),
+		))
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewBadPathParameterError("id"))
+		return
+	}
+
+	var peering models.VPCPeering
+	var active []models.VPCPeering
+	err = api.transaction(ctx, func(tx *gorm.DB) error {
+		if res := api.VPCPeeringIsWriteableByCurrentUser(c, tx).
+			First(&peering, "id = ?", id); res.Error != nil {
+			if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+				return NewApiResponseError(http.StatusNotFound, models.NewNotFoundError("vpc peering"))
+			}
+			return res.Error
+		}
+		var err error
+		active, err = deleteVPCPeerings(tx, []models.VPCPeering{peering})
+		return err
+	})
+
+	if err != nil {
+		var apiResponseError *ApiResponseError
+		if errors.As(err, &apiResponseError) {
+			c.JSON(apiResponseError.Status, apiResponseError.Body)
+		} else {
+			api.SendInternalServerError(c, err)
+		}
+		return
+	}
+
+	for _, peering := range active {
+		api.notifyVPCPeeringChange(peering)
+	}
+	c.JSON(http.StatusOK, peering)
+}
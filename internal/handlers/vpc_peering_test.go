@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/models"
+)
+
+func (suite *HandlerTestSuite) TestVPCPeering() {
+	require := suite.Require()
+	api := suite.api
+	db := api.db
+
+	// the web and db VPCs belong to different organizations
+	createVPC := func(name string, vpc models.VPC) (uuid.UUID, models.VPC, models.Device) {
+		org := models.Organization{Name: name}
+		require.NoError(db.Create(&org).Error)
+		user := models.User{Base: models.Base{ID: uuid.New()}, IdpID: uuid.NewString(), UserName: name}
+		require.NoError(db.Create(&user).Error)
+		require.NoError(db.Create(&models.UserOrganization{UserID: user.ID, OrganizationID: org.ID, Roles: []string{RoleOwner}}).Error)
+		vpc.OrganizationID = org.ID
+		require.NoError(db.Create(&vpc).Error)
+		device := models.Device{VpcID: vpc.ID, OrganizationID: org.ID, OwnerID: user.ID, PublicKey: name, SecurityGroupIds: models.StringArray{}}
+		require.NoError(db.Create(&device).Error)
+		return user.ID, vpc, device
+	}
+	webUser, web, webDevice := createVPC("web", models.VPC{Ipv4Cidr: "100.64.0.0/10", Ipv6Cidr: "200::/64"})
+	dbUser, dbVPC, dbDevice := createVPC("database", models.VPC{Ipv4Cidr: "100.64.0.0/10", Ipv6Cidr: "200::/64"})
+	_, private, _ := createVPC("private", models.VPC{PrivateCidr: true, Ipv4Cidr: "100.100.0.0/16", Ipv6Cidr: "300::/64"})
+
+	serve := func(userID uuid.UUID, handler gin.HandlerFunc, id uuid.UUID, body interface{}) (int, models.VPCPeering) {
+		_, res, err := suite.ServeRequestAsUser(userID, http.MethodPost, "/vpc-peerings/:id", "/vpc-peerings/"+id.String(),
+			handler, bytes.NewBuffer(suite.jsonMarshal(body)))
+		require.NoError(err)
+		var peering models.VPCPeering
+		if res.Code < 300 {
+			require.NoError(json.Unmarshal(res.Body.Bytes(), &peering))
+		}
+		return res.Code, peering
+	}
+	devices := func(vpcId uuid.UUID) map[uuid.UUID]bool {
+		items, err := fetchDevicesInVPC(db, vpcId, 0)
+		require.NoError(err)
+		deleted := map[uuid.UUID]bool{}
+		for _, item := range items {
+			deleted[item.ID] = item.DeletedAt.Valid
+		}
+		return deleted
+	}
+
+	// the private vpc uses addresses of the shared pool
+	code, _ := serve(webUser, api.CreateVPCPeering, uuid.Nil, models.AddVPCPeering{VpcID: web.ID, PeerVpcID: private.ID})
+	require.Equal(http.StatusBadRequest, code)
+	code, _ = serve(webUser, api.CreateVPCPeering, uuid.Nil, models.AddVPCPeering{VpcID: dbVPC.ID, PeerVpcID: web.ID})
+	require.Equal(http.StatusNotFound, code)
+
+	code, peering := serve(webUser, api.CreateVPCPeering, uuid.Nil, models.AddVPCPeering{VpcID: web.ID, PeerVpcID: dbVPC.ID})
+	require.Equal(http.StatusCreated, code)
+	require.Equal(models.VPCPeeringStatusPending, peering.Status)
+	require.Equal(dbVPC.OrganizationID, peering.AccepterOrganizationID)
+	code, _ = serve(dbUser, api.CreateVPCPeering, uuid.Nil, models.AddVPCPeering{VpcID: dbVPC.ID, PeerVpcID: web.ID})
+	require.Equal(http.StatusConflict, code)
+
+	// a pending peering does not share the devices
+	require.Equal(map[uuid.UUID]bool{webDevice.ID: false}, devices(web.ID))
+
+	// only the peer vpc can accept the peering
+	code, _ = serve(webUser, api.AcceptVPCPeering, peering.ID, nil)
+	require.Equal(http.StatusNotFound, code)
+	code, peering = serve(dbUser, api.AcceptVPCPeering, peering.ID, nil)
+	require.Equal(http.StatusOK, code)
+	require.Equal(models.VPCPeeringStatusActive, peering.Status)
+
+	require.Equal(map[uuid.UUID]bool{webDevice.ID: false, dbDevice.ID: false}, devices(web.ID))
+	require.Equal(map[uuid.UUID]bool{webDevice.ID: false, dbDevice.ID: false}, devices(dbVPC.ID))
+	var listed []models.Device
+	require.NoError(devicesInVPC(db, web.ID).Find(&listed).Error)
+	require.Len(listed, 2)
+
+	// the watchers of the web vpc are woken up by the changes of the devices of the peered vpc
+	sub := api.signalBus.Subscribe(fmt.Sprintf("/devices/vpc=%s", web.ID))
+	defer sub.Close()
+	_, res, err := suite.ServeRequestAsUser(dbUser, http.MethodPatch, "/devices/:id", "/devices/"+dbDevice.ID.String(),
+		api.UpdateDevice, bytes.NewBuffer(suite.jsonMarshal(models.UpdateDevice{Hostname: "database"})))
+	require.NoError(err)
+	require.Equal(http.StatusOK, res.Code, res.Body.String())
+	require.True(sub.IsSignaled())
+	var updated models.Device
+	require.NoError(devicesInVPC(db, web.ID).First(&updated, "id = ?", dbDevice.ID).Error)
+	require.Equal("database", updated.Hostname)
+
+	// the rules of the security groups can reference the peered vpc
+	rules := []models.SecurityRule{{IpProtocol: "tcp", FromPort: 5432, ToPort: 5432, VpcIds: []uuid.UUID{web.ID}}}
+	require.NoError(validateSecurityGroupReferences(db, dbVPC.ID, rules))
+	rules[0].VpcIds = []uuid.UUID{private.ID}
+	var apiResponseError *ApiResponseError
+	require.True(errors.As(validateSecurityGroupReferences(db, dbVPC.ID, rules), &apiResponseError))
+	require.Equal(http.StatusUnprocessableEntity, apiResponseError.Status)
+
+	// the watchers of the vpcs drop the devices of the vpc they are no longer peered with
+	code, _ = serve(webUser, api.DeleteVPCPeering, peering.ID, nil)
+	require.Equal(http.StatusOK, code)
+	require.Equal(map[uuid.UUID]bool{webDevice.ID: false, dbDevice.ID: true}, devices(web.ID))
+	require.Equal(map[uuid.UUID]bool{webDevice.ID: true, dbDevice.ID: false}, devices(dbVPC.ID))
+	require.NoError(devicesInVPC(db, web.ID).Find(&listed).Error)
+	require.Len(listed, 1)
+
+	// but only up to the revision the devices were bumped to when the peering was deleted
+	var unpeered models.VPCPeering
+	require.NoError(db.Unscoped().First(&unpeered, "id = ?", peering.ID).Error)
+	require.NoError(db.Model(&dbDevice).UpdateColumn("revision", unpeered.UnpeeredRevision+1).Error)
+	require.Equal(map[uuid.UUID]bool{webDevice.ID: false}, devices(web.ID))
+
+	// deleting a vpc unpeers it the same way
+	cache := models.VPC{OrganizationID: web.OrganizationID, Ipv4Cidr: "100.64.0.0/10", Ipv6Cidr: "200::/64"}
+	require.NoError(db.Create(&cache).Error)
+	code, peering = serve(webUser, api.CreateVPCPeering, uuid.Nil, models.AddVPCPeering{VpcID: cache.ID, PeerVpcID: web.ID})
+	require.Equal(http.StatusCreated, code)
+	code, _ = serve(webUser, api.AcceptVPCPeering, peering.ID, nil)
+	require.Equal(http.StatusOK, code)
+	require.True(sub.IsSignaled())
+	_, res, err = suite.ServeRequestAsUser(webUser, http.MethodDelete, "/vpcs/:id", "/vpcs/"+cache.ID.String(), api.DeleteVPC, nil)
+	require.NoError(err)
+	require.Equal(http.StatusOK, res.Code, res.Body.String())
+	require.True(sub.IsSignaled())
+	var deleted models.VPCPeering
+	require.NoError(db.Unscoped().First(&deleted, "id = ?", peering.ID).Error)
+	require.True(deleted.DeletedAt.Valid)
+}
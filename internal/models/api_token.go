@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApiToken is a long-lived token used to access the API without an interactive login.
+type ApiToken struct {
+	Base
+	OwnerID          uuid.UUID   `json:"owner_id"`                                   // OwnerID is the ID of the user that created the token.
+	UserID           uuid.UUID   `json:"user_id"`                                    // UserID is the ID of the user the token acts as, the owner or the user of the service account.
+	ServiceAccountID *uuid.UUID  `json:"service_account_id,omitempty"`               // ServiceAccountID is set when the token belongs to a service account.
+	OrganizationID   *uuid.UUID  `json:"organization_id,omitempty" gorm:"type:uuid"` // OrganizationID is denormalized from the service account record for performance
+	Description      string      `json:"description,omitempty"`                      // Description of the token.
+	Scopes           StringArray `json:"scopes" swaggertype:"array,string"`          // Scopes limit the API endpoints the token can access.
+	ExpiresAt        *time.Time  `json:"expires_at,omitempty"`                       // ExpiresAt is optional, if set the token is only valid until the ExpiresAt time.
+	AccessToken      string      `json:"access_token,omitempty" gorm:"-"`            // AccessToken is the bearer token to send to the API, it is only returned when the token is created.
+}
+
+type AddApiToken struct {
+	ServiceAccountID *uuid.UUID `json:"service_account_id,omitempty"` // ServiceAccountID is the ID of the service account the token belongs to, the token belongs to the current user when not set.
+	Description      string     `json:"description,omitempty"`        // Description of the token.
+	Scopes           []string   `json:"scopes"`                       // Scopes limit the API endpoints the token can access, like read:devices or write:organizations.
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`         // ExpiresAt is optional, if set the token is only valid until the ExpiresAt time.
+}
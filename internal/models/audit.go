@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntry records a change made to a resource through the API. The entries of an organization form a hash chain,
+// each entry hashes the hash of the previous one with a key only the api server has, so that changing or removing an
+// entry breaks the chain.
+type AuditEntry struct {
+	ID             uuid.UUID       `json:"id" gorm:"type:uuid;primary_key" example:"aa22666c-0f57-45cb-a449-16efecc04f2e"`
+	OrganizationID uuid.UUID       `json:"organization_id" gorm:"type:uuid;uniqueIndex:idx_audit_entries_chain"`                 // OrganizationID is the organization of the changed resource, nil for resources outside organizations
+	Sequence       int64           `json:"sequence" gorm:"uniqueIndex:idx_audit_entries_chain" format:"int64" example:"1"`       // Sequence is the position of the entry in the chain of the organization, starting at 1
+	CreatedAt      time.Time       `json:"created_at"`                                                                           // CreatedAt is when the change was made
+	ActorKind      string          `json:"actor_kind" example:"user"`                                                            // ActorKind is user, reg-key, device or api-token
+	ActorID        uuid.UUID       `json:"actor_id" gorm:"type:uuid"`                                                            // ActorID is the ID of the user, registration key, device or api token that made the change
+	UserID         uuid.UUID       `json:"user_id" gorm:"type:uuid"`                                                             // UserID is the user the actor acts for
+	SourceIP       string          `json:"source_ip" example:"192.0.2.10"`                                                       // SourceIP is the client address of the request
+	Method         string          `json:"method" example:"PATCH"`                                                               // Method of the request that made the change
+	Path           string          `json:"path" example:"/api/devices/aa22666c-0f57-45cb-a449-16efecc04f2e"`                     // Path of the request that made the change
+	Action         string          `json:"action" example:"update"`                                                              // Action is create, update or delete
+	ResourceKind   string          `json:"resource_kind" example:"devices"`                                                      // ResourceKind is the kind of the changed resource
+	ResourceID     string          `json:"resource_id" example:"aa22666c-0f57-45cb-a449-16efecc04f2e"`                           // ResourceID is the ID of the changed resource, IDs made of several fields are joined with '/'
+	Diff           json.RawMessage `json:"diff" gorm:"type:JSONB; serializer:json" swaggertype:"object"`                         // Diff maps the changed fields of the resource to their before and after values
+	PrevHash       string          `json:"prev_hash" example:"3f0c9a0a1f5e3b0c6e8a1d2b7c4f5e6a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e"` // PrevHash is the hash of the previous entry of the chain, empty for the first entry
+	Hash           string          `json:"hash" example:"9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f0c9a0a1f5e3b0c6e8a1d2b7c4f5e6a"`      // Hash is the HMAC-SHA256 of the entry and PrevHash
+}
+
+// AuditHead is the last entry of the audit chain of an organization. It is stored apart from the entries, so that
+// removing the latest entries also breaks the chain.
+type AuditHead struct {
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"type:uuid;primary_key"`
+	Sequence       int64     `json:"sequence"`
+	Hash           string    `json:"hash"`
+}
+
+// AuditChange is the before and after value of a changed field, a missing value means the resource did not exist
+type AuditChange struct {
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// AuditVerification is the result of checking the hash chain of the audit entries of an organization
+type AuditVerification struct {
+	Verified        bool   `json:"verified"`                                                                                       // Verified is true when every entry of the chain is intact
+	Entries         int64  `json:"entries" format:"int64" example:"42"`                                                            // Entries is the number of entries checked
+	HeadSequence    int64  `json:"head_sequence" format:"int64" example:"42"`                                                      // HeadSequence is the sequence of the last entry of the chain
+	HeadHash        string `json:"head_hash,omitempty" example:"9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f0c9a0a1f5e3b0c6e8a1d2b7c4f5e6a"` // HeadHash is the hash of the last entry of the chain
+	InvalidSequence *int64 `json:"invalid_sequence,omitempty" format:"int64" example:"7"`                                          // InvalidSequence is the sequence of the first entry that does not match the chain
+	Error           string `json:"error,omitempty" example:"hash does not match"`                                                  // Error describes why the entry does not match
+}
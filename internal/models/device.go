@@ -11,49 +11,74 @@ import (
 // Devices belong to one User and may be onboarded into an organization
 type Device struct {
 	Base
-	OwnerID         uuid.UUID      `json:"owner_id"`
-	VpcID           uuid.UUID      `json:"vpc_id" example:"694aa002-5d19-495e-980b-3d8fd508ea10"`
-	OrganizationID  uuid.UUID      `json:"-"` // Denormalized from the VPC record for performance
-	PublicKey       string         `json:"public_key"`
-	AllowedIPs      pq.StringArray `json:"allowed_ips" gorm:"type:text[]" swaggertype:"array,string"`
-	IPv4TunnelIPs   []TunnelIP     `json:"ipv4_tunnel_ips" gorm:"type:JSONB; serializer:json"`
-	IPv6TunnelIPs   []TunnelIP     `json:"ipv6_tunnel_ips" gorm:"type:JSONB; serializer:json"`
-	AdvertiseCidrs  pq.StringArray `json:"advertise_cidrs" gorm:"type:text[]" swaggertype:"array,string"`
-	Relay           bool           `json:"relay"`
-	SymmetricNat    bool           `json:"symmetric_nat"`
-	Hostname        string         `json:"hostname"`
-	Os              string         `json:"os"`
-	Endpoints       []Endpoint     `json:"endpoints" gorm:"type:JSONB; serializer:json"`
-	Revision        uint64         `json:"revision" gorm:"type:bigserial;index:"`
-	SecurityGroupId uuid.UUID      `json:"security_group_id"`
-	Online          bool           `json:"online"`
-	OnlineAt        *time.Time     `json:"online_at"`
-	RegKeyID        uuid.UUID      `json:"-"`                      // the reg key id that created the device (if it was created with a registration token)
-	BearerToken     string         `json:"bearer_token,omitempty"` // the token nexd should use to reconcile device state.
+	OwnerID          uuid.UUID       `json:"owner_id"`
+	VpcID            uuid.UUID       `json:"vpc_id" example:"694aa002-5d19-495e-980b-3d8fd508ea10"`
+	OrganizationID   uuid.UUID       `json:"-"` // Denormalized from the VPC record for performance
+	PublicKey        string          `json:"public_key"`
+	AllowedIPs       pq.StringArray  `json:"allowed_ips" gorm:"type:text[]" swaggertype:"array,string"`
+	IPv4TunnelIPs    []TunnelIP      `json:"ipv4_tunnel_ips" gorm:"type:JSONB; serializer:json"`
+	IPv6TunnelIPs    []TunnelIP      `json:"ipv6_tunnel_ips" gorm:"type:JSONB; serializer:json"`
+	AdvertiseCidrs   pq.StringArray  `json:"advertise_cidrs" gorm:"type:text[]" swaggertype:"array,string"`
+	Relay            bool            `json:"relay"`
+	SymmetricNat     bool            `json:"symmetric_nat"`
+	Hostname         string          `json:"hostname"`
+	Os               string          `json:"os"`
+	Endpoints        []Endpoint      `json:"endpoints" gorm:"type:JSONB; serializer:json"`
+	Revision         uint64          `json:"revision" gorm:"type:bigserial;index:"`
+	SecurityGroupId  uuid.UUID       `json:"security_group_id"`                                   // SecurityGroupId is the first of the SecurityGroupIds, it is kept for older clients.
+	SecurityGroupIds StringArray     `json:"security_group_ids" swaggertype:"array,string"`       // SecurityGroupIds are the security groups of the device, the device is allowed the traffic allowed by any of them.
+	ExitNodePolicy   *ExitNodePolicy `json:"exit_node_policy" gorm:"type:JSONB; serializer:json"` // ExitNodePolicy selects the traffic this device sends through an exit node, all of it when not set.
+	TrafficLimits    *TrafficLimits  `json:"traffic_limits" gorm:"type:JSONB; serializer:json"`   // TrafficLimits caps the traffic the device sends into the VPC, it is unlimited when not set.
+	Online           bool            `json:"online"`
+	OnlineAt         *time.Time      `json:"online_at"`
+	RegKeyID         uuid.UUID       `json:"-"`                      // the reg key id that created the device (if it was created with a registration token)
+	BearerToken      string          `json:"bearer_token,omitempty"` // the token nexd should use to reconcile device state.
 }
 
 // AddDevice is the information needed to add a new Device.
 type AddDevice struct {
-	VpcID           uuid.UUID  `json:"vpc_id" example:"694aa002-5d19-495e-980b-3d8fd508ea10"`
-	PublicKey       string     `json:"public_key"`
-	AdvertiseCidrs  []string   `json:"advertise_cidrs" example:"172.16.42.0/24"`
-	IPv4TunnelIPs   []TunnelIP `json:"ipv4_tunnel_ips" gorm:"type:JSONB; serializer:json"`
-	Relay           bool       `json:"relay"`
-	SymmetricNat    bool       `json:"symmetric_nat"`
-	Hostname        string     `json:"hostname" example:"myhost"`
-	Endpoints       []Endpoint `json:"endpoints" gorm:"type:JSONB; serializer:json"`
-	Os              string     `json:"os"`
-	SecurityGroupId uuid.UUID  `json:"security_group_id"`
+	VpcID            uuid.UUID   `json:"vpc_id" example:"694aa002-5d19-495e-980b-3d8fd508ea10"`
+	PublicKey        string      `json:"public_key"`
+	AdvertiseCidrs   []string    `json:"advertise_cidrs" example:"172.16.42.0/24"`
+	IPv4TunnelIPs    []TunnelIP  `json:"ipv4_tunnel_ips" gorm:"type:JSONB; serializer:json"`
+	Relay            bool        `json:"relay"`
+	SymmetricNat     bool        `json:"symmetric_nat"`
+	Hostname         string      `json:"hostname" example:"myhost"`
+	Endpoints        []Endpoint  `json:"endpoints" gorm:"type:JSONB; serializer:json"`
+	Os               string      `json:"os"`
+	SecurityGroupId  uuid.UUID   `json:"security_group_id"`  // SecurityGroupId is only used when SecurityGroupIds is not set, it is kept for older clients.
+	SecurityGroupIds []uuid.UUID `json:"security_group_ids"` // SecurityGroupIds are the security groups of the device, the default security group of the vpc is used if none are set.
 }
 
 // UpdateDevice is the information needed to update a Device.
 type UpdateDevice struct {
-	VpcID           *uuid.UUID `json:"vpc_id" example:"694aa002-5d19-495e-980b-3d8fd508ea10"`
-	AdvertiseCidrs  []string   `json:"advertise_cidrs" example:"172.16.42.0/24"`
-	SymmetricNat    *bool      `json:"symmetric_nat"`
-	Hostname        string     `json:"hostname" example:"myhost"`
-	Endpoints       []Endpoint `json:"endpoints" gorm:"type:JSONB; serializer:json"`
-	Revision        *uint64    `json:"revision"`
-	Relay           *bool      `json:"relay"`
-	SecurityGroupId *uuid.UUID `json:"security_group_id"`
+	VpcID            *uuid.UUID      `json:"vpc_id" example:"694aa002-5d19-495e-980b-3d8fd508ea10"`
+	AdvertiseCidrs   []string        `json:"advertise_cidrs" example:"172.16.42.0/24"`
+	SymmetricNat     *bool           `json:"symmetric_nat"`
+	Hostname         string          `json:"hostname" example:"myhost"`
+	Endpoints        []Endpoint      `json:"endpoints" gorm:"type:JSONB; serializer:json"`
+	Revision         *uint64         `json:"revision"`
+	Relay            *bool           `json:"relay"`
+	SecurityGroupId  *uuid.UUID      `json:"security_group_id"`  // SecurityGroupId is only used when SecurityGroupIds is not set, it is kept for older clients.
+	SecurityGroupIds []uuid.UUID     `json:"security_group_ids"` // SecurityGroupIds replaces the security groups of the device when set, an empty list removes the device from all security groups.
+	ExitNodePolicy   *ExitNodePolicy `json:"exit_node_policy"`   // ExitNodePolicy replaces the exit node policy of the device when set, an empty policy sends all the traffic through the exit node.
+	TrafficLimits    *TrafficLimits  `json:"traffic_limits"`     // TrafficLimits replaces the traffic limits of the device when set, empty limits remove them.
+}
+
+// ExitNodePolicy is the split tunnel policy of an exit node client. Entries are either CIDRs or domain names,
+// domain names are resolved by the device.
+type ExitNodePolicy struct {
+	// Include are the destinations sent through the exit node, all destinations are when empty
+	Include []string `json:"include,omitempty" example:"0.0.0.0/0"`
+	// Exclude are the destinations that bypass the exit node, they take precedence over Include
+	Exclude []string `json:"exclude,omitempty" example:"192.168.0.0/16"`
+}
+
+// TrafficLimits are the rates at which the peers, relays and exit nodes receiving traffic from a device start
+// dropping it. Zero values are unlimited.
+type TrafficLimits struct {
+	// BitsPerSecond limits the bandwidth used by the device
+	BitsPerSecond uint64 `json:"bits_per_second,omitempty" format:"int64" example:"100000000"`
+	// PacketsPerSecond limits the packet rate of the device
+	PacketsPerSecond uint64 `json:"packets_per_second,omitempty" example:"10000"`
 }
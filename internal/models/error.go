@@ -138,3 +138,20 @@ func NewNotAllowedError(reason string) NotAllowedError {
 		},
 	}
 }
+
+// QuotaExceededError is returned in the body of an HTTP 403 when an organization has reached one of its quotas
+type QuotaExceededError struct {
+	BaseError
+	Quota string `json:"quota" example:"vpcs"`
+	Limit int64  `json:"limit" example:"10"`
+}
+
+func NewQuotaExceededError(quota string, limit int64) QuotaExceededError {
+	return QuotaExceededError{
+		Quota: quota,
+		Limit: limit,
+		BaseError: BaseError{
+			Error: "quota exceeded",
+		},
+	}
+}
@@ -12,6 +12,9 @@ type Organization struct {
 
 	Users       []*User       `json:"-" gorm:"many2many:user_organizations;"`
 	Invitations []*Invitation `json:"-"`
+
+	Quotas *OrganizationQuotas `json:"-" gorm:"type:JSONB; serializer:json"`
+	Usage  *OrganizationUsage  `json:"usage,omitempty" gorm:"-"` // Usage of the quotas, only reported when getting a single organization.
 }
 
 func (z *Organization) BeforeCreate(tx *gorm.DB) error {
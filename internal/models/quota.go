@@ -0,0 +1,26 @@
+package models
+
+// OrganizationQuotas override the default quotas of the api server for an organization. A quota that is not set uses
+// the default, and a quota of 0 is unlimited.
+type OrganizationQuotas struct {
+	Vpcs           *int64 `json:"vpcs,omitempty" example:"10"`
+	Devices        *int64 `json:"devices,omitempty" example:"1000"`
+	RegKeys        *int64 `json:"reg_keys,omitempty" example:"1000"`
+	SecurityGroups *int64 `json:"security_groups,omitempty" example:"500"`
+	MetadataKeys   *int64 `json:"metadata_keys,omitempty" example:"10000"`
+}
+
+// QuotaUsage is the number of resources of a kind an organization has, and the number it can have.
+type QuotaUsage struct {
+	Used  int64 `json:"used" example:"3"`
+	Limit int64 `json:"limit" example:"10"` // Limit is 0 when the quota is unlimited.
+}
+
+// OrganizationUsage reports the usage of the quotas of an organization.
+type OrganizationUsage struct {
+	Vpcs           QuotaUsage `json:"vpcs"`
+	Devices        QuotaUsage `json:"devices"`
+	RegKeys        QuotaUsage `json:"reg_keys"`
+	SecurityGroups QuotaUsage `json:"security_groups"`
+	MetadataKeys   QuotaUsage `json:"metadata_keys"` // MetadataKeys counts the metadata keys of all the devices of the organization.
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -30,10 +32,103 @@ type UpdateSecurityGroup struct {
 	OutboundRules []SecurityRule `json:"outbound_rules,omitempty" gorm:"type:JSONB; serializer:json"`
 }
 
+const (
+	SecurityRuleActionAllow = "allow"
+	SecurityRuleActionDeny  = "deny"
+)
+
 // SecurityRule represents a Security Rule
 type SecurityRule struct {
 	IpProtocol string   `json:"ip_protocol"`
 	FromPort   int64    `json:"from_port"`
 	ToPort     int64    `json:"to_port"`
 	IpRanges   []string `json:"ip_ranges,omitempty"`
+	// Action is either allow or deny, rules without an action allow the traffic.
+	Action string `json:"action,omitempty" enums:"allow,deny"`
+	// Priority orders the rules of a direction, rules with a lower priority are evaluated first.
+	Priority int64 `json:"priority,omitempty"`
+	// IcmpType optionally restricts an icmp rule to a single ICMP type.
+	IcmpType *int64 `json:"icmp_type,omitempty"`
+	// IcmpCode optionally restricts an icmp rule with an icmp_type to a single ICMP code.
+	IcmpCode *int64 `json:"icmp_code,omitempty"`
+	// SecurityGroupIds matches the tunnel IPs of the devices in the referenced security groups, in addition to the IpRanges.
+	SecurityGroupIds []uuid.UUID `json:"security_group_ids,omitempty"`
+	// VpcIds matches the tunnel IPs of the devices in the referenced VPCs, which are the VPC of the security group or VPCs actively peered with it.
+	VpcIds []uuid.UUID `json:"vpc_ids,omitempty"`
+}
+
+// SecurityGroupStatsMetadataKey is the device metadata key nexd publishes the rule counters of its security groups under
+const SecurityGroupStatsMetadataKey = "security-group-stats"
+
+// SecurityRuleStats are the counters of a security rule, Rule is the index of the rule in the inbound or outbound rules
+type SecurityRuleStats struct {
+	Rule    int    `json:"rule"`
+	Packets uint64 `json:"packets" format:"int64"`
+	Bytes   uint64 `json:"bytes"   format:"int64"`
+}
+
+// DeviceSecurityGroupStats is the value of the security-group-stats metadata of a device, it holds the
+// rule counters of every security group of the device.
+type DeviceSecurityGroupStats struct {
+	SecurityGroups []SecurityGroupDeviceStats `json:"security_groups"`
+	CollectedAt    time.Time                  `json:"collected_at"`
+}
+
+// SecurityGroupDeviceStats are the rule counters of one security group reported by a device.
+type SecurityGroupDeviceStats struct {
+	SecurityGroupId uuid.UUID           `json:"security_group_id"`
+	Revision        uint64              `json:"revision"`
+	InboundRules    []SecurityRuleStats `json:"inbound_rules"`
+	OutboundRules   []SecurityRuleStats `json:"outbound_rules"`
+}
+
+// SecurityGroupStats are the rule counters of a security group summed over the devices in the group.
+// Only the packets that start a connection are evaluated by the rules, the packets of established
+// connections are not counted.
+type SecurityGroupStats struct {
+	SecurityGroupId uuid.UUID `json:"security_group_id"`
+	Revision        uint64    `json:"revision"`
+	// Devices is the number of devices that reported counters for the current revision of the security group
+	Devices       int                 `json:"devices"`
+	InboundRules  []SecurityRuleStats `json:"inbound_rules"`
+	OutboundRules []SecurityRuleStats `json:"outbound_rules"`
+}
+
+// SecurityGroupSimulation is a proposed update of a Security Group and the traffic it is evaluated with.
+type SecurityGroupSimulation struct {
+	// Update holds the proposed rules, the current rules of the security group are used for the directions that are not set.
+	Update              UpdateSecurityGroup `json:"update"`
+	SourceDeviceId      uuid.UUID           `json:"source_device_id"`
+	DestinationDeviceId uuid.UUID           `json:"destination_device_id"`
+	// IpProtocol is the protocol of the traffic.
+	IpProtocol string `json:"ip_protocol" enums:"tcp,udp,icmp,icmpv6"`
+	// Port is the destination port of tcp and udp traffic.
+	Port int64 `json:"port,omitempty"`
+	// IcmpType is the type of icmp traffic, it defaults to an echo request.
+	IcmpType *int64 `json:"icmp_type,omitempty"`
+	// IcmpCode is the code of icmp traffic, it defaults to 0.
+	IcmpCode *int64 `json:"icmp_code,omitempty"`
+	// IpVersion selects the tunnel addresses of the devices the traffic is sent between, 4 or 6. Only icmpv6 defaults to 6.
+	IpVersion int64 `json:"ip_version,omitempty" enums:"4,6"`
+}
+
+// SecurityGroupSimulationResult tells whether the simulated traffic would be allowed. The traffic has to
+// be allowed by the outbound rules of the source device and by the inbound rules of the destination device.
+type SecurityGroupSimulationResult struct {
+	Allowed  bool                `json:"allowed"`
+	Outbound SecurityRuleVerdict `json:"outbound"`
+	Inbound  SecurityRuleVerdict `json:"inbound"`
+}
+
+// SecurityRuleVerdict is the outcome of evaluating the rules of one direction of the security groups of a device.
+type SecurityRuleVerdict struct {
+	// SecurityGroupIds are the security groups of the device the rules belong to, it is empty if the device has no security group.
+	SecurityGroupIds []uuid.UUID `json:"security_group_ids"`
+	Allowed          bool        `json:"allowed"`
+	// SecurityGroupId is the security group of the rule that matched the traffic, it is the nil uuid when no rule matched.
+	SecurityGroupId uuid.UUID `json:"security_group_id"`
+	// Rule is the index of the rule that matched the traffic in its security group, it is not set when no rule matched.
+	Rule        *int64        `json:"rule,omitempty"`
+	MatchedRule *SecurityRule `json:"matched_rule,omitempty"`
+	Reason      string        `json:"reason"`
 }
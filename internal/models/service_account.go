@@ -0,0 +1,23 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// ServiceAccount is a member of an organization that is not a person, it is used by automation through API tokens.
+type ServiceAccount struct {
+	Base
+	OrganizationID uuid.UUID   `json:"organization_id" gorm:"type:uuid"`          // OrganizationID is the ID of the organization the service account is a member of.
+	UserID         uuid.UUID   `json:"user_id" gorm:"type:uuid"`                  // UserID is the ID of the user record the service account acts as.
+	OwnerID        uuid.UUID   `json:"owner_id" gorm:"type:uuid"`                 // OwnerID is the ID of the user that created the service account.
+	Name           string      `json:"name" example:"ci-pipeline"`                // Name of the service account, unique in the organization.
+	Description    string      `json:"description,omitempty"`                     // Description of the service account.
+	Roles          StringArray `json:"roles" gorm:"-" swaggertype:"array,string"` // Roles of the service account in the organization.
+}
+
+type AddServiceAccount struct {
+	OrganizationID uuid.UUID `json:"organization_id"`       // OrganizationID is the ID of the organization the service account is a member of.
+	Name           string    `json:"name"`                  // Name of the service account, unique in the organization.
+	Description    string    `json:"description,omitempty"` // Description of the service account.
+	Roles          []string  `json:"roles"`                 // Roles of the service account in the organization, defaults to member.
+}
@@ -11,3 +11,8 @@ type UserOrganization struct {
 	User           *User       `json:"user,omitempty"`
 	Roles          StringArray `json:"roles" swaggertype:"array,string"`
 }
+
+// UpdateUserOrganization is the information needed to change the roles of a user in an organization
+type UpdateUserOrganization struct {
+	Roles []string `json:"roles"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	// VPCPeeringStatusPending is the status of a peering waiting for the owners of the accepter VPC to accept it.
+	VPCPeeringStatusPending = "pending"
+	// VPCPeeringStatusActive is the status of an accepted peering, the devices of both VPCs see each other.
+	VPCPeeringStatusActive = "active"
+)
+
+// VPCPeering connects the devices of two VPCs, which may belong to different organizations.
+type VPCPeering struct {
+	Base
+	RequesterVpcID          uuid.UUID `json:"requester_vpc_id" gorm:"type:uuid"`               // RequesterVpcID is the ID of the VPC that requested the peering.
+	RequesterOrganizationID uuid.UUID `json:"requester_organization_id" gorm:"type:uuid"`      // RequesterOrganizationID is the ID of the organization of the requester VPC.
+	AccepterVpcID           uuid.UUID `json:"accepter_vpc_id" gorm:"type:uuid"`                // AccepterVpcID is the ID of the VPC that has to accept the peering.
+	AccepterOrganizationID  uuid.UUID `json:"accepter_organization_id" gorm:"type:uuid"`       // AccepterOrganizationID is the ID of the organization of the accepter VPC.
+	Status                  string    `json:"status" enums:"pending,active" example:"pending"` // Status is pending until the peering is accepted, and active after.
+	Description             string    `json:"description,omitempty" example:"web to database"` // Description of the peering.
+	// UnpeeredRevision is the highest revision of the devices of the VPCs, bumped when the active peering was deleted.
+	// The watchers of each VPC are sent the devices of the other one as deleted up to that revision.
+	UnpeeredRevision uint64 `json:"-"`
+}
+
+// AddVPCPeering is the information needed to request a VPC peering.
+type AddVPCPeering struct {
+	VpcID       uuid.UUID `json:"vpc_id"`                                          // VpcID is the ID of the VPC requesting the peering.
+	PeerVpcID   uuid.UUID `json:"peer_vpc_id"`                                     // PeerVpcID is the ID of the VPC to peer with, it may belong to another organization.
+	Description string    `json:"description,omitempty" example:"web to database"` // Description of the peering.
+}
@@ -0,0 +1,169 @@
+package nexodus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/api"
+	"github.com/nexodus-io/nexodus/internal/client"
+)
+
+// eventSubscriberBuffer is the number of events queued for a subscriber, subscribers falling further behind are disconnected
+const eventSubscriberBuffer = 64
+
+// eventBus keeps the recent events and fans new ones out to the subscribers, the zero value is ready to use
+type eventBus struct {
+	mu          sync.Mutex
+	recent      []api.Event
+	subscribers map[chan api.Event]struct{}
+}
+
+// publish records the event and queues it for the subscribers without blocking
+func (b *eventBus) publish(event api.Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.recent = append(b.recent, event)
+	if len(b.recent) > api.SubscribeRecentEvents {
+		b.recent = b.recent[len(b.recent)-api.SubscribeRecentEvents:]
+	}
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// subscribe returns the recent events and a channel receiving the ones published afterward. The channel is closed
+// by unsubscribe, or when the subscriber does not keep up.
+func (b *eventBus) subscribe() ([]api.Event, <-chan api.Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribers == nil {
+		b.subscribers = map[chan api.Event]struct{}{}
+	}
+	ch := make(chan api.Event, eventSubscriberBuffer)
+	b.subscribers[ch] = struct{}{}
+	recent := append([]api.Event{}, b.recent...)
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return recent, ch, unsubscribe
+}
+
+// publishPeerEvent publishes an event about a peer device
+func (nx *Nexodus) publishPeerEvent(eventType string, device client.ModelsDevice) {
+	nx.events.publish(api.Event{
+		Type:      eventType,
+		PublicKey: device.GetPublicKey(),
+		Hostname:  device.GetHostname(),
+		DeviceId:  device.GetId(),
+	})
+}
+
+// Subscribe is only served as the first request of a control socket connection, see serveCtlConn
+func (ac *NexdCtl) Subscribe(_ string, _ *string) error {
+	return fmt.Errorf("%s must be the first request on the connection", api.SubscribeMethod)
+}
+
+// serveCtlConn serves the JSON-RPC requests of a control socket connection. When the first request is
+// NexdCtl.Subscribe, the connection streams the events as lines of JSON instead: the recent events first, then
+// with the follow argument every new event until the client disconnects.
+func (nx *Nexodus) serveCtlConn(ctx context.Context, conn io.ReadWriteCloser) {
+	decoder := json.NewDecoder(conn)
+	var first json.RawMessage
+	if err := decoder.Decode(&first); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	var request struct {
+		Method string   `json:"method"`
+		Params []string `json:"params"`
+	}
+	if err := json.Unmarshal(first, &request); err == nil && request.Method == api.SubscribeMethod {
+		follow := len(request.Params) > 0 && request.Params[0] == api.SubscribeFollow
+		nx.streamEvents(ctx, conn, follow)
+		return
+	}
+
+	// hand the request that was already read back to the JSON-RPC codec
+	jsonrpc.ServeConn(&ctlConn{
+		Reader: io.MultiReader(bytes.NewReader(first), decoder.Buffered(), conn),
+		conn:   conn,
+	})
+}
+
+// streamEvents writes the events to the subscriber connection and closes it once done
+func (nx *Nexodus) streamEvents(ctx context.Context, conn io.ReadWriteCloser, follow bool) {
+	defer func() { _ = conn.Close() }()
+
+	recent, events, unsubscribe := nx.events.subscribe()
+	defer unsubscribe()
+
+	encoder := json.NewEncoder(conn)
+	for _, event := range recent {
+		if err := encoder.Encode(event); err != nil {
+			return
+		}
+	}
+	if !follow {
+		return
+	}
+
+	// the client does not send anything else, reading only notices it went away
+	disconnected := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, conn)
+		close(disconnected)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-disconnected:
+			return
+		case event, ok := <-events:
+			if !ok {
+				nx.logger.Debug("Dropping an event subscriber that fell behind")
+				return
+			}
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// ctlConn reads from Reader and writes to and closes the control socket connection
+type ctlConn struct {
+	io.Reader
+	conn io.ReadWriteCloser
+}
+
+func (c *ctlConn) Write(p []byte) (int, error) {
+	return c.conn.Write(p)
+}
+
+func (c *ctlConn) Close() error {
+	return c.conn.Close()
+}
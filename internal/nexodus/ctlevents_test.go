@@ -0,0 +1,80 @@
+package nexodus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"testing"
+
+	"github.com/nexodus-io/nexodus/internal/api"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestEventBus(t *testing.T) {
+	require := require.New(t)
+
+	bus := eventBus{}
+	for i := 0; i < api.SubscribeRecentEvents+5; i++ {
+		bus.publish(api.Event{Type: api.EventPeerAdded, Hostname: fmt.Sprintf("peer-%d", i)})
+	}
+	recent, events, unsubscribe := bus.subscribe()
+	require.Len(recent, api.SubscribeRecentEvents)
+	require.Equal("peer-5", recent[0].Hostname)
+	require.False(recent[0].Time.IsZero())
+
+	bus.publish(api.Event{Type: api.EventPeerRemoved})
+	require.Equal(api.EventPeerRemoved, (<-events).Type)
+
+	// a subscriber that falls behind is dropped instead of blocking nexd
+	for i := 0; i < eventSubscriberBuffer+1; i++ {
+		bus.publish(api.Event{Type: api.EventPeerHealthy})
+	}
+	for i := 0; i < eventSubscriberBuffer; i++ {
+		<-events
+	}
+	_, ok := <-events
+	require.False(ok)
+	unsubscribe()
+}
+
+func TestServeCtlConn(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nx := &Nexodus{logger: zap.NewNop().Sugar(), version: "test"}
+	require.NoError(rpc.Register(&NexdCtl{nx: nx}))
+	nx.events.publish(api.Event{Type: api.EventStatusChanged, Status: "Running"})
+
+	// regular requests are still served
+	server, conn := net.Pipe()
+	go nx.serveCtlConn(ctx, server)
+	var version string
+	require.NoError(jsonrpc.NewClient(conn).Call("NexdCtl.Version", "", &version))
+	require.Equal("test", version)
+	conn.Close()
+
+	// subscribers get the recent events, then the new ones
+	server, conn = net.Pipe()
+	defer conn.Close()
+	go nx.serveCtlConn(ctx, server)
+	go func() {
+		_ = json.NewEncoder(conn).Encode(map[string]interface{}{"method": api.SubscribeMethod, "params": []string{api.SubscribeFollow}, "id": 0})
+	}()
+	lines := bufio.NewScanner(conn)
+	event := api.Event{}
+	require.True(lines.Scan())
+	require.NoError(json.Unmarshal(lines.Bytes(), &event))
+	require.Equal("Running", event.Status)
+
+	nx.events.publish(api.Event{Type: api.EventPeerAdded, Hostname: "west"})
+	require.True(lines.Scan())
+	require.NoError(json.Unmarshal(lines.Bytes(), &event))
+	require.Equal(api.EventPeerAdded, event.Type)
+	require.Equal("west", event.Hostname)
+}
@@ -3,10 +3,30 @@ package nexodus
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/nexodus-io/nexodus/internal/api"
+	"github.com/nexodus-io/nexodus/internal/util"
 )
 
+// ExitNodeOrigin is an exit node as listed by nexctl nexd exit-node list
+type ExitNodeOrigin struct {
+	PublicKey  string
+	Endpoint   string
+	AllowedIPs []string
+	Hostname   string
+	DeviceId   string
+	// Healthy is set when the peering with the exit node is up
+	Healthy bool
+	// Active is set on the exit node the default routes are sent to
+	Active bool
+	// Selected is set on the exit node picked with nexctl nexd exit-node use
+	Selected bool
+	// Local is set when this device is the exit node
+	Local bool
+}
+
 func (ac *NexdCtl) EnableExitNodeClient(_ string, result *string) error {
-	err := ac.nx.ExitNodeClientSetup()
+	err := ac.nx.ExitNodeClientEnable(true)
 
 	enableExitNodeClientJson, err := json.Marshal(err)
 	if err != nil {
@@ -19,7 +39,7 @@ func (ac *NexdCtl) EnableExitNodeClient(_ string, result *string) error {
 }
 
 func (ac *NexdCtl) DisableExitNodeClient(_ string, result *string) error {
-	err := ac.nx.exitNodeClientTeardown()
+	err := ac.nx.ExitNodeClientEnable(false)
 
 	disableExitNodeClientJson, err := json.Marshal(err)
 	if err != nil {
@@ -31,34 +51,26 @@ func (ac *NexdCtl) DisableExitNodeClient(_ string, result *string) error {
 	return nil
 }
 
-// ListExitNodes lists all exit node origins
-func (ac *NexdCtl) ListExitNodes(_ string, result *string) error {
-	var allExitNodeOrigins []wgPeerConfig
-	isExitNode := false
-
-	// Check if the local node is an exit node
-	for _, prefix := range ac.nx.advertiseCidrs {
-		if prefix == "0.0.0.0/0" {
-			isExitNode = true
-			break
-		}
+// UseExitNode picks the exit node the default routes are sent to, an empty device goes back to
+// automatically picking the healthiest exit node.
+func (ac *NexdCtl) UseExitNode(device string, result *string) error {
+	if err := ac.nx.ExitNodeUse(device); err != nil {
+		return err
 	}
 
-	// If the local node is an exit node, create a copy and append the new instance
-	if isExitNode {
-		// Make a copy of exitNodeOrigins to a new slice
-		allExitNodeOrigins = make([]wgPeerConfig, len(ac.nx.exitNode.exitNodeOrigins))
-		copy(allExitNodeOrigins, ac.nx.exitNode.exitNodeOrigins)
+	ac.nx.deviceCacheLock.RLock()
+	active := ac.nx.exitNode.exitNodeActive
+	ac.nx.deviceCacheLock.RUnlock()
 
-		// Create a new instance of wgPeerConfig
-		newPeerConfig := wgPeerConfig{
-			PublicKey: ac.nx.wireguardPubKey,
-			Endpoint:  ac.nx.nodeReflexiveAddressIPv4.String(),
-		}
-		// Append the local node if it is an exit node
-		allExitNodeOrigins = append(allExitNodeOrigins, newPeerConfig)
-	} else {
-		allExitNodeOrigins = ac.nx.exitNode.exitNodeOrigins
+	*result = active
+	return nil
+}
+
+// ListExitNodes lists all exit node origins
+func (ac *NexdCtl) ListExitNodes(_ string, result *string) error {
+	var allExitNodeOrigins []ExitNodeOrigin
+	for _, exitNode := range ac.nx.listExitNodes() {
+		allExitNodeOrigins = append(allExitNodeOrigins, ExitNodeOrigin(exitNode))
 	}
 
 	exitNodeOriginsJSON, err := json.Marshal(allExitNodeOrigins)
@@ -70,3 +82,40 @@ func (ac *NexdCtl) ListExitNodes(_ string, result *string) error {
 
 	return nil
 }
+
+// listExitNodes returns the exit node origins in the peerings, followed by this device if it is an exit node
+func (nx *Nexodus) listExitNodes() []api.ExitNode {
+	var exitNodes []api.ExitNode
+
+	nx.deviceCacheLock.RLock()
+	for _, origin := range nx.exitNode.exitNodeOrigins {
+		d := nx.deviceCache[origin.PublicKey]
+		exitNodes = append(exitNodes, api.ExitNode{
+			PublicKey:  origin.PublicKey,
+			Endpoint:   origin.Endpoint,
+			AllowedIPs: origin.AllowedIPs,
+			Hostname:   d.device.GetHostname(),
+			DeviceId:   d.device.GetId(),
+			Healthy:    d.peerHealthy,
+			Active:     origin.PublicKey == nx.exitNode.exitNodeActive,
+			Selected:   origin.PublicKey == nx.exitNode.exitNodeSelected,
+		})
+	}
+	nx.deviceCacheLock.RUnlock()
+
+	// Append the local node if it is an exit node
+	for _, prefix := range nx.advertiseCidrs {
+		if util.IsDefaultIPRoute(prefix) {
+			exitNodes = append(exitNodes, api.ExitNode{
+				PublicKey: nx.wireguardPubKey,
+				Endpoint:  nx.nodeReflexiveAddressIPv4.String(),
+				Hostname:  nx.hostname,
+				DeviceId:  nx.deviceId,
+				Healthy:   true,
+				Local:     true,
+			})
+			break
+		}
+	}
+	return exitNodes
+}
@@ -1,10 +1,15 @@
 package nexodus
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/rpc"
+	"sync"
 
-	"github.com/bytedance/gopkg/util/logger"
-
+	"github.com/nexodus-io/nexodus/internal/api"
+	"github.com/nexodus-io/nexodus/internal/util"
 	"go.uber.org/zap"
 )
 
@@ -13,18 +18,7 @@ type NexdCtl struct {
 }
 
 func (ac *NexdCtl) Status(_ string, result *string) error {
-	var statusStr string
-	switch ac.nx.status {
-	case NexdStatusStarting:
-		statusStr = "Starting"
-	case NexdStatusAuth:
-		statusStr = "WaitingForAuth"
-	case NexdStatusRunning:
-		statusStr = "Running"
-	default:
-		statusStr = "Unknown"
-	}
-	res := fmt.Sprintf("Status: %s\n", statusStr)
+	res := fmt.Sprintf("Status: %s\n", statusString(ac.nx.status))
 	if len(ac.nx.statusMsg) > 0 {
 		res += ac.nx.statusMsg
 	}
@@ -32,6 +26,19 @@ func (ac *NexdCtl) Status(_ string, result *string) error {
 	return nil
 }
 
+// statusString returns the name of a NexdStatus value
+func statusString(status int) string {
+	switch status {
+	case NexdStatusStarting:
+		return "Starting"
+	case NexdStatusAuth:
+		return "WaitingForAuth"
+	case NexdStatusRunning:
+		return "Running"
+	}
+	return "Unknown"
+}
+
 func (ac *NexdCtl) Version(_ string, result *string) error {
 	*result = ac.nx.version
 	return nil
@@ -49,73 +56,124 @@ func (ac *NexdCtl) GetTunnelIPv6(_ string, result *string) error {
 
 func (ac *NexdCtl) ProxyList(_ string, result *string) error {
 	*result = ""
-	ac.nx.proxyLock.RLock()
-	defer ac.nx.proxyLock.RUnlock()
-	for _, proxy := range ac.nx.proxies {
+	for _, rule := range ac.nx.proxyRules() {
+		*result += fmt.Sprintf("%s\n", rule.AsFlag())
+	}
+	return nil
+}
+
+// proxyRules returns the rules of all the proxies
+func (nx *Nexodus) proxyRules() []ProxyRule {
+	var rules []ProxyRule
+	nx.proxyLock.RLock()
+	defer nx.proxyLock.RUnlock()
+	for _, proxy := range nx.proxies {
 		proxy.mu.RLock()
-		for _, rule := range proxy.rules {
-			*result += fmt.Sprintf("%s\n", rule.AsFlag())
-		}
+		rules = append(rules, proxy.rules...)
 		proxy.mu.RUnlock()
 	}
-	return nil
+	return rules
 }
 
-func (ac *NexdCtl) proxyAdd(proxyType ProxyType, rule string, result *string) error {
+// proxyRuleToApi returns the rule with the load balancing policy of its listener and the health of its destination
+func (nx *Nexodus) proxyRuleToApi(rule ProxyRule) api.ProxyRule {
+	result := rule.toApi()
+	result.LoadBalancing = string(proxyLbRoundRobin)
+	result.Healthy = true
 
+	nx.proxyLock.RLock()
+	defer nx.proxyLock.RUnlock()
+	if proxy, found := nx.proxies[rule.ProxyKey]; found {
+		proxy.mu.RLock()
+		result.LoadBalancing = string(proxy.lbPolicy)
+		result.Healthy = proxy.destHealthy(rule.dest)
+		proxy.mu.RUnlock()
+	}
+	return result
+}
+
+// ctlProxyAdd adds and starts a proxy rule, and stores it so that it is loaded again when nexd restarts. A non
+// empty lbPolicy sets the load balancing policy of the rule's listener.
+func (nx *Nexodus) ctlProxyAdd(proxyType ProxyType, rule string, lbPolicy string) (ProxyRule, error) {
 	proxyRule, err := ParseProxyRule(rule, proxyType)
 	if err != nil {
-		logger.Fatal(fmt.Sprintf("Failed to parse %s proxy rule (%s): %v", proxyType, rule, err))
+		return ProxyRule{}, fmt.Errorf("failed to parse %s proxy rule (%s): %w", proxyType, rule, err)
 	}
 	proxyRule.stored = true
 
-	proxy, err := ac.nx.UserspaceProxyAdd(proxyRule)
+	policy, err := parseProxyLbPolicy(lbPolicy)
 	if err != nil {
-		return err
+		return ProxyRule{}, err
 	}
-	proxy.Start(ac.nx.nexCtx, ac.nx.nexWg, ac.nx.userspaceNet)
 
-	err = ac.nx.StoreProxyRules()
+	proxy, err := nx.UserspaceProxyAdd(proxyRule)
+	if errors.Is(err, ProxyExistsError) && lbPolicy != "" {
+		// adding an existing rule again only changes the policy of its listener
+		err = nil
+	}
 	if err != nil {
-		return err
+		return ProxyRule{}, err
 	}
-	*result = fmt.Sprintf("Added %s proxy rule: %s\n", proxyType, rule)
-	return nil
-}
-
-func (ac *NexdCtl) ProxyAddIngress(rule string, result *string) error {
-	return ac.proxyAdd(ProxyTypeIngress, rule, result)
-}
+	if lbPolicy != "" {
+		if err := nx.UserspaceProxySetLbPolicy(proxyRule.ProxyKey, policy); err != nil {
+			return ProxyRule{}, err
+		}
+	}
+	proxy.Start(nx.nexCtx, nx.nexWg, nx.userspaceNet)
 
-func (ac *NexdCtl) ProxyAddEgress(rule string, result *string) error {
-	return ac.proxyAdd(ProxyTypeEgress, rule, result)
+	if err := nx.StoreProxyRules(); err != nil {
+		return ProxyRule{}, err
+	}
+	return proxyRule, nil
 }
 
-func (ac *NexdCtl) proxyRemove(proxyType ProxyType, rule string, result *string) error {
+// ctlProxyRemove removes a proxy rule from the running and the stored rules
+func (nx *Nexodus) ctlProxyRemove(proxyType ProxyType, rule string) (ProxyRule, error) {
 	proxyRule, err := ParseProxyRule(rule, proxyType)
 	if err != nil {
-		logger.Fatal(fmt.Sprintf("Failed to parse %s proxy rule (%s): %v", proxyType, rule, err))
+		return ProxyRule{}, fmt.Errorf("failed to parse %s proxy rule (%s): %w", proxyType, rule, err)
 	}
 	proxyRule.stored = true
 
-	_, err = ac.nx.UserspaceProxyRemove(proxyRule)
-	if err != nil {
-		return err
+	if _, err := nx.UserspaceProxyRemove(proxyRule); err != nil {
+		return ProxyRule{}, err
 	}
-	err = ac.nx.StoreProxyRules()
-	if err != nil {
+	if err := nx.StoreProxyRules(); err != nil {
+		return ProxyRule{}, err
+	}
+	return proxyRule, nil
+}
+
+func (ac *NexdCtl) ProxyAddIngress(rule string, result *string) error {
+	if _, err := ac.nx.ctlProxyAdd(ProxyTypeIngress, rule, ""); err != nil {
 		return err
 	}
+	*result = fmt.Sprintf("Added %s proxy rule: %s\n", ProxyTypeIngress, rule)
+	return nil
+}
 
-	*result = fmt.Sprintf("Removed ingress proxy rule: %s\n", rule)
+func (ac *NexdCtl) ProxyAddEgress(rule string, result *string) error {
+	if _, err := ac.nx.ctlProxyAdd(ProxyTypeEgress, rule, ""); err != nil {
+		return err
+	}
+	*result = fmt.Sprintf("Added %s proxy rule: %s\n", ProxyTypeEgress, rule)
 	return nil
 }
+
 func (ac *NexdCtl) ProxyRemoveIngress(rule string, result *string) error {
-	return ac.proxyRemove(ProxyTypeIngress, rule, result)
+	if _, err := ac.nx.ctlProxyRemove(ProxyTypeIngress, rule); err != nil {
+		return err
+	}
+	*result = fmt.Sprintf("Removed %s proxy rule: %s\n", ProxyTypeIngress, rule)
+	return nil
 }
 
 func (ac *NexdCtl) ProxyRemoveEgress(rule string, result *string) error {
-	return ac.proxyRemove(ProxyTypeEgress, rule, result)
+	if _, err := ac.nx.ctlProxyRemove(ProxyTypeEgress, rule); err != nil {
+		return err
+	}
+	*result = fmt.Sprintf("Removed %s proxy rule: %s\n", ProxyTypeEgress, rule)
+	return nil
 }
 
 func (ac *NexdCtl) SetDebugOn(_ string, result *string) error {
@@ -138,3 +196,61 @@ func (ac *NexdCtl) GetDebug(_ string, result *string) error {
 	}
 	return nil
 }
+
+// ctlServerRun registers the control services and hands the connections accepted on the listener to serve until
+// the context is done or Accept() fails.
+func (nx *Nexodus) ctlServerRun(ctx context.Context, ctlWg *sync.WaitGroup, l net.Listener, serve func(conn net.Conn)) error {
+	ac := new(NexdCtl)
+	ac.nx = nx
+	err := rpc.Register(ac)
+	if err != nil {
+		nx.logger.Error("Error on rpc.Register(): ", err)
+		return err
+	}
+	err = rpc.Register(&NexdCtlV1{nx: nx})
+	if err != nil {
+		nx.logger.Error("Error on rpc.Register(): ", err)
+		return err
+	}
+
+	// This routine will exit when the listener is closed intentionally,
+	// or some error occurs.
+	errChan := make(chan error)
+	util.GoWithWaitGroup(ctlWg, func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				// Don't return an error if the context was canceled
+				if ctx.Err() == nil {
+					errChan <- err
+				}
+				break
+			}
+			util.GoWithWaitGroup(ctlWg, func() {
+				serve(conn)
+			})
+		}
+	})
+
+	// Handle new connections until we get notified to stop the CtlServer,
+	// or Accept() fails for some reason.
+	stopNow := false
+	for {
+		select {
+		case err = <-errChan:
+			// Accept() failed, collect the error and stop the CtlServer
+			stopNow = true
+			nx.logger.Error("Error on Accept(): ", err)
+			break
+		case <-ctx.Done():
+			nx.logger.Info("Stopping CtlServer")
+			stopNow = true
+			err = nil
+		}
+		if stopNow {
+			break
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,132 @@
+package nexodus
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/api"
+	"github.com/nexodus-io/nexodus/internal/util"
+	"tailscale.com/atomicfile"
+)
+
+// ctlAuthTimeout is how long a loopback control connection has to send its token
+const ctlAuthTimeout = 10 * time.Second
+
+// createCtlTCPListener listens on a random loopback port and writes its address with a new token to the token
+// file in the state directory.
+func (nx *Nexodus) createCtlTCPListener() (net.Listener, string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, "", fmt.Errorf("failed to generate the control token: %w", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		nx.logger.Error("Error creating the control listener: ", err)
+		return nil, "", err
+	}
+	if err := nx.writeCtlToken(api.CtlToken{Address: l.Addr().String(), Token: token}); err != nil {
+		_ = l.Close()
+		return nil, "", err
+	}
+	return l, token, nil
+}
+
+func (nx *Nexodus) ctlTokenFile() string {
+	return filepath.Join(nx.stateDir, api.CtlTokenFileName)
+}
+
+func (nx *Nexodus) writeCtlToken(ctlToken api.CtlToken) error {
+	if err := os.MkdirAll(nx.stateDir, 0700); err != nil {
+		return fmt.Errorf("failed to create the state directory: %w", err)
+	}
+	// the token file is created with the access control list of the directory, it is never readable by other users
+	if err := restrictCtlTokenAccess(nx.stateDir, true); err != nil {
+		return err
+	}
+	data, err := json.Marshal(ctlToken)
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(nx.ctlTokenFile(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write the control token file: %w", err)
+	}
+	return restrictCtlTokenAccess(nx.ctlTokenFile(), false)
+}
+
+// CtlServerTCPStart serves the control methods on a loopback TCP port for the platforms without unix sockets.
+// Every connection has to start with the api.CtlAuth message carrying the token of the token file, so that only
+// the users allowed to read the state directory can control nexd.
+func (nx *Nexodus) CtlServerTCPStart(ctx context.Context, wg *sync.WaitGroup) error {
+	l, token, err := nx.createCtlTCPListener()
+	if err != nil {
+		return err
+	}
+
+	util.GoWithWaitGroup(wg, func() {
+		defer func() { _ = os.Remove(nx.ctlTokenFile()) }()
+		for {
+			// Use a different waitgroup here, because we want to make sure
+			// all of the subroutines have exited before we attempt to restart
+			// the control server.
+			ctlWg := &sync.WaitGroup{}
+			currentToken := token
+			err := nx.ctlServerRun(ctx, ctlWg, l, func(conn net.Conn) {
+				nx.serveAuthenticatedCtlConn(ctx, conn, currentToken)
+			})
+			l.Close()
+			ctlWg.Wait()
+			if err == nil {
+				// No error means it shut down cleanly because it got a message to stop
+				return
+			}
+			nx.logger.Error("Ctl interface error, restarting: ", err)
+			for {
+				time.Sleep(time.Second * 5)
+				if ctx.Err() != nil {
+					return
+				}
+				l, token, err = nx.createCtlTCPListener()
+				if err == nil {
+					break
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// serveAuthenticatedCtlConn checks the token sent first on the connection, then serves it like a control
+// socket connection.
+func (nx *Nexodus) serveAuthenticatedCtlConn(ctx context.Context, conn net.Conn, token string) {
+	_ = conn.SetReadDeadline(time.Now().Add(ctlAuthTimeout))
+	decoder := json.NewDecoder(conn)
+	auth := api.CtlAuth{}
+	if err := decoder.Decode(&auth); err != nil {
+		nx.logger.Debugf("Closing the control connection from %s: %v", conn.RemoteAddr(), err)
+		_ = conn.Close()
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(auth.Token), []byte(token)) != 1 {
+		nx.logger.Warnf("Rejecting the control connection from %s: invalid token", conn.RemoteAddr())
+		_ = conn.Close()
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	nx.serveCtlConn(ctx, &ctlConn{
+		Reader: io.MultiReader(decoder.Buffered(), conn),
+		conn:   conn,
+	})
+}
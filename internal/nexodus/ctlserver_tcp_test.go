@@ -0,0 +1,72 @@
+package nexodus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nexodus-io/nexodus/internal/api"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCtlTCPListener(t *testing.T) {
+	require := require.New(t)
+
+	nx := &Nexodus{logger: zap.NewNop().Sugar(), stateDir: filepath.Join(t.TempDir(), "nexd")}
+	l, token, err := nx.createCtlTCPListener()
+	require.NoError(err)
+	defer l.Close()
+
+	data, err := os.ReadFile(filepath.Join(nx.stateDir, api.CtlTokenFileName))
+	require.NoError(err)
+	ctlToken := api.CtlToken{}
+	require.NoError(json.Unmarshal(data, &ctlToken))
+	require.Equal(l.Addr().String(), ctlToken.Address)
+	require.Equal(token, ctlToken.Token)
+	require.Len(token, 64)
+
+	host, _, err := net.SplitHostPort(ctlToken.Address)
+	require.NoError(err)
+	require.Equal("127.0.0.1", host)
+}
+
+func TestServeAuthenticatedCtlConn(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nx := &Nexodus{logger: zap.NewNop().Sugar()}
+	nx.events.publish(api.Event{Type: api.EventStatusChanged, Status: "Running"})
+	subscribe := map[string]interface{}{"method": api.SubscribeMethod, "params": []string{}, "id": 0}
+
+	// a connection with the wrong token is closed without being served
+	server, conn := net.Pipe()
+	go nx.serveAuthenticatedCtlConn(ctx, server, "secret")
+	go func() {
+		encoder := json.NewEncoder(conn)
+		_ = encoder.Encode(api.CtlAuth{Token: "guess"})
+		_ = encoder.Encode(subscribe)
+	}()
+	require.False(bufio.NewScanner(conn).Scan())
+	conn.Close()
+
+	// the requests following a valid token are served
+	server, conn = net.Pipe()
+	defer conn.Close()
+	go nx.serveAuthenticatedCtlConn(ctx, server, "secret")
+	go func() {
+		encoder := json.NewEncoder(conn)
+		_ = encoder.Encode(api.CtlAuth{Token: "secret"})
+		_ = encoder.Encode(subscribe)
+	}()
+	lines := bufio.NewScanner(conn)
+	require.True(lines.Scan())
+	event := api.Event{}
+	require.NoError(json.Unmarshal(lines.Bytes(), &event))
+	require.Equal("Running", event.Status)
+}
@@ -1,12 +1,10 @@
-//go:build linux || darwin || windows
+//go:build linux || darwin
 
 package nexodus
 
 import (
 	"context"
 	"net"
-	"net/rpc"
-	"net/rpc/jsonrpc"
 	"os"
 	"sync"
 	"time"
@@ -20,6 +18,11 @@ func (nx *Nexodus) CtlServerStart(ctx context.Context, wg *sync.WaitGroup) error
 	return nx.CtlServerUnixStart(ctx, wg)
 }
 
+// restrictCtlTokenAccess is a no-op, the file modes already restrict the access to the token file
+func restrictCtlTokenAccess(path string, dir bool) error {
+	return nil
+}
+
 func (nx *Nexodus) createListener() (*net.UnixListener, error) {
 	socketPath := api.UnixSocketPath
 	_ = os.Remove(socketPath)
@@ -67,52 +70,7 @@ func (nx *Nexodus) CtlServerUnixStart(ctx context.Context, wg *sync.WaitGroup) e
 }
 
 func (nx *Nexodus) CtlServerUnixRun(ctx context.Context, ctlWg *sync.WaitGroup, l *net.UnixListener) error {
-	ac := new(NexdCtl)
-	ac.nx = nx
-	err := rpc.Register(ac)
-	if err != nil {
-		nx.logger.Error("Error on rpc.Register(): ", err)
-		return err
-	}
-
-	// This routine will exit when the listener is closed intentionally,
-	// or some error occurs.
-	errChan := make(chan error)
-	util.GoWithWaitGroup(ctlWg, func() {
-		for {
-			conn, err := l.Accept()
-			if err != nil {
-				// Don't return an error if the context was canceled
-				if ctx.Err() == nil {
-					errChan <- err
-				}
-				break
-			}
-			util.GoWithWaitGroup(ctlWg, func() {
-				jsonrpc.ServeConn(conn)
-			})
-		}
+	return nx.ctlServerRun(ctx, ctlWg, l, func(conn net.Conn) {
+		nx.serveCtlConn(ctx, conn)
 	})
-
-	// Handle new connections until we get notified to stop the CtlServer,
-	// or Accept() fails for some reason.
-	stopNow := false
-	for {
-		select {
-		case err = <-errChan:
-			// Accept() failed, collect the error and stop the CtlServer
-			stopNow = true
-			nx.logger.Error("Error on Accept(): ", err)
-			break
-		case <-ctx.Done():
-			nx.logger.Info("Stopping CtlServer")
-			stopNow = true
-			err = nil
-		}
-		if stopNow {
-			break
-		}
-	}
-
-	return err
 }
@@ -0,0 +1,49 @@
+//go:build windows
+
+package nexodus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// ctlTokenDirSDDL only grants SYSTEM and the Administrators access to the state directory and the files
+	// created in it, the entries inherited from the parent directory are removed.
+	ctlTokenDirSDDL = "D:P(A;OICI;FA;;;SY)(A;OICI;FA;;;BA)"
+	// ctlTokenFileSDDL only grants SYSTEM and the Administrators access to the token file
+	ctlTokenFileSDDL = "D:P(A;;FA;;;SY)(A;;FA;;;BA)"
+)
+
+// CtlServerStart serves the control methods on a loopback TCP listener, nexctl finds it through the token file
+// in the state directory.
+func (nx *Nexodus) CtlServerStart(ctx context.Context, wg *sync.WaitGroup) error {
+	return nx.CtlServerTCPStart(ctx, wg)
+}
+
+// restrictCtlTokenAccess replaces the access control list of the state directory or the token file. The file modes
+// are ignored on windows, the state directory would otherwise inherit the access control list of its parent, which
+// lets every user of C:\ read the token.
+func restrictCtlTokenAccess(path string, dir bool) error {
+	sddl := ctlTokenFileSDDL
+	if dir {
+		sddl = ctlTokenDirSDDL
+	}
+	sd, err := windows.SecurityDescriptorFromString(sddl)
+	if err != nil {
+		return err
+	}
+	dacl, _, err := sd.DACL()
+	if err != nil {
+		return err
+	}
+	err = windows.SetNamedSecurityInfo(path, windows.SE_FILE_OBJECT,
+		windows.DACL_SECURITY_INFORMATION|windows.PROTECTED_DACL_SECURITY_INFORMATION, nil, nil, dacl, nil)
+	if err != nil {
+		return fmt.Errorf("failed to set the access control list of %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package nexodus
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/nexodus-io/nexodus/internal/api"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/sys/windows"
+)
+
+func TestCtlTokenAccess(t *testing.T) {
+	require := require.New(t)
+
+	nx := &Nexodus{logger: zap.NewNop().Sugar(), stateDir: filepath.Join(t.TempDir(), "nexd")}
+	l, _, err := nx.createCtlTCPListener()
+	require.NoError(err)
+	defer l.Close()
+
+	// only SYSTEM and the Administrators are granted access, nothing is inherited from the parent directory
+	aces := regexp.MustCompile(`\(([^)]*)\)`)
+	for _, path := range []string{nx.stateDir, filepath.Join(nx.stateDir, api.CtlTokenFileName)} {
+		sd, err := windows.GetNamedSecurityInfo(path, windows.SE_FILE_OBJECT, windows.DACL_SECURITY_INFORMATION)
+		require.NoError(err)
+		control, _, err := sd.Control()
+		require.NoError(err)
+		require.NotZero(control&windows.SE_DACL_PROTECTED, path)
+
+		sddl := sd.String()
+		matches := aces.FindAllStringSubmatch(sddl, -1)
+		require.NotEmpty(matches, sddl)
+		for _, ace := range matches {
+			require.Regexp(`^A;[A-Z]*;FA;;;(SY|BA)$`, ace[1], sddl)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package nexodus
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nexodus-io/nexodus/internal/api"
+	"go.uber.org/zap"
+)
+
+// NexdCtlV1 serves the typed control methods of the api.CtlServiceV1 service. NexdCtl keeps serving the
+// older methods with preformatted string results.
+type NexdCtlV1 struct {
+	nx *Nexodus
+}
+
+func (ac *NexdCtlV1) Status(_ api.Empty, result *api.StatusResponse) error {
+	*result = api.StatusResponse{
+		Status:  statusString(ac.nx.status),
+		Message: ac.nx.statusMsg,
+		Version: ac.nx.version,
+	}
+	return nil
+}
+
+func (ac *NexdCtlV1) TunnelIPs(_ api.Empty, result *api.TunnelIPsResponse) error {
+	*result = api.TunnelIPsResponse{
+		IPv4: ac.nx.TunnelIP,
+		IPv6: ac.nx.TunnelIpV6,
+	}
+	return nil
+}
+
+func (ac *NexdCtlV1) Peers(_ api.Empty, result *api.PeersResponse) error {
+	sessions, err := ac.nx.DumpPeersDefault()
+	if err != nil {
+		return fmt.Errorf("error getting list of peers: %w", err)
+	}
+
+	response := api.PeersResponse{
+		RelayRequired: ac.nx.symmetricNat,
+		Peers:         []api.Peer{},
+	}
+	ac.nx.deviceCacheIterRead(func(d deviceCacheEntry) {
+		if d.device.GetPublicKey() == ac.nx.wireguardPubKey {
+			return
+		}
+		session, ok := sessions[d.device.GetPublicKey()]
+		if !ok {
+			return
+		}
+		response.Peers = append(response.Peers, api.Peer{
+			PublicKey:       session.PublicKey,
+			Hostname:        d.device.GetHostname(),
+			DeviceId:        d.device.GetId(),
+			Endpoint:        session.Endpoint,
+			AllowedIPs:      session.AllowedIPs,
+			LatestHandshake: session.LastHandshakeTime,
+			Tx:              session.Tx,
+			Rx:              session.Rx,
+			Healthy:         d.peerHealthy,
+			PeeringMethod:   d.peeringMethod,
+		})
+		if d.peerHealthy && d.device.GetRelay() {
+			response.RelayPresent = true
+		}
+	})
+	sort.Slice(response.Peers, func(i, j int) bool {
+		return response.Peers[i].PublicKey < response.Peers[j].PublicKey
+	})
+
+	*result = response
+	return nil
+}
+
+func (ac *NexdCtlV1) PingPeers(request api.PingPeersRequest, result *api.PingPeersResponse) error {
+	switch request.Family {
+	case v4, v6:
+	default:
+		return fmt.Errorf("unknown address family %q, expected %s or %s", request.Family, v4, v6)
+	}
+	*result = ac.nx.connectivityProbe(request.Family)
+	return nil
+}
+
+func (ac *NexdCtlV1) ProxyRules(_ api.Empty, result *api.ProxyRulesResponse) error {
+	response := api.ProxyRulesResponse{Rules: []api.ProxyRule{}}
+	for _, rule := range ac.nx.proxyRules() {
+		response.Rules = append(response.Rules, ac.nx.proxyRuleToApi(rule))
+	}
+	*result = response
+	return nil
+}
+
+func (ac *NexdCtlV1) ProxyAdd(request api.ProxyRuleRequest, result *api.ProxyRule) error {
+	proxyType, err := parseProxyType(request.Type)
+	if err != nil {
+		return err
+	}
+	rule, err := ac.nx.ctlProxyAdd(proxyType, request.Rule, request.LoadBalancing)
+	if err != nil {
+		return err
+	}
+	*result = ac.nx.proxyRuleToApi(rule)
+	return nil
+}
+
+func (ac *NexdCtlV1) ProxyRemove(request api.ProxyRuleRequest, result *api.ProxyRule) error {
+	proxyType, err := parseProxyType(request.Type)
+	if err != nil {
+		return err
+	}
+	rule, err := ac.nx.ctlProxyRemove(proxyType, request.Rule)
+	if err != nil {
+		return err
+	}
+	*result = rule.toApi()
+	return nil
+}
+
+func (ac *NexdCtlV1) ExitNodes(_ api.Empty, result *api.ExitNodesResponse) error {
+	exitNodes := ac.nx.listExitNodes()
+	if exitNodes == nil {
+		exitNodes = []api.ExitNode{}
+	}
+	*result = api.ExitNodesResponse{ExitNodes: exitNodes}
+	return nil
+}
+
+func (ac *NexdCtlV1) ExitNodeClient(request api.ExitNodeClientRequest, _ *api.Empty) error {
+	return ac.nx.ExitNodeClientEnable(request.Enabled)
+}
+
+// UseExitNode picks the exit node the default routes are sent to and returns the active exit node
+func (ac *NexdCtlV1) UseExitNode(request api.UseExitNodeRequest, result *api.ExitNode) error {
+	if err := ac.nx.ExitNodeUse(request.Device); err != nil {
+		return err
+	}
+	for _, exitNode := range ac.nx.listExitNodes() {
+		if exitNode.Active {
+			*result = exitNode
+			return nil
+		}
+	}
+	return fmt.Errorf("no exit node found in this device's peerings")
+}
+
+func (ac *NexdCtlV1) Debug(_ api.Empty, result *api.DebugResponse) error {
+	*result = api.DebugResponse{Debug: ac.nx.logLevel.Level() == zap.DebugLevel}
+	return nil
+}
+
+func (ac *NexdCtlV1) SetDebug(request api.DebugRequest, result *api.DebugResponse) error {
+	if request.Debug {
+		ac.nx.logLevel.SetLevel(zap.DebugLevel)
+	} else {
+		ac.nx.logLevel.SetLevel(zap.InfoLevel)
+	}
+	return ac.Debug(api.Empty{}, result)
+}
@@ -0,0 +1,69 @@
+package nexodus
+
+import (
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"testing"
+
+	"github.com/nexodus-io/nexodus/internal/api"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNexdCtlV1(t *testing.T) {
+	require := require.New(t)
+
+	logLevel := zap.NewAtomicLevelAt(zap.InfoLevel)
+	nx := &Nexodus{
+		logger:     zap.NewNop().Sugar(),
+		logLevel:   &logLevel,
+		version:    "test",
+		status:     NexdStatusRunning,
+		TunnelIP:   "100.64.0.1",
+		TunnelIpV6: "200::1",
+	}
+	server := rpc.NewServer()
+	require.NoError(server.Register(&NexdCtlV1{nx: nx}))
+	serverConn, conn := net.Pipe()
+	go server.ServeCodec(jsonrpc.NewServerCodec(serverConn))
+	c := jsonrpc.NewClient(conn)
+	defer c.Close()
+
+	status := api.StatusResponse{}
+	require.NoError(c.Call(api.CtlServiceV1+".Status", api.Empty{}, &status))
+	require.Equal(api.StatusResponse{Status: "Running", Version: "test"}, status)
+
+	tunnelIPs := api.TunnelIPsResponse{}
+	require.NoError(c.Call(api.CtlServiceV1+".TunnelIPs", api.Empty{}, &tunnelIPs))
+	require.Equal(api.TunnelIPsResponse{IPv4: "100.64.0.1", IPv6: "200::1"}, tunnelIPs)
+
+	debug := api.DebugResponse{}
+	require.NoError(c.Call(api.CtlServiceV1+".SetDebug", api.DebugRequest{Debug: true}, &debug))
+	require.True(debug.Debug)
+	require.Equal(zap.DebugLevel, logLevel.Level())
+
+	rules := api.ProxyRulesResponse{}
+	require.NoError(c.Call(api.CtlServiceV1+".ProxyRules", api.Empty{}, &rules))
+	require.Empty(rules.Rules)
+
+	rule := api.ProxyRule{}
+	require.ErrorContains(c.Call(api.CtlServiceV1+".ProxyAdd", api.ProxyRuleRequest{Type: "sideways", Rule: "tcp:80:10.0.0.1:8080"}, &rule), "invalid proxy rule type")
+
+	pingPeers := api.PingPeersResponse{}
+	require.ErrorContains(c.Call(api.CtlServiceV1+".PingPeers", api.PingPeersRequest{Family: "v5"}, &pingPeers), "unknown address family")
+}
+
+func TestProxyRuleToApi(t *testing.T) {
+	require := require.New(t)
+
+	rule, err := ParseProxyRule("tcp:8080:10.0.0.1:80", ProxyTypeIngress)
+	require.NoError(err)
+	require.Equal(api.ProxyRule{
+		Type:        "ingress",
+		Protocol:    "tcp",
+		ListenPort:  8080,
+		Destination: "10.0.0.1:80",
+		Rule:        "tcp:8080:10.0.0.1:80",
+	}, rule.toApi())
+}
@@ -2,8 +2,20 @@ package nexodus
 
 import (
 	"fmt"
+	"net"
 
-	"go.uber.org/zap"
+	"github.com/nexodus-io/nexodus/internal/util"
+)
+
+// exitRouteFamily holds the ip command family flag and the default route of an address family
+type exitRouteFamily struct {
+	flag         string
+	defaultRoute string
+}
+
+var (
+	exitRouteFamilyV4 = exitRouteFamily{flag: "-4", defaultRoute: "0.0.0.0/0"}
+	exitRouteFamilyV6 = exitRouteFamily{flag: "-6", defaultRoute: "::/0"}
 )
 
 // enableExitSrcValidMarkV4 enables the src_valid_mark functionality for all v4 network interfaces.
@@ -17,8 +29,8 @@ func enableExitSrcValidMarkV4() error {
 
 // addExitSrcRuleToRPDB adds a rule to the routing policy database (RPDB) that says, If a packet does
 // not have the firewall mark 51820, look up the routing table 51820.
-func addExitSrcRuleToRPDB() error {
-	if _, err := RunCommand("ip", "-4", "rule", "add", "not", "fwmark", wgFwMarkStr, "table", wgFwMarkStr); err != nil {
+func addExitSrcRuleToRPDB(family exitRouteFamily) error {
+	if _, err := RunCommand("ip", family.flag, "rule", "add", "not", "fwmark", wgFwMarkStr, "table", wgFwMarkStr); err != nil {
 		return fmt.Errorf("failed to add fwmark rule to RPDB: %w", err)
 	}
 
@@ -27,8 +39,8 @@ func addExitSrcRuleToRPDB() error {
 
 // addExitSrcRuleIgnorePrefixLength adds a rule to the RPDB that says, "When looking up the main routing table, ignore
 // the source address prefix length. This is useful for avoiding unnecessary routing cache updates when using policy-based routing.
-func addExitSrcRuleIgnorePrefixLength() error {
-	if _, err := RunCommand("ip", "-4", "rule", "add", "table", "main", "suppress_prefixlength", "0"); err != nil {
+func addExitSrcRuleIgnorePrefixLength(family exitRouteFamily) error {
+	if _, err := RunCommand("ip", family.flag, "rule", "add", "table", "main", "suppress_prefixlength", "0"); err != nil {
 		return fmt.Errorf("failed to add fwmark rule to RPDB: %w", err)
 	}
 
@@ -36,81 +48,87 @@ func addExitSrcRuleIgnorePrefixLength() error {
 }
 
 // addExitSrcDefaultRouteTable adds a default route to the routing table 51820, which says that all traffic should be sent through wg0.
-func addExitSrcDefaultRouteTable() error {
-	if _, err := RunCommand("ip", "-4", "route", "add", "0.0.0.0/0", "dev", wgIface, "table", wgFwMarkStr); err != nil {
+func addExitSrcDefaultRouteTable(family exitRouteFamily) error {
+	if _, err := RunCommand("ip", family.flag, "route", "add", family.defaultRoute, "dev", wgIface, "table", wgFwMarkStr); err != nil {
 		return fmt.Errorf("failed to add default route to routing table: %w", err)
 	}
 
 	return nil
 }
 
-// nfAddExitSrcMangleTable create a nftables table for mangle
-func nfAddExitSrcMangleTable(logger *zap.SugaredLogger) error {
-	if _, err := policyCmd(logger, []string{"add", "table", "inet", nfOobMangleTable}); err != nil {
-		return fmt.Errorf("failed to add nftables table nexodus-stun-mangle: %w", err)
+// nfExitSrcMangleTable returns the nftables mangle (alter) table that sets the mark 0x4B66 for OOB (out of band)
+// packets sent to the DNS and STUN ports and to the api server, so that they are routed through the physical interface.
+// The split tunnel policy marks the excluded destinations the same way, and when it lists the destinations to include,
+// every other destination as well.
+func nfExitSrcMangleTable(apiServerIPs []net.IP, splitTunnel exitNodeSplitTunnel) nfTable {
+	rules := []nfRule{
+		{nfL4proto(ipProtoUDP), nfDport{from: oobDNS, to: oobDNS}, nfCounter{}, nfMarkSet(oobFwdMark)},
 	}
-
-	return nil
-}
-
-// nfAddExitSrcMangleOutputChain creates a nftables chain OUTPUT within the nftables mangle (alter) table.
-func nfAddExitSrcMangleOutputChain(logger *zap.SugaredLogger) error {
-	if _, err := policyCmd(logger, []string{"add", "chain", "inet", nfOobMangleTable,
-		"OUTPUT", "{", "type", "route", "hook", "output", "priority", "mangle", ";", "policy", "accept", ";", "}"}); err != nil {
-		return fmt.Errorf("failed to add nftables OUTPUT chain: %w", err)
+	for _, ip := range apiServerIPs {
+		addrFamily := "ip"
+		if ip.To4() == nil {
+			addrFamily = "ip6"
+		}
+		rules = append(rules, nfRule{nfAddr{family: addrFamily, dst: true, value: ip.String()}, nfL4proto(ipProtoTCP), nfDport{from: oobHttps, to: oobHttps}, nfCounter{}, nfMarkSet(oobFwdMark)})
 	}
-
-	return nil
-}
-
-// nfAddExitSrcDestPortMangleRule adds a rule to the nftables mangle (alter) table that
-// sets the mark 0x4B66 for OOB (out of band) packets sent to a specific port.
-func nfAddExitSrcDestPortMangleRule(logger *zap.SugaredLogger, proto string, port int) error {
-	if _, err := policyCmd(logger, []string{"add", "rule", "inet", nfOobMangleTable, "OUTPUT", "meta", "l4proto", proto,
-		proto, "dport", fmt.Sprintf("%d", port), "counter", "mark", "set", oobFwdMarkHex}); err != nil {
-		return fmt.Errorf("failed to add nftables OUTPUT rule: %w", err)
+	rules = append(rules, nfRule{nfL4proto(ipProtoUDP), nfDport{from: oobGoogleStun, to: oobGoogleStun}, nfCounter{}, nfMarkSet(oobFwdMark)})
+	for _, cidr := range splitTunnel.exclude {
+		rules = append(rules, nfRule{nfAddr{family: nfCidrFamily(cidr), dst: true, value: cidr}, nfCounter{}, nfMarkSet(oobFwdMark)})
 	}
-
-	return nil
-}
-
-// nfAddExitSrcDestPortMangleRule adds a rule to the nftables mangle (alter) table that
-// sets the mark 0x4B66 for OOB (out of band) packets sent to a specific port.
-func nfAddExitSrcApiServerOOBMangleRule(logger *zap.SugaredLogger, proto, apiServer string, port int) error {
-	if _, err := policyCmd(logger, []string{"add", "rule", "inet", nfOobMangleTable, "OUTPUT", "ip", "daddr", apiServer,
-		proto, "dport", fmt.Sprintf("%d", port), "counter", "mark", "set", oobFwdMarkHex}); err != nil {
-		return fmt.Errorf("failed to add nftables OUTPUT rule: %w", err)
+	if splitTunnel.includeOnly {
+		for _, cidr := range splitTunnel.include {
+			rules = append(rules, nfRule{nfAddr{family: nfCidrFamily(cidr), dst: true, value: cidr}, nfCounter{}, nfAccept})
+		}
+		rules = append(rules, nfRule{nfCounter{}, nfMarkSet(oobFwdMark)})
 	}
 
-	return nil
+	return nfTable{
+		family: tableFamily,
+		name:   nfOobMangleTable,
+		chains: []nfChain{
+			{name: "OUTPUT", chainType: nfChainTypeRoute, hook: nfHookOutput, priority: nfPriorityMangle, rules: rules},
+		},
+	}
 }
 
-// nfAddExitSrcSnatTable create a nftables table for OOB SNAT
-func nfAddExitSrcSnatTable(logger *zap.SugaredLogger) error {
-	if _, err := policyCmd(logger, []string{"add", "table", "inet", nfOobSnatTable}); err != nil {
-		return fmt.Errorf("failed to add nftables table nexodus-stun-mangle: %w", err)
-
+// nfCidrFamily returns the nftables address family of the cidr
+func nfCidrFamily(cidr string) string {
+	if util.IsIPv4Prefix(cidr) {
+		return "ip"
 	}
-
-	return nil
+	return "ip6"
 }
 
-// nfAddExitSrcSnatOutputChain the purpose of this chain is to perform source NAT (SNAT) for outgoing packets
-func nfAddExitSrcSnatOutputChain(logger *zap.SugaredLogger) error {
-	if _, err := policyCmd(logger, []string{"add", "chain", "inet", nfOobSnatTable, "POSTROUTING", "{", "type", "nat", "hook", "postrouting", "priority", "srcnat", ";", "policy", "accept", ";", "}"}); err != nil {
-		return fmt.Errorf("failed to add nftables snat chain POSTROUTING: %w", err)
+// nfExitSrcSnatTable returns the nexodus oob snat table, the purpose of this table is to perform source NAT (SNAT)
+// for outgoing packets by masquerading them in postrouting
+func nfExitSrcSnatTable(phyIfaces ...string) nfTable {
+	return nfTable{
+		family: tableFamily,
+		name:   nfOobSnatTable,
+		chains: []nfChain{
+			{
+				name:      "POSTROUTING",
+				chainType: nfChainTypeNAT,
+				hook:      nfHookPostrouting,
+				priority:  nfPrioritySrcNAT,
+				rules:     nfMasqueradeRules(phyIfaces),
+			},
+		},
 	}
-
-	return nil
 }
 
-// nfAddExitSrcSnatRule adds a rule to the nexodus oob snat table that applies masquerading to postrouting
-func nfAddExitSrcSnatRule(logger *zap.SugaredLogger, phyIface string) error {
-	if _, err := policyCmd(logger, []string{"add", "rule", "inet", nfOobSnatTable, "POSTROUTING", "oifname", phyIface, "counter", "masquerade"}); err != nil {
-		return fmt.Errorf("failed to add nftables rule SNAT POSTROUTING: %w", err)
+// nfMasqueradeRules masquerades the packets leaving through each of the interfaces, empty and duplicate names are skipped
+func nfMasqueradeRules(phyIfaces []string) []nfRule {
+	var rules []nfRule
+	seen := map[string]bool{}
+	for _, phyIface := range phyIfaces {
+		if phyIface == "" || seen[phyIface] {
+			continue
+		}
+		seen[phyIface] = true
+		rules = append(rules, nfRule{nfIfname{output: true, name: phyIface}, nfCounter{}, nfMasquerade{}})
 	}
-
-	return nil
+	return rules
 }
 
 // addExitSrcDefaultRouteTableOOB adds a default route to the OOB routing table, which sources traffic through the physical interface with a gateway
@@ -127,19 +145,41 @@ func addExitSrcDefaultRouteTableOOB(phyIface string) error {
 	return nil
 }
 
+// addExitSrcDefaultRouteTableOOBv6 adds the IPv6 default route to the OOB routing table
+func addExitSrcDefaultRouteTableOOBv6(gwIP, phyIface string) error {
+	if _, err := RunCommand("ip", "-6", "route", "add", "::/0", "table", oobFwMark, "via", gwIP, "dev", phyIface); err != nil {
+		return fmt.Errorf("failed to add IPv6 default route to routing table %s: %w", oobFwMark, err)
+	}
+
+	return nil
+}
+
 // addExitSrcRuleFwMarkOOB This command adds a rule to the RPDB that says, If a packet has the firewall mark 19302, look up the routing
 // table 19302. This is used to route marked packets with destination port 19302 using the custom routing table
-func addExitSrcRuleFwMarkOOB() error {
-	if _, err := RunCommand("ip", "-4", "rule", "add", "fwmark", oobFwMark, "table", oobFwMark); err != nil {
+func addExitSrcRuleFwMarkOOB(family exitRouteFamily) error {
+	if _, err := RunCommand("ip", family.flag, "rule", "add", "fwmark", oobFwMark, "table", oobFwMark); err != nil {
 		return fmt.Errorf("failed to add OOB fwmark rule to RPDB: %w", err)
 	}
 
 	return nil
 }
 
+// deleteExitSrcRules removes the RPDB rules added by the exit node client, rules that do not exist are ignored
+// so that setting up the client again after a failover does not pile up duplicate rules.
+func deleteExitSrcRules(family exitRouteFamily) {
+	rules := [][]string{
+		{"not", "fwmark", wgFwMarkStr, "table", wgFwMarkStr},
+		{"table", "main", "suppress_prefixlength", "0"},
+		{"fwmark", oobFwMark, "table", oobFwMark},
+	}
+	for _, rule := range rules {
+		_, _ = RunCommand(append([]string{"ip", family.flag, "rule", "del"}, rule...)...)
+	}
+}
+
 // flushExitSrcRouteTableOOB flushes the specified routing table
-func flushExitSrcRouteTableOOB(routeTable string) error {
-	if _, err := RunCommand("ip", "route", "flush", "table", routeTable); err != nil {
+func flushExitSrcRouteTableOOB(family exitRouteFamily, routeTable string) error {
+	if _, err := RunCommand("ip", family.flag, "route", "flush", "table", routeTable); err != nil {
 		return fmt.Errorf("failed to flush routing table %s: %w", routeTable, err)
 	}
 
@@ -2,6 +2,12 @@ package nexodus
 
 import (
 	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
 	"github.com/nexodus-io/nexodus/internal/util"
 	"golang.zx2c4.com/wireguard/wgctrl"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
@@ -14,7 +20,7 @@ const (
 	wgFwMark         = 51820
 	wgFwMarkStr      = "51820"
 	oobFwMark        = "19302"
-	oobFwdMarkHex    = "0x4B66"
+	oobFwdMark       = 0x4B66
 	nfExitNodeTable  = "nexodus-exit-node"
 	nfOobMangleTable = "nexodus-oob-mangle"
 	nfOobSnatTable   = "nexodus-oob-snat"
@@ -22,40 +28,14 @@ const (
 
 // ExitNodeClientSetup setups up the routing tables, netfilter tables and out of band connections for the exit node client
 func (nx *Nexodus) ExitNodeClientSetup() error {
+	nx.deviceCacheLock.Lock()
 	nx.exitNode.exitNodeClientEnabled = true
+	nx.updateExitNodeOrigins()
+	origin, found := nx.exitNodeActiveOrigin()
+	nx.exitNode.exitNodeReconfigure = false
+	nx.deviceCacheLock.Unlock()
 
-	// Lock deviceCache for read
-	nx.deviceCacheLock.RLock()
-	defer nx.deviceCacheLock.RUnlock()
-
-	exitNodeFound := false
-	for _, deviceEntry := range nx.deviceCache {
-		for _, allowedIp := range deviceEntry.device.AllowedIps {
-			if util.IsDefaultIPv4Route(allowedIp) {
-				// assign the device advertising a default route as the exit node server/origin node
-				localEndpoint := ""
-				for _, endpoint := range deviceEntry.device.Endpoints {
-					if endpoint.GetSource() == "local" {
-						localEndpoint = endpoint.GetAddress()
-						break
-					}
-				}
-				nx.exitNode.exitNodeOrigins[0] = wgPeerConfig{
-					PublicKey:           deviceEntry.device.GetPublicKey(),
-					Endpoint:            localEndpoint,
-					AllowedIPs:          deviceEntry.device.AllowedIps,
-					PersistentKeepAlive: persistentKeepalive,
-				}
-				exitNodeFound = true
-				break
-			}
-		}
-		if exitNodeFound {
-			break
-		}
-	}
-
-	if len(nx.exitNode.exitNodeOrigins) == 0 {
+	if !found {
 		return fmt.Errorf("no exit node found in this device's peerings")
 	}
 
@@ -82,7 +62,7 @@ func (nx *Nexodus) ExitNodeClientSetup() error {
 		return fmt.Errorf("error adding exit node client fwdMark: %w", err)
 	}
 
-	if err := nx.handlePeerTunnel(nx.exitNode.exitNodeOrigins[0]); err != nil {
+	if err := nx.handlePeerTunnel(origin); err != nil {
 		nx.logger.Debug(err)
 		return err
 	}
@@ -92,126 +72,96 @@ func (nx *Nexodus) ExitNodeClientSetup() error {
 		nx.logger.Debugf("failed to discover the interface with the address [ %s ] %v", nx.endpointLocalAddress, err)
 	}
 
-	if err := enableExitSrcValidMarkV4(); err != nil {
-		nx.logger.Debug(err)
-		return err
-	}
-
-	if err := addExitSrcRuleToRPDB(); err != nil {
-		nx.logger.Debug(err)
-		return err
-	}
-
-	if err := addExitSrcRuleIgnorePrefixLength(); err != nil {
-		nx.logger.Debug(err)
-		return err
-	}
-
-	if err := addExitSrcDefaultRouteTable(); err != nil {
-		nx.logger.Debug(err)
-		nx.logger.Debugf("default route already exists in table %s", oobFwMark)
-	}
-
-	if err := nfAddExitSrcMangleTable(nx.logger); err != nil {
-		nx.logger.Debug(err)
-		return err
-	}
-
-	if err := nfAddExitSrcMangleOutputChain(nx.logger); err != nil {
-		nx.logger.Debug(err)
-		return err
+	// IPv6 traffic is only sent to the exit node if the origin advertises an IPv6 default route,
+	// otherwise it keeps using the local IPv6 default gateway.
+	ipv6 := nx.ipv6Supported && exitNodeOriginIPv6(origin)
+	gwIPv6, devNameIPv6 := "", ""
+	if ipv6 {
+		gwIPv6, devNameIPv6, err = getDefaultGatewayIPv6()
+		if err != nil {
+			nx.logger.Debugf("no IPv6 default gateway found, out of band IPv6 traffic will not bypass the exit node: %v", err)
+		}
 	}
 
-	if err := nfAddExitSrcDestPortMangleRule(nx.logger, "udp", oobDNS); err != nil {
+	if err := enableExitSrcValidMarkV4(); err != nil {
 		nx.logger.Debug(err)
 		return err
 	}
 
-	ips, err := ResolveURLToIP(nx.apiURL.String())
-	if err != nil {
-		nx.logger.Debug(err)
-		return err
+	families := []exitRouteFamily{exitRouteFamilyV4}
+	if ipv6 {
+		families = append(families, exitRouteFamilyV6)
 	}
+	for _, family := range families {
+		if err := addExitSrcRuleToRPDB(family); err != nil {
+			nx.logger.Debug(err)
+			return err
+		}
 
-	for _, ip := range ips {
-		if err := nfAddExitSrcApiServerOOBMangleRule(nx.logger, "tcp", ip.String(), oobHttps); err != nil {
-			fmt.Printf("Error adding rule for IP %s: %v\n", ip, err)
+		if err := addExitSrcDefaultRouteTable(family); err != nil {
+			nx.logger.Debug(err)
+			nx.logger.Debugf("default route already exists in table %s", wgFwMarkStr)
 		}
 	}
 
-	if err := nfAddExitSrcDestPortMangleRule(nx.logger, "udp", oobGoogleStun); err != nil {
+	if err := nx.exitNodeApplyPolicy(); err != nil {
 		nx.logger.Debug(err)
 		return err
 	}
 
-	if err := nfAddExitSrcSnatTable(nx.logger); err != nil {
+	if err := nfApplyTable(nx.logger, nfExitSrcSnatTable(devName, devNameIPv6)); err != nil {
 		nx.logger.Debug(err)
 		return err
 	}
 
-	if err := nfAddExitSrcSnatOutputChain(nx.logger); err != nil {
-		nx.logger.Debug(err)
-		return err
+	if err := addExitSrcDefaultRouteTableOOB(devName); err != nil {
+		nx.logger.Debugf("default route already exists in table %s", oobFwMark)
 	}
 
-	if err := nfAddExitSrcSnatOutputChain(nx.logger); err != nil {
-		nx.logger.Debug(err)
-		return err
+	if gwIPv6 != "" {
+		if err := addExitSrcDefaultRouteTableOOBv6(gwIPv6, devNameIPv6); err != nil {
+			nx.logger.Debugf("IPv6 default route already exists in table %s", oobFwMark)
+		}
 	}
 
-	if err := nfAddExitSrcSnatRule(nx.logger, devName); err != nil {
-		nx.logger.Debug(err)
-		return err
-	}
+	// rules added later are evaluated first: the main table lookup that ignores default routes comes before the
+	// OOB mark, so that LAN and mesh destinations left out by the exit node policy keep using their routes
+	for _, family := range families {
+		if err := addExitSrcRuleFwMarkOOB(family); err != nil {
+			nx.logger.Debug(err)
+			return err
+		}
 
-	if err := addExitSrcDefaultRouteTableOOB(devName); err != nil {
-		nx.logger.Debugf("default route already exists in table %s", oobFwMark)
+		if err := addExitSrcRuleIgnorePrefixLength(family); err != nil {
+			nx.logger.Debug(err)
+			return err
+		}
 	}
 
-	if err := addExitSrcRuleFwMarkOOB(); err != nil {
-		nx.logger.Debug(err)
-		return err
-	}
+	nx.exitNode.exitNodeClientIPv6 = ipv6
+	nx.exitNode.exitNodeClientApplied = true
 
 	nx.logger.Info("Exit node client configuration has been enabled")
-	nx.logger.Debugf("Exit node client enabled and using the exit node server: %+v", nx.exitNode.exitNodeOrigins[0])
+	nx.logger.Debugf("Exit node client enabled and using the exit node server: %+v", origin)
 
 	return nil
 }
 
 // exitNodeOriginSetup sets up the exit node origin where traffic is originated when it exits the wireguard network
 func (nx *Nexodus) exitNodeOriginSetup() error {
-	// clean up any existing exit-node tables from previous executions
-	if err := nx.exitNodeOriginTeardown(); err != nil {
-		return err
-	}
-
 	devName, err := getInterfaceFromIPv4(nx.endpointLocalAddress)
 	if err != nil {
 		nx.logger.Debugf("failed to discover the interface with the address [ %s ] %v", nx.endpointLocalAddress, err)
 	}
 
-	if err := addExitDestinationTable(nx.logger); err != nil {
-		return err
-	}
-
-	if err := addExitOriginPreroutingChain(nx.logger); err != nil {
-		return err
-	}
-
-	if err := addExitOriginPostroutingChain(nx.logger); err != nil {
-		return err
-	}
-
-	if err := addExitOriginForwardChain(nx.logger); err != nil {
-		return err
-	}
-
-	if err := addExitOriginPostroutingRule(nx.logger, devName); err != nil {
-		return err
+	// IPv6 traffic from the clients leaves through the interface of the IPv6 default route
+	_, devNameIPv6, err := getDefaultGatewayIPv6()
+	if err != nil {
+		nx.logger.Debugf("no IPv6 default gateway found, only IPv4 traffic will exit through this node: %v", err)
 	}
 
-	if err := addExitOriginForwardRule(nx.logger); err != nil {
+	// existing tables from previous executions are updated in place
+	if err := nfApplyTable(nx.logger, nfExitOriginTable(devName, devNameIPv6)); err != nil {
 		return err
 	}
 
@@ -225,11 +175,19 @@ func (nx *Nexodus) exitNodeClientTeardown() error {
 
 	// TODO: this needs to be able to be set by nexctl but not for initial pre-deploy checks
 	// nx.exitNode.exitNodeClientEnabled = false
+	nx.exitNode.exitNodeClientApplied = false
 
+	families := []exitRouteFamily{exitRouteFamilyV4}
+	if nx.ipv6Supported {
+		families = append(families, exitRouteFamilyV6)
+	}
 	exitNodeRouteTables := []string{wgFwMarkStr, oobFwMark}
-	for _, routeTable := range exitNodeRouteTables {
-		if err1 = flushExitSrcRouteTableOOB(routeTable); err1 != nil {
-			nx.logger.Debug(err1)
+	for _, family := range families {
+		deleteExitSrcRules(family)
+		for _, routeTable := range exitNodeRouteTables {
+			if err1 = flushExitSrcRouteTableOOB(family, routeTable); err1 != nil {
+				nx.logger.Debug(err1)
+			}
 		}
 	}
 
@@ -266,3 +224,275 @@ func (nx *Nexodus) exitNodeOriginTeardown() error {
 
 	return nil
 }
+
+// exitNodeCandidate is an exit node origin along with the health of the peering with it
+type exitNodeCandidate struct {
+	publicKey         string
+	healthy           bool
+	lastHandshakeTime time.Time
+}
+
+// advertisesDefaultRoute returns true if the device advertises an IPv4 or IPv6 default route
+func advertisesDefaultRoute(device client.ModelsDevice) bool {
+	for _, cidr := range device.AdvertiseCidrs {
+		if util.IsDefaultIPRoute(cidr) {
+			return true
+		}
+	}
+	return false
+}
+
+// exitNodeOriginIPv6 returns true if the origin advertises an IPv6 default route
+func exitNodeOriginIPv6(origin wgPeerConfig) bool {
+	for _, allowedIP := range origin.AllowedIPs {
+		if util.IsDefaultIPv6Route(allowedIP) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutDefaultRoutes returns the allowed ips without the IPv4 and IPv6 default routes
+func withoutDefaultRoutes(allowedIPs []string) []string {
+	filtered := make([]string, 0, len(allowedIPs))
+	for _, allowedIP := range allowedIPs {
+		if !util.IsDefaultIPRoute(allowedIP) {
+			filtered = append(filtered, allowedIP)
+		}
+	}
+	if len(filtered) == len(allowedIPs) {
+		return allowedIPs
+	}
+	return filtered
+}
+
+// exitNodeAllowedIPs removes the default routes from the allowed ips of every peer but the active exit node
+// origin. Wireguard assigns an allowed ip to a single peer, so when several devices advertise a default route
+// the origin that gets it has to be chosen here rather than left to the order the peers are configured in.
+// Assumes deviceCacheLock is held.
+func (nx *Nexodus) exitNodeAllowedIPs(publicKey string, allowedIPs []string) []string {
+	if publicKey == nx.exitNode.exitNodeActive {
+		return allowedIPs
+	}
+	return withoutDefaultRoutes(allowedIPs)
+}
+
+// chooseExitNodeOrigin returns the public key of the origin the default routes should be sent to. The selected
+// origin is used while it is healthy, otherwise the active origin is kept while it is healthy so that connections
+// are not moved between exit nodes needlessly. When neither is healthy, the healthiest of the remaining origins is
+// used, the one with the most recent handshake. If no origin is healthy, the selected or active origin is kept.
+func chooseExitNodeOrigin(candidates []exitNodeCandidate, selected, active string) string {
+	if len(candidates) == 0 {
+		return ""
+	}
+	sorted := make([]exitNodeCandidate, len(candidates))
+	copy(sorted, candidates)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].healthy != sorted[j].healthy {
+			return sorted[i].healthy
+		}
+		if !sorted[i].lastHandshakeTime.Equal(sorted[j].lastHandshakeTime) {
+			return sorted[i].lastHandshakeTime.After(sorted[j].lastHandshakeTime)
+		}
+		return sorted[i].publicKey < sorted[j].publicKey
+	})
+	byKey := map[string]exitNodeCandidate{}
+	for _, c := range sorted {
+		byKey[c.publicKey] = c
+	}
+
+	if c, ok := byKey[selected]; ok && c.healthy {
+		return selected
+	}
+	if c, ok := byKey[active]; ok && c.healthy {
+		return active
+	}
+	if sorted[0].healthy {
+		return sorted[0].publicKey
+	}
+	if _, ok := byKey[selected]; ok {
+		return selected
+	}
+	if _, ok := byKey[active]; ok {
+		return active
+	}
+	return sorted[0].publicKey
+}
+
+// updateExitNodeOrigins refreshes the exit node origins from the device cache and fails over to another origin
+// when the active one is no longer healthy. Assumes deviceCacheLock is held with a write-lock.
+func (nx *Nexodus) updateExitNodeOrigins() {
+	var origins []wgPeerConfig
+	var candidates []exitNodeCandidate
+	for _, d := range nx.deviceCache {
+		if d.device.GetPublicKey() == nx.wireguardPubKey || !advertisesDefaultRoute(d.device) {
+			continue
+		}
+		endpoint := d.endpoint
+		if peer, ok := nx.wgConfig.Peers[d.device.GetPublicKey()]; ok && peer.Endpoint != "" {
+			endpoint = peer.Endpoint
+		}
+		allowedIPs := append(append([]string{}, d.device.AllowedIps...), d.device.AdvertiseCidrs...)
+		origins = append(origins, wgPeerConfig{
+			PublicKey:           d.device.GetPublicKey(),
+			Endpoint:            endpoint,
+			AllowedIPs:          allowedIPs,
+			PersistentKeepAlive: persistentKeepalive,
+		})
+		candidates = append(candidates, exitNodeCandidate{
+			publicKey:         d.device.GetPublicKey(),
+			healthy:           d.peerHealthy,
+			lastHandshakeTime: d.lastHandshakeTime,
+		})
+	}
+	sort.Slice(origins, func(i, j int) bool {
+		return origins[i].PublicKey < origins[j].PublicKey
+	})
+	nx.exitNode.exitNodeOrigins = origins
+	nx.exitNode.exitNodeExists = len(origins) > 0
+
+	previous := nx.exitNode.exitNodeActive
+	active := chooseExitNodeOrigin(candidates, nx.exitNode.exitNodeSelected, previous)
+	if active == previous {
+		return
+	}
+	nx.exitNode.exitNodeActive = active
+	if active == "" {
+		nx.logger.Warn("No exit node origin is left in this device's peerings")
+		return
+	}
+	if previous == "" {
+		nx.logger.Infof("Using the exit node origin [ %s ]", active)
+	} else {
+		nx.logger.Infof("Exit node origin failed over from [ %s ] to [ %s ]", previous, active)
+	}
+
+	// the exit node client routes only depend on the origin when it was not known yet or the IPv6 support differs,
+	// otherwise moving the default route to the new origin peer is enough.
+	if nx.exitNode.exitNodeClientEnabled {
+		origin, _ := nx.exitNodeActiveOrigin()
+		if previous == "" || nx.exitNode.exitNodeClientIPv6 != (nx.ipv6Supported && exitNodeOriginIPv6(origin)) {
+			nx.exitNode.exitNodeReconfigure = true
+		}
+	}
+}
+
+// exitNodeActiveOrigin returns the peer configuration of the active exit node origin. Assumes deviceCacheLock is held.
+func (nx *Nexodus) exitNodeActiveOrigin() (wgPeerConfig, bool) {
+	for _, origin := range nx.exitNode.exitNodeOrigins {
+		if origin.PublicKey == nx.exitNode.exitNodeActive {
+			return origin, true
+		}
+	}
+	return wgPeerConfig{}, false
+}
+
+// exitNodeOriginKey returns the public key of the exit node origin matching the device, which is either
+// its public key, device id, hostname or tunnel ip. Assumes deviceCacheLock is held.
+func (nx *Nexodus) exitNodeOriginKey(device string) (string, error) {
+	var matches []string
+	for _, origin := range nx.exitNode.exitNodeOrigins {
+		d, ok := nx.deviceCache[origin.PublicKey]
+		if !ok {
+			continue
+		}
+		if device == origin.PublicKey || device == d.device.GetId() || strings.EqualFold(device, d.device.GetHostname()) {
+			matches = append(matches, origin.PublicKey)
+			continue
+		}
+		for _, allowedIP := range d.device.AllowedIps {
+			if ip, _, err := net.ParseCIDR(allowedIP); err == nil && ip.String() == device {
+				matches = append(matches, origin.PublicKey)
+				break
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("device %s is not an exit node in this device's peerings", device)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("several exit nodes match %s, use the device id or public key instead", device)
+}
+
+// ExitNodeUse picks the exit node origin the default routes are sent to, an empty device goes back to
+// automatically using the healthiest origin.
+func (nx *Nexodus) ExitNodeUse(device string) error {
+	nx.deviceCacheLock.Lock()
+	selected := ""
+	if device != "" {
+		var err error
+		if selected, err = nx.exitNodeOriginKey(device); err != nil {
+			nx.deviceCacheLock.Unlock()
+			return err
+		}
+	}
+	nx.exitNode.exitNodeSelected = selected
+	nx.deviceCacheLock.Unlock()
+
+	// the main loop applies the selection so that it does not race its own reconcile
+	done := make(chan error, 1)
+	select {
+	case nx.exitNode.exitNodeSelectedChanged <- done:
+	case <-nx.nexCtx.Done():
+		return nx.nexCtx.Err()
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-nx.nexCtx.Done():
+		return nx.nexCtx.Err()
+	}
+}
+
+// ExitNodeClientEnable sets up or tears down the exit node client of a running nexd
+func (nx *Nexodus) ExitNodeClientEnable(enabled bool) error {
+	// the main loop applies the change so that it does not race a failover setting the client up again
+	request := exitNodeClientRequest{enabled: enabled, done: make(chan error, 1)}
+	select {
+	case nx.exitNode.exitNodeClientChanged <- request:
+	case <-nx.nexCtx.Done():
+		return nx.nexCtx.Err()
+	}
+	select {
+	case err := <-request.done:
+		return err
+	case <-nx.nexCtx.Done():
+		return nx.nexCtx.Err()
+	}
+}
+
+// exitNodeClientApply sets up or tears down the exit node client, it is called from the main loop
+func (nx *Nexodus) exitNodeClientApply(enabled bool) error {
+	if enabled {
+		return nx.ExitNodeClientSetup()
+	}
+	// keep a failover from setting the client up again
+	nx.deviceCacheLock.Lock()
+	nx.exitNode.exitNodeClientEnabled = false
+	nx.deviceCacheLock.Unlock()
+	return nx.exitNodeClientTeardown()
+}
+
+// exitNodeReconcile applies the exit node client configuration again when updateExitNodeOrigins noticed
+// that the routes depend on the new active origin, and updates the mangle table when the exit node policy changed.
+func (nx *Nexodus) exitNodeReconcile() {
+	nx.deviceCacheLock.Lock()
+	reconfigure := nx.exitNode.exitNodeReconfigure && nx.exitNode.exitNodeClientEnabled
+	policyChanged := nx.exitNode.exitNodePolicyChanged && nx.exitNode.exitNodeClientApplied
+	nx.exitNode.exitNodeReconfigure = false
+	nx.exitNode.exitNodePolicyChanged = false
+	nx.deviceCacheLock.Unlock()
+	if reconfigure {
+		if err := nx.ExitNodeClientSetup(); err != nil {
+			nx.logger.Errorf("failed to update the exit node client configuration: %v", err)
+		}
+		return
+	}
+	if policyChanged {
+		if err := nx.exitNodeApplyPolicy(); err != nil {
+			nx.logger.Errorf("failed to apply the exit node policy: %v", err)
+		}
+	}
+}
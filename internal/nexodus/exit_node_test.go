@@ -0,0 +1,119 @@
+package nexodus
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestChooseExitNodeOrigin(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	a := exitNodeCandidate{publicKey: "a", healthy: true, lastHandshakeTime: now.Add(-time.Minute)}
+	b := exitNodeCandidate{publicKey: "b", healthy: true, lastHandshakeTime: now}
+	down := exitNodeCandidate{publicKey: "c", lastHandshakeTime: now.Add(-time.Hour)}
+
+	tests := []struct {
+		name       string
+		candidates []exitNodeCandidate
+		selected   string
+		active     string
+		expected   string
+	}{
+		{name: "no origins", expected: ""},
+		{name: "healthiest origin", candidates: []exitNodeCandidate{a, b, down}, expected: "b"},
+		{name: "active origin is kept while healthy", candidates: []exitNodeCandidate{a, b}, active: "a", expected: "a"},
+		{name: "selected origin wins over the active one", candidates: []exitNodeCandidate{a, b}, selected: "a", active: "b", expected: "a"},
+		{name: "fail over from an unhealthy active origin", candidates: []exitNodeCandidate{a, down}, active: "c", expected: "a"},
+		{name: "fail over from an unhealthy selected origin", candidates: []exitNodeCandidate{a, b, down}, selected: "c", active: "c", expected: "b"},
+		{name: "unknown selection is ignored", candidates: []exitNodeCandidate{a}, selected: "x", expected: "a"},
+		{name: "keep the active origin when none is healthy", candidates: []exitNodeCandidate{down, {publicKey: "d"}}, active: "d", expected: "d"},
+		{name: "keep the selected origin when none is healthy", candidates: []exitNodeCandidate{down, {publicKey: "d"}}, selected: "d", active: "c", expected: "d"},
+		{name: "most recent handshake when none is healthy", candidates: []exitNodeCandidate{{publicKey: "d"}, down}, expected: "c"},
+	}
+	for _, test := range tests {
+		require.Equal(test.expected, chooseExitNodeOrigin(test.candidates, test.selected, test.active), test.name)
+	}
+}
+
+func TestExitNodeAllowedIPs(t *testing.T) {
+	require := require.New(t)
+
+	nx := &Nexodus{exitNode: exitNode{exitNodeActive: "active"}}
+	allowedIPs := []string{"100.64.0.2/32", "0.0.0.0/0", "200::2/128", "::/0"}
+
+	require.Equal(allowedIPs, nx.exitNodeAllowedIPs("active", allowedIPs))
+	require.Equal([]string{"100.64.0.2/32", "200::2/128"}, nx.exitNodeAllowedIPs("other", allowedIPs))
+	require.Nil(nx.exitNodeAllowedIPs("other", nil))
+}
+
+func TestNfExitOriginTable(t *testing.T) {
+	require := require.New(t)
+
+	postrouting := nfExitOriginTable("eth0", "eth1").chains[1]
+	require.Len(postrouting.rules, 2)
+	require.Equal(`oifname "eth1" counter masquerade`, postrouting.rules[1].String())
+
+	// the IPv6 default route commonly uses the same interface, or there is none
+	require.Len(nfExitOriginTable("eth0", "eth0").chains[1].rules, 1)
+	require.Len(nfExitOriginTable("eth0", "").chains[1].rules, 1)
+}
+
+func TestResolveExitNodePolicy(t *testing.T) {
+	require := require.New(t)
+
+	lookup := func(name string) ([]net.IP, error) {
+		if name == "vpn.example.com" {
+			return []net.IP{net.ParseIP("203.0.113.10"), net.ParseIP("2001:db8::10")}, nil
+		}
+		return nil, fmt.Errorf("no such host %s", name)
+	}
+	logger := zap.NewNop().Sugar()
+
+	require.Equal(exitNodeSplitTunnel{}, resolveExitNodePolicy(logger, nil, lookup))
+
+	splitTunnel := resolveExitNodePolicy(logger, &client.ModelsExitNodePolicy{
+		Exclude: []string{"10.0.0.0/8", "192.168.1.7/16", "vpn.example.com", "missing.example.com", "10.0.0.0/8"},
+	}, lookup)
+	require.False(splitTunnel.includeOnly)
+	require.Equal([]string{"10.0.0.0/8", "192.168.0.0/16", "203.0.113.10/32", "2001:db8::10/128"}, splitTunnel.exclude)
+
+	splitTunnel = resolveExitNodePolicy(logger, &client.ModelsExitNodePolicy{Include: []string{"vpn.example.com"}}, lookup)
+	require.True(splitTunnel.includeOnly)
+	require.Equal([]string{"203.0.113.10/32", "2001:db8::10/128"}, splitTunnel.include)
+
+	// an include list that does not resolve still keeps the traffic away from the exit node
+	splitTunnel = resolveExitNodePolicy(logger, &client.ModelsExitNodePolicy{Include: []string{"missing.example.com"}}, lookup)
+	require.True(splitTunnel.includeOnly)
+	require.Empty(splitTunnel.include)
+}
+
+func TestNfExitSrcMangleTable(t *testing.T) {
+	require := require.New(t)
+
+	apiServerIPs := []net.IP{net.ParseIP("198.51.100.1")}
+	require.Len(nfExitSrcMangleTable(apiServerIPs, exitNodeSplitTunnel{}).chains[0].rules, 3)
+
+	rules := nfExitSrcMangleTable(apiServerIPs, exitNodeSplitTunnel{
+		exclude: []string{"192.168.0.0/16", "fd00::/8"},
+	}).chains[0].rules
+	require.Len(rules, 5)
+	require.Equal("ip daddr 192.168.0.0/16 counter meta mark set 0x4b66", rules[3].String())
+	require.Equal("ip6 daddr fd00::/8 counter meta mark set 0x4b66", rules[4].String())
+
+	rules = nfExitSrcMangleTable(apiServerIPs, exitNodeSplitTunnel{
+		includeOnly: true,
+		include:     []string{"0.0.0.0/0"},
+		exclude:     []string{"10.0.0.0/8"},
+	}).chains[0].rules
+	require.Len(rules, 6)
+	require.Equal("ip daddr 10.0.0.0/8 counter meta mark set 0x4b66", rules[3].String())
+	require.Equal("ip daddr 0.0.0.0/0 counter accept", rules[4].String())
+	require.Equal("counter meta mark set 0x4b66", rules[5].String())
+}
@@ -0,0 +1,116 @@
+package nexodus
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/nexodus-io/nexodus/internal/util"
+	"go.uber.org/zap"
+)
+
+// exitNodePolicyInterval is how often the domain names of the exit node policy are resolved again
+const exitNodePolicyInterval = time.Minute
+
+// exitNodeSplitTunnel is the exit node policy of this device with the domain names resolved to addresses
+type exitNodeSplitTunnel struct {
+	// includeOnly is set when the policy lists the destinations to include, everything else bypasses the exit node
+	includeOnly bool
+	include     []string
+	exclude     []string
+}
+
+// resolveExitNodePolicy turns the include and exclude lists of the policy into cidrs. Domain names are resolved
+// with lookup, names that fail to resolve are logged and left out until the next refresh.
+func resolveExitNodePolicy(logger *zap.SugaredLogger, policy *client.ModelsExitNodePolicy, lookup func(string) ([]net.IP, error)) exitNodeSplitTunnel {
+	if policy == nil {
+		return exitNodeSplitTunnel{}
+	}
+	return exitNodeSplitTunnel{
+		includeOnly: len(policy.Include) > 0,
+		include:     resolveExitNodePolicyEntries(logger, policy.Include, lookup),
+		exclude:     resolveExitNodePolicyEntries(logger, policy.Exclude, lookup),
+	}
+}
+
+func resolveExitNodePolicyEntries(logger *zap.SugaredLogger, entries []string, lookup func(string) ([]net.IP, error)) []string {
+	var cidrs []string
+	seen := map[string]bool{}
+	add := func(prefix netip.Prefix) {
+		cidr := prefix.Masked().String()
+		if !seen[cidr] {
+			seen[cidr] = true
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	for _, entry := range entries {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			add(prefix)
+			continue
+		}
+		ips, err := lookup(entry)
+		if err != nil {
+			logger.Warnf("failed to resolve the exit node policy domain %s: %v", entry, err)
+			continue
+		}
+		for _, ip := range ips {
+			addr, ok := netip.AddrFromSlice(ip)
+			if !ok {
+				continue
+			}
+			addr = addr.Unmap()
+			add(netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return cidrs
+}
+
+// exitNodePolicyHasDomains returns true if the policy lists domain names that need to be resolved periodically
+func exitNodePolicyHasDomains(policy *client.ModelsExitNodePolicy) bool {
+	if policy == nil {
+		return false
+	}
+	for _, entry := range append(append([]string{}, policy.Include...), policy.Exclude...) {
+		if !util.IsValidPrefix(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateExitNodePolicy records the exit node policy of this device. Assumes deviceCacheLock is held with a write-lock.
+func (nx *Nexodus) updateExitNodePolicy(policy *client.ModelsExitNodePolicy) {
+	if reflect.DeepEqual(nx.exitNode.exitNodePolicy, policy) {
+		return
+	}
+	nx.exitNode.exitNodePolicy = policy
+	nx.exitNode.exitNodePolicyChanged = true
+	nx.logger.Debugf("exit node policy changed: %s", util.JsonStringer(policy))
+}
+
+// refreshExitNodePolicy has the domain names of the exit node policy resolved again on the next reconcile
+func (nx *Nexodus) refreshExitNodePolicy() {
+	nx.deviceCacheLock.Lock()
+	defer nx.deviceCacheLock.Unlock()
+	if exitNodePolicyHasDomains(nx.exitNode.exitNodePolicy) {
+		nx.exitNode.exitNodePolicyChanged = true
+	}
+}
+
+// exitNodeApplyPolicy updates the mangle table of the exit node client so that the out of band traffic and the
+// destinations the exit node policy leaves out are routed through the physical interface.
+func (nx *Nexodus) exitNodeApplyPolicy() error {
+	ips, err := ResolveURLToIP(nx.apiURL.String())
+	if err != nil {
+		return err
+	}
+
+	nx.deviceCacheLock.RLock()
+	policy := nx.exitNode.exitNodePolicy
+	nx.deviceCacheLock.RUnlock()
+
+	splitTunnel := resolveExitNodePolicy(nx.logger, policy, net.LookupIP)
+	return nfApplyTable(nx.logger, nfExitSrcMangleTable(ips, splitTunnel))
+}
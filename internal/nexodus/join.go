@@ -12,15 +12,16 @@ import (
 
 func (nx *Nexodus) createOrUpdateDeviceOperation(userID string, endpoints []client.ModelsEndpoint) (client.ModelsDevice, string, error) {
 	newDev := client.ModelsAddDevice{
-		VpcId:           nx.vpc.Id,
-		SecurityGroupId: client.PtrOptionalString(nx.securityGroupId),
-		PublicKey:       &nx.wireguardPubKey,
-		AdvertiseCidrs:  nx.advertiseCidrs,
-		SymmetricNat:    &nx.symmetricNat,
-		Hostname:        &nx.hostname,
-		Relay:           client.PtrBool(nx.relay || nx.relayDerp),
-		Os:              &nx.os,
-		Endpoints:       endpoints,
+		VpcId:            nx.vpc.Id,
+		SecurityGroupId:  client.PtrOptionalString(firstSecurityGroupId(nx.securityGroupIds)),
+		SecurityGroupIds: nx.securityGroupIds,
+		PublicKey:        &nx.wireguardPubKey,
+		AdvertiseCidrs:   nx.advertiseCidrs,
+		SymmetricNat:     &nx.symmetricNat,
+		Hostname:         &nx.hostname,
+		Relay:            client.PtrBool(nx.relay || nx.relayDerp),
+		Os:               &nx.os,
+		Endpoints:        endpoints,
 	}
 
 	if len(nx.requestedIP) > 0 {
@@ -40,13 +41,14 @@ func (nx *Nexodus) createOrUpdateDeviceOperation(userID string, endpoints []clie
 			switch model := apiError.Model().(type) {
 			case client.ModelsConflictsError:
 				d, resp, err = nx.client.DevicesApi.UpdateDevice(context.Background(), model.GetId()).Update(client.ModelsUpdateDevice{
-					AdvertiseCidrs:  newDev.AdvertiseCidrs,
-					Endpoints:       newDev.Endpoints,
-					Hostname:        newDev.Hostname,
-					Relay:           newDev.Relay,
-					SecurityGroupId: newDev.SecurityGroupId,
-					SymmetricNat:    newDev.SymmetricNat,
-					VpcId:           newDev.VpcId,
+					AdvertiseCidrs:   newDev.AdvertiseCidrs,
+					Endpoints:        newDev.Endpoints,
+					Hostname:         newDev.Hostname,
+					Relay:            newDev.Relay,
+					SecurityGroupId:  newDev.SecurityGroupId,
+					SecurityGroupIds: newDev.SecurityGroupIds,
+					SymmetricNat:     newDev.SymmetricNat,
+					VpcId:            newDev.VpcId,
 				}).Execute()
 				deviceOperationMsg = "Reconnected as device"
 				if err != nil {
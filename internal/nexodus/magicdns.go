@@ -0,0 +1,304 @@
+package nexodus
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/nexodus-io/nexodus/internal/dnsserver"
+	"github.com/nexodus-io/nexodus/internal/util"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+
+	_ "github.com/coredns/coredns/plugin/bind"
+	_ "github.com/coredns/coredns/plugin/file"
+	_ "github.com/coredns/coredns/plugin/forward"
+)
+
+const (
+	// magicDnsDomain is the parent domain of every per-VPC zone served by nexd
+	magicDnsDomain = "nexodus.internal"
+	// magicDnsTTL is the ttl in seconds of the records in the generated zone
+	magicDnsTTL = 60
+	// magicDnsZoneFile is the name of the zone file written to the state dir
+	magicDnsZoneFile = "magicdns.zone"
+	// magicDnsDefaultUpstream is used to forward queries outside the nexodus domain
+	magicDnsDefaultUpstream = "/etc/resolv.conf"
+	// magicDnsMetadataPrefix is the prefix of the device metadata keys published in the zone
+	magicDnsMetadataPrefix = "dns."
+	// magicDnsSrvKey is the device metadata key holding the services advertised with SRV records
+	magicDnsSrvKey = magicDnsMetadataPrefix + "srv"
+	// magicDnsTxtKey is the device metadata key holding the TXT records of the device name
+	magicDnsTxtKey = magicDnsMetadataPrefix + "txt"
+)
+
+// magicDnsSrvMetadata is the value of the dns.srv device metadata key, for example:
+//
+//	{"services": [{"service": "postgres", "protocol": "tcp", "port": 5432}]}
+//
+// is published as _postgres._tcp.<vpc-id>.nexodus.internal pointing at the device name.
+type magicDnsSrvMetadata struct {
+	Services []magicDnsService `json:"services"`
+}
+
+type magicDnsService struct {
+	Service  string   `json:"service"`
+	Protocol string   `json:"protocol"`
+	Port     int      `json:"port"`
+	Priority int      `json:"priority"`
+	Weight   int      `json:"weight"`
+	Txt      []string `json:"txt"`
+}
+
+// magicDnsTxtMetadata is the value of the dns.txt device metadata key, for example:
+//
+//	{"records": ["owner=db-team"]}
+type magicDnsTxtMetadata struct {
+	Records []string `json:"records"`
+}
+
+// embedded in Nexodus struct
+type magicDns struct {
+	magicDnsEnabled   bool
+	magicDnsUpstreams []string
+	magicDnsServer    *dnsserver.Server
+	// the last corefile and zone records applied to the dns server
+	magicDnsCorefile string
+	magicDnsRecords  string
+}
+
+// magicDnsRecord is a single resource record of the VPC zone, the name is relative
+// to the magicDnsDomain origin.
+type magicDnsRecord struct {
+	name   string
+	rrType string
+	value  string
+}
+
+func (r magicDnsRecord) String() string {
+	return fmt.Sprintf("%s\t%d\tIN\t%s\t%s", r.name, magicDnsTTL, r.rrType, r.value)
+}
+
+// magicDnsLabel converts a device hostname into a valid dns label.
+func magicDnsLabel(hostname string) string {
+	label := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, hostname)
+	label = strings.Trim(label, "-")
+	if len(label) > 63 {
+		label = strings.TrimRight(label[:63], "-")
+	}
+	return label
+}
+
+// magicDnsVpcName returns the name of the VPC zone relative to magicDnsDomain
+func magicDnsVpcName(vpcId string) string {
+	return strings.ToLower(vpcId)
+}
+
+// magicDnsTxtValue quotes a TXT record value, splitting it into the 255 byte strings allowed by RFC 1035.
+func magicDnsTxtValue(txt string) string {
+	var parts []string
+	for len(txt) > 255 {
+		parts = append(parts, txt[:255])
+		txt = txt[255:]
+	}
+	parts = append(parts, txt)
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, `\`, `\\`)
+		part = strings.ReplaceAll(part, `"`, `\"`)
+		parts[i] = `"` + part + `"`
+	}
+	return strings.Join(parts, " ")
+}
+
+// buildMagicDnsRecords returns the sorted records that should be served for the devices of the VPC
+// and the services they advertise in their dns.* metadata keys.
+func buildMagicDnsRecords(logger *zap.SugaredLogger, vpcId string, devices []client.ModelsDevice, metadata []client.ModelsDeviceMetadata) []magicDnsRecord {
+	vpcName := magicDnsVpcName(vpcId)
+	names := map[string]string{}
+	var records []magicDnsRecord
+	for _, d := range devices {
+		label := magicDnsLabel(d.GetHostname())
+		if label == "" {
+			continue
+		}
+		name := label + "." + vpcName
+		names[d.GetId()] = name
+		for _, ip := range d.GetIpv4TunnelIps() {
+			if addr := net.ParseIP(ip.GetAddress()); addr != nil && addr.To4() != nil {
+				records = append(records, magicDnsRecord{name: name, rrType: "A", value: addr.String()})
+			}
+		}
+		for _, ip := range d.GetIpv6TunnelIps() {
+			if addr := net.ParseIP(ip.GetAddress()); addr != nil && addr.To4() == nil {
+				records = append(records, magicDnsRecord{name: name, rrType: "AAAA", value: addr.String()})
+			}
+		}
+	}
+
+	for _, md := range metadata {
+		name, found := names[md.GetDeviceId()]
+		if !found {
+			continue
+		}
+		switch md.GetKey() {
+		case magicDnsSrvKey:
+			value := magicDnsSrvMetadata{}
+			if err := util.JsonUnmarshal(md.Value, &value); err != nil {
+				logger.Debugf("ignoring invalid %s metadata of device %s: %v", magicDnsSrvKey, md.GetDeviceId(), err)
+				continue
+			}
+			for _, svc := range value.Services {
+				service := magicDnsLabel(svc.Service)
+				protocol := ProxyProtocol(strings.ToLower(svc.Protocol))
+				if protocol == "" {
+					protocol = proxyProtocolTCP
+				}
+				if service == "" || (protocol != proxyProtocolTCP && protocol != proxyProtocolUDP) ||
+					svc.Port < 1 || svc.Port > 65535 || svc.Priority < 0 || svc.Priority > 65535 || svc.Weight < 0 || svc.Weight > 65535 {
+					logger.Debugf("ignoring invalid service %+v in the %s metadata of device %s", svc, magicDnsSrvKey, md.GetDeviceId())
+					continue
+				}
+				srvName := fmt.Sprintf("_%s._%s.%s", service, protocol, vpcName)
+				records = append(records, magicDnsRecord{
+					name:   srvName,
+					rrType: "SRV",
+					value:  fmt.Sprintf("%d %d %d %s.%s.", svc.Priority, svc.Weight, svc.Port, name, magicDnsDomain),
+				})
+				for _, txt := range svc.Txt {
+					records = append(records, magicDnsRecord{name: srvName, rrType: "TXT", value: magicDnsTxtValue(txt)})
+				}
+			}
+		case magicDnsTxtKey:
+			value := magicDnsTxtMetadata{}
+			if err := util.JsonUnmarshal(md.Value, &value); err != nil {
+				logger.Debugf("ignoring invalid %s metadata of device %s: %v", magicDnsTxtKey, md.GetDeviceId(), err)
+				continue
+			}
+			for _, txt := range value.Records {
+				records = append(records, magicDnsRecord{name: name, rrType: "TXT", value: magicDnsTxtValue(txt)})
+			}
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].String() < records[j].String()
+	})
+	return records
+}
+
+// buildMagicDnsZone renders the records as an RFC 1035 zone file for the magicDnsDomain origin.
+func buildMagicDnsZone(records []magicDnsRecord, serial int64) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("$ORIGIN %s.\n", magicDnsDomain))
+	sb.WriteString(fmt.Sprintf("@\t%d\tIN\tSOA\tns.%s. hostmaster.%s. %d 7200 3600 1209600 %d\n",
+		magicDnsTTL, magicDnsDomain, magicDnsDomain, serial, magicDnsTTL))
+	for _, r := range records {
+		sb.WriteString(r.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// buildMagicDnsCorefile returns the CoreDNS configuration that serves the zone file on the
+// tunnel addresses and forwards all other queries to the upstream resolvers.
+func buildMagicDnsCorefile(bindAddrs []string, zoneFile string, upstreams []string) string {
+	bind := strings.Join(bindAddrs, " ")
+	return fmt.Sprintf(`%s:53 {
+	bind %s
+	file %s {
+		reload 0
+	}
+}
+.:53 {
+	bind %s
+	forward . %s
+}
+`, magicDnsDomain, bind, zoneFile, bind, strings.Join(upstreams, " "))
+}
+
+// dnsMetadataChanged returns the change notification channel of the dns metadata informer, the
+// returned channel is nil when magic dns is disabled so that it never fires.
+func (nx *Nexodus) dnsMetadataChanged() <-chan struct{} {
+	if nx.dnsMetadataInformer == nil {
+		return nil
+	}
+	return nx.dnsMetadataInformer.Changed()
+}
+
+// reconcileMagicDns regenerates the VPC zone from the device cache and starts or restarts the
+// dns server if the zone or the listen addresses changed.
+func (nx *Nexodus) reconcileMagicDns() {
+	if !nx.magicDnsEnabled || nx.userspaceMode || nx.TunnelIP == "" {
+		return
+	}
+
+	var devices []client.ModelsDevice
+	nx.deviceCacheIterRead(func(d deviceCacheEntry) {
+		devices = append(devices, d.device)
+	})
+	var metadata []client.ModelsDeviceMetadata
+	if nx.dnsMetadataInformer != nil {
+		items, _, err := nx.dnsMetadataInformer.Execute()
+		if err != nil {
+			nx.logger.Errorf("failed to list the dns metadata of the VPC devices: %v", err)
+			return
+		}
+		metadata = maps.Values(items)
+	}
+	records := buildMagicDnsRecords(nx.logger, nx.vpc.GetId(), devices, metadata)
+	recordsText := ""
+	for _, r := range records {
+		recordsText += r.String() + "\n"
+	}
+
+	bindAddrs := []string{nx.TunnelIP}
+	if nx.ipv6Supported && nx.TunnelIpV6 != "" {
+		bindAddrs = append(bindAddrs, nx.TunnelIpV6)
+	}
+	upstreams := nx.magicDnsUpstreams
+	if len(upstreams) == 0 {
+		upstreams = []string{magicDnsDefaultUpstream}
+	}
+	zoneFile := filepath.Join(nx.stateDir, magicDnsZoneFile)
+	corefile := buildMagicDnsCorefile(bindAddrs, zoneFile, upstreams)
+
+	if nx.magicDnsServer != nil && corefile == nx.magicDnsCorefile && recordsText == nx.magicDnsRecords {
+		// nothing changed
+		return
+	}
+
+	zone := buildMagicDnsZone(records, time.Now().Unix())
+	if err := os.WriteFile(zoneFile, []byte(zone), 0644); err != nil {
+		nx.logger.Errorf("failed to write the magic dns zone file: %v", err)
+		return
+	}
+
+	if nx.magicDnsServer == nil {
+		server, err := dnsserver.Start(nx.nexCtx, nx.nexWg, corefile)
+		if err != nil {
+			nx.logger.Errorf("failed to start the magic dns server: %v", err)
+			return
+		}
+		nx.magicDnsServer = server
+		nx.logger.Infof("Magic DNS is serving %s.%s on %s", magicDnsVpcName(nx.vpc.GetId()), magicDnsDomain, strings.Join(bindAddrs, ", "))
+	} else if err := nx.magicDnsServer.Restart(corefile); err != nil {
+		nx.logger.Errorf("failed to reload the magic dns server: %v", err)
+		return
+	}
+	nx.logger.Debugf("Magic DNS zone updated with %d records", len(records))
+	nx.magicDnsCorefile = corefile
+	nx.magicDnsRecords = recordsText
+}
@@ -0,0 +1,144 @@
+package nexodus
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/nexodus-io/nexodus/internal/dnsserver"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMagicDnsLabel(t *testing.T) {
+	tests := map[string]string{
+		"web1":                   "web1",
+		"Web_Server":             "web-server",
+		"host.example.com":       "host-example-com",
+		"--edge--":               "edge",
+		"":                       "",
+		string(make([]byte, 70)): "",
+	}
+	for hostname, expected := range tests {
+		require.Equal(t, expected, magicDnsLabel(hostname), "hostname %q", hostname)
+	}
+}
+
+func TestMagicDnsZone(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	vpcId := "3F8c9a64-1111-2222-3333-444455556666"
+	devices := []client.ModelsDevice{
+		{
+			Id:            client.PtrString("device-1"),
+			Hostname:      client.PtrString("web1"),
+			Ipv4TunnelIps: []client.ModelsTunnelIP{{Address: client.PtrString("100.64.0.1")}},
+			Ipv6TunnelIps: []client.ModelsTunnelIP{{Address: client.PtrString("200::1")}},
+		},
+		{
+			Id:            client.PtrString("device-2"),
+			Hostname:      client.PtrString("DB.local"),
+			Ipv4TunnelIps: []client.ModelsTunnelIP{{Address: client.PtrString("100.64.0.2")}},
+		},
+		{
+			// devices without a usable hostname are skipped
+			Hostname:      client.PtrString("..."),
+			Ipv4TunnelIps: []client.ModelsTunnelIP{{Address: client.PtrString("100.64.0.3")}},
+		},
+	}
+
+	metadata := []client.ModelsDeviceMetadata{
+		{
+			DeviceId: client.PtrString("device-2"),
+			Key:      client.PtrString(magicDnsSrvKey),
+			Value: map[string]interface{}{
+				"services": []interface{}{
+					map[string]interface{}{"service": "postgres", "port": 5432, "priority": 10, "weight": 5, "txt": []interface{}{"version=15"}},
+					// invalid port, skipped
+					map[string]interface{}{"service": "bad", "port": 0},
+				},
+			},
+		},
+		{
+			DeviceId: client.PtrString("device-1"),
+			Key:      client.PtrString(magicDnsTxtKey),
+			Value: map[string]interface{}{
+				"records": []interface{}{`owner="web team"`},
+			},
+		},
+		{
+			// metadata of unknown devices is ignored
+			DeviceId: client.PtrString("device-9"),
+			Key:      client.PtrString(magicDnsTxtKey),
+			Value: map[string]interface{}{
+				"records": []interface{}{"ignored"},
+			},
+		},
+	}
+
+	records := buildMagicDnsRecords(zap.NewNop().Sugar(), vpcId, devices, metadata)
+	require.Len(records, 6)
+
+	zoneFile := filepath.Join(t.TempDir(), magicDnsZoneFile)
+	require.NoError(os.WriteFile(zoneFile, []byte(buildMagicDnsZone(records, 1)), 0644))
+
+	server, err := dnsserver.Start(ctx, nil, fmt.Sprintf(`
+			%s:0 {
+					bind 127.0.0.1
+					file %s
+			}
+		`, magicDnsDomain, zoneFile))
+	require.NoError(err)
+	listenAddr, _, err := server.Ports()
+	require.NoError(err)
+
+	d := &dns.Client{
+		Timeout: 5 * time.Second,
+	}
+	query := func(name string, qtype uint16) *dns.Msg {
+		m := &dns.Msg{}
+		m.SetQuestion(name, qtype)
+		resp, _, err := d.Exchange(m, listenAddr.String())
+		require.NoError(err)
+		return resp
+	}
+
+	resp := query("web1.3f8c9a64-1111-2222-3333-444455556666.nexodus.internal.", dns.TypeA)
+	require.Equal(dns.RcodeSuccess, resp.Rcode)
+	require.Len(resp.Answer, 1)
+	require.Equal("web1.3f8c9a64-1111-2222-3333-444455556666.nexodus.internal.\t60\tIN\tA\t100.64.0.1", resp.Answer[0].String())
+
+	resp = query("web1.3f8c9a64-1111-2222-3333-444455556666.nexodus.internal.", dns.TypeAAAA)
+	require.Equal(dns.RcodeSuccess, resp.Rcode)
+	require.Len(resp.Answer, 1)
+	require.Equal("web1.3f8c9a64-1111-2222-3333-444455556666.nexodus.internal.\t60\tIN\tAAAA\t200::1", resp.Answer[0].String())
+
+	resp = query("db-local.3f8c9a64-1111-2222-3333-444455556666.nexodus.internal.", dns.TypeA)
+	require.Equal(dns.RcodeSuccess, resp.Rcode)
+	require.Len(resp.Answer, 1)
+
+	resp = query("_postgres._tcp.3f8c9a64-1111-2222-3333-444455556666.nexodus.internal.", dns.TypeSRV)
+	require.Equal(dns.RcodeSuccess, resp.Rcode)
+	require.Len(resp.Answer, 1)
+	require.Equal("_postgres._tcp.3f8c9a64-1111-2222-3333-444455556666.nexodus.internal.\t60\tIN\tSRV\t10 5 5432 db-local.3f8c9a64-1111-2222-3333-444455556666.nexodus.internal.", resp.Answer[0].String())
+
+	resp = query("_postgres._tcp.3f8c9a64-1111-2222-3333-444455556666.nexodus.internal.", dns.TypeTXT)
+	require.Equal(dns.RcodeSuccess, resp.Rcode)
+	require.Len(resp.Answer, 1)
+	require.Equal([]string{"version=15"}, resp.Answer[0].(*dns.TXT).Txt)
+
+	resp = query("web1.3f8c9a64-1111-2222-3333-444455556666.nexodus.internal.", dns.TypeTXT)
+	require.Equal(dns.RcodeSuccess, resp.Rcode)
+	require.Len(resp.Answer, 1)
+	require.Equal([]string{`owner=\"web team\"`}, resp.Answer[0].(*dns.TXT).Txt)
+
+	resp = query("missing.3f8c9a64-1111-2222-3333-444455556666.nexodus.internal.", dns.TypeA)
+	require.Equal(dns.RcodeNameError, resp.Rcode)
+}
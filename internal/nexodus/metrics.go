@@ -0,0 +1,136 @@
+package nexodus
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var peerMetricLabels = []string{"public_key", "hostname", "device_id"}
+
+var (
+	peerTxBytesDesc = prometheus.NewDesc("nexd_peer_tx_bytes_total",
+		"Bytes sent to the peer over the wireguard tunnel.", peerMetricLabels, nil)
+	peerRxBytesDesc = prometheus.NewDesc("nexd_peer_rx_bytes_total",
+		"Bytes received from the peer over the wireguard tunnel.", peerMetricLabels, nil)
+	peerHandshakeAgeDesc = prometheus.NewDesc("nexd_peer_last_handshake_age_seconds",
+		"Seconds since the last wireguard handshake with the peer, not reported before the first handshake.", peerMetricLabels, nil)
+	peerHealthyDesc = prometheus.NewDesc("nexd_peer_healthy",
+		"1 if the connection to the peer is healthy, 0 otherwise.", peerMetricLabels, nil)
+	peerPeeringMethodIndexDesc = prometheus.NewDesc("nexd_peer_peering_method_index",
+		"Index of the peering method in use, higher values are tried after the lower ones failed. -1 before the first peering.", peerMetricLabels, nil)
+	peerRelayedDesc = prometheus.NewDesc("nexd_peer_relayed",
+		"1 if the traffic to the peer goes through a wireguard or DERP relay, 0 if it is sent directly.", peerMetricLabels, nil)
+	peerInfoDesc = prometheus.NewDesc("nexd_peer_info",
+		"Connection details of the peer, the value is always 1.",
+		append(append([]string{}, peerMetricLabels...), "peering_method", "endpoint", "derp_region"), nil)
+)
+
+// peerMetricsCollector exports the connection state of the peers in the device cache. The metrics are built
+// when scraped so that peers leaving the VPC do not linger in the registry.
+type peerMetricsCollector struct {
+	nx *Nexodus
+}
+
+func (c peerMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- peerTxBytesDesc
+	ch <- peerRxBytesDesc
+	ch <- peerHandshakeAgeDesc
+	ch <- peerHealthyDesc
+	ch <- peerPeeringMethodIndexDesc
+	ch <- peerRelayedDesc
+	ch <- peerInfoDesc
+}
+
+func (c peerMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	c.nx.deviceCacheIterRead(func(d deviceCacheEntry) {
+		if d.device.GetPublicKey() == c.nx.wireguardPubKey {
+			return
+		}
+		labels := []string{d.device.GetPublicKey(), d.device.GetHostname(), d.device.GetId()}
+
+		ch <- prometheus.MustNewConstMetric(peerTxBytesDesc, prometheus.CounterValue, float64(d.lastTxBytes), labels...)
+		ch <- prometheus.MustNewConstMetric(peerRxBytesDesc, prometheus.CounterValue, float64(d.lastRxBytes), labels...)
+		if !d.lastHandshakeTime.IsZero() {
+			ch <- prometheus.MustNewConstMetric(peerHandshakeAgeDesc, prometheus.GaugeValue, now.Sub(d.lastHandshakeTime).Seconds(), labels...)
+		}
+		ch <- prometheus.MustNewConstMetric(peerHealthyDesc, prometheus.GaugeValue, boolMetric(d.peerHealthy), labels...)
+		ch <- prometheus.MustNewConstMetric(peerPeeringMethodIndexDesc, prometheus.GaugeValue, float64(d.peeringMethodIndex), labels...)
+		ch <- prometheus.MustNewConstMetric(peerRelayedDesc, prometheus.GaugeValue, boolMetric(peeringMethodRelayed(d.peeringMethod)), labels...)
+		ch <- prometheus.MustNewConstMetric(peerInfoDesc, prometheus.GaugeValue, 1,
+			append(labels, d.peeringMethod, d.endpoint, peerDerpRegion(d))...)
+	})
+}
+
+// peeringMethodRelayed returns true if the peering method sends the traffic through a relay
+func peeringMethodRelayed(method string) bool {
+	return method == peeringMethodViaRelay || method == peeringMethodViaDerpRelay
+}
+
+// peerDerpRegion returns the DERP region the peer is reached through, the port of a DERP peer endpoint is the region id
+func peerDerpRegion(d deviceCacheEntry) string {
+	if d.peeringMethod != peeringMethodViaDerpRelay {
+		return ""
+	}
+	_, port, err := net.SplitHostPort(d.endpoint)
+	if err != nil {
+		return ""
+	}
+	return port
+}
+
+func boolMetric(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// newMetricsRegistry returns the registry served on the metrics endpoint
+func (nx *Nexodus) newMetricsRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		peerMetricsCollector{nx: nx},
+	)
+	return registry
+}
+
+// MetricsServerStart serves the Prometheus metrics of nexd on the --metrics-listen address until the context is done
+func (nx *Nexodus) MetricsServerStart(ctx context.Context, wg *sync.WaitGroup) error {
+	l, err := net.Listen("tcp", nx.metricsListen)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(nx.newMetricsRegistry(), promhttp.HandlerOpts{}))
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	util.GoWithWaitGroup(wg, func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	})
+	util.GoWithWaitGroup(wg, func() {
+		nx.logger.Infof("Serving metrics on http://%s/metrics", l.Addr())
+		if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			nx.logger.Errorf("metrics server failed: %v", err)
+		}
+	})
+	return nil
+}
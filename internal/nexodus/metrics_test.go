@@ -0,0 +1,78 @@
+package nexodus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerMetricsCollector(t *testing.T) {
+	require := require.New(t)
+
+	nx := &Nexodus{
+		wireguardPubKey: "self",
+		deviceCache: map[string]deviceCacheEntry{
+			"self": {device: client.ModelsDevice{PublicKey: client.PtrString("self")}},
+			"direct": {
+				device: client.ModelsDevice{PublicKey: client.PtrString("direct"), Hostname: client.PtrString("east"), Id: client.PtrString("d1")},
+				peerHealth: peerHealth{
+					lastTxBytes:       100,
+					lastRxBytes:       200,
+					lastHandshakeTime: time.Now().Add(-time.Minute),
+					endpoint:          "203.0.113.1:51820",
+					peerHealthy:       true,
+				},
+				peeringMethod:      peeringMethodReflexive,
+				peeringMethodIndex: 3,
+			},
+			"derp": {
+				device:             client.ModelsDevice{PublicKey: client.PtrString("derp"), Hostname: client.PtrString("west"), Id: client.PtrString("d2")},
+				peerHealth:         peerHealth{endpoint: "127.0.0.2:900"},
+				peeringMethod:      peeringMethodViaDerpRelay,
+				peeringMethodIndex: 6,
+			},
+		},
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(peerMetricsCollector{nx: nx})
+	families, err := registry.Gather()
+	require.NoError(err)
+
+	metrics := map[string]map[string]float64{}
+	labels := map[string]map[string]string{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			l := map[string]string{}
+			for _, pair := range m.GetLabel() {
+				l[pair.GetName()] = pair.GetValue()
+			}
+			if metrics[family.GetName()] == nil {
+				metrics[family.GetName()] = map[string]float64{}
+			}
+			value := m.GetGauge().GetValue() + m.GetCounter().GetValue()
+			metrics[family.GetName()][l["public_key"]] = value
+			if family.GetName() == "nexd_peer_info" {
+				labels[l["public_key"]] = l
+			}
+		}
+	}
+
+	require.Equal(map[string]float64{"direct": 100, "derp": 0}, metrics["nexd_peer_tx_bytes_total"])
+	require.Equal(map[string]float64{"direct": 200, "derp": 0}, metrics["nexd_peer_rx_bytes_total"])
+	require.Equal(map[string]float64{"direct": 1, "derp": 0}, metrics["nexd_peer_healthy"])
+	require.Equal(map[string]float64{"direct": 0, "derp": 1}, metrics["nexd_peer_relayed"])
+	require.Equal(map[string]float64{"direct": 3, "derp": 6}, metrics["nexd_peer_peering_method_index"])
+
+	// the handshake age is only reported once a handshake happened
+	require.Len(metrics["nexd_peer_last_handshake_age_seconds"], 1)
+	require.InDelta(60, metrics["nexd_peer_last_handshake_age_seconds"]["direct"], 5)
+
+	require.Equal("east", labels["direct"]["hostname"])
+	require.Equal("", labels["direct"]["derp_region"])
+	require.Equal(peeringMethodViaDerpRelay, labels["derp"]["peering_method"])
+	require.Equal("900", labels["derp"]["derp_region"])
+}
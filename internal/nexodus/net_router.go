@@ -21,6 +21,10 @@ func (nx *Nexodus) setupNetworkRouterNode() error {
 	// iterate over advertiseCidrs and find the best matching interface for each cidr based on the device's
 	// default namespace routing table. If no match is found, use the interface containing the default gateway.
 	for _, cidr := range nx.advertiseCidrs {
+		// the IPv6 default route of an exit node is handled by the exit node origin table
+		if util.IsDefaultIPv6Route(cidr) {
+			continue
+		}
 		if util.IsIPv6Prefix(cidr) {
 			nx.logger.Warnf("IPv6 is not currently supported for --net-router: %s", cidr)
 			continue
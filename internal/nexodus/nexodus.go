@@ -33,6 +33,7 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/api"
 	"github.com/nexodus-io/nexodus/internal/client"
 	"github.com/nexodus-io/nexodus/internal/stun"
 	"github.com/nexodus-io/nexodus/internal/util"
@@ -81,10 +82,15 @@ type userspaceWG struct {
 	userspaceTun  tun.Device
 	userspaceNet  *netstack.Net
 	userspaceDev  *device.Device
+	// enforces the security group rules between the netstack and wireguard-go
+	userspaceFilter *packetFilter
+	// enforces the traffic limits of the peers on the packets received from them
+	userspaceLimiter *trafficLimiter
 	// the last address configured on the userspace wireguard interface
 	userspaceLastAddress string
 	proxyLock            sync.RWMutex
 	proxies              map[ProxyKey]*UsProxy
+	proxyResolver        *proxyResolver
 }
 
 type nexRelay struct {
@@ -177,10 +183,37 @@ type deviceCacheEntry struct {
 }
 
 type exitNode struct {
-	exitNodeExists        bool
+	exitNodeExists bool
+	// exitNodeClientEnabled is only read and written with deviceCacheLock held
 	exitNodeClientEnabled bool
 	exitNodeOriginEnabled bool
-	exitNodeOrigins       []wgPeerConfig
+	// exitNodeOrigins are the peers advertising a default route, ordered by public key
+	exitNodeOrigins []wgPeerConfig
+	// exitNodeSelected is the public key of the origin picked with nexctl nexd exit-node use,
+	// when empty the healthiest origin is used.
+	exitNodeSelected string
+	// exitNodeSelectedChanged asks the main loop to apply a new exitNodeSelected, the result is sent back on the request
+	exitNodeSelectedChanged chan chan error
+	// exitNodeClientChanged asks the main loop to set up or tear down the exit node client
+	exitNodeClientChanged chan exitNodeClientRequest
+	// exitNodeActive is the public key of the origin the default routes are sent to
+	exitNodeActive string
+	// exitNodeClientIPv6 is set when the client routes the IPv6 traffic to the exit node as well
+	exitNodeClientIPv6 bool
+	// exitNodeReconfigure is set when the client configuration needs to be applied again after the active origin changed
+	exitNodeReconfigure bool
+	// exitNodeClientApplied is set while the exit node client routes and tables are in place
+	exitNodeClientApplied bool
+	// exitNodePolicy is the split tunnel policy of this device, nil sends all the traffic to the exit node
+	exitNodePolicy *client.ModelsExitNodePolicy
+	// exitNodePolicyChanged is set when the mangle table needs to be updated for the exit node policy
+	exitNodePolicyChanged bool
+}
+
+// exitNodeClientRequest asks the main loop to set up or tear down the exit node client, the result is sent on done
+type exitNodeClientRequest struct {
+	enabled bool
+	done    chan error
 }
 
 type Options struct {
@@ -190,10 +223,14 @@ type Options struct {
 	Derper                  *Derper
 	ExitNodeClientEnabled   bool
 	ExitNodeOriginEnabled   bool
+	GatewayListen           string
 	InsecureSkipTlsVerify   bool
 	ListenPort              int
 	LogLevel                *zap.AtomicLevel
 	Logger                  *zap.SugaredLogger
+	MagicDns                bool
+	MagicDnsUpstreams       []string
+	MetricsListen           string
 	NetworkRouter           bool
 	NetworkRouterDisableNAT bool
 	Password                string
@@ -209,15 +246,17 @@ type Options struct {
 	UserspaceMode           bool
 	Version                 string
 	VpcId                   string
-	SecurityGroupId         string
+	SecurityGroupIds        []string
 }
 type Nexodus struct {
 	advertiseCidrs          []string
 	apiURL                  *url.URL
+	gatewayListen           string
 	insecureSkipTlsVerify   bool
 	listenPort              int
 	logLevel                *zap.AtomicLevel
 	logger                  *zap.SugaredLogger
+	metricsListen           string
 	networkRouter           bool
 	networkRouterDisableNAT bool
 	password                string
@@ -232,9 +271,10 @@ type Nexodus struct {
 	username                string
 	version                 string
 	vpcId                   string
-	securityGroupId         string
+	securityGroupIds        []string
 
 	userspaceWG
+	magicDns
 	Derper                   *Derper
 	nexRelay                 nexRelay
 	TunnelIP                 string
@@ -246,6 +286,7 @@ type Nexodus struct {
 	deviceReconciled         bool
 	devicesInformer          *client.ListInformer[client.ModelsDevice]
 	endpointLocalAddress     string
+	events                   eventBus
 	exitNode                 exitNode
 	hostname                 string
 	informerStop             context.CancelFunc
@@ -258,11 +299,15 @@ type Nexodus struct {
 	os                       string
 	reflexiveAddrStunSrc     string
 	relayWgIP                string
-	securityGroup            *client.ModelsSecurityGroup
+	securityGroups           []client.ModelsSecurityGroup
+	securityRules            securityRules
+	secGroupStatsReported    *securityGroupStatsReport // the rule counters last published in the device metadata
 	securityGroupsInformer   *client.ListInformer[client.ModelsSecurityGroup]
 	status                   int // See the NexdStatus* constants
 	statusMsg                string
 	symmetricNat             bool
+	trafficLimits            []peerTrafficLimit
+	trafficLimitsApplied     bool
 	tunnelIface              string
 	vpc                      *client.ModelsVPC
 	wgConfig                 wgConfig
@@ -270,6 +315,7 @@ type Nexodus struct {
 	wireguardPubKeyInConfig  bool
 	wireguardPvtKey          string
 	relayMetadataInformer    *client.ListInformer[client.ModelsDeviceMetadata]
+	dnsMetadataInformer      *client.ListInformer[client.ModelsDeviceMetadata]
 	deviceId                 string
 }
 
@@ -315,6 +361,8 @@ func New(o Options) (*Nexodus, error) {
 		relayOnly:               o.RelayOnly,
 		logger:                  o.Logger,
 		logLevel:                o.LogLevel,
+		metricsListen:           o.MetricsListen,
+		gatewayListen:           o.GatewayListen,
 		version:                 o.Version,
 		regKey:                  o.RegKey,
 		username:                o.Username,
@@ -323,7 +371,7 @@ func New(o Options) (*Nexodus, error) {
 		stateStore:              o.StateStore,
 		stateDir:                o.StateDir,
 		vpcId:                   o.VpcId,
-		securityGroupId:         o.SecurityGroupId,
+		securityGroupIds:        o.SecurityGroupIds,
 
 		hostname:    hostname,
 		deviceCache: make(map[string]deviceCacheEntry),
@@ -331,6 +379,10 @@ func New(o Options) (*Nexodus, error) {
 		userspaceWG: userspaceWG{
 			proxies: map[ProxyKey]*UsProxy{},
 		},
+		magicDns: magicDns{
+			magicDnsEnabled:   o.MagicDns,
+			magicDnsUpstreams: o.MagicDnsUpstreams,
+		},
 		Derper: o.Derper,
 		nexRelay: nexRelay{
 			derpIpMapping: NewDerpIpMapping(),
@@ -342,8 +394,10 @@ func New(o Options) (*Nexodus, error) {
 			inMemResolver: NewInMemResolver(),
 		},
 		exitNode: exitNode{
-			exitNodeClientEnabled: o.ExitNodeClientEnabled,
-			exitNodeOriginEnabled: o.ExitNodeOriginEnabled,
+			exitNodeClientEnabled:   o.ExitNodeClientEnabled,
+			exitNodeOriginEnabled:   o.ExitNodeOriginEnabled,
+			exitNodeSelectedChanged: make(chan chan error),
+			exitNodeClientChanged:   make(chan exitNodeClientRequest),
 		},
 	}
 
@@ -355,8 +409,13 @@ func New(o Options) (*Nexodus, error) {
 	nx.nexRelay.connCtx, nx.nexRelay.connCtxCancel = context.WithCancel(context.Background())
 	nx.nexRelay.donec = nx.nexRelay.connCtx.Done()
 	nx.nexRelay.muCond = sync.NewCond(&nx.nexRelay.mu)
+	nx.proxyResolver = newProxyResolver(nx.peerTunnelIP, nx.peerHostname)
 
 	nx.userspaceMode = o.UserspaceMode
+	if nx.userspaceMode {
+		nx.userspaceFilter = newPacketFilter(nx.logger)
+		nx.userspaceLimiter = newTrafficLimiter()
+	}
 
 	if !nx.userspaceMode {
 		isOk, err := isElevated()
@@ -432,8 +491,12 @@ func (nx *Nexodus) setListenPort(requestedPort int) error {
 }
 
 func (nx *Nexodus) SetStatus(status int, msg string) {
+	changed := nx.status != status || nx.statusMsg != msg
 	nx.statusMsg = msg
 	nx.status = status
+	if changed {
+		nx.events.publish(api.Event{Type: api.EventStatusChanged, Status: statusString(status), Message: msg})
+	}
 }
 
 type StateTokenStore struct {
@@ -521,10 +584,18 @@ func (nx *Nexodus) Start(ctx context.Context, wg *sync.WaitGroup) error {
 		return fmt.Errorf("CtlServerStart(): %w", err)
 	}
 
-	if runtime.GOOS != Linux.String() && runtime.GOOS != Darwin.String() {
-		nx.logger.Info("Security Groups are currently only supported on Linux and macOS")
-	} else if nx.userspaceMode {
-		nx.logger.Info("Security Groups are not supported in userspace proxy mode")
+	if nx.metricsListen != "" {
+		if err := nx.MetricsServerStart(ctx, wg); err != nil {
+			return fmt.Errorf("MetricsServerStart(): %w", err)
+		}
+	}
+
+	if !nx.userspaceMode && runtime.GOOS != Linux.String() && runtime.GOOS != Darwin.String() {
+		nx.logger.Info("Security Groups are currently only supported on Linux and macOS, or in userspace proxy mode")
+	}
+
+	if nx.magicDnsEnabled && nx.userspaceMode {
+		nx.logger.Info("Magic DNS is not supported in userspace proxy mode")
 	}
 
 	options := []client.Option{
@@ -676,6 +747,9 @@ func (nx *Nexodus) Start(ctx context.Context, wg *sync.WaitGroup) error {
 	nx.securityGroupsInformer = nx.client.VPCApi.ListSecurityGroupsInVPC(informerCtx, nx.vpc.GetId()).Informer()
 	nx.devicesInformer = nx.client.VPCApi.ListDevicesInVPC(informerCtx, nx.vpc.GetId()).Informer()
 	nx.relayMetadataInformer = nx.client.VPCApi.ListMetadataInVPC(informerCtx, nx.vpc.GetId()).Key("relay").Informer()
+	if nx.magicDnsEnabled {
+		nx.dnsMetadataInformer = nx.client.VPCApi.ListMetadataInVPC(informerCtx, nx.vpc.GetId()).Prefix([]string{magicDnsMetadataPrefix}).Informer()
+	}
 
 	if nx.relay {
 		peerMap, _, err := nx.devicesInformer.Execute()
@@ -697,7 +771,7 @@ func (nx *Nexodus) Start(ctx context.Context, wg *sync.WaitGroup) error {
 		if err := nx.enableForwardingIP(); err != nil {
 			return err
 		}
-		if err := nfRelayTablesSetup(wgIface); err != nil {
+		if err := nx.nfRelayTablesSetup(wgIface); err != nil {
 			return err
 		}
 	}
@@ -713,14 +787,26 @@ func (nx *Nexodus) Start(ctx context.Context, wg *sync.WaitGroup) error {
 		for _, proxy := range nx.proxies {
 			proxy.Start(ctx, wg, nx.userspaceNet)
 		}
-		if nx.exitNode.exitNodeClientEnabled {
+		if nx.gatewayListen != "" {
+			if err := nx.gatewayServerStart(ctx, wg); err != nil {
+				nx.logger.Errorf("failed to start the SOCKS5 and HTTP CONNECT gateway: %v", err)
+			}
+		}
+		nx.deviceCacheLock.RLock()
+		exitNodeClientEnabled := nx.exitNode.exitNodeClientEnabled
+		nx.deviceCacheLock.RUnlock()
+		if exitNodeClientEnabled {
 			if err := nx.ExitNodeClientSetup(); err != nil {
 				nx.logger.Errorf("failed to enable this device as an exit-node client: %v", err)
 			}
 		}
 		stunTicker := time.NewTicker(time.Second * 20)
 		secGroupTicker := time.NewTicker(time.Second * 20)
+		secGroupStatsTicker := time.NewTicker(securityGroupStatsInterval)
+		exitNodePolicyTicker := time.NewTicker(exitNodePolicyInterval)
 		defer stunTicker.Stop()
+		defer secGroupStatsTicker.Stop()
+		defer exitNodePolicyTicker.Stop()
 		pollTicker := time.NewTicker(pollInterval)
 		defer pollTicker.Stop()
 		for {
@@ -737,6 +823,10 @@ func (nx *Nexodus) Start(ctx context.Context, wg *sync.WaitGroup) error {
 				nx.reconcileDevices(ctx, options)
 			case <-nx.devicesInformer.Changed():
 				nx.reconcileDevices(ctx, options)
+				// rules referencing security groups follow the tunnel addresses of the member devices
+				nx.reconcileSecurityGroups(ctx)
+			case <-nx.dnsMetadataChanged():
+				nx.reconcileMagicDns()
 			case <-nx.securityGroupsInformer.Changed():
 				nx.reconcileSecurityGroups(ctx)
 			case <-pollTicker.C:
@@ -746,12 +836,26 @@ func (nx *Nexodus) Start(ctx context.Context, wg *sync.WaitGroup) error {
 				nx.reconcileDevices(ctx, options)
 			case <-secGroupTicker.C:
 				nx.reconcileSecurityGroups(ctx)
+			case <-secGroupStatsTicker.C:
+				nx.reportSecurityGroupStats(ctx)
+			case <-exitNodePolicyTicker.C:
+				// the addresses of the domain names in the exit node policy may have changed
+				nx.refreshExitNodePolicy()
+			case done := <-nx.exitNode.exitNodeSelectedChanged:
+				// apply the exit node picked with nexctl right away instead of waiting for the next reconcile
+				err := nx.reconcileDeviceCache()
+				nx.exitNodeReconcile()
+				done <- err
+			case request := <-nx.exitNode.exitNodeClientChanged:
+				request.done <- nx.exitNodeClientApply(request.enabled)
 			}
 			if nx.needSecGroupReconcile {
 				// device reconcile noticed that the security group Id changed
 				nx.reconcileSecurityGroups(ctx)
 				nx.needSecGroupReconcile = false
 			}
+			// device reconcile may have failed over to another exit node origin or updated the exit node policy
+			nx.exitNodeReconcile()
 		}
 	})
 
@@ -783,7 +887,11 @@ func (nx *Nexodus) fetchRegistrationTokenUserIdAndVPC(ctx context.Context) (stri
 		return "", nil, fmt.Errorf("could not fetch registration settings: %w", err)
 	}
 
-	nx.securityGroupId = regKeyModel.GetSecurityGroupId()
+	nx.securityGroupIds = regKeyModel.GetSecurityGroupIds()
+	if len(nx.securityGroupIds) == 0 && regKeyModel.GetSecurityGroupId() != "" {
+		// older api servers only support a single security group per reg key
+		nx.securityGroupIds = []string{regKeyModel.GetSecurityGroupId()}
+	}
 	nx.vpcId = regKeyModel.GetVpcId()
 
 	vpc, _, err := nx.client.VPCApi.GetVPC(ctx, regKeyModel.GetVpcId()).Execute()
@@ -858,7 +966,10 @@ func (nx *Nexodus) Stop() {
 		proxy.Stop()
 	}
 
-	if nx.exitNode.exitNodeClientEnabled {
+	nx.deviceCacheLock.RLock()
+	exitNodeClientEnabled := nx.exitNode.exitNodeClientEnabled
+	nx.deviceCacheLock.RUnlock()
+	if exitNodeClientEnabled {
 		nx.logger.Debugf("Stopping Exit Node Client")
 		if err := nx.exitNodeClientTeardown(); err != nil {
 			nx.logger.Errorf("failed to remove the exit node client configuration %v", err)
@@ -882,9 +993,9 @@ func (nx *Nexodus) Stop() {
 	}
 }
 
-// reconcileSecurityGroups will check the security group and update it if necessary.
+// reconcileSecurityGroups will check the security groups of the device and update them if necessary.
 func (nx *Nexodus) reconcileSecurityGroups(ctx context.Context) {
-	if runtime.GOOS != Linux.String() && runtime.GOOS != Darwin.String() || nx.userspaceMode {
+	if runtime.GOOS != Linux.String() && runtime.GOOS != Darwin.String() && !nx.userspaceMode {
 		return
 	}
 
@@ -894,70 +1005,96 @@ func (nx *Nexodus) reconcileSecurityGroups(ctx context.Context) {
 		return
 	}
 
-	if existing.device.GetSecurityGroupId() == uuid.Nil.String() {
+	ids := deviceSecurityGroupIds(existing.device)
+	if len(ids) == 0 {
 		// local device has no security group
-		if nx.securityGroup == nil {
+		if nx.securityGroups == nil {
 			// already set up that way, nothing to do
 			return
 		}
 		// drop local security group configuration
-		nx.securityGroup = nil
-		if err := nx.processSecurityGroupRules(); err != nil {
-			nx.logger.Error(err)
-		}
+		nx.clearSecurityGroups()
 		return
 	}
 
-	// if the security group ID is not nil, lookup the ID and check for any changes
+	// if the device has security groups, lookup the IDs and check for any changes
 	securityGroups, httpResp, err := nx.securityGroupsInformer.Execute()
 	if err != nil {
 		// if the group ID returns a 404, clear the current rules
 		if httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
-			nx.securityGroup = nil
-			if err := nx.processSecurityGroupRules(); err != nil {
-				nx.logger.Error(err)
-			}
+			nx.clearSecurityGroups()
 			return
 		}
 		nx.logger.Errorf("Error retrieving the security groups: %v", err)
 		return
 	}
 
-	responseSecGroup, found := securityGroups[existing.device.GetSecurityGroupId()]
-	if !found {
-		nx.securityGroup = nil
-		if err := nx.processSecurityGroupRules(); err != nil {
-			nx.logger.Error(err)
+	groups := make([]client.ModelsSecurityGroup, 0, len(ids))
+	for _, id := range ids {
+		responseSecGroup, found := securityGroups[id]
+		if !found {
+			nx.logger.Errorf("Error retrieving the security group %s", id)
+			continue
 		}
-		nx.logger.Errorf("Error retrieving the security group")
+		groups = append(groups, responseSecGroup)
+	}
+	if len(groups) == 0 {
+		nx.clearSecurityGroups()
 		return
 	}
 
-	if nx.securityGroup != nil && reflect.DeepEqual(responseSecGroup, *nx.securityGroup) {
-		// no changes to previously applied security group
+	// the tunnel addresses of referenced security groups change as devices join or leave the groups,
+	// so the rules are compared after resolving the references.
+	rules := nx.resolveSecurityGroupRules(groups)
+	if reflect.DeepEqual(groups, nx.securityGroups) && reflect.DeepEqual(rules, nx.securityRules) {
+		// no changes to previously applied security groups
 		return
 	}
 
-	nx.logger.Debugf("Security Group change detected: %+v", util.JsonStringer(responseSecGroup))
-	oldSecGroup := nx.securityGroup
-	nx.securityGroup = &responseSecGroup
+	nx.logger.Debugf("Security Group change detected: %+v", util.JsonStringer(groups))
+	wasEnabled := nx.securityGroups != nil
+	nx.securityGroups = groups
 
-	if oldSecGroup != nil && responseSecGroup.GetId() == oldSecGroup.GetId() &&
-		reflect.DeepEqual(responseSecGroup.InboundRules, oldSecGroup.InboundRules) &&
-		reflect.DeepEqual(responseSecGroup.OutboundRules, oldSecGroup.OutboundRules) {
-		// the group changed, but not in a way that matters for applying the rules locally
+	if wasEnabled && reflect.DeepEqual(rules, nx.securityRules) {
+		// the groups changed, but not in a way that matters for applying the rules locally
 		return
 	}
+	nx.securityRules = rules
 
 	// apply the new security group rules
-	if err := nx.processSecurityGroupRules(); err != nil {
+	if err := nx.applySecurityGroupRules(); err != nil {
+		nx.logger.Error(err)
+		return
+	}
+	nx.events.publish(api.Event{Type: api.EventSecurityGroupsApplied, SecurityGroupIds: securityGroupIdsOf(groups)})
+}
+
+// clearSecurityGroups drops the local security group configuration
+func (nx *Nexodus) clearSecurityGroups() {
+	nx.securityGroups = nil
+	nx.securityRules = securityRules{}
+	if err := nx.applySecurityGroupRules(); err != nil {
 		nx.logger.Error(err)
+		return
+	}
+	// no security group ids in the event means the rules were removed
+	nx.events.publish(api.Event{Type: api.EventSecurityGroupsApplied})
+}
+
+// applySecurityGroupRules enforces the current security group rules with the packet filter of the host,
+// or with the userspace packet filter when running in userspace mode.
+func (nx *Nexodus) applySecurityGroupRules() error {
+	if nx.userspaceMode {
+		nx.userspaceFilter.setRules(nx.securityRules, nx.securityGroups != nil)
+		return nil
 	}
+	return nx.processSecurityGroupRules()
 }
 
 func (nx *Nexodus) reconcileDevices(ctx context.Context, options []client.Option) {
 	var err error
 	if err = nx.reconcileDeviceCache(); err == nil {
+		nx.reconcileMagicDns()
 		if !nx.deviceReconciled {
 			nx.deviceReconciled = true
 			nx.logger.Info("Nexodus agent has reconciled state with API server")
@@ -1010,6 +1147,9 @@ func (nx *Nexodus) reconcileDevices(ctx context.Context, options []client.Option
 	nx.securityGroupsInformer = nx.client.VPCApi.ListSecurityGroupsInVPC(informerCtx, nx.vpc.GetId()).Informer()
 	nx.devicesInformer = nx.client.VPCApi.ListDevicesInVPC(informerCtx, nx.vpc.GetId()).Informer()
 	nx.relayMetadataInformer = nx.client.VPCApi.ListMetadataInVPC(informerCtx, nx.vpc.GetId()).Key("relay").Informer()
+	if nx.magicDnsEnabled {
+		nx.dnsMetadataInformer = nx.client.VPCApi.ListMetadataInVPC(informerCtx, nx.vpc.GetId()).Prefix([]string{magicDnsMetadataPrefix}).Informer()
+	}
 
 	nx.SetStatus(NexdStatusRunning, "")
 	nx.logger.Infoln("Nexodus agent has re-established a connection to the api-server")
@@ -1161,12 +1301,20 @@ func (nx *Nexodus) reconcileDeviceCache() error {
 	// Get our device cache up to date
 	newLocalConfig := false
 	for _, p := range peerMap {
+		if p.GetPublicKey() == nx.wireguardPubKey {
+			// the exit node policy only updates the mangle table, the peerings are left alone
+			nx.updateExitNodePolicy(p.ExitNodePolicy)
+		}
+
 		// Update the cache if the device is new or has changed
 		existing, ok := nx.deviceCache[p.GetPublicKey()]
+		if !ok && p.GetPublicKey() != nx.wireguardPubKey {
+			nx.publishPeerEvent(api.EventPeerAdded, p)
+		}
 		if !ok || deviceUpdated(existing.device, p) {
 			if p.GetPublicKey() == nx.wireguardPubKey {
 				newLocalConfig = true
-				if nx.securityGroup == nil || !reflect.DeepEqual(p.SecurityGroupId, nx.securityGroup.Id) {
+				if nx.securityGroups == nil || !reflect.DeepEqual(deviceSecurityGroupIds(p), securityGroupIdsOf(nx.securityGroups)) {
 					nx.needSecGroupReconcile = true
 				}
 			}
@@ -1209,10 +1357,18 @@ func (nx *Nexodus) reconcileDeviceCache() error {
 		existing.lastHandshake = curStats.LatestHandshake
 		existing.lastRefresh = now
 		existing.endpoint = curStats.Endpoint
+		wasHealthy := existing.peerHealthy
 		existing.peerHealthy = nx.peerIsHealthy(existing)
 		if existing.peerHealthy {
 			existing.peerHealthyTime = now
 		}
+		if existing.peerHealthy != wasHealthy && p.GetPublicKey() != nx.wireguardPubKey {
+			if existing.peerHealthy {
+				nx.publishPeerEvent(api.EventPeerHealthy, p)
+			} else {
+				nx.publishPeerEvent(api.EventPeerUnhealthy, p)
+			}
+		}
 		nx.deviceCache[p.GetPublicKey()] = existing
 	}
 
@@ -1252,6 +1408,8 @@ func (nx *Nexodus) reconcileDeviceCache() error {
 		}
 	}
 
+	nx.reconcileTrafficLimits(peerMap)
+
 	// check for any peer deletions
 	if err := nx.handlePeerDelete(peerMap); err != nil {
 		nx.logger.Error(err)
@@ -1268,7 +1426,7 @@ func deviceUpdated(d1, d2 client.ModelsDevice) bool {
 		!reflect.DeepEqual(d1.Endpoints, d2.Endpoints) ||
 		d1.GetRelay() != d2.GetRelay() ||
 		d1.GetSymmetricNat() != d2.GetSymmetricNat() ||
-		d1.GetSecurityGroupId() != d2.GetSecurityGroupId()
+		!reflect.DeepEqual(deviceSecurityGroupIds(d1), deviceSecurityGroupIds(d2))
 }
 
 // checkUnsupportedConfigs general matrix checks of required information or constraints to run the agent and join the mesh
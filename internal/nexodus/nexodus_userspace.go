@@ -36,7 +36,8 @@ func (nx *Nexodus) setupInterfaceUS() error {
 		nx.logger.Errorf("Failed to create userspace tunnel device: %w", err)
 		return err
 	}
-	nx.userspaceTun = tun
+	// security group rules and traffic limits are enforced on the packets exchanged between the netstack and wireguard-go
+	nx.userspaceTun = newPacketFilterTun(tun, nx.userspaceFilter, nx.userspaceLimiter)
 	nx.userspaceNet = tnet
 	logger := &device.Logger{
 		Verbosef: device.DiscardLogf,
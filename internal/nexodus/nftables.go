@@ -0,0 +1,320 @@
+package nexodus
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// The nftables tables managed by nexd are described with the types below and applied by nfApplyTable,
+// which talks netlink directly on linux. Every rule is identified by its nft syntax, a hash of it is
+// stored in the rule comment so that an update only adds and deletes the rules that changed in one
+// atomic batch instead of dropping and recreating the whole table.
+
+const (
+	tableFamily = "inet"
+
+	nfChainTypeFilter = "filter"
+	nfChainTypeNAT    = "nat"
+	nfChainTypeRoute  = "route"
+
+	nfHookPrerouting  = "prerouting"
+	nfHookInput       = "input"
+	nfHookForward     = "forward"
+	nfHookOutput      = "output"
+	nfHookPostrouting = "postrouting"
+
+	nfPriorityMangle = -150
+	nfPriorityDstNAT = -100
+	nfPriorityFilter = 0
+	nfPrioritySrcNAT = 100
+
+	// nfRuleCommentPrefix marks the rules that were added by nexd
+	nfRuleCommentPrefix = "nexodus "
+	// nfUdataRuleComment is the user data attribute nft uses for rule comments
+	nfUdataRuleComment = 0
+)
+
+// nfTable is the desired state of a nftables table
+type nfTable struct {
+	// family is one of inet, ip or ip6
+	family string
+	name   string
+	chains []nfChain
+	// shared tables are also used by other tools like iptables-nft or firewalld, only the rules
+	// added by nexd are updated or removed and the other rules and chains are left untouched.
+	shared bool
+}
+
+// nfChain is the desired state of a chain, a chain without a hook is a regular chain that is only
+// evaluated when a rule jumps to it. Regular chains must be listed before the chains that jump to them.
+type nfChain struct {
+	name      string
+	chainType string
+	hook      string
+	priority  int32
+	rules     []nfRule
+}
+
+// nfRule is a list of statements that are evaluated in order
+type nfRule []nfMatch
+
+// String returns the rule in nft syntax, it is used to identify the rule when diffing the chain.
+func (r nfRule) String() string {
+	statements := make([]string, len(r))
+	for i, m := range r {
+		statements[i] = m.String()
+	}
+	return strings.Join(statements, " ")
+}
+
+// nfMatch is a single match or statement of a rule
+type nfMatch interface {
+	fmt.Stringer
+}
+
+// nfIfname matches the input or output interface name
+type nfIfname struct {
+	output bool
+	name   string
+}
+
+func (m nfIfname) String() string {
+	if m.output {
+		return fmt.Sprintf("oifname %q", m.name)
+	}
+	return fmt.Sprintf("iifname %q", m.name)
+}
+
+// nfNfproto matches the address family of the packet, ipv4 or ipv6
+type nfNfproto string
+
+func (m nfNfproto) String() string {
+	return "meta nfproto " + string(m)
+}
+
+// nfL4proto matches the transport protocol of the packet
+type nfL4proto uint8
+
+func (m nfL4proto) String() string {
+	switch m {
+	case ipProtoICMP:
+		return "meta l4proto icmp"
+	case ipProtoTCP:
+		return "meta l4proto tcp"
+	case ipProtoUDP:
+		return "meta l4proto udp"
+	case ipProtoICMPv6:
+		return "meta l4proto ipv6-icmp"
+	}
+	return fmt.Sprintf("meta l4proto %d", uint8(m))
+}
+
+// nfAddr matches the source or destination address against an address, cidr or dash separated range
+type nfAddr struct {
+	// family is ip or ip6
+	family string
+	dst    bool
+	value  string
+}
+
+func (m nfAddr) String() string {
+	if m.dst {
+		return fmt.Sprintf("%s daddr %s", m.family, m.value)
+	}
+	return fmt.Sprintf("%s saddr %s", m.family, m.value)
+}
+
+// nfDport matches the transport destination port
+type nfDport struct {
+	from uint16
+	to   uint16
+}
+
+func (m nfDport) String() string {
+	if m.from == m.to {
+		return fmt.Sprintf("th dport %d", m.from)
+	}
+	return fmt.Sprintf("th dport %d-%d", m.from, m.to)
+}
+
+// nfIcmp matches the icmp type and optionally the code, the l4proto must be matched first
+type nfIcmp struct {
+	v6       bool
+	icmpType uint8
+	hasCode  bool
+	code     uint8
+}
+
+func (m nfIcmp) String() string {
+	proto := "icmp"
+	if m.v6 {
+		proto = "icmpv6"
+	}
+	s := fmt.Sprintf("%s type %d", proto, m.icmpType)
+	if m.hasCode {
+		s += fmt.Sprintf(" %s code %d", proto, m.code)
+	}
+	return s
+}
+
+// nfCtEstablished matches packets of established connections and related traffic
+type nfCtEstablished struct{}
+
+func (nfCtEstablished) String() string {
+	return "ct state established,related"
+}
+
+// nfCounter counts the packets and bytes that reach the statement
+type nfCounter struct{}
+
+func (nfCounter) String() string {
+	return "counter"
+}
+
+// nfLimit matches the packets over the rate, in bytes or packets per second. Every rule has its own token
+// bucket, which holds one second worth of traffic.
+type nfLimit struct {
+	rate  uint64
+	bytes bool
+}
+
+func (m nfLimit) String() string {
+	if m.bytes {
+		return fmt.Sprintf("limit rate over %d bytes/second", m.rate)
+	}
+	return fmt.Sprintf("limit rate over %d/second", m.rate)
+}
+
+// nfVerdict is accept, drop or return, return continues with the rule after the jump to the chain
+type nfVerdict string
+
+const (
+	nfAccept nfVerdict = "accept"
+	nfDrop   nfVerdict = "drop"
+	nfReturn nfVerdict = "return"
+)
+
+func (m nfVerdict) String() string {
+	return string(m)
+}
+
+// nfJump evaluates the regular chain, the evaluation continues with the next rule when the chain returns
+type nfJump string
+
+func (m nfJump) String() string {
+	return "jump " + string(m)
+}
+
+// nfMasquerade source nats the packet to the address of the output interface
+type nfMasquerade struct{}
+
+func (nfMasquerade) String() string {
+	return "masquerade"
+}
+
+// nfMarkSet sets the packet mark
+type nfMarkSet uint32
+
+func (m nfMarkSet) String() string {
+	return fmt.Sprintf("meta mark set 0x%x", uint32(m))
+}
+
+// nfRuleComment returns the comment that identifies a rule, the rule text is hashed since comments
+// are limited to 128 bytes.
+func nfRuleComment(rule string) string {
+	sum := sha256.Sum256([]byte(rule))
+	return fmt.Sprintf("%s%x", nfRuleCommentPrefix, sum[:8])
+}
+
+// nfRuleUserData encodes the comment in the TLV format used by nft so that it shows up in nft list ruleset
+func nfRuleUserData(comment string) []byte {
+	value := append([]byte(comment), 0)
+	return append([]byte{nfUdataRuleComment, byte(len(value))}, value...)
+}
+
+// nfRuleUserDataComment returns the comment stored in the user data of a rule
+func nfRuleUserDataComment(data []byte) (string, bool) {
+	for len(data) >= 2 {
+		attrType, attrLen := data[0], int(data[1])
+		if len(data) < 2+attrLen {
+			return "", false
+		}
+		if attrType == nfUdataRuleComment {
+			return strings.TrimRight(string(data[2:2+attrLen]), "\x00"), true
+		}
+		data = data[2+attrLen:]
+	}
+	return "", false
+}
+
+// nfRuleDiff is the list of changes that turns the existing rules of a chain into the desired ones
+type nfRuleDiff struct {
+	// deletes are the indexes of the existing rules to delete
+	deletes []int
+	adds    []nfRuleAdd
+}
+
+// nfRuleAdd adds the desired rule before the existing rule, a negative before appends the rule to the chain
+type nfRuleAdd struct {
+	rule   int
+	before int
+}
+
+// nfDiffRules compares the comments of the existing and desired rules of a chain. The longest common
+// subsequence of both is kept so that unchanged rules keep their counters and the chain never
+// passes through a state where rules are missing.
+func nfDiffRules(existing, desired []string) nfRuleDiff {
+	// lcs[i][j] is the length of the longest common subsequence of existing[i:] and desired[j:]
+	lcs := make([][]int, len(existing)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(desired)+1)
+	}
+	for i := len(existing) - 1; i >= 0; i-- {
+		for j := len(desired) - 1; j >= 0; j-- {
+			if existing[i] != "" && existing[i] == desired[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := nfRuleDiff{}
+	// kept maps the desired rules that are already in place to the index of the existing rule
+	kept := make([]int, len(desired))
+	for j := range kept {
+		kept[j] = -1
+	}
+	i, j := 0, 0
+	for i < len(existing) && j < len(desired) {
+		switch {
+		case existing[i] != "" && existing[i] == desired[j]:
+			kept[j] = i
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff.deletes = append(diff.deletes, i)
+			i++
+		default:
+			j++
+		}
+	}
+	for ; i < len(existing); i++ {
+		diff.deletes = append(diff.deletes, i)
+	}
+
+	next := -1
+	for j := len(desired) - 1; j >= 0; j-- {
+		if kept[j] >= 0 {
+			next = kept[j]
+			continue
+		}
+		diff.adds = append(diff.adds, nfRuleAdd{rule: j, before: next})
+	}
+	// the adds were collected back to front, rules inserted before the same rule must be added in order
+	for l, r := 0, len(diff.adds)-1; l < r; l, r = l+1, r-1 {
+		diff.adds[l], diff.adds[r] = diff.adds[r], diff.adds[l]
+	}
+	return diff
+}
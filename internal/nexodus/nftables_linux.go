@@ -0,0 +1,445 @@
+//go:build linux
+
+package nexodus
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+)
+
+// nfApplyTable creates or updates the table so that its chains contain exactly the desired rules. All
+// changes are sent to the kernel in a single batch which is applied atomically.
+func nfApplyTable(logger *zap.SugaredLogger, table nfTable) error {
+	family, err := nfTableFamily(table.family)
+	if err != nil {
+		return err
+	}
+
+	conn, err := nftables.New()
+	if err != nil {
+		return fmt.Errorf("failed to open nftables netlink connection: %w", err)
+	}
+
+	exists, err := nfTableExists(conn, family, table.name)
+	if err != nil {
+		return err
+	}
+
+	t := conn.AddTable(&nftables.Table{Name: table.name, Family: family})
+
+	existingChains := map[string]*nftables.Chain{}
+	if exists {
+		chains, err := conn.ListChainsOfTableFamily(family)
+		if err != nil {
+			return fmt.Errorf("failed to list nftables chains of table %s %s: %w", table.family, table.name, err)
+		}
+		for _, c := range chains {
+			if c.Table.Name == table.name {
+				existingChains[c.Name] = c
+			}
+		}
+	}
+
+	for _, chain := range table.chains {
+		c := &nftables.Chain{Name: chain.name, Table: t}
+		if chain.hook != "" {
+			hook, err := nfChainHook(chain.hook)
+			if err != nil {
+				return err
+			}
+			priority := nftables.ChainPriority(chain.priority)
+			c.Type = nftables.ChainType(chain.chainType)
+			c.Hooknum = hook
+			c.Priority = &priority
+		}
+		c = conn.AddChain(c)
+
+		var existing []*nftables.Rule
+		if _, ok := existingChains[chain.name]; ok {
+			existing, err = conn.GetRules(t, c)
+			if err != nil {
+				return fmt.Errorf("failed to list nftables rules of chain %s: %w", chain.name, err)
+			}
+			delete(existingChains, chain.name)
+		}
+
+		if err := nfApplyChainRules(logger, conn, table, t, c, existing, chain.rules); err != nil {
+			return err
+		}
+	}
+
+	// chains that are no longer needed are removed from the tables owned by nexd, the rules that jumped
+	// to them were already deleted above.
+	if !table.shared {
+		for _, c := range existingChains {
+			logger.Debugf("nftables: delete chain %s %s %s", table.family, table.name, c.Name)
+			conn.FlushChain(c)
+			conn.DelChain(c)
+		}
+	}
+
+	if err := conn.Flush(); err != nil {
+		return fmt.Errorf("failed to apply nftables table %s %s: %w", table.family, table.name, err)
+	}
+
+	return nil
+}
+
+// nfApplyChainRules queues the rule deletions and additions that turn the existing rules into the desired rules
+func nfApplyChainRules(logger *zap.SugaredLogger, conn *nftables.Conn, table nfTable, t *nftables.Table, c *nftables.Chain, existing []*nftables.Rule, rules []nfRule) error {
+	var current []*nftables.Rule
+	var currentComments []string
+	for _, r := range existing {
+		comment, ok := nfRuleUserDataComment(r.UserData)
+		if !ok || !strings.HasPrefix(comment, nfRuleCommentPrefix) {
+			if table.shared {
+				// not one of ours
+				continue
+			}
+			comment = ""
+		}
+		current = append(current, r)
+		currentComments = append(currentComments, comment)
+	}
+
+	desiredComments := make([]string, len(rules))
+	for i, rule := range rules {
+		desiredComments[i] = nfRuleComment(rule.String())
+	}
+
+	diff := nfDiffRules(currentComments, desiredComments)
+	for _, i := range diff.deletes {
+		logger.Debugf("nftables: delete rule %s %s %s handle %d", table.family, table.name, c.Name, current[i].Handle)
+		if err := conn.DelRule(current[i]); err != nil {
+			return fmt.Errorf("failed to delete nftables rule in chain %s: %w", c.Name, err)
+		}
+	}
+
+	for _, add := range diff.adds {
+		rule := rules[add.rule]
+		exprs, err := nfRuleExprs(rule)
+		if err != nil {
+			return fmt.Errorf("invalid nftables rule %q in chain %s: %w", rule.String(), c.Name, err)
+		}
+		r := &nftables.Rule{
+			Table:    t,
+			Chain:    c,
+			Exprs:    exprs,
+			UserData: nfRuleUserData(desiredComments[add.rule]),
+		}
+		logger.Debugf("nftables: add rule %s %s %s %s", table.family, table.name, c.Name, rule.String())
+		if add.before < 0 {
+			conn.AddRule(r)
+		} else {
+			// inserting at the position of a rule places the new rule before it
+			r.Position = current[add.before].Handle
+			conn.InsertRule(r)
+		}
+	}
+
+	return nil
+}
+
+// nfDeleteTable deletes the table if it exists
+func nfDeleteTable(family string, name string) error {
+	tableFamily, err := nfTableFamily(family)
+	if err != nil {
+		return err
+	}
+
+	conn, err := nftables.New()
+	if err != nil {
+		return fmt.Errorf("failed to open nftables netlink connection: %w", err)
+	}
+
+	exists, err := nfTableExists(conn, tableFamily, name)
+	if err != nil || !exists {
+		return err
+	}
+
+	conn.DelTable(&nftables.Table{Name: name, Family: tableFamily})
+	if err := conn.Flush(); err != nil {
+		return fmt.Errorf("failed to delete nftables table %s %s: %w", family, name, err)
+	}
+
+	return nil
+}
+
+// nfChainCounters returns the counters of the rules nexd added to the chain by rule comment, rules with
+// the same comment are listed in the order of the chain. An empty map is returned if the chain does not exist.
+func nfChainCounters(family string, tableName string, chainName string) (map[string][]*expr.Counter, error) {
+	tableFamily, err := nfTableFamily(family)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := nftables.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open nftables netlink connection: %w", err)
+	}
+
+	counters := map[string][]*expr.Counter{}
+	chains, err := conn.ListChainsOfTableFamily(tableFamily)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nftables chains of table %s %s: %w", family, tableName, err)
+	}
+	for _, c := range chains {
+		if c.Table.Name != tableName || c.Name != chainName {
+			continue
+		}
+		rules, err := conn.GetRules(c.Table, c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nftables rules of chain %s: %w", chainName, err)
+		}
+		for _, r := range rules {
+			comment, ok := nfRuleUserDataComment(r.UserData)
+			if !ok || !strings.HasPrefix(comment, nfRuleCommentPrefix) {
+				continue
+			}
+			for _, e := range r.Exprs {
+				if counter, ok := e.(*expr.Counter); ok {
+					counters[comment] = append(counters[comment], counter)
+					break
+				}
+			}
+		}
+	}
+	return counters, nil
+}
+
+// nfTableExists returns true if the table exists in the family
+func nfTableExists(conn *nftables.Conn, family nftables.TableFamily, name string) (bool, error) {
+	tables, err := conn.ListTablesOfFamily(family)
+	if err != nil {
+		return false, fmt.Errorf("failed to list nftables tables: %w", err)
+	}
+	for _, t := range tables {
+		if t.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func nfTableFamily(family string) (nftables.TableFamily, error) {
+	switch family {
+	case "inet":
+		return nftables.TableFamilyINet, nil
+	case "ip":
+		return nftables.TableFamilyIPv4, nil
+	case "ip6":
+		return nftables.TableFamilyIPv6, nil
+	}
+	return 0, fmt.Errorf("unsupported nftables family %s", family)
+}
+
+func nfChainHook(hook string) (*nftables.ChainHook, error) {
+	switch hook {
+	case nfHookPrerouting:
+		return nftables.ChainHookPrerouting, nil
+	case nfHookInput:
+		return nftables.ChainHookInput, nil
+	case nfHookForward:
+		return nftables.ChainHookForward, nil
+	case nfHookOutput:
+		return nftables.ChainHookOutput, nil
+	case nfHookPostrouting:
+		return nftables.ChainHookPostrouting, nil
+	}
+	return nil, fmt.Errorf("unsupported nftables hook %s", hook)
+}
+
+// nfRuleExprs compiles the rule into the netlink expressions evaluated by the kernel
+func nfRuleExprs(rule nfRule) ([]expr.Any, error) {
+	var exprs []expr.Any
+	for _, m := range rule {
+		matchExprs, err := nfMatchExprs(m)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, matchExprs...)
+	}
+	return exprs, nil
+}
+
+func nfMatchExprs(m nfMatch) ([]expr.Any, error) {
+	switch m := m.(type) {
+	case nfIfname:
+		key := expr.MetaKeyIIFNAME
+		if m.output {
+			key = expr.MetaKeyOIFNAME
+		}
+		if len(m.name) >= unix.IFNAMSIZ {
+			return nil, fmt.Errorf("invalid interface name %q", m.name)
+		}
+		name := make([]byte, unix.IFNAMSIZ)
+		copy(name, m.name)
+		return []expr.Any{
+			&expr.Meta{Key: key, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: name},
+		}, nil
+	case nfNfproto:
+		return nfNfprotoExprs(string(m))
+	case nfL4proto:
+		return []expr.Any{
+			&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{uint8(m)}},
+		}, nil
+	case nfAddr:
+		return nfAddrExprs(m)
+	case nfDport:
+		exprs := []expr.Any{
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 2, Len: 2},
+		}
+		if m.from == m.to {
+			return append(exprs, &expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: binaryutil.BigEndian.PutUint16(m.from)}), nil
+		}
+		return append(exprs, &expr.Range{Op: expr.CmpOpEq, Register: 1, FromData: binaryutil.BigEndian.PutUint16(m.from), ToData: binaryutil.BigEndian.PutUint16(m.to)}), nil
+	case nfIcmp:
+		exprs := []expr.Any{
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 0, Len: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{m.icmpType}},
+		}
+		if m.hasCode {
+			exprs = append(exprs,
+				&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 1, Len: 1},
+				&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{m.code}},
+			)
+		}
+		return exprs, nil
+	case nfCtEstablished:
+		return []expr.Any{
+			&expr.Ct{Register: 1, Key: expr.CtKeySTATE},
+			&expr.Bitwise{
+				SourceRegister: 1,
+				DestRegister:   1,
+				Len:            4,
+				Mask:           binaryutil.NativeEndian.PutUint32(expr.CtStateBitESTABLISHED | expr.CtStateBitRELATED),
+				Xor:            binaryutil.NativeEndian.PutUint32(0),
+			},
+			&expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: binaryutil.NativeEndian.PutUint32(0)},
+		}, nil
+	case nfCounter:
+		return []expr.Any{&expr.Counter{}}, nil
+	case nfLimit:
+		limitType := expr.LimitTypePkts
+		if m.bytes {
+			limitType = expr.LimitTypePktBytes
+		}
+		return []expr.Any{&expr.Limit{Type: limitType, Rate: m.rate, Over: true, Unit: expr.LimitTimeSecond}}, nil
+	case nfVerdict:
+		switch m {
+		case nfAccept:
+			return []expr.Any{&expr.Verdict{Kind: expr.VerdictAccept}}, nil
+		case nfDrop:
+			return []expr.Any{&expr.Verdict{Kind: expr.VerdictDrop}}, nil
+		case nfReturn:
+			return []expr.Any{&expr.Verdict{Kind: expr.VerdictReturn}}, nil
+		}
+	case nfJump:
+		return []expr.Any{&expr.Verdict{Kind: expr.VerdictJump, Chain: string(m)}}, nil
+	case nfMasquerade:
+		return []expr.Any{&expr.Masq{}}, nil
+	case nfMarkSet:
+		return []expr.Any{
+			&expr.Immediate{Register: 1, Data: binaryutil.NativeEndian.PutUint32(uint32(m))},
+			&expr.Meta{Key: expr.MetaKeyMARK, SourceRegister: true, Register: 1},
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported nftables statement %q", m.String())
+}
+
+func nfNfprotoExprs(family string) ([]expr.Any, error) {
+	var proto byte
+	switch family {
+	case protoIPv4:
+		proto = unix.NFPROTO_IPV4
+	case protoIPv6:
+		proto = unix.NFPROTO_IPV6
+	default:
+		return nil, fmt.Errorf("unsupported nfproto %s", family)
+	}
+	return []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{proto}},
+	}, nil
+}
+
+// nfAddrExprs matches an address, cidr or dash separated range. The address family is matched first,
+// nft adds the same dependency when an ip or ip6 address is matched in an inet table.
+func nfAddrExprs(m nfAddr) ([]expr.Any, error) {
+	var exprs []expr.Any
+	var offset, length uint32
+	var err error
+	switch m.family {
+	case "ip":
+		exprs, err = nfNfprotoExprs(protoIPv4)
+		offset, length = 12, 4
+	case "ip6":
+		exprs, err = nfNfprotoExprs(protoIPv6)
+		offset, length = 8, 16
+	default:
+		return nil, fmt.Errorf("unsupported address family %s", m.family)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m.dst {
+		offset += length
+	}
+	exprs = append(exprs, &expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: offset, Len: length})
+
+	validFamily := func(addr netip.Addr) bool {
+		return (length == 4) == addr.Is4()
+	}
+
+	if from, to, ok := strings.Cut(m.value, "-"); ok {
+		fromAddr, err := netip.ParseAddr(strings.TrimSpace(from))
+		if err != nil {
+			return nil, err
+		}
+		toAddr, err := netip.ParseAddr(strings.TrimSpace(to))
+		if err != nil {
+			return nil, err
+		}
+		if !validFamily(fromAddr) || !validFamily(toAddr) {
+			return nil, fmt.Errorf("address range %s is not in the %s family", m.value, m.family)
+		}
+		return append(exprs, &expr.Range{Op: expr.CmpOpEq, Register: 1, FromData: fromAddr.AsSlice(), ToData: toAddr.AsSlice()}), nil
+	}
+
+	if strings.Contains(m.value, "/") {
+		prefix, err := netip.ParsePrefix(m.value)
+		if err != nil {
+			return nil, err
+		}
+		if !validFamily(prefix.Addr()) {
+			return nil, fmt.Errorf("prefix %s is not in the %s family", m.value, m.family)
+		}
+		prefix = prefix.Masked()
+		if prefix.Bits() < prefix.Addr().BitLen() {
+			mask := make([]byte, length)
+			for i := 0; i < prefix.Bits(); i++ {
+				mask[i/8] |= 0x80 >> (i % 8)
+			}
+			exprs = append(exprs, &expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: length, Mask: mask, Xor: make([]byte, length)})
+		}
+		return append(exprs, &expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: prefix.Addr().AsSlice()}), nil
+	}
+
+	addr, err := netip.ParseAddr(m.value)
+	if err != nil {
+		return nil, err
+	}
+	if !validFamily(addr) {
+		return nil, fmt.Errorf("address %s is not in the %s family", m.value, m.family)
+	}
+	return append(exprs, &expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: addr.AsSlice()}), nil
+}
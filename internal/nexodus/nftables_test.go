@@ -0,0 +1,96 @@
+package nexodus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// applyRuleDiff replays the diff the way the kernel applies the batch
+func applyRuleDiff(existing, desired []string, diff nfRuleDiff) []string {
+	deleted := map[int]bool{}
+	for _, i := range diff.deletes {
+		deleted[i] = true
+	}
+	type entry struct {
+		key    string
+		handle int
+	}
+	var chain []entry
+	for i, key := range existing {
+		if !deleted[i] {
+			chain = append(chain, entry{key: key, handle: i})
+		}
+	}
+	for _, add := range diff.adds {
+		e := entry{key: desired[add.rule], handle: -1}
+		if add.before < 0 {
+			chain = append(chain, e)
+			continue
+		}
+		for i := range chain {
+			if chain[i].handle == add.before {
+				chain = append(chain[:i], append([]entry{e}, chain[i:]...)...)
+				break
+			}
+		}
+	}
+	result := make([]string, len(chain))
+	for i, e := range chain {
+		result[i] = e.key
+	}
+	return result
+}
+
+func TestDiffRules(t *testing.T) {
+	require := require.New(t)
+
+	tests := []struct {
+		existing []string
+		desired  []string
+		deletes  int
+		adds     int
+	}{
+		{existing: nil, desired: []string{"a", "b"}, adds: 2},
+		{existing: []string{"a", "b"}, desired: nil, deletes: 2},
+		{existing: []string{"a", "b", "c"}, desired: []string{"a", "b", "c"}},
+		{existing: []string{"a", "b", "c"}, desired: []string{"a", "x", "c"}, deletes: 1, adds: 1},
+		{existing: []string{"a", "b", "c"}, desired: []string{"x", "y", "a", "c", "z"}, deletes: 1, adds: 3},
+		{existing: []string{"a", "b", "c"}, desired: []string{"c", "b", "a"}, deletes: 2, adds: 2},
+		{existing: []string{"a", "a", "b"}, desired: []string{"a", "b", "b"}, deletes: 1, adds: 1},
+		// rules without a nexodus comment are always replaced
+		{existing: []string{"", "a"}, desired: []string{"a"}, deletes: 1},
+	}
+	for _, test := range tests {
+		diff := nfDiffRules(test.existing, test.desired)
+		require.Len(diff.deletes, test.deletes, "existing %v desired %v", test.existing, test.desired)
+		require.Len(diff.adds, test.adds, "existing %v desired %v", test.existing, test.desired)
+		if len(test.desired) == 0 {
+			require.Empty(applyRuleDiff(test.existing, test.desired, diff))
+		} else {
+			require.Equal(test.desired, applyRuleDiff(test.existing, test.desired, diff))
+		}
+	}
+}
+
+func TestRuleUserData(t *testing.T) {
+	require := require.New(t)
+
+	rule := nfRule{nfIfname{name: "wg0"}, nfCounter{}, nfAccept}
+	require.Equal(`iifname "wg0" counter accept`, rule.String())
+
+	comment := nfRuleComment(rule.String())
+	require.True(strings.HasPrefix(comment, nfRuleCommentPrefix))
+	require.Len(comment, len(nfRuleCommentPrefix)+16)
+	require.NotEqual(comment, nfRuleComment(nfRule{nfIfname{name: "wg0"}, nfCounter{}, nfDrop}.String()))
+
+	decoded, ok := nfRuleUserDataComment(nfRuleUserData(comment))
+	require.True(ok)
+	require.Equal(comment, decoded)
+
+	_, ok = nfRuleUserDataComment(nil)
+	require.False(ok)
+	_, ok = nfRuleUserDataComment([]byte{nfUdataRuleComment, 10, 'a'})
+	require.False(ok)
+}
@@ -0,0 +1,582 @@
+package nexodus
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"go.uber.org/zap"
+	"golang.zx2c4.com/wireguard/tun"
+)
+
+const (
+	ipProtoICMP   = 1
+	ipProtoTCP    = 6
+	ipProtoUDP    = 17
+	ipProtoICMPv6 = 58
+
+	// flows that have not seen a packet within the timeout are forgotten
+	packetFilterTCPFlowTimeout   = 30 * time.Minute
+	packetFilterOtherFlowTimeout = 2 * time.Minute
+	packetFilterSweepInterval    = time.Minute
+)
+
+// packetFilter enforces the security group rules in userspace mode, where there is no kernel
+// packet filter between the wireguard device and the netstack. It is stateful: once a packet
+// of a flow is allowed in either direction, the following packets of the flow are allowed in
+// both directions, like the ct state established,related rules of the nftables chains.
+type packetFilter struct {
+	logger *zap.SugaredLogger
+	rules  atomic.Pointer[packetFilterRules]
+
+	flowsLock sync.Mutex
+	flows     map[packetFlow]time.Time
+	lastSweep time.Time
+}
+
+// packetFilterRules are the compiled rules of the inbound and outbound direction with one chain per
+// security group, a packet is allowed when any of the chains allows it. A nil packetFilterRules allows
+// all traffic, so does a direction without chains.
+type packetFilterRules struct {
+	inbound  []packetFilterChain
+	outbound []packetFilterChain
+}
+
+type packetFilterChain struct {
+	rules []packetFilterRule
+	// allow the traffic that does not match any rule
+	defaultAllow bool
+}
+
+type packetFilterRule struct {
+	// index of the security group rule the rule was created from
+	index   int
+	counter *packetFilterCounter
+	deny    bool
+	// 0 matches both address families
+	family int
+	// nil matches all protocols
+	protocols []uint8
+	fromPort  uint16
+	toPort    uint16
+	icmpType  int
+	icmpCode  int
+	// nil matches all addresses
+	ranges []packetFilterRange
+}
+
+// packetFilterCounter counts the packets that matched a rule, the packets of flows that were already
+// allowed do not reach the rules and are not counted.
+type packetFilterCounter struct {
+	packets atomic.Uint64
+	bytes   atomic.Uint64
+}
+
+// packetFilterRange is an inclusive range of addresses of the same family
+type packetFilterRange struct {
+	from netip.Addr
+	to   netip.Addr
+}
+
+// packetFlow identifies a flow from the point of view of the local device
+type packetFlow struct {
+	protocol uint8
+	local    netip.AddrPort
+	remote   netip.AddrPort
+}
+
+// packetInfo holds the fields of an IP packet the rules match on
+type packetInfo struct {
+	family   int
+	protocol uint8
+	src      netip.Addr
+	dst      netip.Addr
+	srcPort  uint16
+	dstPort  uint16
+	icmpType int
+	icmpCode int
+	// a non-first fragment does not carry the transport header
+	fragment bool
+	// tcp, udp and icmp echo packets belong to a flow
+	tracked bool
+	// inner is the tracked packet an icmp error was sent in response to, parsed from the header the error carries
+	inner *packetInfo
+}
+
+func newPacketFilter(logger *zap.SugaredLogger) *packetFilter {
+	return &packetFilter{
+		logger: logger,
+		flows:  map[packetFlow]time.Time{},
+	}
+}
+
+// setRules replaces the rules enforced by the filter, flows that were already allowed are kept.
+func (f *packetFilter) setRules(rules securityRules, enabled bool) {
+	if !enabled {
+		f.rules.Store(nil)
+		return
+	}
+	compiled := &packetFilterRules{}
+	for _, group := range rules.inbound {
+		compiled.inbound = append(compiled.inbound, compilePacketFilterChain(f.logger, group))
+	}
+	for _, group := range rules.outbound {
+		compiled.outbound = append(compiled.outbound, compilePacketFilterChain(f.logger, group))
+	}
+	f.rules.Store(compiled)
+}
+
+// counters returns the counters of the rules by security group rule index, nil is returned if the filter
+// is disabled. The counters start over when the rules are replaced.
+func (f *packetFilter) counters() *securityGroupCounters {
+	rules := f.rules.Load()
+	if rules == nil {
+		return nil
+	}
+	counters := newSecurityGroupCounters()
+	for i := range rules.inbound {
+		rules.inbound[i].addCounters(counters.inbound)
+	}
+	for i := range rules.outbound {
+		rules.outbound[i].addCounters(counters.outbound)
+	}
+	return &counters
+}
+
+func (c *packetFilterChain) addCounters(counters map[int]securityRuleCounter) {
+	for i := range c.rules {
+		rule := &c.rules[i]
+		if rule.index < 0 {
+			continue
+		}
+		counter := counters[rule.index]
+		counter.packets += rule.counter.packets.Load()
+		counter.bytes += rule.counter.bytes.Load()
+		counters[rule.index] = counter
+	}
+}
+
+func compilePacketFilterChain(logger *zap.SugaredLogger, group securityGroupRules) packetFilterChain {
+	chain := packetFilterChain{
+		defaultAllow: group.defaultAllow,
+	}
+	for i, rule := range group.rules {
+		compiled, ok := compilePacketFilterRule(rule)
+		if !ok {
+			logger.Debugf("no match for userspace packet filter rule: %v", rule)
+			continue
+		}
+		compiled.index = ruleIndex(group.index, i)
+		compiled.counter = &packetFilterCounter{}
+		chain.rules = append(chain.rules, compiled)
+	}
+	return chain
+}
+
+func compilePacketFilterRule(rule client.ModelsSecurityRule) (packetFilterRule, bool) {
+	compiled := packetFilterRule{
+		deny:     securityRuleDenies(rule),
+		fromPort: uint16(rule.GetFromPort()),
+		toPort:   uint16(rule.GetToPort()),
+		icmpType: -1,
+		icmpCode: -1,
+	}
+	if rule.HasIcmpType() {
+		compiled.icmpType = int(rule.GetIcmpType())
+		if rule.HasIcmpCode() {
+			compiled.icmpCode = int(rule.GetIcmpCode())
+		}
+	}
+	hasPorts := compiled.fromPort != 0 && compiled.toPort != 0
+
+	switch rule.GetIpProtocol() {
+	case "ipv4", "ipv6", "":
+		if rule.GetIpProtocol() == "ipv4" {
+			compiled.family = 4
+		} else if rule.GetIpProtocol() == "ipv6" {
+			compiled.family = 6
+		}
+		if hasPorts {
+			compiled.protocols = []uint8{ipProtoTCP, ipProtoUDP}
+		}
+	case "tcp":
+		compiled.protocols = []uint8{ipProtoTCP}
+	case "udp":
+		compiled.protocols = []uint8{ipProtoUDP}
+	case "icmp":
+		compiled.protocols = []uint8{ipProtoICMP, ipProtoICMPv6}
+		if rule.HasIcmpType() {
+			// icmp types are ICMPv4 types, an icmp rule with a type does not match ICMPv6
+			compiled.family = 4
+			compiled.protocols = []uint8{ipProtoICMP}
+		}
+	case "icmpv4", "icmp4":
+		compiled.family = 4
+		compiled.protocols = []uint8{ipProtoICMP}
+	case "icmpv6", "icmp6":
+		compiled.family = 6
+		compiled.protocols = []uint8{ipProtoICMPv6}
+	default:
+		return compiled, false
+	}
+	if !hasPorts {
+		compiled.fromPort, compiled.toPort = 0, 0
+	}
+
+	for _, ipRange := range rule.IpRanges {
+		if ipRange == "" {
+			// the wildcard range matches any address
+			compiled.ranges = nil
+			break
+		}
+		r, ok := parsePacketFilterRange(ipRange)
+		if !ok {
+			continue
+		}
+		compiled.ranges = append(compiled.ranges, r)
+	}
+	if len(rule.IpRanges) > 0 && compiled.ranges == nil && !containsEmptyIpRange(rule.IpRanges) {
+		// none of the ranges could be parsed, the rule can't match anything
+		return compiled, false
+	}
+	return compiled, true
+}
+
+func containsEmptyIpRange(ipRanges []string) bool {
+	for _, ipRange := range ipRanges {
+		if ipRange == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePacketFilterRange parses an address, a cidr or a dash separated range of addresses
+func parsePacketFilterRange(ipRange string) (packetFilterRange, bool) {
+	if from, to, found := strings.Cut(ipRange, "-"); found {
+		fromAddr, err := netip.ParseAddr(strings.TrimSpace(from))
+		if err != nil {
+			return packetFilterRange{}, false
+		}
+		toAddr, err := netip.ParseAddr(strings.TrimSpace(to))
+		if err != nil || fromAddr.Is4() != toAddr.Is4() {
+			return packetFilterRange{}, false
+		}
+		return packetFilterRange{from: fromAddr.Unmap(), to: toAddr.Unmap()}, true
+	}
+	if strings.Contains(ipRange, "/") {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(ipRange))
+		if err != nil {
+			return packetFilterRange{}, false
+		}
+		prefix = prefix.Masked()
+		from := prefix.Addr()
+		to := from.AsSlice()
+		for bit := prefix.Bits(); bit < len(to)*8; bit++ {
+			to[bit/8] |= 0x80 >> (bit % 8)
+		}
+		toAddr, _ := netip.AddrFromSlice(to)
+		return packetFilterRange{from: from, to: toAddr}, true
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(ipRange))
+	if err != nil {
+		return packetFilterRange{}, false
+	}
+	return packetFilterRange{from: addr.Unmap(), to: addr.Unmap()}, true
+}
+
+func (r packetFilterRange) contains(addr netip.Addr) bool {
+	return r.from.Is4() == addr.Is4() && r.from.Compare(addr) <= 0 && addr.Compare(r.to) <= 0
+}
+
+// matches returns true if the rule matches the packet, remote is the address of the peer device
+func (r *packetFilterRule) matches(p *packetInfo, remote netip.Addr) bool {
+	if r.family != 0 && r.family != p.family {
+		return false
+	}
+	if r.protocols != nil {
+		found := false
+		for _, protocol := range r.protocols {
+			if protocol == p.protocol {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.fromPort != 0 && (p.dstPort < r.fromPort || p.dstPort > r.toPort) {
+		return false
+	}
+	if r.icmpType >= 0 && p.icmpType != r.icmpType {
+		return false
+	}
+	if r.icmpCode >= 0 && p.icmpCode != r.icmpCode {
+		return false
+	}
+	if r.ranges != nil {
+		found := false
+		for _, ipRange := range r.ranges {
+			if ipRange.contains(remote) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// allow returns true if the packet should be delivered. Inbound packets were received from a
+// peer and are matched on their source address, outbound packets are sent to a peer and are
+// matched on their destination address.
+func (f *packetFilter) allow(packet []byte, inbound bool) bool {
+	rules := f.rules.Load()
+	if rules == nil {
+		return true
+	}
+	p, ok := parsePacket(packet)
+	if !ok {
+		return false
+	}
+	if p.fragment {
+		// the first fragment carries the ports and decides whether the packet is reassembled
+		return true
+	}
+
+	flow := packetFlow{protocol: p.protocol}
+	chains := rules.outbound
+	if inbound {
+		chains = rules.inbound
+		flow.local = netip.AddrPortFrom(p.dst, p.dstPort)
+		flow.remote = netip.AddrPortFrom(p.src, p.srcPort)
+	} else {
+		flow.local = netip.AddrPortFrom(p.src, p.srcPort)
+		flow.remote = netip.AddrPortFrom(p.dst, p.dstPort)
+	}
+
+	now := time.Now()
+	f.flowsLock.Lock()
+	defer f.flowsLock.Unlock()
+	if now.Sub(f.lastSweep) > packetFilterSweepInterval {
+		f.sweepFlows(now)
+	}
+	if expires, found := f.flows[flow]; p.tracked && found && now.Before(expires) {
+		f.flows[flow] = now.Add(flowTimeout(p.protocol))
+		return true
+	}
+	if p.inner != nil {
+		// icmp errors are related to the flow of the packet they were sent in response to, which went the other way
+		related := packetFlow{protocol: p.inner.protocol}
+		if inbound {
+			related.local = netip.AddrPortFrom(p.inner.src, p.inner.srcPort)
+			related.remote = netip.AddrPortFrom(p.inner.dst, p.inner.dstPort)
+		} else {
+			related.local = netip.AddrPortFrom(p.inner.dst, p.inner.dstPort)
+			related.remote = netip.AddrPortFrom(p.inner.src, p.inner.srcPort)
+		}
+		if expires, found := f.flows[related]; found && now.Before(expires) {
+			return true
+		}
+	}
+
+	allowed := len(chains) == 0
+	for i := range chains {
+		if chains[i].allows(&p, flow.remote.Addr(), len(packet)) {
+			allowed = true
+			break
+		}
+	}
+	if allowed && p.tracked {
+		f.flows[flow] = now.Add(flowTimeout(p.protocol))
+	}
+	return allowed
+}
+
+// allows returns true if the security group of the chain allows the packet, the first matching rule decides
+// and counts the packet.
+func (c *packetFilterChain) allows(p *packetInfo, remote netip.Addr, size int) bool {
+	for i := range c.rules {
+		if c.rules[i].matches(p, remote) {
+			c.rules[i].counter.packets.Add(1)
+			c.rules[i].counter.bytes.Add(uint64(size))
+			return !c.rules[i].deny
+		}
+	}
+	return c.defaultAllow
+}
+
+func (f *packetFilter) sweepFlows(now time.Time) {
+	for flow, expires := range f.flows {
+		if now.After(expires) {
+			delete(f.flows, flow)
+		}
+	}
+	f.lastSweep = now
+}
+
+func flowTimeout(protocol uint8) time.Duration {
+	if protocol == ipProtoTCP {
+		return packetFilterTCPFlowTimeout
+	}
+	return packetFilterOtherFlowTimeout
+}
+
+// parsePacket extracts the addresses, the protocol and the ports or icmp type and code of an IP packet.
+func parsePacket(packet []byte) (packetInfo, bool) {
+	p := packetInfo{icmpType: -1, icmpCode: -1}
+	if len(packet) < 1 {
+		return p, false
+	}
+	var payload []byte
+	switch packet[0] >> 4 {
+	case 4:
+		if len(packet) < 20 {
+			return p, false
+		}
+		headerLen := int(packet[0]&0x0f) * 4
+		if headerLen < 20 || len(packet) < headerLen {
+			return p, false
+		}
+		p.family = 4
+		p.protocol = packet[9]
+		p.src, _ = netip.AddrFromSlice(packet[12:16])
+		p.dst, _ = netip.AddrFromSlice(packet[16:20])
+		p.fragment = binary.BigEndian.Uint16(packet[6:8])&0x1fff != 0
+		payload = packet[headerLen:]
+	case 6:
+		if len(packet) < 40 {
+			return p, false
+		}
+		p.family = 6
+		p.src, _ = netip.AddrFromSlice(packet[8:24])
+		p.dst, _ = netip.AddrFromSlice(packet[24:40])
+		nextHeader := packet[6]
+		payload = packet[40:]
+		// skip the extension headers to find the transport protocol
+		for done := false; !done; {
+			switch nextHeader {
+			case 0, 43, 60: // hop-by-hop, routing and destination options
+				if len(payload) < 8 {
+					return p, false
+				}
+				length := (int(payload[1]) + 1) * 8
+				if len(payload) < length {
+					return p, false
+				}
+				nextHeader = payload[0]
+				payload = payload[length:]
+			case 44: // fragment
+				if len(payload) < 8 {
+					return p, false
+				}
+				p.fragment = binary.BigEndian.Uint16(payload[2:4])&0xfff8 != 0
+				nextHeader = payload[0]
+				payload = payload[8:]
+			default:
+				done = true
+			}
+		}
+		p.protocol = nextHeader
+	default:
+		return p, false
+	}
+	if p.fragment {
+		return p, true
+	}
+
+	switch p.protocol {
+	case ipProtoTCP, ipProtoUDP:
+		if len(payload) < 4 {
+			return p, false
+		}
+		p.srcPort = binary.BigEndian.Uint16(payload[0:2])
+		p.dstPort = binary.BigEndian.Uint16(payload[2:4])
+		p.tracked = true
+	case ipProtoICMP, ipProtoICMPv6:
+		if len(payload) < 4 {
+			return p, false
+		}
+		p.icmpType = int(payload[0])
+		p.icmpCode = int(payload[1])
+		if isIcmpEcho(p.protocol, p.icmpType) && len(payload) >= 6 {
+			// echo requests and replies are tracked by their identifier
+			id := binary.BigEndian.Uint16(payload[4:6])
+			p.srcPort, p.dstPort = id, id
+			p.tracked = true
+		}
+		if isIcmpError(p.protocol, p.icmpType) && len(payload) > 8 {
+			// the error carries the ip header and at least the first 8 bytes of the payload of the packet
+			if inner, ok := parsePacket(payload[8:]); ok && inner.tracked {
+				p.inner = &inner
+			}
+		}
+	}
+	return p, true
+}
+
+// isIcmpError returns true for the icmp destination unreachable and time exceeded messages, and for the ICMPv6
+// destination unreachable, packet too big, time exceeded and parameter problem messages.
+func isIcmpError(protocol uint8, icmpType int) bool {
+	if protocol == ipProtoICMP {
+		return icmpType == 3 || icmpType == 11
+	}
+	return icmpType >= 1 && icmpType <= 4
+}
+
+func isIcmpEcho(protocol uint8, icmpType int) bool {
+	if protocol == ipProtoICMP {
+		return icmpType == 8 || icmpType == 0
+	}
+	return icmpType == 128 || icmpType == 129
+}
+
+// packetFilterTun wraps the netstack tun device handed to wireguard-go: packets read from
+// the device are sent to the peers and packets written to the device were received from them.
+type packetFilterTun struct {
+	tun.Device
+	filter  *packetFilter
+	limiter *trafficLimiter
+}
+
+func newPacketFilterTun(device tun.Device, filter *packetFilter, limiter *trafficLimiter) tun.Device {
+	return &packetFilterTun{
+		Device:  device,
+		filter:  filter,
+		limiter: limiter,
+	}
+}
+
+func (t *packetFilterTun) Read(bufs [][]byte, sizes []int, offset int) (int, error) {
+	n, err := t.Device.Read(bufs, sizes, offset)
+	for i := 0; i < n; i++ {
+		if sizes[i] > 0 && !t.filter.allow(bufs[i][offset:offset+sizes[i]], false) {
+			// wireguard-go skips the packets with a zero size
+			sizes[i] = 0
+		}
+	}
+	return n, err
+}
+
+func (t *packetFilterTun) Write(bufs [][]byte, offset int) (int, error) {
+	allowed := bufs[:0:0]
+	for _, buf := range bufs {
+		if t.limiter.allow(buf[offset:]) && t.filter.allow(buf[offset:], true) {
+			allowed = append(allowed, buf)
+		}
+	}
+	if len(allowed) == 0 {
+		return len(bufs), nil
+	}
+	if _, err := t.Device.Write(allowed, offset); err != nil {
+		return 0, err
+	}
+	return len(bufs), nil
+}
@@ -0,0 +1,236 @@
+package nexodus
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"testing"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testPacket builds an IP packet with a transport header, for icmp the ports are the type and code.
+func testPacket(src, dst string, protocol uint8, srcPort, dstPort uint16) []byte {
+	srcAddr := netip.MustParseAddr(src)
+	dstAddr := netip.MustParseAddr(dst)
+	transport := make([]byte, 8)
+	if protocol == ipProtoICMP || protocol == ipProtoICMPv6 {
+		transport[0] = byte(srcPort)
+		transport[1] = byte(dstPort)
+	} else {
+		binary.BigEndian.PutUint16(transport[0:2], srcPort)
+		binary.BigEndian.PutUint16(transport[2:4], dstPort)
+	}
+	if srcAddr.Is4() {
+		header := make([]byte, 20)
+		header[0] = 0x45
+		header[9] = protocol
+		copy(header[12:16], srcAddr.AsSlice())
+		copy(header[16:20], dstAddr.AsSlice())
+		return append(header, transport...)
+	}
+	header := make([]byte, 40)
+	header[0] = 0x60
+	header[6] = protocol
+	copy(header[8:24], srcAddr.AsSlice())
+	copy(header[24:40], dstAddr.AsSlice())
+	return append(header, transport...)
+}
+
+func TestPacketFilter(t *testing.T) {
+	require := require.New(t)
+
+	filter := newPacketFilter(zap.NewNop().Sugar())
+	local := "100.64.0.1"
+
+	// without a security group all traffic is allowed
+	require.True(filter.allow(testPacket("100.64.0.2", local, ipProtoTCP, 40000, 22), true))
+
+	filter.setRules(securityRules{
+		inbound: []securityGroupRules{resolveGroupSecurityRules([]client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("tcp"), FromPort: client.PtrInt32(22), ToPort: client.PtrInt32(22), IpRanges: []string{"100.64.0.3"}, Action: client.PtrString(securityRuleActionDeny)},
+			{IpProtocol: client.PtrString("tcp"), FromPort: client.PtrInt32(22), ToPort: client.PtrInt32(22), IpRanges: []string{"100.64.0.0/16", "200::/64"}},
+			{IpProtocol: client.PtrString("icmp"), IcmpType: client.PtrInt32(8)},
+		}, nil, 0)},
+		outbound: []securityGroupRules{resolveGroupSecurityRules([]client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("udp"), FromPort: client.PtrInt32(53), ToPort: client.PtrInt32(53), Action: client.PtrString(securityRuleActionDeny)},
+		}, nil, 0)},
+	}, true)
+
+	// inbound rules match the source address and the destination port
+	require.True(filter.allow(testPacket("100.64.0.2", local, ipProtoTCP, 40000, 22), true))
+	require.True(filter.allow(testPacket("200::2", "200::1", ipProtoTCP, 40000, 22), true))
+	require.False(filter.allow(testPacket("100.64.0.3", local, ipProtoTCP, 40000, 22), true))
+	require.False(filter.allow(testPacket("100.64.0.2", local, ipProtoTCP, 40000, 80), true))
+	require.False(filter.allow(testPacket("10.0.0.2", local, ipProtoTCP, 40000, 22), true))
+	require.False(filter.allow(testPacket("100.64.0.2", local, ipProtoUDP, 40000, 22), true))
+
+	// icmp type 8 is an echo request, other types and ICMPv6 are dropped
+	require.True(filter.allow(testPacket("100.64.0.4", local, ipProtoICMP, 8, 0), true))
+	require.False(filter.allow(testPacket("100.64.0.4", local, ipProtoICMP, 13, 0), true))
+	require.False(filter.allow(testPacket("200::2", "200::1", ipProtoICMPv6, 128, 0), true))
+
+	// replies of allowed connections are allowed in both directions
+	require.True(filter.allow(testPacket(local, "100.64.0.2", ipProtoTCP, 22, 40000), false))
+	require.True(filter.allow(testPacket("100.64.0.2", local, ipProtoTCP, 40000, 22), true))
+
+	// the outbound direction only has deny rules, everything else is allowed
+	require.False(filter.allow(testPacket(local, "100.64.0.5", ipProtoUDP, 50000, 53), false))
+	require.True(filter.allow(testPacket(local, "100.64.0.5", ipProtoTCP, 50000, 443), false))
+	require.True(filter.allow(testPacket("100.64.0.5", local, ipProtoTCP, 443, 50000), true))
+
+	// malformed packets are dropped
+	require.False(filter.allow([]byte{0x45, 0x00}, true))
+
+	// removing the security group allows all traffic again
+	filter.setRules(securityRules{}, false)
+	require.True(filter.allow(testPacket("100.64.0.3", local, ipProtoTCP, 40000, 22), true))
+}
+
+func TestPacketFilterSecurityGroupUnion(t *testing.T) {
+	require := require.New(t)
+
+	filter := newPacketFilter(zap.NewNop().Sugar())
+	local := "100.64.0.1"
+
+	// an empty default group allows all traffic, even though the db group only allows postgres
+	nx := &Nexodus{deviceCache: map[string]deviceCacheEntry{}}
+	filter.setRules(nx.resolveSecurityGroupRules([]client.ModelsSecurityGroup{
+		{Id: client.PtrString("default")},
+		{Id: client.PtrString("db"), InboundRules: []client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("tcp"), FromPort: client.PtrInt32(5432), ToPort: client.PtrInt32(5432)},
+		}},
+	}), true)
+	require.True(filter.allow(testPacket("100.64.0.2", local, ipProtoTCP, 40000, 5432), true))
+	require.True(filter.allow(testPacket("100.64.0.2", local, ipProtoTCP, 40000, 22), true))
+
+	// a deny rule of one group does not drop the traffic another group allows
+	filter.setRules(nx.resolveSecurityGroupRules([]client.ModelsSecurityGroup{
+		{Id: client.PtrString("ssh"), InboundRules: []client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("tcp"), FromPort: client.PtrInt32(22), ToPort: client.PtrInt32(22)},
+		}},
+		{Id: client.PtrString("db"), InboundRules: []client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(10), IpRanges: []string{"100.64.0.3"}, Action: client.PtrString(securityRuleActionDeny)},
+			{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(20), FromPort: client.PtrInt32(5432), ToPort: client.PtrInt32(5432)},
+		}},
+	}), true)
+	require.True(filter.allow(testPacket("100.64.0.3", local, ipProtoTCP, 40001, 22), true))
+	require.False(filter.allow(testPacket("100.64.0.3", local, ipProtoTCP, 40001, 5432), true))
+	require.True(filter.allow(testPacket("100.64.0.2", local, ipProtoTCP, 40001, 5432), true))
+	// the traffic none of the groups allows is dropped
+	require.False(filter.allow(testPacket("100.64.0.2", local, ipProtoTCP, 40001, 80), true))
+
+	counters := filter.counters()
+	require.Equal(map[int]securityRuleCounter{
+		0: {packets: 1, bytes: 28},
+		1: {packets: 1, bytes: 28},
+		2: {packets: 1, bytes: 28},
+	}, counters.inbound)
+}
+
+func TestPacketFilterIcmpErrors(t *testing.T) {
+	require := require.New(t)
+
+	filter := newPacketFilter(zap.NewNop().Sugar())
+	local := "100.64.0.1"
+	filter.setRules(securityRules{
+		inbound: []securityGroupRules{resolveGroupSecurityRules([]client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("tcp"), FromPort: client.PtrInt32(22), ToPort: client.PtrInt32(22)},
+		}, nil, 0)},
+	}, true)
+
+	// an icmp error carries the header of the packet it was sent in response to
+	icmpError := func(src, dst string, protocol uint8, icmpType uint8, original []byte) []byte {
+		return append(testPacket(src, dst, protocol, uint16(icmpType), 0), original...)
+	}
+
+	// the errors of the flows that were allowed are allowed in, even from another address
+	request := testPacket(local, "100.64.0.2", ipProtoUDP, 50000, 443)
+	require.True(filter.allow(request, false))
+	require.True(filter.allow(icmpError("100.64.0.2", local, ipProtoICMP, 3, request), true))
+	require.True(filter.allow(icmpError("100.64.0.9", local, ipProtoICMP, 11, request), true))
+	// the errors of other flows and other icmp messages are not
+	require.False(filter.allow(icmpError("100.64.0.2", local, ipProtoICMP, 3, testPacket(local, "100.64.0.2", ipProtoUDP, 50001, 443)), true))
+	require.False(filter.allow(icmpError("100.64.0.2", local, ipProtoICMP, 5, request), true))
+
+	// the errors of the ICMPv6 echo requests and the tcp connections over IPv6 as well
+	echo := testPacket("200::1", "200::2", ipProtoICMPv6, 128, 0)
+	require.True(filter.allow(echo, false))
+	require.True(filter.allow(icmpError("200::9", "200::1", ipProtoICMPv6, 3, echo), true))
+	connect := testPacket("200::1", "200::2", ipProtoTCP, 40000, 80)
+	require.True(filter.allow(connect, false))
+	for _, icmpType := range []uint8{1, 2, 3, 4} {
+		require.True(filter.allow(icmpError("200::2", "200::1", ipProtoICMPv6, icmpType, connect), true), icmpType)
+	}
+	require.False(filter.allow(icmpError("200::2", "200::1", ipProtoICMPv6, 1, testPacket("200::1", "200::3", ipProtoTCP, 40000, 80)), true))
+
+	// errors sent by this device about the packets of an allowed inbound flow are allowed out
+	ssh := testPacket("100.64.0.2", local, ipProtoTCP, 40000, 22)
+	require.True(filter.allow(ssh, true))
+	require.True(filter.allow(icmpError(local, "100.64.0.2", ipProtoICMP, 3, ssh), false))
+}
+
+func TestPacketFilterCounters(t *testing.T) {
+	require := require.New(t)
+
+	filter := newPacketFilter(zap.NewNop().Sugar())
+	local := "100.64.0.1"
+	require.Nil(filter.counters())
+
+	rules := securityRules{inbound: []securityGroupRules{resolveGroupSecurityRules([]client.ModelsSecurityRule{
+		{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(20), FromPort: client.PtrInt32(22), ToPort: client.PtrInt32(22), IpRanges: []string{"100.64.0.0/16", "200::/64"}},
+		{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(10), IpRanges: []string{"100.64.0.3"}, Action: client.PtrString(securityRuleActionDeny)},
+		{IpProtocol: client.PtrString("udp")},
+	}, nil, 0)}}
+	filter.setRules(rules, true)
+
+	packet := testPacket("100.64.0.2", local, ipProtoTCP, 40000, 22)
+	require.True(filter.allow(packet, true))
+	// the packets of the established flow do not reach the rules
+	require.True(filter.allow(testPacket(local, "100.64.0.2", ipProtoTCP, 22, 40000), false))
+	require.True(filter.allow(packet, true))
+	require.True(filter.allow(testPacket("200::2", "200::1", ipProtoTCP, 40000, 22), true))
+	require.False(filter.allow(testPacket("100.64.0.3", local, ipProtoTCP, 40000, 22), true))
+	// the default drop is not a security group rule
+	require.False(filter.allow(testPacket("10.0.0.2", local, ipProtoTCP, 40000, 22), true))
+
+	counters := filter.counters()
+	require.NotNil(counters)
+	require.Equal(map[int]securityRuleCounter{
+		0: {packets: 2, bytes: uint64(len(packet)) + 48},
+		1: {packets: 1, bytes: uint64(len(packet))},
+		2: {},
+	}, counters.inbound)
+	require.Empty(counters.outbound)
+
+	require.Equal([]client.ModelsSecurityRuleStats{
+		{Rule: client.PtrInt32(0), Packets: client.PtrInt64(2), Bytes: client.PtrInt64(int64(len(packet)) + 48)},
+		{Rule: client.PtrInt32(1), Packets: client.PtrInt64(1), Bytes: client.PtrInt64(int64(len(packet)))},
+		{Rule: client.PtrInt32(2), Packets: client.PtrInt64(0), Bytes: client.PtrInt64(0)},
+	}, securityRuleStats(counters.inbound, 0, 3))
+
+	// the counters of the second security group of a device start after the rules of the first one
+	require.Equal([]client.ModelsSecurityRuleStats{
+		{Rule: client.PtrInt32(0), Packets: client.PtrInt64(1), Bytes: client.PtrInt64(int64(len(packet)))},
+		{Rule: client.PtrInt32(1), Packets: client.PtrInt64(0), Bytes: client.PtrInt64(0)},
+	}, securityRuleStats(counters.inbound, 1, 2))
+	require.Empty(securityRuleStats(counters.inbound, 3, 1))
+}
+
+func TestParsePacketFilterRange(t *testing.T) {
+	require := require.New(t)
+
+	r, ok := parsePacketFilterRange("100.64.0.0/30")
+	require.True(ok)
+	require.Equal(packetFilterRange{from: netip.MustParseAddr("100.64.0.0"), to: netip.MustParseAddr("100.64.0.3")}, r)
+
+	r, ok = parsePacketFilterRange("200::1 - 200::8")
+	require.True(ok)
+	require.True(r.contains(netip.MustParseAddr("200::5")))
+	require.False(r.contains(netip.MustParseAddr("200::9")))
+	require.False(r.contains(netip.MustParseAddr("100.64.0.1")))
+
+	_, ok = parsePacketFilterRange("100.64.0.1-200::1")
+	require.False(ok)
+}
@@ -32,7 +32,7 @@ func (nx *Nexodus) processSecurityGroupRules() error {
 	// file permitting all traffic and return. The goal is to not interrupt any existing PF rules. If pfctl
 	// is already running, we leave it alone and simply write an empty file permitting all traffic.
 	// If pfctl is disabled on the host and there are no rules we leave it disabled.
-	if nx.securityGroup == nil || (len(nx.securityGroup.InboundRules) == 0 && len(nx.securityGroup.OutboundRules) == 0) {
+	if nx.securityGroups == nil || (len(nx.securityRules.inbound) == 0 && len(nx.securityRules.outbound) == 0) {
 		if _, err := os.Stat(pfAnchorFile); os.IsNotExist(err) {
 			// Create the file if it does not exist
 			_, err := os.Create(pfAnchorFile)
@@ -77,54 +77,10 @@ func (nx *Nexodus) processSecurityGroupRules() error {
 		return fmt.Errorf("failed to append io.nexodus anchor: %w", err)
 	}
 
-	// Explicit drop if rules are defined
-	if len(nx.securityGroup.InboundRules) > 0 {
-		prb.pfBlockAll("in")
-	}
-
-	// Process inbound rules
-	for _, rule := range nx.securityGroup.InboundRules {
-		if len(rule.IpRanges) == 0 || containsEmptyRange(rule.IpRanges) {
-			if err := prb.pfPermitProtoPortAnyAddr(rule, "inbound"); err != nil {
-				nx.logger.Errorf("pfctl setup error, failed to process inbound rule with 'any': %v", err)
-				return fmt.Errorf("pfctl setup error, failed to process inbound rule with 'any': %w", err)
-			}
-		} else if util.ContainsValidCustomIPv4Ranges(rule.IpRanges) || util.ContainsValidCustomIPv6Ranges(rule.IpRanges) {
-			if err := prb.pfPermitProtoPortAddr(rule, "inbound"); err != nil {
-				nx.logger.Errorf("pfctl setup error, failed to process inbound rule: %v", err)
-				return fmt.Errorf("pfctl setup error, failed to process inbound rule: %w", err)
-			}
-		} else {
-			if err := prb.pfPermitProtoPortAnyAddr(rule, "inbound"); err != nil {
-				nx.logger.Errorf("pfctl setup error, failed to process inbound rule with 'any': %v", err)
-				return fmt.Errorf("pfctl setup error, failed to process inbound rule with 'any': %w", err)
-			}
-		}
-	}
-
-	// Explicit drop if rules are defined
-	if len(nx.securityGroup.OutboundRules) > 0 {
-		prb.pfBlockAll("out")
-	}
-
-	// Process outbound rules
-	for _, rule := range nx.securityGroup.OutboundRules {
-		if len(rule.IpRanges) == 0 || containsEmptyRange(rule.IpRanges) {
-			if err := prb.pfPermitProtoPortAnyAddr(rule, "outbound"); err != nil {
-				nx.logger.Errorf("pfctl setup error, failed to process outbound rule with 'any': %v", err)
-				return fmt.Errorf("pfctl setup error, failed to process outbound rule with 'any': %w", err)
-			}
-		} else if util.ContainsValidCustomIPv4Ranges(rule.IpRanges) || util.ContainsValidCustomIPv6Ranges(rule.IpRanges) {
-			if err := prb.pfPermitProtoPortAddr(rule, "outbound"); err != nil {
-				nx.logger.Errorf("pfctl setup error, failed to process outbound rule: %v", err)
-				return fmt.Errorf("pfctl setup error, failed to process outbound rule: %w", err)
-			}
-		} else {
-			if err := prb.pfPermitProtoPortAnyAddr(rule, "outbound"); err != nil {
-				nx.logger.Errorf("pfctl setup error, failed to process outbound rule with 'any': %v", err)
-				return fmt.Errorf("pfctl setup error, failed to process outbound rule with 'any': %w", err)
-			}
-		}
+	// Add the rules ordered by priority with the security group references resolved to tunnel addresses
+	if err := prb.pfBuildRules(nx.securityRules.inbound, nx.securityRules.outbound); err != nil {
+		nx.logger.Errorf("pfctl setup error: %v", err)
+		return fmt.Errorf("pfctl setup error: %w", err)
 	}
 
 	// Open the anchor file to write pf rules
@@ -158,20 +114,103 @@ func (nx *Nexodus) processSecurityGroupRules() error {
 	return nil
 }
 
-func (prb *pfRuleBuilder) pfPermitProtoPortAddr(rule client.ModelsSecurityRule, direction string) error {
-	var portOption string
-	var directionToken string
+// pfBuildRules adds the inbound and outbound rules to the rule set. A device in several security groups
+// is allowed the traffic any of its groups allows, which the last matching rule semantics of pf can not
+// express with quick rules alone. The rules of every group are therefore rendered as non-quick rules in
+// reverse priority order that tag the packet with the verdict of the group, the last matching rule being
+// the one with the highest priority. A quick pass rule accepts the packets a group tagged as allowed
+// and the packets no group allowed are blocked at the end of the direction.
+func (prb *pfRuleBuilder) pfBuildRules(inbound, outbound []securityGroupRules) error {
+	if err := prb.pfBuildDirectionRules(inbound, "inbound"); err != nil {
+		return err
+	}
+	return prb.pfBuildDirectionRules(outbound, "outbound")
+}
+
+// pfBuildDirectionRules adds the rules of the groups in one direction, a direction without groups allows
+// all traffic.
+func (prb *pfRuleBuilder) pfBuildDirectionRules(groups []securityGroupRules, direction string) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	dir := pfDirection(direction)
+	for i, group := range groups {
+		passTag := fmt.Sprintf("nexodus-%s-%d-pass", dir, i)
+		blockTag := fmt.Sprintf("nexodus-%s-%d-block", dir, i)
+
+		// the verdict of the group when none of its rules match
+		defaultTag := blockTag
+		if group.defaultAllow {
+			defaultTag = passTag
+		}
+		prb.sb.WriteString(fmt.Sprintf("pass %s on %s all tag %s\n", dir, prb.iface, defaultTag))
+
+		for j := len(group.rules) - 1; j >= 0; j-- {
+			rule := group.rules[j]
+			tag := passTag
+			if securityRuleDenies(rule) {
+				tag = blockTag
+			}
+			if len(rule.IpRanges) == 0 || containsEmptyRange(rule.IpRanges) {
+				if err := prb.pfPermitProtoPortAnyAddr(rule, direction, tag); err != nil {
+					return fmt.Errorf("failed to process %s rule with 'any': %w", direction, err)
+				}
+			} else if util.ContainsValidCustomIPv4Ranges(rule.IpRanges) || util.ContainsValidCustomIPv6Ranges(rule.IpRanges) {
+				if err := prb.pfPermitProtoPortAddr(rule, direction, tag); err != nil {
+					return fmt.Errorf("failed to process %s rule: %w", direction, err)
+				}
+			} else {
+				if err := prb.pfPermitProtoPortAnyAddr(rule, direction, tag); err != nil {
+					return fmt.Errorf("failed to process %s rule with 'any': %w", direction, err)
+				}
+			}
+		}
 
+		prb.sb.WriteString(fmt.Sprintf("pass %s quick on %s all tagged %s\n", dir, prb.iface, passTag))
+	}
+
+	// none of the groups allowed the traffic
+	prb.pfBlockAll(dir)
+	return nil
+}
+
+// pfDirection returns the pf direction keyword of the inbound or outbound rules
+func pfDirection(direction string) string {
 	if direction == "inbound" {
-		directionToken = "pass in"
-	} else if direction == "outbound" {
-		directionToken = "pass out"
+		return "in"
 	}
+	return "out"
+}
+
+// pfDirectionToken returns the action and direction of the non-quick rule that tags the packets the rule
+// matches, deny rules tag the packets as blocked by the group and are rendered as pass rules as well.
+func pfDirectionToken(direction string) string {
+	return "pass " + pfDirection(direction)
+}
+
+// pfIcmpOption returns the icmp-type and code match of the rule, keyword is either icmp-type or icmp6-type
+func pfIcmpOption(keyword string, rule client.ModelsSecurityRule) string {
+	if !rule.HasIcmpType() {
+		return ""
+	}
+	option := fmt.Sprintf(" %s %d", keyword, rule.GetIcmpType())
+	if rule.HasIcmpCode() {
+		option += fmt.Sprintf(" code %d", rule.GetIcmpCode())
+	}
+	return option
+}
+
+func (prb *pfRuleBuilder) pfPermitProtoPortAddr(rule client.ModelsSecurityRule, direction string, tag string) error {
+	var portOption string
+	var directionToken string
+
+	directionToken = pfDirectionToken(direction)
+	tagOption := " tag " + tag
 
 	if rule.GetFromPort() == 0 && rule.GetToPort() == 0 {
 		portOption = ""
 	} else {
-		portOption = fmt.Sprintf("port %d:%d", rule.GetFromPort(), rule.GetToPort())
+		portOption = fmt.Sprintf(" port %d:%d", rule.GetFromPort(), rule.GetToPort())
 	}
 
 	ipRangesStr := strings.Join(rule.IpRanges, ", ")
@@ -194,20 +233,23 @@ func (prb *pfRuleBuilder) pfPermitProtoPortAddr(rule client.ModelsSecurityRule,
 	switch protocol {
 	case "ipv4", "ipv6":
 		if portOption != "" {
-			prb.sb.WriteString(fmt.Sprintf("%s quick on %s %s proto tcp %s %s\n", directionToken, prb.iface, inetType, ipDirection, portOption))
-			prb.sb.WriteString(fmt.Sprintf("%s quick on %s %s proto udp %s %s\n", directionToken, prb.iface, inetType, ipDirection, portOption))
+			prb.sb.WriteString(fmt.Sprintf("%s on %s %s proto tcp %s%s%s\n", directionToken, prb.iface, inetType, ipDirection, portOption, tagOption))
+			prb.sb.WriteString(fmt.Sprintf("%s on %s %s proto udp %s%s%s\n", directionToken, prb.iface, inetType, ipDirection, portOption, tagOption))
 		} else {
-			prb.sb.WriteString(fmt.Sprintf("%s quick on %s %s %s %s\n", directionToken, prb.iface, inetType, ipDirection, portOption))
+			prb.sb.WriteString(fmt.Sprintf("%s on %s %s %s%s%s\n", directionToken, prb.iface, inetType, ipDirection, portOption, tagOption))
 		}
 	case "tcp", "udp":
-		prb.sb.WriteString(fmt.Sprintf("%s quick on %s %s proto %s %s %s\n", directionToken, prb.iface, inetType, protocol, ipDirection, portOption))
+		prb.sb.WriteString(fmt.Sprintf("%s on %s %s proto %s %s%s%s\n", directionToken, prb.iface, inetType, protocol, ipDirection, portOption, tagOption))
 	case "icmp4", "icmpv4":
-		prb.sb.WriteString(fmt.Sprintf("%s quick on %s inet proto icmp %s\n", directionToken, prb.iface, ipDirection))
+		prb.sb.WriteString(fmt.Sprintf("%s on %s inet proto icmp %s%s%s\n", directionToken, prb.iface, ipDirection, pfIcmpOption("icmp-type", rule), tagOption))
 	case "icmp6", "icmpv6":
-		prb.sb.WriteString(fmt.Sprintf("%s quick on %s inet6 proto icmp6 %s\n", directionToken, prb.iface, ipDirection))
+		prb.sb.WriteString(fmt.Sprintf("%s on %s inet6 proto icmp6 %s%s%s\n", directionToken, prb.iface, ipDirection, pfIcmpOption("icmp6-type", rule), tagOption))
 	case "icmp":
-		prb.sb.WriteString(fmt.Sprintf("%s quick on %s inet proto icmp to any\n", directionToken, prb.iface))
-		prb.sb.WriteString(fmt.Sprintf("%s quick on %s inet6 proto icmp6 to any\n", directionToken, prb.iface))
+		prb.sb.WriteString(fmt.Sprintf("%s on %s inet proto icmp to any%s%s\n", directionToken, prb.iface, pfIcmpOption("icmp-type", rule), tagOption))
+		// icmp types are ICMPv4 types, an icmp rule with a type does not match ICMPv6
+		if !rule.HasIcmpType() {
+			prb.sb.WriteString(fmt.Sprintf("%s on %s inet6 proto icmp6 to any%s\n", directionToken, prb.iface, tagOption))
+		}
 	default:
 		return fmt.Errorf("no match for permit proto port/port/address rule: %v", rule)
 	}
@@ -215,20 +257,17 @@ func (prb *pfRuleBuilder) pfPermitProtoPortAddr(rule client.ModelsSecurityRule,
 	return nil
 }
 
-func (prb *pfRuleBuilder) pfPermitProtoPortAnyAddr(rule client.ModelsSecurityRule, direction string) error {
+func (prb *pfRuleBuilder) pfPermitProtoPortAnyAddr(rule client.ModelsSecurityRule, direction string, tag string) error {
 	var portOption string
 	var directionToken string
 
-	if direction == "inbound" {
-		directionToken = "pass in"
-	} else if direction == "outbound" {
-		directionToken = "pass out"
-	}
+	directionToken = pfDirectionToken(direction)
+	tagOption := " tag " + tag
 
 	if rule.GetFromPort() == 0 && rule.GetToPort() == 0 {
 		portOption = ""
 	} else {
-		portOption = fmt.Sprintf("port %d:%d", rule.GetFromPort(), rule.GetToPort())
+		portOption = fmt.Sprintf(" port %d:%d", rule.GetFromPort(), rule.GetToPort())
 	}
 
 	ipDirection := "to any"
@@ -246,21 +285,23 @@ func (prb *pfRuleBuilder) pfPermitProtoPortAnyAddr(rule client.ModelsSecurityRul
 
 	switch protocol {
 	case "tcp", "udp":
-		prb.sb.WriteString(fmt.Sprintf("%s quick on %s %s proto %s %s %s\n", directionToken, prb.iface, inetType, protocol, ipDirection, portOption))
+		prb.sb.WriteString(fmt.Sprintf("%s on %s %s proto %s %s%s%s\n", directionToken, prb.iface, inetType, protocol, ipDirection, portOption, tagOption))
 	case "ipv4", "ipv6":
 		if portOption != "" {
-			prb.sb.WriteString(fmt.Sprintf("%s quick on %s %s proto tcp %s %s\n", directionToken, prb.iface, inetType, ipDirection, portOption))
-			prb.sb.WriteString(fmt.Sprintf("%s quick on %s %s proto udp %s %s\n", directionToken, prb.iface, inetType, ipDirection, portOption))
+			prb.sb.WriteString(fmt.Sprintf("%s on %s %s proto tcp %s%s%s\n", directionToken, prb.iface, inetType, ipDirection, portOption, tagOption))
+			prb.sb.WriteString(fmt.Sprintf("%s on %s %s proto udp %s%s%s\n", directionToken, prb.iface, inetType, ipDirection, portOption, tagOption))
 		} else {
-			prb.sb.WriteString(fmt.Sprintf("%s quick on %s %s %s %s\n", directionToken, "utun8", inetType, ipDirection, portOption))
+			prb.sb.WriteString(fmt.Sprintf("%s on %s %s %s%s%s\n", directionToken, prb.iface, inetType, ipDirection, portOption, tagOption))
 		}
 	case "icmp4", "icmpv4":
-		prb.sb.WriteString(fmt.Sprintf("%s quick on %s %s proto icmp %s\n", directionToken, prb.iface, inetType, ipDirection))
+		prb.sb.WriteString(fmt.Sprintf("%s on %s %s proto icmp %s%s%s\n", directionToken, prb.iface, inetType, ipDirection, pfIcmpOption("icmp-type", rule), tagOption))
 	case "icmp6", "icmpv6":
-		prb.sb.WriteString(fmt.Sprintf("%s quick on %s %s proto icmp6 %s\n", directionToken, prb.iface, inetType, ipDirection))
+		prb.sb.WriteString(fmt.Sprintf("%s on %s %s proto icmp6 %s%s%s\n", directionToken, prb.iface, inetType, ipDirection, pfIcmpOption("icmp6-type", rule), tagOption))
 	case "icmp":
-		prb.sb.WriteString(fmt.Sprintf("%s quick on %s inet proto icmp %s\n", directionToken, prb.iface, ipDirection))
-		prb.sb.WriteString(fmt.Sprintf("%s quick on %s inet6 proto icmp6 %s\n", directionToken, prb.iface, ipDirection))
+		prb.sb.WriteString(fmt.Sprintf("%s on %s inet proto icmp %s%s%s\n", directionToken, prb.iface, ipDirection, pfIcmpOption("icmp-type", rule), tagOption))
+		if !rule.HasIcmpType() {
+			prb.sb.WriteString(fmt.Sprintf("%s on %s inet6 proto icmp6 %s%s\n", directionToken, prb.iface, ipDirection, tagOption))
+		}
 	default:
 		return fmt.Errorf("no policy PF match for permit proto port any address rule: %v", rule)
 	}
@@ -342,9 +383,9 @@ func appendToFile(filename, lines string) error {
 	return nil
 }
 
-// pfBlockAll adds an implicit drop once an explicit allow is added
+// pfBlockAll blocks the traffic that none of the security groups allowed
 func (prb *pfRuleBuilder) pfBlockAll(direction string) {
-	prb.sb.WriteString(fmt.Sprintf("block %s on %s all\n", direction, prb.iface))
+	prb.sb.WriteString(fmt.Sprintf("block %s quick on %s all\n", direction, prb.iface))
 }
 
 // containsEmptyString checks if the slice contains an empty string
@@ -403,7 +444,25 @@ func (nx *Nexodus) networkRouterSetup() error {
 	return nil
 }
 
+// securityGroupRuleCounters for Darwin build purposes, the counters of the pf rules are not reported
+func (nx *Nexodus) securityGroupRuleCounters() (*securityGroupCounters, error) {
+	return nil, nil
+}
+
+// processTrafficLimits traffic limits are only enforced on linux and in userspace mode
+func (nx *Nexodus) processTrafficLimits(limits []peerTrafficLimit) error {
+	if len(limits) > 0 {
+		nx.logger.Warn("Traffic limits of the peers are only enforced on Linux and in userspace proxy mode")
+	}
+	return nil
+}
+
 // policyTableDrop for Darwin build purposes
 func (nx *Nexodus) policyTableDrop(table string) error {
 	return nil
 }
+
+// nfApplyTable nftables are only available on linux, the exit node and relay tables are unsupported on darwin
+func nfApplyTable(logger *zap.SugaredLogger, table nfTable) error {
+	return fmt.Errorf("nftables table %s %s is not supported on darwin", table.family, table.name)
+}
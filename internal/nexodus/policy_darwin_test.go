@@ -7,7 +7,6 @@ import (
 	"github.com/nexodus-io/nexodus/internal/client"
 	"testing"
 
-	"github.com/nexodus-io/nexodus/internal/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,45 +20,10 @@ func runTestPacketFilterRuleBuilder(t *testing.T, securityGroupJSON string, expe
 	// Initialize pfRuleBuilder
 	prb := &pfRuleBuilder{iface: "utun8"}
 
-	// Explicit drop if inbound rules are defined
-	if len(secGroup.InboundRules) > 0 {
-		prb.pfBlockAll("in")
-	}
-	// Process inbound rules
-	for _, rule := range secGroup.InboundRules {
-		if len(rule.IpRanges) == 0 || containsEmptyRange(rule.IpRanges) {
-			if err := prb.pfPermitProtoPortAnyAddr(rule, "inbound"); err != nil {
-				t.Errorf("pfctl setup error, failed to process inbound rule with 'any': %v", err)
-			}
-		} else if util.ContainsValidCustomIPv4Ranges(rule.IpRanges) || util.ContainsValidCustomIPv6Ranges(rule.IpRanges) {
-			if err := prb.pfPermitProtoPortAddr(rule, "inbound"); err != nil {
-				t.Errorf("pfctl setup error, failed to process inbound rule: %v", err)
-			}
-		} else {
-			if err := prb.pfPermitProtoPortAnyAddr(rule, "inbound"); err != nil {
-				t.Errorf("pfctl setup error, failed to process inbound rule with 'any': %v", err)
-			}
-		}
-	}
-	// Explicit drop if outbound rules are defined
-	if len(secGroup.OutboundRules) > 0 {
-		prb.pfBlockAll("out")
-	}
-	// Process outbound rules
-	for _, rule := range secGroup.OutboundRules {
-		if len(rule.IpRanges) == 0 || containsEmptyRange(rule.IpRanges) {
-			if err := prb.pfPermitProtoPortAnyAddr(rule, "outbound"); err != nil {
-				t.Errorf("pfctl setup error, failed to process outbound rule with 'any': %v", err)
-			}
-		} else if util.ContainsValidCustomIPv4Ranges(rule.IpRanges) || util.ContainsValidCustomIPv6Ranges(rule.IpRanges) {
-			if err := prb.pfPermitProtoPortAddr(rule, "outbound"); err != nil {
-				t.Errorf("pfctl setup error, failed to process outbound rule: %v", err)
-			}
-		} else {
-			if err := prb.pfPermitProtoPortAnyAddr(rule, "outbound"); err != nil {
-				t.Errorf("pfctl setup error, failed to process outbound rule with 'any': %v", err)
-			}
-		}
+	inboundRules := []securityGroupRules{resolveGroupSecurityRules(secGroup.InboundRules, nil, 0)}
+	outboundRules := []securityGroupRules{resolveGroupSecurityRules(secGroup.OutboundRules, nil, 0)}
+	if err := prb.pfBuildRules(inboundRules, outboundRules); err != nil {
+		t.Errorf("pfctl setup error: %v", err)
 	}
 
 	// Assert and output the generated rules for debugging
@@ -114,52 +78,115 @@ func TestDarwinRuleBuilder(t *testing.T) {
 `
 
 	mockSecurityGroup1ExpectedRules := []string{
-		"block in on utun8 all",
-		"pass in quick on utun8 inet from { 10.0.0.1/24, 192.168.1.1/32 } to any",
-		"pass in quick on utun8 inet from { 0.0.0.0/0 } to any",
-		"pass in quick on utun8 inet proto tcp from any to any",
-		"pass in quick on utun8 inet6 from { ::1/128, 2001:db8::/64 } to any",
-		"pass in quick on utun8 inet proto tcp from { 10.0.0.1, 192.168.0.1 } to any port 22:22",
-		"pass in quick on utun8 inet proto tcp from { 10.0.0.2, 10.0.0.3 } to any port 80:80",
-		"pass in quick on utun8 inet proto icmp from { 10.0.0.1 } to any",
-		"pass in quick on utun8 inet proto icmp from any to any",
-		"pass in quick on utun8 inet proto tcp from { 100.64.0.1  -  100.64.0.45 } to any port 9001:9005",
-		"pass in quick on utun8 inet6 proto icmp6 from { ::1 } to any",
-		"pass in quick on utun8 inet proto icmp to any",
-		"pass in quick on utun8 inet6 proto icmp6 to any",
-		"pass in quick on utun8 inet proto udp from any to any",
-		"pass in quick on utun8 inet proto tcp from any to any port 22:22",
-		"pass in quick on utun8 inet6 proto icmp6 from any to any",
-		"pass in quick on utun8 inet proto icmp from any to any",
-		"pass in quick on utun8 inet from any to any",
-		"pass in quick on utun8 inet proto udp from { 100.64.0.1  -  100.64.0.45, 100.64.100.0/24, 100.64.0.120 } to any port 54:54",
-		"pass in quick on utun8 inet proto tcp from any to any port 9000:9001",
-		"pass in quick on utun8 inet proto udp from any to any port 9000:9001",
-		"pass in quick on utun8 inet6 proto tcp from any to any port 43333:53333",
-		"pass in quick on utun8 inet6 proto udp from any to any port 43333:53333",
-		"pass in quick on utun8 inet6 from any to any",
-		"pass in quick on utun8 inet from any to any",
-		"block out on utun8 all",
-		"pass out quick on utun8 inet proto tcp to { 192.168.0.1, 10.120.0.2 } port 80:80",
-		"pass out quick on utun8 inet proto udp to { 8.8.8.8 } port 53:53",
-		"pass out quick on utun8 inet6 proto icmp6 to { ::1, 2001:db8:1234::/48 }",
-		"pass out quick on utun8 inet6 proto icmp6 to any",
-		"pass out quick on utun8 inet proto icmp to any",
-		"pass out quick on utun8 inet6 proto icmp6 to any",
-		"pass out quick on utun8 inet to any",
-		"pass out quick on utun8 inet proto tcp to any port 123:456",
-		"pass out quick on utun8 inet proto udp to any port 123:456",
-		"pass out quick on utun8 inet6 proto tcp to any port 41000:41500",
-		"pass out quick on utun8 inet6 proto udp to any port 41000:41500",
-		"pass out quick on utun8 inet proto tcp to { 192.168.0.1, 10.130.0.2 } port 52000:53000",
-		"pass out quick on utun8 inet proto udp to { 192.168.0.1, 10.130.0.2 } port 52000:53000",
-		"pass out quick on utun8 inet6 proto tcp to { 2001:db9::2/64, 3001:da9::2 - 3001:da9::6 } port 78:89",
-		"pass out quick on utun8 inet6 proto udp to { 2001:db9::2/64, 3001:da9::2 - 3001:da9::6 } port 78:89",
-		"pass in quick on utun8 inet proto icmp from any to any",
-		"pass in quick on utun8 inet6 proto icmp6 from any to any",
+		"pass in on utun8 all tag nexodus-in-0-block",
+		"pass in on utun8 inet from { 10.0.0.1/24, 192.168.1.1/32 } to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet from { 0.0.0.0/0 } to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto tcp from any to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet6 from { ::1/128, 2001:db8::/64 } to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto tcp from { 10.0.0.1, 192.168.0.1 } to any port 22:22 tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto tcp from { 10.0.0.2, 10.0.0.3 } to any port 80:80 tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto icmp from { 10.0.0.1 } to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto icmp from any to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto tcp from { 100.64.0.1  -  100.64.0.45 } to any port 9001:9005 tag nexodus-in-0-pass",
+		"pass in on utun8 inet6 proto icmp6 from { ::1 } to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto icmp to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet6 proto icmp6 to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto udp from any to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto tcp from any to any port 22:22 tag nexodus-in-0-pass",
+		"pass in on utun8 inet6 proto icmp6 from any to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto icmp from any to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet from any to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto udp from { 100.64.0.1  -  100.64.0.45, 100.64.100.0/24, 100.64.0.120 } to any port 54:54 tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto tcp from any to any port 9000:9001 tag nexodus-in-0-pass",
+		"pass in on utun8 inet proto udp from any to any port 9000:9001 tag nexodus-in-0-pass",
+		"pass in on utun8 inet6 proto tcp from any to any port 43333:53333 tag nexodus-in-0-pass",
+		"pass in on utun8 inet6 proto udp from any to any port 43333:53333 tag nexodus-in-0-pass",
+		"pass in on utun8 inet6 from any to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet from any to any tag nexodus-in-0-pass",
+		"pass out on utun8 all tag nexodus-out-0-block",
+		"pass out on utun8 inet proto tcp to { 192.168.0.1, 10.120.0.2 } port 80:80 tag nexodus-out-0-pass",
+		"pass out on utun8 inet proto udp to { 8.8.8.8 } port 53:53 tag nexodus-out-0-pass",
+		"pass out on utun8 inet6 proto icmp6 to { ::1, 2001:db8:1234::/48 } tag nexodus-out-0-pass",
+		"pass out on utun8 inet6 proto icmp6 to any tag nexodus-out-0-pass",
+		"pass out on utun8 inet proto icmp to any tag nexodus-out-0-pass",
+		"pass out on utun8 inet6 proto icmp6 to any tag nexodus-out-0-pass",
+		"pass out on utun8 inet to any tag nexodus-out-0-pass",
+		"pass out on utun8 inet proto tcp to any port 123:456 tag nexodus-out-0-pass",
+		"pass out on utun8 inet proto udp to any port 123:456 tag nexodus-out-0-pass",
+		"pass out on utun8 inet6 proto tcp to any port 41000:41500 tag nexodus-out-0-pass",
+		"pass out on utun8 inet6 proto udp to any port 41000:41500 tag nexodus-out-0-pass",
+		"pass out on utun8 inet proto tcp to { 192.168.0.1, 10.130.0.2 } port 52000:53000 tag nexodus-out-0-pass",
+		"pass out on utun8 inet proto udp to { 192.168.0.1, 10.130.0.2 } port 52000:53000 tag nexodus-out-0-pass",
+		"pass out on utun8 inet6 proto tcp to { 2001:db9::2/64, 3001:da9::2 - 3001:da9::6 } port 78:89 tag nexodus-out-0-pass",
+		"pass out on utun8 inet6 proto udp to { 2001:db9::2/64, 3001:da9::2 - 3001:da9::6 } port 78:89 tag nexodus-out-0-pass",
+		"pass in on utun8 inet proto icmp from any to any tag nexodus-in-0-pass",
+		"pass in on utun8 inet6 proto icmp6 from any to any tag nexodus-in-0-pass",
+		"pass in quick on utun8 all tagged nexodus-in-0-pass",
+		"block in quick on utun8 all",
+		"pass out quick on utun8 all tagged nexodus-out-0-pass",
+		"block out quick on utun8 all",
 	}
 
 	t.Run("Test with mockSecurityGroup1", func(t *testing.T) {
 		runTestPacketFilterRuleBuilder(t, mockSecurityGroup1, mockSecurityGroup1ExpectedRules)
 	})
+
+	mockSecurityGroup2 := `
+{
+	"group_name": "Test",
+	"inbound_rules": [
+		{"ip_protocol": "tcp", "from_port": 22, "to_port": 22, "priority": 20},
+		{"ip_protocol": "tcp", "from_port": 22, "to_port": 22, "ip_ranges": ["100.64.0.10"], "action": "deny", "priority": 10},
+		{"ip_protocol": "icmpv4", "icmp_type": 8, "icmp_code": 0},
+		{"ip_protocol": "icmpv6", "icmp_type": 128, "ip_ranges": ["200::/64"]}
+	],
+	"outbound_rules": [
+		{"ip_protocol": "udp", "from_port": 53, "to_port": 53, "action": "deny"}
+	]
+}
+`
+
+	// the rules of a group tag the packets in reverse priority order, the last matching rule decides
+	mockSecurityGroup2ExpectedRules := []string{
+		"pass in on utun8 all tag nexodus-in-0-block\n" +
+			"pass in on utun8 inet proto tcp from any to any port 22:22 tag nexodus-in-0-pass\n" +
+			"pass in on utun8 inet proto tcp from { 100.64.0.10 } to any port 22:22 tag nexodus-in-0-block\n" +
+			"pass in on utun8 inet6 proto icmp6 from { 200::/64 } to any icmp6-type 128 tag nexodus-in-0-pass\n" +
+			"pass in on utun8 inet proto icmp from any to any icmp-type 8 code 0 tag nexodus-in-0-pass\n" +
+			"pass in quick on utun8 all tagged nexodus-in-0-pass\n" +
+			"block in quick on utun8 all\n" +
+			"pass out on utun8 all tag nexodus-out-0-pass\n" +
+			"pass out on utun8 inet proto udp to any port 53:53 tag nexodus-out-0-block\n" +
+			"pass out quick on utun8 all tagged nexodus-out-0-pass\n" +
+			"block out quick on utun8 all\n",
+	}
+
+	t.Run("Test with mockSecurityGroup2", func(t *testing.T) {
+		runTestPacketFilterRuleBuilder(t, mockSecurityGroup2, mockSecurityGroup2ExpectedRules)
+	})
+
+	// a device in several groups is allowed the traffic any of the groups allows, the empty default group
+	// allows all the traffic the db group does not
+	t.Run("Test with several security groups", func(t *testing.T) {
+		nx := &Nexodus{deviceCache: map[string]deviceCacheEntry{}}
+		rules := nx.resolveSecurityGroupRules([]client.ModelsSecurityGroup{
+			{Id: client.PtrString("default")},
+			{Id: client.PtrString("db"), InboundRules: []client.ModelsSecurityRule{
+				{IpProtocol: client.PtrString("tcp"), FromPort: client.PtrInt32(5432), ToPort: client.PtrInt32(5432)},
+			}},
+		})
+		prb := &pfRuleBuilder{iface: "utun8"}
+		assert.NoError(t, prb.pfBuildRules(rules.inbound, rules.outbound))
+		assert.Equal(t, "pass in on utun8 all tag nexodus-in-0-pass\n"+
+			"pass in quick on utun8 all tagged nexodus-in-0-pass\n"+
+			"pass in on utun8 all tag nexodus-in-1-block\n"+
+			"pass in on utun8 inet proto tcp from any to any port 5432:5432 tag nexodus-in-1-pass\n"+
+			"pass in quick on utun8 all tagged nexodus-in-1-pass\n"+
+			"block in quick on utun8 all\n"+
+			"pass out on utun8 all tag nexodus-out-0-pass\n"+
+			"pass out quick on utun8 all tagged nexodus-out-0-pass\n"+
+			"pass out on utun8 all tag nexodus-out-1-pass\n"+
+			"pass out quick on utun8 all tagged nexodus-out-1-pass\n"+
+			"block out quick on utun8 all\n", prb.sb.String())
+	})
 }
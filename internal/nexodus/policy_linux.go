@@ -3,11 +3,10 @@ package nexodus
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/nexodus-io/nexodus/internal/client"
-	"os/exec"
+	"sort"
 	"strings"
 
-	"github.com/nexodus-io/nexodus/internal/util"
+	"github.com/nexodus-io/nexodus/internal/client"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -15,15 +14,8 @@ import (
 const (
 	// Nftables keywords
 	sgTableName  = "nexodus"
-	tableFamily  = "inet"
 	ingressChain = "nexodus-inbound"
 	egressChain  = "nexodus-outbound"
-	destPort     = "dport"
-	destAddr     = "daddr"
-	srcAddr      = "saddr"
-	actionAccept = "accept"
-	actionDrop   = "drop"
-	counter      = "counter"
 	// Protocols
 	protoIPv4   = "ipv4"
 	protoIPv6   = "ipv6"
@@ -37,579 +29,352 @@ const (
 	chainPrerouting  = "prerouting"
 	chainPostrouting = "postrouting"
 	chainForward     = "forward"
-	chainTypeNAT     = "nat"
-	chainTypeFilter  = "filter"
-	priorityDstNAT   = "dstnat"
-	prioritySrcNAT   = "srcnat"
-	priorityFilter   = "filter"
-)
-
-var (
-	ruleInterface string
 )
 
 // processSecurityGroupRules processes a security group for a Linux node
 func (nx *Nexodus) processSecurityGroupRules() error {
 
 	// Delete the table if the security group is empty and attempt to drop a table if one exists
-	if nx.securityGroup == nil {
+	if nx.securityGroups == nil {
 		// Drop the existing table and return nil if a group was not found to drop
 		_ = nx.policyTableDrop(sgTableName)
 		return nil
 	}
 
-	ruleInterface = fmt.Sprintf("iifname %s", wgIface)
-
-	inboundRules := nx.securityGroup.InboundRules
-	outboundRules := nx.securityGroup.OutboundRules
-
 	// Enable rule debugging to print rules via debug logging as they are processed
 	if nx.logger.Level().Enabled(zapcore.DebugLevel) {
-		err := debugSecurityGroupRules(nx.logger, inboundRules, outboundRules)
+		err := debugSecurityGroupRules(nx.logger, nx.securityRules.inbound, nx.securityRules.outbound)
 		if err != nil {
 			nx.logger.Debug(err)
 		}
 	}
 
-	// Drop the existing table
-	if err := nx.policyTableDrop(sgTableName); err != nil {
-		return fmt.Errorf("nftables setup error, failed to flush nftables: %w", err)
+	// the rules that changed are replaced in a single transaction, traffic keeps flowing through the
+	// rules that did not change while the table is updated.
+	if err := nfApplyTable(nx.logger, nfSecurityGroupTable(nx.logger, wgIface, nx.securityRules)); err != nil {
+		return fmt.Errorf("nftables setup error, failed to apply the security group rules: %w", err)
 	}
 
-	// Create the nftables table
-	if err := nx.nfCreateTable(sgTableName); err != nil {
-		return fmt.Errorf("nftables setup error, failed to create nftables inet table: %w", err)
-	}
-
-	// Create the ingress nftables chains
-	if err := nx.nfCreateChain(ingressChain); err != nil {
-		return fmt.Errorf("nftables setup error, failed to create nftables chain %s: %w", ingressChain, err)
-	}
+	return nil
+}
 
-	// Create the egress nftables chains
-	if err := nx.nfCreateChain(egressChain); err != nil {
-		return fmt.Errorf("nftables setup error, failed to create nftables chain %s: %w", egressChain, err)
+// nfSecurityGroupTable renders the security group rules into the nexodus table. The rules of every security
+// group go to a chain of their own, the base chains jump to the chains of the groups in order and accept the
+// traffic that any of the groups accepts. The traffic no group accepts is dropped.
+func nfSecurityGroupTable(logger *zap.SugaredLogger, iface string, rules securityRules) nfTable {
+	var chains []nfChain
+	directions := []struct {
+		name   string
+		groups []securityGroupRules
+		dst    bool
+	}{
+		{ingressChain, rules.inbound, false},
+		{egressChain, rules.outbound, true},
+	}
+	// the chains of the groups are created before the base chains jump to them
+	for _, direction := range directions {
+		for i, group := range direction.groups {
+			groupRules, _ := nfSecurityGroupChainRules(logger, iface, direction.dst, group)
+			chains = append(chains, nfChain{
+				name:  nfSecurityGroupChain(direction.name, i),
+				rules: groupRules,
+			})
+		}
+	}
+	for _, direction := range directions {
+		chains = append(chains, nfChain{
+			name:      direction.name,
+			chainType: nfChainTypeFilter,
+			hook:      nfHookInput,
+			priority:  nfPriorityFilter,
+			rules:     nfSecurityGroupBaseChainRules(iface, direction.name, len(direction.groups)),
+		})
+	}
+	return nfTable{
+		family: tableFamily,
+		name:   sgTableName,
+		chains: chains,
 	}
+}
 
-	// Process the inbound rules
-	for _, rule := range inboundRules {
-		if len(rule.IpRanges) == 0 { // If the ip range is empty, add one
-			rule.IpRanges = append(rule.IpRanges, "")
-		}
-		if util.ContainsValidCustomIPv4Ranges(rule.IpRanges) {
-			// if the rule is a L3 addresses in v4 family, with or without L4 port(s)
-			if err := nx.nfPermitProtoPortAddrV4(ingressChain, rule); err != nil {
-				return fmt.Errorf("nftables setup error, failed to process inbound v4 rule: %w", err)
-			}
-		} else if util.ContainsValidCustomIPv6Ranges(rule.IpRanges) {
-			// if the rule is a L3 addresses in v6 family, with or without L4 port(s)
-			if err := nx.nfPermitProtoPortAddrV6(ingressChain, rule); err != nil {
-				return fmt.Errorf("nftables setup error, failed to process inbound v6 rule: %w", err)
-			}
-		} else if rule.GetFromPort() != 0 && rule.GetToPort() != 0 {
-			// if the rule is L4 port(s) range with no l3 addresses
-			if err := nx.nfPermitProtoPort(ingressChain, rule); err != nil {
-				return fmt.Errorf("nftables setup error, failed to process inbound destination port rule: %w", err)
-			}
-		} else {
-			// if the rule is only protocol to permit (no L4 ports or L3 addresses)
-			if err := nx.nfPermitProtoAny(ingressChain, rule); err != nil {
-				return fmt.Errorf("nftables setup error, failed to process inbound destination port rule: %w", err)
-			}
-		}
-	}
+// nfSecurityGroupChain returns the name of the chain of the security group with index i in the base chain
+func nfSecurityGroupChain(baseChain string, i int) string {
+	return fmt.Sprintf("%s-%d", baseChain, i)
+}
 
-	// Process the outbound rules
-	for _, rule := range outboundRules {
-		if len(rule.IpRanges) == 0 { // If the ip range is empty, add one
-			rule.IpRanges = append(rule.IpRanges, "")
-		}
-		if util.ContainsValidCustomIPv4Ranges(rule.IpRanges) {
-			// if the rule is a L3 addresses in v4 family, with or without L4 port(s)
-			if err := nx.nfPermitProtoPortAddrV4(egressChain, rule); err != nil {
-				return fmt.Errorf("nftables setup error, failed to process outbound v4 rule: %w", err)
-			}
-		} else if util.ContainsValidCustomIPv6Ranges(rule.IpRanges) {
-			// if the rule is a L3 addresses in v6 family, with or without L4 port(s)
-			if err := nx.nfPermitProtoPortAddrV6(egressChain, rule); err != nil {
-				return fmt.Errorf("nftables setup error, failed to process outbound v6 rule: %w", err)
-			}
-		} else if rule.GetFromPort() != 0 && rule.GetToPort() != 0 {
-			// if the rule is L4 port(s) range with no l3 addresses
-			if err := nx.nfPermitProtoPort(egressChain, rule); err != nil {
-				return fmt.Errorf("nftables setup error, failed to process inbound destination port rule: %w", err)
-			}
-		} else {
-			// if the rule is only protocol to permit (no L4 ports or L3 addresses)
-			if err := nx.nfPermitProtoAny(egressChain, rule); err != nil {
-				return fmt.Errorf("nftables setup error, failed to process inbound destination port rule: %w", err)
-			}
-		}
-	}
+// nfSecurityGroupBaseChainRules returns the rules of a base chain that jumps to the chains of the groups
+func nfSecurityGroupBaseChainRules(iface string, baseChain string, groups int) []nfRule {
+	ifname := nfIfname{name: iface}
 
 	// the ct module provides access to the connection tracking subsystem, which tracks the state of network
 	// connections. The state keyword is used to match traffic based on its connection state, in this case as
 	// established. The established state refers to traffic that is part of an existing connection that has
 	// already been established, and where both endpoints have exchanged packets.
-	nft := []string{"insert", "rule", tableFamily, sgTableName, ingressChain, "ct", "state", "established,related", ruleInterface, "counter", "accept"}
-	if _, err := policyCmd(nx.logger, nft); err != nil {
-		return err
+	// Deny rules only apply to new connections, replies to connections that were allowed are always accepted.
+	chain := []nfRule{{nfCtEstablished{}, ifname, nfCounter{}, nfAccept}}
+	for i := 0; i < groups; i++ {
+		chain = append(chain, nfRule{ifname, nfJump(nfSecurityGroupChain(baseChain, i))})
 	}
 
-	// append a default drop that appears implicit to the user only if there are any rules in the egress chain
-	if nx.securityGroup.InboundRules != nil && len(nx.securityGroup.InboundRules) != 0 {
-		if err := nx.nfIngressRuleDrop(); err != nil {
-			return fmt.Errorf("nftables setup error, failed to add ingress drop rule: %w", err)
-		}
+	// none of the groups accepted the traffic, a direction without groups allows all traffic
+	if groups > 0 {
+		chain = append(chain, nfRule{ifname, nfCounter{}, nfDrop})
 	}
+	return chain
+}
 
-	// append a drop that appears implicit to the user only if there are any user defined rules in the egress chain
-	if nx.securityGroup.OutboundRules != nil && len(nx.securityGroup.OutboundRules) != 0 {
-		if err := nx.nfEgressRuleDrop(); err != nil {
-			return fmt.Errorf("nftables setup error, failed to add egress drop rule: %w", err)
+// nfSecurityGroupChainRules returns the rules of the chain of a security group, the ingress chains match the
+// source address and the egress chains the destination address of the security rules. Allow rules accept
+// the traffic and deny rules return to the base chain, which continues with the next group. The index of the
+// security group rule every nftables rule was created from is returned as well, -1 for the rules nexd adds
+// on its own.
+func nfSecurityGroupChainRules(logger *zap.SugaredLogger, iface string, matchDst bool, group securityGroupRules) ([]nfRule, []int) {
+	ifname := nfIfname{name: iface}
+
+	var chain []nfRule
+	var origins []int
+	for i, rule := range group.rules {
+		for _, matches := range nfSecurityRuleMatches(logger, rule, matchDst) {
+			chain = append(chain, append(matches, ifname, nfCounter{}, nftRuleAction(rule)))
+			origins = append(origins, ruleIndex(group.index, i))
 		}
 	}
 
+	// a group without allow rules accepts the traffic its deny rules did not match, otherwise the chain
+	// ends with an implicit return that does not accept the traffic.
+	if group.defaultAllow {
+		chain = append(chain, nfRule{ifname, nfCounter{}, nfAccept})
+		origins = append(origins, -1)
+	}
+
+	return chain, origins
+}
+
+// processTrafficLimits applies the traffic limits of the peers to the tunnel interface, the table is dropped
+// when no peer has limits.
+func (nx *Nexodus) processTrafficLimits(limits []peerTrafficLimit) error {
+	if len(limits) == 0 {
+		return nx.policyTableDrop(trafficLimitsTableName)
+	}
+	if err := nfApplyTable(nx.logger, nfTrafficLimitsTable(wgIface, limits)); err != nil {
+		return fmt.Errorf("nftables setup error, failed to apply the traffic limits: %w", err)
+	}
 	return nil
 }
 
-// nfPermitProtoPortAddrV4 creates a nftables rule that permits the specified rule. Example Rules handled by this method:
-// nft add rule inet nexodus nexodus-inbound meta nfproto ipv4 ip protocol icmp ip saddr 100.100.0.0/20 counter accept
-// nft add rule inet nexodus nexodus-outbound meta nfproto ipv4 ip daddr 100.100.0.1-100.100.0.100 iifname wg0 accept
-// nft add rule inet nexodus nexodus-outbound meta nfproto ipv4 ip daddr 8.8.8.8 udp dport 53 iifname "wg0" accept
-func (nx *Nexodus) nfPermitProtoPortAddrV4(chain string, rule client.ModelsSecurityRule) error {
-	var dportOption, srcOrDst string
-	var nft []string
-
-	dportOption = nx.nftPortOption(rule)
-
-	if chain == ingressChain {
-		srcOrDst = srcAddr
-	} else {
-		srcOrDst = destAddr
-	}
-	switch rule.GetIpProtocol() {
-	case protoIPv4:
-		// if the specified proto is ipv4 that specifies an L3 address and does not specify ports.
-		if rule.GetFromPort() == 0 && rule.GetToPort() == 0 {
-			for _, ipRange := range rule.IpRanges {
-				srcOrDstOption := fmt.Sprintf("ip %s %s", srcOrDst, ipRange)
-				// v4 permits for L3 src or dst
-				nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, srcOrDstOption, ruleInterface, counter, actionAccept}
-				if _, err := policyCmd(nx.logger, nft); err != nil {
-					return err
-				}
-			}
-		} else if rule.GetFromPort() != 0 && rule.GetToPort() != 0 {
-			// if the specified proto is ipv4 that specifies an L3 address and does specify ports.
-			ports := fmt.Sprintf("%d-%d", rule.GetFromPort(), rule.GetToPort())
-			if len(rule.IpRanges) > 0 {
-				for _, ipRange := range rule.IpRanges {
-					srcOrDstOption := fmt.Sprintf("ip %s %s", srcOrDst, ipRange)
-					// v4 permits for L3 src or dst with specific ports
-					nft := []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, srcOrDstOption, "th", "dport", ports, ruleInterface, counter, actionAccept}
-					if _, err := policyCmd(nx.logger, nft); err != nil {
-						return err
-					}
-				}
-			}
-		}
-	case protoTCP:
-		// permit ipv4 tcp to src/dst L3 to any destination port
-		if rule.GetFromPort() == 0 && rule.GetToPort() == 0 {
-			for _, ipRange := range rule.IpRanges {
-				srcOrDstOption := fmt.Sprintf("ip %s %s", srcOrDst, ipRange)
-				nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, srcOrDstOption, protoTCP, destPort, "0-65535", ruleInterface, "counter", actionAccept}
-				if _, err := policyCmd(nx.logger, nft); err != nil {
-					return err
-				}
+// securityGroupRuleCounters reads the counters of the rules in the nexodus table and adds them up by the
+// security group rule they were created from.
+func (nx *Nexodus) securityGroupRuleCounters() (*securityGroupCounters, error) {
+	if nx.securityGroups == nil {
+		return nil, nil
+	}
+	counters := newSecurityGroupCounters()
+	directions := []struct {
+		name     string
+		matchDst bool
+		groups   []securityGroupRules
+		counters map[int]securityRuleCounter
+	}{
+		{ingressChain, false, nx.securityRules.inbound, counters.inbound},
+		{egressChain, true, nx.securityRules.outbound, counters.outbound},
+	}
+	for _, direction := range directions {
+		for g, group := range direction.groups {
+			chainName := nfSecurityGroupChain(direction.name, g)
+			existing, err := nfChainCounters(tableFamily, sgTableName, chainName)
+			if err != nil {
+				return nil, err
 			}
-		}
-		// permit ipv4 tcp to L3 src/dst to specified destination port or port range
-		if rule.GetFromPort() != 0 && rule.GetToPort() != 0 {
-			for _, ipRange := range rule.IpRanges {
-				srcOrDstOption := fmt.Sprintf("ip %s %s", srcOrDst, ipRange)
-				nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, srcOrDstOption, protoTCP, dportOption, ruleInterface, "counter", actionAccept}
-				if _, err := policyCmd(nx.logger, nft); err != nil {
-					return err
+			rules, origins := nfSecurityGroupChainRules(nx.logger, wgIface, direction.matchDst, group)
+			for i, rule := range rules {
+				// identical rules share the comment, their counters are listed in the order of the rules
+				comment := nfRuleComment(rule.String())
+				values := existing[comment]
+				if len(values) == 0 {
+					continue
 				}
-			}
-		}
-	case protoUDP:
-		// permit ipv4 udp to src/dst L3 to any destination port
-		if rule.GetFromPort() == 0 && rule.GetToPort() == 0 {
-			for _, ipRange := range rule.IpRanges {
-				srcOrDstOption := fmt.Sprintf("ip %s %s", srcOrDst, ipRange)
-				nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, srcOrDstOption, protoUDP, destPort, "0-65535", ruleInterface, "counter", actionAccept}
-				if _, err := policyCmd(nx.logger, nft); err != nil {
-					return err
+				existing[comment] = values[1:]
+				if origins[i] < 0 {
+					continue
 				}
+				c := direction.counters[origins[i]]
+				c.packets += values[0].Packets
+				c.bytes += values[0].Bytes
+				direction.counters[origins[i]] = c
 			}
 		}
-		// permit ipv4 udp to L3 src/dst to specified destination port or port range
-		if rule.GetFromPort() != 0 && rule.GetToPort() != 0 {
-			for _, ipRange := range rule.IpRanges {
-				srcOrDstOption := fmt.Sprintf("ip %s %s", srcOrDst, ipRange)
-				nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, srcOrDstOption, rule.GetIpProtocol(), dportOption, ruleInterface, "counter", actionAccept}
-				if _, err := policyCmd(nx.logger, nft); err != nil {
-					return err
-				}
-			}
-		}
-	case protoICMP, protoICMPv4:
-		// icmpv4 permits to L3 src or dst
-		for _, ipRange := range rule.IpRanges {
-			srcOrDstOption := fmt.Sprintf("ip %s %s", srcOrDst, ipRange)
-			nft = []string{"insert", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, "ip", "protocol", protoICMP, srcOrDstOption, ruleInterface, counter, actionAccept}
-			if _, err := policyCmd(nx.logger, nft); err != nil {
-				return err
-			}
-		}
+	}
+	return &counters, nil
+}
+
+// nfSecurityRuleMatches returns the matches of each nftables rule a security rule is rendered into. Example
+// rules handled by this method:
+// meta nfproto ipv4 ip saddr 100.100.0.0/20 meta l4proto icmp iifname "wg0" counter accept
+// meta nfproto ipv4 ip daddr 100.100.0.1-100.100.0.100 iifname "wg0" counter accept
+// meta nfproto ipv6 ip6 daddr 2001:4860:4860::8888 meta l4proto udp th dport 53 iifname "wg0" counter accept
+// meta nfproto ipv4 meta l4proto icmp icmp type 8 iifname "wg0" counter drop
+// meta nfproto ipv6 meta l4proto tcp th dport 1-80 iifname "wg0" counter accept
+func nfSecurityRuleMatches(logger *zap.SugaredLogger, rule client.ModelsSecurityRule, matchDst bool) []nfRule {
+	proto := strings.ToLower(rule.GetIpProtocol())
+
+	ipRanges := rule.IpRanges
+	wildcard := len(ipRanges) == 0
+	for _, ipRange := range ipRanges {
+		if ipRange == "" {
+			wildcard = true
+		}
+	}
+
+	// the resolved rules only contain ranges of a single address family
+	var families []string
+	switch {
+	case !wildcard && isIPv4Range(ipRanges[0]):
+		families = []string{protoIPv4}
+	case !wildcard:
+		families = []string{protoIPv6}
+	case proto == protoIPv4 || proto == protoICMP || proto == protoICMPv4:
+		families = []string{protoIPv4}
+	case proto == protoIPv6 || proto == protoICMPv6:
+		families = []string{protoIPv6}
 	default:
-		nx.logger.Debugf("no match for permit proto dport rule: %v", rule)
-		return nil
+		families = []string{protoIPv4, protoIPv6}
 	}
 
-	return nil
-}
+	dport, hasPorts := nftPortOption(rule)
 
-// nfPermitProtoPortAddrV6 creates a nftables rule that permits the specified rule. Example Rules handled by this method:
-// nft add rule inet nexodus nexodus-outbound meta nfproto ipv6 ip6 daddr 2001:4860:4860::8888-2001:4860:4860::8889 udp dport 0-65535 iifname "wg0" accept
-// nft add rule inet nexodus nexodus-outbound meta nfproto ipv6 ip6 daddr 2001:4860:4860::8888-2001:4860:4860::8889  iifname "wg0" accept
-// nft add rule inet nexodus nexodus-outbound meta nfproto ipv6 ip6 daddr 2001:4860:4860::8888-2001:4860:4860::8889 udp dport 53 iifname "wg0" accept
-// nft add rule inet nexodus nexodus-inbound meta nfproto ipv6 ip6 nexthdr ipv6-icmp ip6 saddr 200::/64 counter accept
-func (nx *Nexodus) nfPermitProtoPortAddrV6(chain string, rule client.ModelsSecurityRule) error {
-	var dportOption, srcOrDst string
-	var nft []string
-
-	dportOption = nx.nftPortOption(rule)
-
-	if chain == ingressChain {
-		srcOrDst = srcAddr
-	} else {
-		srcOrDst = destAddr
-	}
-
-	switch rule.GetIpProtocol() {
-	case protoIPv6:
-		// nft add rule inet nexodus nexodus-outbound meta nfproto ipv6 ip6 daddr 2001:4860:4860::8888-2001:4860:4860::8889  iifname "wg0" accept
-		// ipv6 that specifies an L3 src/dst and does not specify ports.
-		if rule.GetFromPort() == 0 && rule.GetToPort() == 0 {
-			for _, ipRange := range rule.IpRanges {
-				srcOrDstIpAddrOption := fmt.Sprintf("ip6 %s %s", srcOrDst, ipRange)
-				nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, srcOrDstIpAddrOption, ruleInterface, counter, actionAccept}
-				if _, err := policyCmd(nx.logger, nft); err != nil {
-					return err
-				}
+	var rules []nfRule
+	for _, family := range families {
+		var protoMatches [][]nfMatch
+		switch proto {
+		case protoIPv4, protoIPv6:
+			if proto != family {
+				continue
 			}
-		} else if rule.GetFromPort() > 0 && rule.GetToPort() > 0 {
-			// ipv6 that specifies an L3 src/dst and specifies ports.
-			ports := fmt.Sprintf("%d-%d", rule.GetFromPort(), rule.GetToPort())
-			if len(rule.IpRanges) > 0 {
-				for _, ipRange := range rule.IpRanges {
-					srcOrDstIpAddrOption := fmt.Sprintf("ip6 %s %s", srcOrDst, ipRange)
-					// IPv6 permits for L3 with specified ports
-					nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, srcOrDstIpAddrOption, "th", "dport", ports, ruleInterface, counter, actionAccept}
-					if _, err := policyCmd(nx.logger, nft); err != nil {
-						return err
-					}
-				}
+			if !hasPorts {
+				protoMatches = [][]nfMatch{nil}
+				break
 			}
-		}
-	case protoTCP:
-		// permit ipv4 tcp to src/dst L3 to any destination port
-		if rule.GetFromPort() == 0 && rule.GetToPort() == 0 {
-			for _, ipRange := range rule.IpRanges {
-				srcOrDstOption := fmt.Sprintf("ip6 %s %s", srcOrDst, ipRange)
-				nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, srcOrDstOption, protoTCP, destPort, "0-65535", ruleInterface, "counter", actionAccept}
-				if _, err := policyCmd(nx.logger, nft); err != nil {
-					return err
-				}
+			// ports without a transport protocol match both tcp and udp
+			protoMatches = [][]nfMatch{
+				{nfL4proto(ipProtoTCP), dport},
+				{nfL4proto(ipProtoUDP), dport},
 			}
-		}
-		// permit ipv6 udp to L3 src/dst to specified destination port or port range
-		if rule.GetFromPort() != 0 && rule.GetToPort() != 0 {
-			for _, ipRange := range rule.IpRanges {
-				srcOrDstIpAddrOption := fmt.Sprintf("ip6 %s %s", srcOrDst, ipRange)
-				nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, srcOrDstIpAddrOption, rule.GetIpProtocol(), dportOption, ruleInterface, "counter", actionAccept}
-				if _, err := policyCmd(nx.logger, nft); err != nil {
-					return err
-				}
+		case protoTCP, protoUDP:
+			l4proto := nfL4proto(ipProtoTCP)
+			if proto == protoUDP {
+				l4proto = nfL4proto(ipProtoUDP)
 			}
-		}
-	case protoUDP:
-		// permit ipv4 udp to src/dst L3 to any destination port
-		if rule.GetFromPort() == 0 && rule.GetToPort() == 0 {
-			for _, ipRange := range rule.IpRanges {
-				srcOrDstOption := fmt.Sprintf("ip6 %s %s", srcOrDst, ipRange)
-				nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, srcOrDstOption, protoUDP, destPort, "0-65535", ruleInterface, "counter", actionAccept}
-				if _, err := policyCmd(nx.logger, nft); err != nil {
-					return err
-				}
+			if hasPorts {
+				protoMatches = [][]nfMatch{{l4proto, dport}}
+			} else {
+				protoMatches = [][]nfMatch{{l4proto}}
 			}
-		}
-		// permit ipv4 udp to L3 src/dst to specified destination port or port range
-		if rule.GetFromPort() != 0 && rule.GetToPort() != 0 {
-			for _, ipRange := range rule.IpRanges {
-				srcOrDstIpAddrOption := fmt.Sprintf("ip6 %s %s", srcOrDst, ipRange)
-				nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, srcOrDstIpAddrOption, protoUDP, dportOption, ruleInterface, "counter", actionAccept}
-				if _, err := policyCmd(nx.logger, nft); err != nil {
-					return err
-				}
+		case protoICMP, protoICMPv4, protoICMPv6:
+			if family == protoIPv4 && proto == protoICMPv6 || family == protoIPv6 && proto == protoICMPv4 {
+				continue
 			}
-		}
-	case protoICMP, protoICMPv6:
-		// icmpv4 permits to L3 src or dst
-		for _, ipRange := range rule.IpRanges {
-			srcOrDstIpAddrOption := fmt.Sprintf("ip6 %s %s", srcOrDst, ipRange)
-			nft = []string{"insert", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, "ip6", "nexthdr", "ipv6-icmp", srcOrDstIpAddrOption, ruleInterface, counter, actionAccept}
-			if _, err := policyCmd(nx.logger, nft); err != nil {
-				return err
+			// icmp types are ICMPv4 types, an icmp rule with a type does not match ICMPv6
+			if family == protoIPv6 && proto == protoICMP && rule.HasIcmpType() {
+				continue
 			}
-		}
-	default:
-		nx.logger.Debugf("no match for permit proto dport rule: %v", rule)
-		return nil
-	}
-
-	return nil
-}
-
-// nfPermitProtoPort creates a nftables rule that permits the specified rule. Example Rules handled by this method:
-// nft add rule inet nexodus nexodus-inbound meta nfproto ipv4 iifname "wg0" tcp dport 1-80 counter accept
-// nft add rule inet nexodus nexodus-inbound meta nfproto ipv6 iifname "wg0" tcp dport 1-80 counter accept
-func (nx *Nexodus) nfPermitProtoPort(chain string, rule client.ModelsSecurityRule) error {
-	var dportOption string
-	var nft []string
-	dportOption = nx.nftPortOption(rule)
-	switch rule.GetIpProtocol() {
-	case protoIPv4:
-		// if the specified proto is ipv4, add rules for both tcp and udp to the chain with the specified dport
-		if dportOption == "" {
-			return nil
-		}
-		// tcp permits for ports to the specified dport for v4/v6
-		nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, protoTCP, dportOption, ruleInterface, counter, actionAccept}
-		if _, err := policyCmd(nx.logger, nft); err != nil {
-			return err
-		}
-		// udp permits for ports to the specified dport for v4/v6
-		nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, protoUDP, dportOption, ruleInterface, counter, actionAccept}
-		if _, err := policyCmd(nx.logger, nft); err != nil {
-			return err
-		}
-	case protoIPv6:
-		// if the specified proto is ipv6, add rules for both tcp and udp to the chain with the specified dport
-		if dportOption == "" {
-			return nil
-		}
-		nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, protoTCP, dportOption, ruleInterface, counter, actionAccept}
-		if _, err := policyCmd(nx.logger, nft); err != nil {
-			return err
-		}
-		nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, protoUDP, dportOption, ruleInterface, counter, actionAccept}
-		if _, err := policyCmd(nx.logger, nft); err != nil {
-			return err
-
-		}
-	case protoUDP, protoTCP:
-		// if the specified proto is tcp or udp, add rules for both ipv4 and ipv6 to the chain with the specified dport
-		if dportOption == "" {
+			matches := []nfMatch{nfL4proto(ipProtoICMP)}
+			if family == protoIPv6 {
+				matches = []nfMatch{nfL4proto(ipProtoICMPv6)}
+			}
+			if icmp, ok := nftIcmpOption(family == protoIPv6, rule); ok {
+				matches = append(matches, icmp)
+			}
+			protoMatches = [][]nfMatch{matches}
+		default:
+			logger.Debugf("no match for security rule protocol: %v", rule)
 			return nil
 		}
-		nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, rule.GetIpProtocol(), dportOption, ruleInterface, counter, actionAccept}
-		if _, err := policyCmd(nx.logger, nft); err != nil {
-			return err
-		}
-		nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, rule.GetIpProtocol(), dportOption, ruleInterface, counter, actionAccept}
-		if _, err := policyCmd(nx.logger, nft); err != nil {
-			return err
-		}
-	default:
-		nx.logger.Debugf("no match for permit proto dport rule: %v", rule)
-		return nil
-	}
-
-	return nil
-}
 
-// nfPermitProtoAny creates a nftables rule that permits the specified rule. Example Rules handled by this method:
-// nft insert rule inet nexodus nexodus-outbound meta nfproto ipv4  iifname "wg0" counter accept
-// nft insert rule inet nexodus nexodus-outbound meta nfproto ipv6  iifname "wg0" counter accept
-// nft add rule inet nexodus nexodus-inbound meta nfproto ipv4 tcp dport 0-65535 iifname "wg0" counter accept
-// nft add rule inet nexodus nexodus-inbound meta nfproto ipv6 tcp dport 0-65535  iifname "wg0" counter accept
-func (nx *Nexodus) nfPermitProtoAny(chain string, rule client.ModelsSecurityRule) error {
-	var nft []string
-	switch rule.GetIpProtocol() {
-	case protoIPv4, protoIPv6:
-		// permit ipv4 any
-		if rule.GetIpProtocol() == protoIPv4 {
-			nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", rule.GetIpProtocol(), ruleInterface, counter, actionAccept}
-			if _, err := policyCmd(nx.logger, nft); err != nil {
-				return err
-			}
+		addrFamily := "ip"
+		if family == protoIPv6 {
+			addrFamily = "ip6"
 		}
-		// permit ipv6 any
-		if rule.GetIpProtocol() == protoIPv6 {
-			nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", rule.GetIpProtocol(), ruleInterface, counter, actionAccept}
-			if _, err := policyCmd(nx.logger, nft); err != nil {
-				return err
+		addrMatches := []nfMatch{nil}
+		if !wildcard {
+			addrMatches = nil
+			for _, ipRange := range ipRanges {
+				addrMatches = append(addrMatches, nfAddr{family: addrFamily, dst: matchDst, value: ipRange})
 			}
 		}
 
-	case "icmp", protoICMPv4, protoICMPv6:
-		// permit icmpv4 any
-		if rule.GetIpProtocol() == protoICMPv4 || rule.GetIpProtocol() == "icmp" {
-			nft = []string{"insert", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, "ip", "protocol", protoICMP, ruleInterface, counter, actionAccept}
-			if _, err := policyCmd(nx.logger, nft); err != nil {
-				return err
-			}
-		}
-		// permit icmpv6 any
-		if rule.GetIpProtocol() == protoICMPv6 {
-			// ip6 nexthdr is used instead of ip6 protocol for IPv6, because the protocol field is not directly in the IPv6 header.
-			nft = []string{"insert", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, "ip6", "nexthdr", "ipv6-icmp", ruleInterface, counter, actionAccept}
-			if _, err := policyCmd(nx.logger, nft); err != nil {
-				return err
+		for _, addr := range addrMatches {
+			for _, matches := range protoMatches {
+				r := nfRule{nfNfproto(family)}
+				if addr != nil {
+					r = append(r, addr)
+				}
+				rules = append(rules, append(r, matches...))
 			}
 		}
-	case protoTCP, protoUDP:
-		// permit ip/ip6 tcp or udp any to all ports
-		nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv4, rule.GetIpProtocol(), destPort, "0-65535", ruleInterface, counter, actionAccept}
-		if _, err := policyCmd(nx.logger, nft); err != nil {
-			return err
-		}
-		// permit ipv6 tcp or udp any
-		nft = []string{"add", "rule", tableFamily, sgTableName, chain, "meta", "nfproto", protoIPv6, rule.GetIpProtocol(), destPort, "0-65535", ruleInterface, counter, actionAccept}
-		if _, err := policyCmd(nx.logger, nft); err != nil {
-			return err
-		}
-	default:
-		nx.logger.Debugf("no match for permit proto any dport rule: %v", rule)
-		return nil
-	}
-
-	return nil
-}
-
-// nftPortOption returns the nftables port option for the specified rule.
-func (nx *Nexodus) nftPortOption(rule client.ModelsSecurityRule) string {
-	var portOption string
-	var portRange string
-
-	if rule.GetFromPort() == 0 && rule.GetToPort() == 0 {
-		portRange = fmt.Sprintf("%d-%d", 0, 65535)
-	} else if rule.GetFromPort() == rule.GetToPort() {
-		portRange = fmt.Sprintf("%d", rule.GetFromPort())
-	} else {
-		portRange = fmt.Sprintf("%d-%d", rule.GetFromPort(), rule.GetToPort())
 	}
-	portOption = fmt.Sprintf("%s %s", destPort, portRange)
 
-	return portOption
+	return rules
 }
 
-// nfIngressRuleDrop is used to append a drop rule to the ingress chain. Example rule handled by this method:
-func (nx *Nexodus) nfIngressRuleDrop() error {
-	nft := []string{"add", "rule", tableFamily, sgTableName, ingressChain, ruleInterface, "counter", actionDrop}
-	if _, err := policyCmd(nx.logger, nft); err != nil {
-		return err
+// nftPortOption returns the destination port match of the rule, false is returned if the rule matches any port.
+func nftPortOption(rule client.ModelsSecurityRule) (nfDport, bool) {
+	from, to := rule.GetFromPort(), rule.GetToPort()
+	if to < from {
+		to = from
 	}
-
-	return nil
-}
-
-// nfEgressRuleDrop is used to append a drop rule to the egress chain
-func (nx *Nexodus) nfEgressRuleDrop() error {
-	nft := []string{"add", "rule", tableFamily, sgTableName, egressChain, ruleInterface, "counter", actionDrop}
-	if _, err := policyCmd(nx.logger, nft); err != nil {
-		return err
+	if to <= 0 || (from <= 0 && to >= 65535) {
+		return nfDport{}, false
 	}
-
-	return nil
-}
-
-// policyTableDrop is used to delete the nftables table if it exists
-func (nx *Nexodus) policyTableDrop(table string) error {
-	// First, check if the table exists
-	exists, err := nx.nfTableExists(table)
-	if err != nil {
-		return err
+	if from < 0 {
+		from = 0
 	}
-
-	if !exists {
-		return nil
+	if to > 65535 {
+		to = 65535
 	}
-
-	// If the table exists, proceed with deletion
-	nft := []string{"delete", "table", tableFamily, table}
-	if _, err := policyCmd(nx.logger, nft); err != nil {
-		return err
-	}
-
-	return nil
+	return nfDport{from: uint16(from), to: uint16(to)}, true
 }
 
-func (nx *Nexodus) nfTableExists(table string) (bool, error) {
-	args := []string{"list", "tables"}
-	output, err := policyCmd(nx.logger, args)
-	if err != nil {
-		return false, err
+// nftRuleAction returns the nftables verdict of the rule in the chain of its security group, a deny rule
+// returns to the base chain so that the other groups of the device can still accept the traffic.
+func nftRuleAction(rule client.ModelsSecurityRule) nfVerdict {
+	if securityRuleDenies(rule) {
+		return nfReturn
 	}
-
-	tableFullName := fmt.Sprintf("table %s %s", tableFamily, table)
-
-	// Split the output into lines
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		// Trim spaces and check for an exact match
-		if strings.TrimSpace(line) == tableFullName {
-			return true, nil
-		}
-	}
-
-	return false, nil
+	return nfAccept
 }
 
-// nfCreateTable is used to create the nftables table
-func (nx *Nexodus) nfCreateTable(table string) error {
-	if _, err := policyCmd(nx.logger, []string{"add", "table", tableFamily, table}); err != nil {
-		return err
+// nftIcmpOption returns the icmp type and code match of the rule, false is returned if the rule matches
+// all icmp messages.
+func nftIcmpOption(v6 bool, rule client.ModelsSecurityRule) (nfIcmp, bool) {
+	if !rule.HasIcmpType() {
+		return nfIcmp{}, false
 	}
-
-	return nil
+	icmp := nfIcmp{v6: v6, icmpType: uint8(rule.GetIcmpType())}
+	if rule.HasIcmpCode() {
+		icmp.hasCode = true
+		icmp.code = uint8(rule.GetIcmpCode())
+	}
+	return icmp, true
 }
 
-// nfCreateChain is used to create the nftables chain in the nf table
-func (nx *Nexodus) nfCreateChain(chainName string) error {
-	if _, err := policyCmd(nx.logger, []string{"add", "chain", tableFamily, sgTableName, chainName, "{", "type", "filter", "hook", "input", "priority", "0", ";", "policy", "accept", ";", "}"}); err != nil {
+// policyTableDrop is used to delete the nftables table if it exists
+func (nx *Nexodus) policyTableDrop(table string) error {
+	if err := nfDeleteTable(tableFamily, table); err != nil {
 		return err
 	}
+	nx.logger.Debugf("nftables: deleted table %s %s", tableFamily, table)
 
 	return nil
 }
 
-// policyCmd is used to execute nft commands
-func policyCmd(logger *zap.SugaredLogger, cmd []string) (string, error) {
-	nft := exec.Command("nft", cmd...)
-
-	output, err := nft.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("nft command: nft %q failed: %w\n", strings.Join(cmd, " "), err)
-	}
-	logger.Debugf("nft command: nft %s", strings.Join(cmd, " "))
-
-	return string(output), nil
-}
-
-func debugSecurityGroupRules(logger *zap.SugaredLogger, inboundRules, outboundRules []client.ModelsSecurityRule) error {
-	inJson, err := json.MarshalIndent(inboundRules, "", "  ")
+func debugSecurityGroupRules(logger *zap.SugaredLogger, inboundRules, outboundRules []securityGroupRules) error {
+	inJson, err := json.MarshalIndent(debugGroupRules(inboundRules), "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to print debug json inbound rules: %w", err)
 	}
 	logger.Debugf("\nInboundRules:\n %s\n", inJson)
 
-	outJson, err := json.MarshalIndent(outboundRules, "", "  ")
+	outJson, err := json.MarshalIndent(debugGroupRules(outboundRules), "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to print debug json inbound rules: %w", err)
 	}
@@ -618,60 +383,54 @@ func debugSecurityGroupRules(logger *zap.SugaredLogger, inboundRules, outboundRu
 	return nil
 }
 
-// networkRouterSetup set up the v4/v6 nftables rules for a network router node
-func (nx *Nexodus) networkRouterSetup() error {
-
-	// Drop the existing table if one exists from previous runs
-	if err := nx.policyTableDrop(rtrTableName); err != nil {
-		return fmt.Errorf("nftables router setup error, failed to flush nftables: %w", err)
-	}
-
-	// Create the net router table
-	if err := nx.nfCreateTable(rtrTableName); err != nil {
-		return fmt.Errorf("nftables setup error, failed to create nftables inet table: %w", err)
-	}
-
-	// Create the prerouting nftables chain
-	if err := nx.rtrCreateChain(chainPrerouting, chainTypeNAT, priorityDstNAT); err != nil {
-		return fmt.Errorf("nftables setup error, failed to create network router nftables chain %s: %w", chainPrerouting, err)
-	}
-
-	// Create the postrouting nftables chain
-	if err := nx.rtrCreateChain(chainPostrouting, chainTypeNAT, prioritySrcNAT); err != nil {
-		return fmt.Errorf("nftables setup error, failed to create network router nftables chain %s: %w", chainTypeNAT, err)
+// debugGroupRules returns the resolved rules of every security group for the debug log
+func debugGroupRules(groups []securityGroupRules) [][]client.ModelsSecurityRule {
+	rules := make([][]client.ModelsSecurityRule, len(groups))
+	for i, group := range groups {
+		rules[i] = group.rules
 	}
+	return rules
+}
 
-	// Create the forward nftables chain
-	if err := nx.rtrCreateChain(chainForward, chainTypeFilter, priorityFilter); err != nil {
-		return fmt.Errorf("nftables setup error, failed to create network router nftables chain %s: %w", chainTypeFilter, err)
+// networkRouterSetup set up the v4/v6 nftables rules for a network router node
+func (nx *Nexodus) networkRouterSetup() error {
+	prefixes := make([]string, 0, len(nx.netRouterInterfaceMap))
+	for prefix := range nx.netRouterInterfaceMap {
+		prefixes = append(prefixes, prefix)
 	}
+	// keep the rule order stable so that only the changed prefixes are updated
+	sort.Strings(prefixes)
 
 	// Create the forwarding rule with a prefix and oifname interface for each destination prefix
-	for prefix, iface := range nx.netRouterInterfaceMap {
+	var forwardRules, postroutingRules []nfRule
+	masqueraded := map[string]bool{}
+	for _, prefix := range prefixes {
+		iface := nx.netRouterInterfaceMap[prefix]
 		nx.logger.Debugf("Adding nftables forwarding rule for prefix: %s on interface: %s", prefix, iface.Name)
-		nft := []string{"add", "rule", tableFamily, rtrTableName, chainForward, "oifname", iface.Name, "ip", destAddr, prefix, counter, "accept"}
-		if _, err := policyCmd(nx.logger, nft); err != nil {
-			return err
+		addrFamily := "ip"
+		if !isIPv4Range(prefix) {
+			addrFamily = "ip6"
 		}
-	}
+		forwardRules = append(forwardRules, nfRule{nfIfname{output: true, name: iface.Name}, nfAddr{family: addrFamily, dst: true, value: prefix}, nfCounter{}, nfAccept})
 
-	// If --disable-snat was not passed, add a masquerade rule to the postrouting chain,
-	if !nx.networkRouterDisableNAT {
-		for _, iface := range nx.netRouterInterfaceMap {
-			nft := []string{"add", "rule", tableFamily, rtrTableName, chainPostrouting, "oifname", iface.Name, counter, "masquerade"}
-			if _, err := policyCmd(nx.logger, nft); err != nil {
-				return err
-			}
+		// If --disable-snat was not passed, add a masquerade rule to the postrouting chain,
+		if !nx.networkRouterDisableNAT && !masqueraded[iface.Name] {
+			masqueraded[iface.Name] = true
+			postroutingRules = append(postroutingRules, nfRule{nfIfname{output: true, name: iface.Name}, nfCounter{}, nfMasquerade{}})
 		}
 	}
 
-	return nil
-}
-
-// rtrCreateChain is used to create the nftables net-router chain in the nf table
-func (nx *Nexodus) rtrCreateChain(chainName, chainType, chainPriority string) error {
-	if _, err := policyCmd(nx.logger, []string{"add", "chain", tableFamily, rtrTableName, chainName, "{", "type", chainType, "hook", chainName, "priority", chainPriority, ";", "}"}); err != nil {
-		return err
+	table := nfTable{
+		family: tableFamily,
+		name:   rtrTableName,
+		chains: []nfChain{
+			{name: chainPrerouting, chainType: nfChainTypeNAT, hook: nfHookPrerouting, priority: nfPriorityDstNAT},
+			{name: chainPostrouting, chainType: nfChainTypeNAT, hook: nfHookPostrouting, priority: nfPrioritySrcNAT, rules: postroutingRules},
+			{name: chainForward, chainType: nfChainTypeFilter, hook: nfHookForward, priority: nfPriorityFilter, rules: forwardRules},
+		},
+	}
+	if err := nfApplyTable(nx.logger, table); err != nil {
+		return fmt.Errorf("nftables router setup error: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,117 @@
+package nexodus
+
+import (
+	"testing"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSecurityGroupTable(t *testing.T) {
+	require := require.New(t)
+
+	rules := securityRules{
+		inbound: []securityGroupRules{resolveGroupSecurityRules([]client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("tcp"), FromPort: client.PtrInt32(22), ToPort: client.PtrInt32(22), IpRanges: []string{"100.64.0.3"}, Action: client.PtrString(securityRuleActionDeny)},
+			{IpProtocol: client.PtrString("tcp"), FromPort: client.PtrInt32(22), ToPort: client.PtrInt32(22), IpRanges: []string{"100.64.0.0/16", "200::/64"}},
+			{IpProtocol: client.PtrString("icmp"), IcmpType: client.PtrInt32(8), IcmpCode: client.PtrInt32(0)},
+			{IpProtocol: client.PtrString("ipv6"), FromPort: client.PtrInt32(80), ToPort: client.PtrInt32(90)},
+		}, nil, 0)},
+		outbound: []securityGroupRules{resolveGroupSecurityRules([]client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("udp"), FromPort: client.PtrInt32(53), ToPort: client.PtrInt32(53), Action: client.PtrString(securityRuleActionDeny)},
+			{IpProtocol: client.PtrString("ipv4"), IpRanges: []string{"10.0.0.1-10.0.0.9"}, Action: client.PtrString(securityRuleActionDeny)},
+		}, nil, 0)},
+	}
+
+	table := nfSecurityGroupTable(zap.NewNop().Sugar(), "wg0", rules)
+	require.Equal(tableFamily, table.family)
+	require.Equal(sgTableName, table.name)
+	require.Len(table.chains, 4)
+
+	chainRules := func(chain nfChain) []string {
+		var result []string
+		for _, rule := range chain.rules {
+			result = append(result, rule.String())
+		}
+		return result
+	}
+
+	// the chains of the groups are regular chains that are created before the base chains
+	require.Equal("nexodus-inbound-0", table.chains[0].name)
+	require.Empty(table.chains[0].hook)
+	require.Equal([]string{
+		`meta nfproto ipv4 ip saddr 100.64.0.3 meta l4proto tcp th dport 22 iifname "wg0" counter return`,
+		`meta nfproto ipv4 ip saddr 100.64.0.0/16 meta l4proto tcp th dport 22 iifname "wg0" counter accept`,
+		`meta nfproto ipv6 ip6 saddr 200::/64 meta l4proto tcp th dport 22 iifname "wg0" counter accept`,
+		`meta nfproto ipv4 meta l4proto icmp icmp type 8 icmp code 0 iifname "wg0" counter accept`,
+		`meta nfproto ipv6 meta l4proto tcp th dport 80-90 iifname "wg0" counter accept`,
+		`meta nfproto ipv6 meta l4proto udp th dport 80-90 iifname "wg0" counter accept`,
+	}, chainRules(table.chains[0]))
+
+	// the outbound group only has deny rules and accepts everything else
+	require.Equal("nexodus-outbound-0", table.chains[1].name)
+	require.Equal([]string{
+		`meta nfproto ipv4 meta l4proto udp th dport 53 iifname "wg0" counter return`,
+		`meta nfproto ipv6 meta l4proto udp th dport 53 iifname "wg0" counter return`,
+		`meta nfproto ipv4 ip daddr 10.0.0.1-10.0.0.9 iifname "wg0" counter return`,
+		`iifname "wg0" counter accept`,
+	}, chainRules(table.chains[1]))
+
+	// the base chains drop the traffic none of the groups accepted
+	require.Equal(ingressChain, table.chains[2].name)
+	require.Equal(nfHookInput, table.chains[2].hook)
+	require.Equal([]string{
+		`ct state established,related iifname "wg0" counter accept`,
+		`iifname "wg0" jump nexodus-inbound-0`,
+		`iifname "wg0" counter drop`,
+	}, chainRules(table.chains[2]))
+	require.Equal(egressChain, table.chains[3].name)
+	require.Equal([]string{
+		`ct state established,related iifname "wg0" counter accept`,
+		`iifname "wg0" jump nexodus-outbound-0`,
+		`iifname "wg0" counter drop`,
+	}, chainRules(table.chains[3]))
+
+	// a device in several groups is allowed the traffic any of the groups allows, the empty default
+	// group accepts all the traffic the db group does not
+	nx := &Nexodus{deviceCache: map[string]deviceCacheEntry{}}
+	union := nfSecurityGroupTable(zap.NewNop().Sugar(), "wg0", nx.resolveSecurityGroupRules([]client.ModelsSecurityGroup{
+		{Id: client.PtrString("default")},
+		{Id: client.PtrString("db"), InboundRules: []client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("tcp"), FromPort: client.PtrInt32(5432), ToPort: client.PtrInt32(5432)},
+		}},
+	}))
+	require.Len(union.chains, 6)
+	require.Equal([]string{`iifname "wg0" counter accept`}, chainRules(union.chains[0]))
+	require.Equal([]string{`meta nfproto ipv4 meta l4proto tcp th dport 5432 iifname "wg0" counter accept`,
+		`meta nfproto ipv6 meta l4proto tcp th dport 5432 iifname "wg0" counter accept`}, chainRules(union.chains[1]))
+	require.Equal([]string{
+		`ct state established,related iifname "wg0" counter accept`,
+		`iifname "wg0" jump nexodus-inbound-0`,
+		`iifname "wg0" jump nexodus-inbound-1`,
+		`iifname "wg0" counter drop`,
+	}, chainRules(union.chains[4]))
+
+	// the nftables rules map back to the security group rule they were created from
+	inbound := resolveGroupSecurityRules([]client.ModelsSecurityRule{
+		{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(20), IpRanges: []string{"100.64.0.0/16", "200::/64"}},
+		{IpProtocol: client.PtrString("ipv6"), Priority: client.PtrInt32(10), FromPort: client.PtrInt32(80), ToPort: client.PtrInt32(90)},
+	}, nil, 3)
+	_, origins := nfSecurityGroupChainRules(zap.NewNop().Sugar(), "wg0", false, inbound)
+	require.Equal([]int{4, 4, 3, 3}, origins)
+
+	// all the rules compile to netlink expressions
+	for _, chain := range table.chains {
+		for _, rule := range chain.rules {
+			exprs, err := nfRuleExprs(rule)
+			require.NoError(err, rule.String())
+			require.NotEmpty(exprs)
+		}
+	}
+
+	_, err := nfRuleExprs(nfRule{nfAddr{family: "ip", value: "200::1"}})
+	require.Error(err)
+	_, err = nfRuleExprs(nfRule{nfAddr{family: "ip6", value: "200::/64"}, nfDport{from: 1, to: 80}, nfMarkSet(oobFwdMark), nfMasquerade{}})
+	require.NoError(err)
+}
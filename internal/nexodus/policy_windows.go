@@ -3,6 +3,8 @@
 package nexodus
 
 import (
+	"fmt"
+
 	"go.uber.org/zap"
 )
 
@@ -16,12 +18,25 @@ func (nx *Nexodus) networkRouterSetup() error {
 	return nil
 }
 
-// policyCmd for windows build purposes
-func policyCmd(logger *zap.SugaredLogger, cmd []string) (string, error) {
-	return "", nil
+// securityGroupRuleCounters for windows build purposes, rule counters are only reported in userspace mode on windows
+func (nx *Nexodus) securityGroupRuleCounters() (*securityGroupCounters, error) {
+	return nil, nil
+}
+
+// processTrafficLimits traffic limits are only enforced on linux and in userspace mode
+func (nx *Nexodus) processTrafficLimits(limits []peerTrafficLimit) error {
+	if len(limits) > 0 {
+		nx.logger.Warn("Traffic limits of the peers are only enforced on Linux and in userspace proxy mode")
+	}
+	return nil
 }
 
 // policyTableDrop for windows build purposes
 func (nx *Nexodus) policyTableDrop(table string) error {
 	return nil
 }
+
+// nfApplyTable for windows build purposes, nftables are only available on linux
+func nfApplyTable(logger *zap.SugaredLogger, table nfTable) error {
+	return fmt.Errorf("nftables table %s %s is not supported on windows", table.family, table.name)
+}
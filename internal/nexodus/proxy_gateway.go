@@ -0,0 +1,275 @@
+package nexodus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/util"
+)
+
+// gatewayHandshakeTimeout bounds the time a gateway client has to send its request and the time to connect to the destination
+const gatewayHandshakeTimeout = 10 * time.Second
+
+// SOCKS5 protocol values, see RFC 1928
+const (
+	socks5Version              = 0x05
+	socks5MethodNoAuth         = 0x00
+	socks5MethodNoAcceptable   = 0xff
+	socks5CmdConnect           = 0x01
+	socks5AddrIPv4             = 0x01
+	socks5AddrDomain           = 0x03
+	socks5AddrIPv6             = 0x04
+	socks5ReplySucceeded       = 0x00
+	socks5ReplyHostUnreach     = 0x04
+	socks5ReplyCmdUnsupported  = 0x07
+	socks5ReplyAddrUnsupported = 0x08
+)
+
+// gatewayDialer connects to a destination address in the form host:port
+type gatewayDialer func(ctx context.Context, address string) (net.Conn, error)
+
+// gatewayServerStart serves SOCKS5 and HTTP CONNECT on the --gateway-listen address until the context is done.
+// The clients reach any tunnel IP or peer hostname of the VPC without a proxy rule per destination.
+func (nx *Nexodus) gatewayServerStart(ctx context.Context, wg *sync.WaitGroup) error {
+	address, err := gatewayListenAddress(nx.gatewayListen)
+	if err != nil {
+		return err
+	}
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	if addr, ok := l.Addr().(*net.TCPAddr); ok && !addr.IP.IsLoopback() {
+		nx.logger.Warnf("The gateway on %s does not authenticate its clients, anyone able to connect to it reaches the VPC as this device", l.Addr())
+	}
+
+	util.GoWithWaitGroup(wg, func() {
+		<-ctx.Done()
+		util.IgnoreError(l.Close)
+	})
+	util.GoWithWaitGroup(wg, func() {
+		nx.logger.Infof("Serving the SOCKS5 and HTTP CONNECT gateway on %s", l.Addr())
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				if ctx.Err() == nil {
+					nx.logger.Errorf("gateway listener failed: %v", err)
+				}
+				return
+			}
+			util.GoWithWaitGroup(wg, func() {
+				err := serveGatewayConn(ctx, conn, nx.gatewayDial)
+				nx.logger.Debugf("Gateway connection from %s closed: %v", conn.RemoteAddr(), err)
+			})
+		}
+	})
+	return nil
+}
+
+// gatewayDial connects to the address through the userspace network, peer hostnames are resolved to their tunnel IPs
+func (nx *Nexodus) gatewayDial(ctx context.Context, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	ip, err := nx.proxyResolver.resolve(ctx, host, true)
+	if err != nil {
+		return nil, err
+	}
+	return nx.userspaceNet.DialContext(ctx, "tcp", net.JoinHostPort(ip.String(), port))
+}
+
+// serveGatewayConn reads the SOCKS5 or HTTP CONNECT request of the client, SOCKS5 requests start with the version
+// byte while HTTP ones start with the method name, then relays the data to the destination until both sides are done.
+func serveGatewayConn(ctx context.Context, conn net.Conn, dial gatewayDialer) error {
+	defer util.IgnoreError(conn.Close)
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	_ = conn.SetDeadline(time.Now().Add(gatewayHandshakeTimeout))
+	dialCtx, cancel := context.WithTimeout(ctx, gatewayHandshakeTimeout)
+	defer cancel()
+
+	reader := bufio.NewReader(conn)
+	first, err := reader.Peek(1)
+	if err != nil {
+		return err
+	}
+	var outConn net.Conn
+	if first[0] == socks5Version {
+		outConn, err = socks5Handshake(dialCtx, reader, conn, dial)
+	} else {
+		outConn, err = httpConnectHandshake(dialCtx, reader, conn, dial)
+	}
+	if err != nil {
+		return err
+	}
+	defer util.IgnoreError(outConn.Close)
+	_ = conn.SetDeadline(time.Time{})
+
+	// the reader holds anything the client sent right after its request
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(conn, outConn)
+		closeWrite(conn)
+		close(done)
+	}()
+	_, _ = io.Copy(outConn, reader)
+	closeWrite(outConn)
+	<-done
+	return nil
+}
+
+// closeWrite signals the end of the data sent on the connection, the connection is closed when it can not be half closed
+func closeWrite(conn net.Conn) {
+	if c, ok := conn.(interface{ CloseWrite() error }); ok {
+		_ = c.CloseWrite()
+		return
+	}
+	_ = conn.Close()
+}
+
+// gatewayListenAddress binds the gateway to the loopback address when the listen address is only a port,
+// the gateway does not authenticate its clients so it is not exposed to the network unless asked to.
+func gatewayListenAddress(listen string) (string, error) {
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return "", fmt.Errorf("invalid gateway listen address %q: %w", listen, err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// socks5Handshake negotiates a SOCKS5 session without authentication and connects to the destination of the CONNECT request
+func socks5Handshake(ctx context.Context, reader *bufio.Reader, conn net.Conn, dial gatewayDialer) (net.Conn, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(reader, methods); err != nil {
+		return nil, err
+	}
+	if !bytes.Contains(methods, []byte{socks5MethodNoAuth}) {
+		_, _ = conn.Write([]byte{socks5Version, socks5MethodNoAcceptable})
+		return nil, fmt.Errorf("the SOCKS5 client does not support connecting without authentication")
+	}
+	if _, err := conn.Write([]byte{socks5Version, socks5MethodNoAuth}); err != nil {
+		return nil, err
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(reader, request); err != nil {
+		return nil, err
+	}
+	if request[0] != socks5Version {
+		return nil, fmt.Errorf("unsupported SOCKS version %d", request[0])
+	}
+	var host string
+	switch request[3] {
+	case socks5AddrIPv4, socks5AddrIPv6:
+		ip := make([]byte, net.IPv4len)
+		if request[3] == socks5AddrIPv6 {
+			ip = make([]byte, net.IPv6len)
+		}
+		if _, err := io.ReadFull(reader, ip); err != nil {
+			return nil, err
+		}
+		host = net.IP(ip).String()
+	case socks5AddrDomain:
+		length, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		domain := make([]byte, length)
+		if _, err := io.ReadFull(reader, domain); err != nil {
+			return nil, err
+		}
+		host = string(domain)
+	default:
+		_ = socks5Reply(conn, socks5ReplyAddrUnsupported, nil)
+		return nil, fmt.Errorf("unsupported SOCKS5 address type %d", request[3])
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(reader, port); err != nil {
+		return nil, err
+	}
+	if request[1] != socks5CmdConnect {
+		_ = socks5Reply(conn, socks5ReplyCmdUnsupported, nil)
+		return nil, fmt.Errorf("unsupported SOCKS5 command %d, only CONNECT is proxied", request[1])
+	}
+
+	outConn, err := dial(ctx, net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+	if err != nil {
+		_ = socks5Reply(conn, socks5ReplyHostUnreach, nil)
+		return nil, err
+	}
+	if err := socks5Reply(conn, socks5ReplySucceeded, outConn.LocalAddr()); err != nil {
+		util.IgnoreError(outConn.Close)
+		return nil, err
+	}
+	return outConn, nil
+}
+
+// socks5Reply sends the reply to the SOCKS5 request with the address the gateway connected from
+func socks5Reply(conn net.Conn, reply byte, bound net.Addr) error {
+	ip := net.IPv4zero
+	port := 0
+	if addr, ok := bound.(*net.TCPAddr); ok {
+		ip = addr.IP
+		port = addr.Port
+	}
+	message := []byte{socks5Version, reply, 0x00}
+	if ip4 := ip.To4(); ip4 != nil {
+		message = append(append(message, socks5AddrIPv4), ip4...)
+	} else {
+		message = append(append(message, socks5AddrIPv6), ip.To16()...)
+	}
+	message = binary.BigEndian.AppendUint16(message, uint16(port))
+	_, err := conn.Write(message)
+	return err
+}
+
+// httpConnectHandshake connects to the destination of an HTTP CONNECT request, other methods are refused
+func httpConnectHandshake(ctx context.Context, reader *bufio.Reader, conn net.Conn, dial gatewayDialer) (net.Conn, error) {
+	request, err := http.ReadRequest(reader)
+	if err != nil {
+		return nil, err
+	}
+	if request.Method != http.MethodConnect {
+		writeGatewayResponse(conn, http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("unsupported %s request, only CONNECT is proxied", request.Method)
+	}
+	if _, _, err := net.SplitHostPort(request.Host); err != nil {
+		writeGatewayResponse(conn, http.StatusBadRequest)
+		return nil, fmt.Errorf("invalid CONNECT destination %q: %w", request.Host, err)
+	}
+
+	outConn, err := dial(ctx, request.Host)
+	if err != nil {
+		writeGatewayResponse(conn, http.StatusBadGateway)
+		return nil, err
+	}
+	if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		util.IgnoreError(outConn.Close)
+		return nil, err
+	}
+	return outConn, nil
+}
+
+func writeGatewayResponse(conn net.Conn, status int) {
+	_, _ = fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\nContent-Length: 0\r\nConnection: close\r\n\r\n", status, http.StatusText(status))
+}
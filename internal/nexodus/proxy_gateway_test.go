@@ -0,0 +1,151 @@
+package nexodus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testGateway serves a gateway connection with a dialer reaching an echo server, the addresses dialed are sent on the channel
+func testGateway(t *testing.T) (net.Conn, chan string) {
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = echo.Close() })
+	go func() {
+		for {
+			conn, err := echo.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(conn, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	dialed := make(chan string, 1)
+	dial := func(ctx context.Context, address string) (net.Conn, error) {
+		dialed <- address
+		if address == "unreachable:80" {
+			return nil, fmt.Errorf("no route to host")
+		}
+		return net.Dial("tcp", echo.Addr().String())
+	}
+
+	client, server := net.Pipe()
+	t.Cleanup(func() { _ = client.Close() })
+	go func() {
+		_ = serveGatewayConn(context.Background(), server, dial)
+	}()
+	return client, dialed
+}
+
+func readN(t *testing.T, r io.Reader, n int) []byte {
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	require.NoError(t, err)
+	return buf
+}
+
+func TestGatewaySocks5(t *testing.T) {
+	require := require.New(t)
+
+	client, dialed := testGateway(t)
+	_, err := client.Write([]byte{socks5Version, 1, socks5MethodNoAuth})
+	require.NoError(err)
+	require.Equal([]byte{socks5Version, socks5MethodNoAuth}, readN(t, client, 2))
+
+	request := []byte{socks5Version, socks5CmdConnect, 0, socks5AddrDomain, byte(len("peer1"))}
+	request = append(append(request, "peer1"...), 0x1f, 0x90)
+	_, err = client.Write(request)
+	require.NoError(err)
+	reply := readN(t, client, 10)
+	require.Equal([]byte{socks5Version, socks5ReplySucceeded, 0, socks5AddrIPv4, 127, 0, 0, 1}, reply[:8])
+	require.Equal("peer1:8080", <-dialed)
+
+	_, err = client.Write([]byte("ping"))
+	require.NoError(err)
+	require.Equal("ping", string(readN(t, client, 4)))
+
+	client, dialed = testGateway(t)
+	_, err = client.Write([]byte{socks5Version, 1, socks5MethodNoAuth, socks5Version, socks5CmdConnect, 0, socks5AddrIPv4, 100, 64, 0, 1, 0x1f, 0x90})
+	require.NoError(err)
+	require.Equal([]byte{socks5Version, socks5MethodNoAuth}, readN(t, client, 2))
+	require.Equal(socks5ReplySucceeded, int(readN(t, client, 10)[1]))
+	require.Equal("100.64.0.1:8080", <-dialed)
+
+	// only connecting without authentication is supported
+	client, _ = testGateway(t)
+	_, err = client.Write([]byte{socks5Version, 1, 0x02})
+	require.NoError(err)
+	require.Equal([]byte{socks5Version, socks5MethodNoAcceptable}, readN(t, client, 2))
+
+	// UDP ASSOCIATE is not supported
+	client, _ = testGateway(t)
+	_, err = client.Write([]byte{socks5Version, 1, socks5MethodNoAuth, socks5Version, 0x03, 0, socks5AddrIPv4, 100, 64, 0, 1, 0, 53})
+	require.NoError(err)
+	readN(t, client, 2)
+	require.Equal(socks5ReplyCmdUnsupported, int(readN(t, client, 10)[1]))
+
+	client, _ = testGateway(t)
+	request = []byte{socks5Version, 1, socks5MethodNoAuth, socks5Version, socks5CmdConnect, 0, socks5AddrDomain, byte(len("unreachable"))}
+	_, err = client.Write(append(append(request, "unreachable"...), 0, 80))
+	require.NoError(err)
+	readN(t, client, 2)
+	require.Equal(socks5ReplyHostUnreach, int(readN(t, client, 10)[1]))
+}
+
+func TestGatewayHttpConnect(t *testing.T) {
+	require := require.New(t)
+
+	client, dialed := testGateway(t)
+	_, err := client.Write([]byte("CONNECT peer1:443 HTTP/1.1\r\nHost: peer1:443\r\n\r\nping"))
+	require.NoError(err)
+	reader := bufio.NewReader(client)
+	response, err := http.ReadResponse(reader, &http.Request{Method: http.MethodConnect})
+	require.NoError(err)
+	require.Equal(http.StatusOK, response.StatusCode)
+	require.Equal("peer1:443", <-dialed)
+	// the data sent along with the request is relayed too
+	require.Equal("ping", string(readN(t, reader, 4)))
+
+	client, _ = testGateway(t)
+	_, err = client.Write([]byte("GET http://peer1/ HTTP/1.1\r\nHost: peer1\r\n\r\n"))
+	require.NoError(err)
+	response, err = http.ReadResponse(bufio.NewReader(client), nil)
+	require.NoError(err)
+	require.Equal(http.StatusMethodNotAllowed, response.StatusCode)
+
+	client, _ = testGateway(t)
+	_, err = client.Write([]byte("CONNECT unreachable:80 HTTP/1.1\r\nHost: unreachable:80\r\n\r\n"))
+	require.NoError(err)
+	response, err = http.ReadResponse(bufio.NewReader(client), nil)
+	require.NoError(err)
+	require.Equal(http.StatusBadGateway, response.StatusCode)
+}
+
+func TestGatewayListenAddress(t *testing.T) {
+	require := require.New(t)
+
+	address, err := gatewayListenAddress(":1080")
+	require.NoError(err)
+	require.Equal("127.0.0.1:1080", address)
+
+	address, err = gatewayListenAddress("0.0.0.0:1080")
+	require.NoError(err)
+	require.Equal("0.0.0.0:1080", address)
+
+	address, err = gatewayListenAddress("[::1]:1080")
+	require.NoError(err)
+	require.Equal("[::1]:1080", address)
+
+	_, err = gatewayListenAddress("1080")
+	require.Error(err)
+}
@@ -0,0 +1,293 @@
+package nexodus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/nexodus-io/nexodus/internal/util"
+)
+
+const (
+	// httpProxyHostnameHeader carries the hostname of the peer device that sent a request to an ingress http proxy
+	httpProxyHostnameHeader = "X-Nexodus-Device-Hostname"
+	// proxyCertRenewBefore is how long before it expires the certificate of a TLS terminating proxy is replaced
+	proxyCertRenewBefore = 24 * time.Hour
+	proxyCertTimeout     = 30 * time.Second
+)
+
+// httpProxyDestKey is the request context key of the destination picked for a request
+type httpProxyDestKey struct{}
+
+// httpRoute returns the rules routing a request: among the rules matching its host and path, the ones for the
+// request host win over the ones for any host, then the ones with the longest path prefix. Assumes proxy.mu is held.
+func (proxy *UsProxy) httpRoute(host string, path string) []ProxyRule {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	var best []ProxyRule
+	bestScore := -1
+	for _, rule := range proxy.rules {
+		if rule.httpHost != "" && rule.httpHost != host {
+			continue
+		}
+		if !httpPathHasPrefix(path, rule.httpPath) {
+			continue
+		}
+		score := len(rule.httpPath)
+		if rule.httpHost != "" {
+			score += 1 << 16
+		}
+		if score > bestScore {
+			best = nil
+			bestScore = score
+		}
+		if score == bestScore {
+			best = append(best, rule)
+		}
+	}
+	return best
+}
+
+// httpPathHasPrefix returns true if the path is under the prefix, /api matches /api and /api/v1 but not /apis
+func httpPathHasPrefix(path string, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
+
+// terminatesTLS returns true if the rules of the proxy have the tls option, they all agree on it
+func (proxy *UsProxy) terminatesTLS() bool {
+	proxy.mu.RLock()
+	defer proxy.mu.RUnlock()
+	return len(proxy.rules) > 0 && proxy.rules[0].tls
+}
+
+func (proxy *UsProxy) runHTTP(ctx context.Context, proxyWg *sync.WaitGroup) error {
+	l, err := proxy.listenTCP()
+	if err != nil {
+		return err
+	}
+	if proxy.terminatesTLS() {
+		l = tls.NewListener(l, &tls.Config{
+			GetCertificate: proxy.getCertificate,
+			MinVersion:     tls.VersionTLS12,
+		})
+	}
+
+	srv := &http.Server{
+		Handler:           proxy.httpHandler(),
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	stopped := make(chan struct{})
+	defer close(stopped)
+	util.GoWithWaitGroup(proxyWg, func() {
+		select {
+		case <-ctx.Done():
+			proxy.logger.Info("Stopping proxy due to context cancel")
+			_ = srv.Close()
+		case <-stopped:
+		}
+	})
+
+	err = srv.Serve(l)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	proxy.logger.Error("Error serving http: ", err)
+	return err
+}
+
+// httpHandler routes the requests to the destinations and adds the X-Forwarded-* headers. The
+// httpProxyHostnameHeader is set to the hostname of the peer device sending the request.
+func (proxy *UsProxy) httpHandler() http.Handler {
+	reverseProxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			dest := pr.In.Context().Value(httpProxyDestKey{}).(HostPort)
+			pr.SetURL(&url.URL{Scheme: "http", Host: dest.String()})
+			pr.Out.Host = pr.In.Host
+			pr.SetXForwarded()
+			pr.Out.Header.Del(httpProxyHostnameHeader)
+			if hostname := proxy.peerHostname(pr.In.RemoteAddr); hostname != "" {
+				pr.Out.Header.Set(httpProxyHostnameHeader, hostname)
+			}
+		},
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _ string, addr string) (net.Conn, error) {
+				host, portStr, err := net.SplitHostPort(addr)
+				if err != nil {
+					return nil, err
+				}
+				port, err := strconv.Atoi(portStr)
+				if err != nil {
+					return nil, err
+				}
+				return proxy.dialDest(ctx, HostPort{host: host, port: port})
+			},
+			MaxIdleConnsPerHost: 16,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			proxy.logger.Debugf("Error proxying the request from %s: %v", r.RemoteAddr, err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxy.mu.Lock()
+		rules := proxy.httpRoute(r.Host, r.URL.Path)
+		if len(rules) == 0 {
+			proxy.mu.Unlock()
+			http.NotFound(w, r)
+			return
+		}
+		clientAddr, _ := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+		dest, release := proxy.nextDest(clientAddr, rules)
+		proxy.mu.Unlock()
+		defer release()
+
+		if proxy.debugTraffic {
+			proxy.logger.Debugf("Proxying %s %s%s from %s to %s", r.Method, r.Host, r.URL.Path, r.RemoteAddr, dest)
+		}
+		reverseProxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), httpProxyDestKey{}, dest)))
+	})
+}
+
+// peerHostname returns the hostname of the peer device at the remote address of an ingress request
+func (proxy *UsProxy) peerHostname(remoteAddr string) string {
+	if proxy.key.ruleType != ProxyTypeIngress || proxy.resolver == nil || proxy.resolver.peerName == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return ""
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+	hostname, _ := proxy.resolver.peerName(ip)
+	return hostname
+}
+
+// tlsNames returns the DNS names the certificate of the proxy is requested for: the hosts of its rules
+func (proxy *UsProxy) tlsNames() []string {
+	proxy.mu.RLock()
+	defer proxy.mu.RUnlock()
+	seen := map[string]bool{}
+	var names []string
+	for _, rule := range proxy.rules {
+		if rule.httpHost != "" && !seen[rule.httpHost] {
+			seen[rule.httpHost] = true
+			names = append(names, rule.httpHost)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getCertificate returns the certificate of a TLS terminating proxy, a new one is requested when the hosts of
+// the rules change or when it is about to expire
+func (proxy *UsProxy) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	proxy.certMu.Lock()
+	defer proxy.certMu.Unlock()
+
+	names := proxy.tlsNames()
+	key := strings.Join(names, ",")
+	if proxy.cert != nil && proxy.certNames == key && time.Until(proxy.cert.Leaf.NotAfter) > proxyCertRenewBefore {
+		return proxy.cert, nil
+	}
+	if proxy.certificate == nil {
+		return nil, fmt.Errorf("no certificate source for the proxy")
+	}
+
+	parent := hello.Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, proxyCertTimeout)
+	defer cancel()
+	cert, err := proxy.certificate(ctx, names)
+	if err != nil {
+		proxy.logger.Errorf("Failed to get the proxy certificate: %v", err)
+		if proxy.cert != nil && time.Now().Before(proxy.cert.Leaf.NotAfter) {
+			return proxy.cert, nil
+		}
+		return nil, err
+	}
+	proxy.cert = cert
+	proxy.certNames = key
+	return cert, nil
+}
+
+// proxyCertificate has a certificate for the names signed by the service network CA of the API server. The
+// device hostname is used when no names are given.
+func (nx *Nexodus) proxyCertificate(ctx context.Context, names []string) (*tls.Certificate, error) {
+	if nx.client == nil {
+		return nil, fmt.Errorf("not connected to the api server")
+	}
+	if len(names) == 0 {
+		names = []string{magicDnsLabel(nx.hostname)}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: names[0]},
+		DNSNames: names,
+	}, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the certificate signing request: %w", err)
+	}
+
+	resp, _, err := nx.client.CAApi.SignCSR(ctx).CertificateSigningRequest(client.ModelsCertificateSigningRequest{
+		Request: client.PtrString(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}))),
+		Usages:  []client.ModelsKeyUsage{client.UsageDigitalSignature, client.UsageKeyEncipherment, client.UsageServerAuth},
+	}).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign the certificate: %w", err)
+	}
+
+	result := &tls.Certificate{PrivateKey: key}
+	rest := []byte(resp.GetCertificate() + "\n" + resp.GetCa())
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			result.Certificate = append(result.Certificate, block.Bytes)
+		}
+	}
+	if len(result.Certificate) == 0 {
+		return nil, fmt.Errorf("the api server did not return a certificate")
+	}
+	result.Leaf, err = x509.ParseCertificate(result.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the signed certificate: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,126 @@
+package nexodus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHttpProxyRule(t *testing.T) {
+	require := require.New(t)
+
+	rule, err := ParseProxyRule("http:443:10.0.0.5:8080,host=API.example.com,path=/v1,tls", ProxyTypeIngress)
+	require.NoError(err)
+	require.Equal("api.example.com", rule.httpHost)
+	require.Equal("/v1", rule.httpPath)
+	require.True(rule.tls)
+	require.Equal("http:443:10.0.0.5:8080,host=api.example.com,path=/v1,tls", rule.String())
+	require.Equal("tcp", rule.protocol.network())
+
+	_, err = ParseProxyRule("tcp:80:10.0.0.5:8080,host=example.com", ProxyTypeIngress)
+	require.ErrorContains(err, "only http rules take the host option")
+	_, err = ParseProxyRule("http:80:10.0.0.5:8080,path=v1", ProxyTypeIngress)
+	require.ErrorContains(err, "must start with /")
+	_, err = ParseProxyRule("http:80:10.0.0.5:8080,weight=2", ProxyTypeIngress)
+	require.ErrorContains(err, "invalid proxy rule option")
+}
+
+func TestHttpRoute(t *testing.T) {
+	require := require.New(t)
+
+	proxy := testProxy(t, ProxyTypeIngress,
+		"http:80:10.0.0.1:80",
+		"http:80:10.0.0.2:80,path=/api",
+		"http:80:10.0.0.3:80,host=example.com",
+		"http:80:10.0.0.4:80,host=example.com,path=/api/v2",
+		"http:80:10.0.0.5:80,host=example.com,path=/api/v2",
+	)
+	route := func(host, path string) []string {
+		var dests []string
+		for _, rule := range proxy.httpRoute(host, path) {
+			dests = append(dests, rule.dest.host)
+		}
+		return dests
+	}
+	require.Equal([]string{"10.0.0.1"}, route("other.com", "/"))
+	require.Equal([]string{"10.0.0.2"}, route("other.com", "/api/users"))
+	require.Equal([]string{"10.0.0.1"}, route("other.com", "/apis"))
+	require.Equal([]string{"10.0.0.3"}, route("Example.com:8080", "/api/users"))
+	require.Equal([]string{"10.0.0.4", "10.0.0.5"}, route("example.com", "/api/v2/users"))
+}
+
+func TestHttpProxyHandler(t *testing.T) {
+	require := require.New(t)
+
+	backend := func(name string) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, "%s %s %s %s %s", name, r.Host, r.URL.Path, r.Header.Get("X-Forwarded-For"), r.Header.Get(httpProxyHostnameHeader))
+		}))
+		t.Cleanup(srv.Close)
+		return strings.TrimPrefix(srv.URL, "http://")
+	}
+	web := backend("web")
+	api := backend("api")
+
+	proxy := testProxy(t, ProxyTypeIngress, "http:80:"+web, "http:80:"+api+",path=/api")
+	proxy.resolver = &proxyResolver{peerName: func(ip net.IP) (string, bool) {
+		return "laptop", ip.Equal(net.ParseIP("127.0.0.1"))
+	}}
+	srv := httptest.NewServer(proxy.httpHandler())
+	defer srv.Close()
+
+	get := func(path string, header http.Header) string {
+		req, err := http.NewRequest("GET", srv.URL+path, nil)
+		require.NoError(err)
+		req.Host = "example.com"
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(err)
+		return string(body)
+	}
+	require.Equal("web example.com /index.html 127.0.0.1 laptop", get("/index.html", nil))
+	// the caller cannot pretend to be another device
+	require.Equal("api example.com /api/users 127.0.0.1 laptop", get("/api/users", http.Header{httpProxyHostnameHeader: {"server"}}))
+}
+
+func TestProxyGetCertificate(t *testing.T) {
+	require := require.New(t)
+
+	proxy := testProxy(t, ProxyTypeIngress, "http:443:10.0.0.1:80,host=a.example.com,tls")
+	var requested [][]string
+	proxy.certificate = func(ctx context.Context, names []string) (*tls.Certificate, error) {
+		requested = append(requested, names)
+		return &tls.Certificate{Leaf: &x509.Certificate{NotAfter: time.Now().Add(48 * time.Hour)}}, nil
+	}
+	hello := &tls.ClientHelloInfo{}
+
+	cert, err := proxy.getCertificate(hello)
+	require.NoError(err)
+	again, err := proxy.getCertificate(hello)
+	require.NoError(err)
+	require.Same(cert, again)
+	require.Equal([][]string{{"a.example.com"}}, requested)
+
+	// a new certificate is requested when the hosts change
+	rule, err := ParseProxyRule("http:443:10.0.0.2:80,host=b.example.com,tls", ProxyTypeIngress)
+	require.NoError(err)
+	proxy.rules = append(proxy.rules, rule)
+	_, err = proxy.getCertificate(hello)
+	require.NoError(err)
+	require.Equal([][]string{{"a.example.com"}, {"a.example.com", "b.example.com"}}, requested)
+}
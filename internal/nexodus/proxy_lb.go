@@ -0,0 +1,238 @@
+package nexodus
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/util"
+)
+
+// ProxyLbPolicy picks the destination of the new connections of a proxy listener with several rules
+type ProxyLbPolicy string
+
+const (
+	proxyLbRoundRobin ProxyLbPolicy = "round-robin"
+	proxyLbLeastConn  ProxyLbPolicy = "least-conn"
+	proxyLbSourceHash ProxyLbPolicy = "source-hash"
+	proxyLbFailover   ProxyLbPolicy = "failover"
+)
+
+func parseProxyLbPolicy(policy string) (ProxyLbPolicy, error) {
+	switch ProxyLbPolicy(strings.ToLower(policy)) {
+	case "", proxyLbRoundRobin:
+		return proxyLbRoundRobin, nil
+	case proxyLbLeastConn:
+		return proxyLbLeastConn, nil
+	case proxyLbSourceHash:
+		return proxyLbSourceHash, nil
+	case proxyLbFailover:
+		return proxyLbFailover, nil
+	default:
+		return "", fmt.Errorf("invalid load balancing policy (%s), must be one of %s, %s, %s or %s",
+			policy, proxyLbRoundRobin, proxyLbLeastConn, proxyLbSourceHash, proxyLbFailover)
+	}
+}
+
+const (
+	// proxyHealthCheckInterval is how often the destinations of the TCP listeners with several rules are checked
+	proxyHealthCheckInterval = 10 * time.Second
+	proxyHealthCheckTimeout  = 3 * time.Second
+)
+
+// proxyDestState tracks a destination of a proxy listener
+type proxyDestState struct {
+	activeConns int
+	unhealthy   bool
+}
+
+// destState returns the state of the destination. Assumes proxy.mu is held with a write-lock.
+func (proxy *UsProxy) destState(dest HostPort) *proxyDestState {
+	if proxy.dests == nil {
+		proxy.dests = map[HostPort]*proxyDestState{}
+	}
+	state, found := proxy.dests[dest]
+	if !found {
+		state = &proxyDestState{}
+		proxy.dests[dest] = state
+	}
+	return state
+}
+
+// destHealthy returns false once a health check of the destination failed. Assumes proxy.mu is held.
+func (proxy *UsProxy) destHealthy(dest HostPort) bool {
+	state, found := proxy.dests[dest]
+	return !found || !state.unhealthy
+}
+
+// candidateDests returns the destinations of the rules in the order they were added, leaving out the unhealthy
+// ones unless none are healthy. Assumes proxy.mu is held.
+func (proxy *UsProxy) candidateDests(rules []ProxyRule) []HostPort {
+	var all, healthy []HostPort
+	for _, rule := range rules {
+		all = append(all, rule.dest)
+		if proxy.destHealthy(rule.dest) {
+			healthy = append(healthy, rule.dest)
+		}
+	}
+	if len(healthy) == 0 {
+		return all
+	}
+	return healthy
+}
+
+// NextDest picks the destination of a new connection from the client according to the load balancing policy of
+// the listener. The returned function has to be called once the connection is closed.
+func (proxy *UsProxy) NextDest(clientAddr net.Addr) (HostPort, func()) {
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	return proxy.nextDest(clientAddr, proxy.rules)
+}
+
+// nextDest picks the destination among the ones of the rules. Assumes proxy.mu is held with a write-lock.
+func (proxy *UsProxy) nextDest(clientAddr net.Addr, rules []ProxyRule) (HostPort, func()) {
+	candidates := proxy.candidateDests(rules)
+	counter := atomic.AddUint64(&proxy.connectionCounter, 1)
+
+	var dest HostPort
+	switch proxy.lbPolicy {
+	case proxyLbLeastConn:
+		// start from the round robin pick so that ties are spread across the destinations
+		start := int(counter % uint64(len(candidates)))
+		dest = candidates[start]
+		for i := 1; i < len(candidates); i++ {
+			candidate := candidates[(start+i)%len(candidates)]
+			if proxy.destState(candidate).activeConns < proxy.destState(dest).activeConns {
+				dest = candidate
+			}
+		}
+	case proxyLbSourceHash:
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(clientHost(clientAddr)))
+		dest = candidates[h.Sum32()%uint32(len(candidates))]
+	case proxyLbFailover:
+		dest = candidates[0]
+	default:
+		dest = candidates[counter%uint64(len(candidates))]
+	}
+
+	proxy.destState(dest).activeConns++
+	var once sync.Once
+	return dest, func() {
+		once.Do(func() {
+			proxy.mu.Lock()
+			defer proxy.mu.Unlock()
+			proxy.destState(dest).activeConns--
+		})
+	}
+}
+
+// clientHost returns the address of the client without the port, so that all the connections of a client hash
+// to the same destination
+func clientHost(clientAddr net.Addr) string {
+	if clientAddr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(clientAddr.String())
+	if err != nil {
+		return clientAddr.String()
+	}
+	return host
+}
+
+// dialDest connects to a destination, through the wireguard tunnel for an egress proxy
+func (proxy *UsProxy) dialDest(ctx context.Context, dest HostPort) (net.Conn, error) {
+	dest, err := proxy.resolveDest(ctx, dest)
+	if err != nil {
+		return nil, err
+	}
+	if proxy.key.ruleType == ProxyTypeEgress {
+		return proxy.userspaceNet.DialContext(ctx, proxy.key.protocol.network(), dest.String())
+	}
+	dialer := net.Dialer{}
+	return dialer.DialContext(ctx, proxy.key.protocol.network(), dest.String())
+}
+
+// runHealthChecks periodically connects to the destinations of a TCP listener and takes the ones that do not
+// accept connections out of the load balancing until they do again. Listeners with a single rule are not
+// checked, there is no other destination to send the connections to.
+func (proxy *UsProxy) runHealthChecks(ctx context.Context) {
+	ticker := time.NewTicker(proxyHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			proxy.checkDests(ctx)
+		}
+	}
+}
+
+func (proxy *UsProxy) checkDests(ctx context.Context) {
+	proxy.mu.RLock()
+	var dests []HostPort
+	if len(proxy.rules) > 1 {
+		for _, rule := range proxy.rules {
+			dests = append(dests, rule.dest)
+		}
+	}
+	proxy.mu.RUnlock()
+
+	results := make([]error, len(dests))
+	wg := &sync.WaitGroup{}
+	for i, dest := range dests {
+		util.GoWithWaitGroup(wg, func() {
+			checkCtx, cancel := context.WithTimeout(ctx, proxyHealthCheckTimeout)
+			defer cancel()
+			conn, err := proxy.dialDest(checkCtx, dest)
+			if err == nil {
+				_ = conn.Close()
+			}
+			results[i] = err
+		})
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	for i, dest := range dests {
+		state := proxy.destState(dest)
+		unhealthy := results[i] != nil
+		if unhealthy == state.unhealthy {
+			continue
+		}
+		state.unhealthy = unhealthy
+		if unhealthy {
+			proxy.logger.Warnf("Proxy destination %s failed its health check, removing it: %v", dest, results[i])
+		} else {
+			proxy.logger.Infof("Proxy destination %s passed its health check, adding it back", dest)
+		}
+	}
+}
+
+// UserspaceProxySetLbPolicy sets the load balancing policy of the listener of a proxy rule
+func (nx *Nexodus) UserspaceProxySetLbPolicy(key ProxyKey, policy ProxyLbPolicy) error {
+	nx.proxyLock.Lock()
+	defer nx.proxyLock.Unlock()
+
+	proxy, found := nx.proxies[key]
+	if !found {
+		return fmt.Errorf("no %s proxy listening on %s port %d", key.ruleType, key.protocol, key.listenPort)
+	}
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	if proxy.lbPolicy != policy {
+		proxy.logger.Infof("Using the %s load balancing policy", policy)
+	}
+	proxy.lbPolicy = policy
+	return nil
+}
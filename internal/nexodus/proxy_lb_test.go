@@ -0,0 +1,147 @@
+package nexodus
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/nexodus-io/nexodus/internal/state/fstore"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testProxy(t *testing.T, ruleType ProxyType, rules ...string) *UsProxy {
+	proxy := &UsProxy{logger: zap.NewNop().Sugar(), lbPolicy: proxyLbRoundRobin}
+	for _, r := range rules {
+		rule, err := ParseProxyRule(r, ruleType)
+		require.NoError(t, err)
+		proxy.key = rule.ProxyKey
+		proxy.rules = append(proxy.rules, rule)
+	}
+	return proxy
+}
+
+func TestParseProxyLbPolicy(t *testing.T) {
+	require := require.New(t)
+
+	policy, err := parseProxyLbPolicy("")
+	require.NoError(err)
+	require.Equal(proxyLbRoundRobin, policy)
+	policy, err = parseProxyLbPolicy("Least-Conn")
+	require.NoError(err)
+	require.Equal(proxyLbLeastConn, policy)
+	_, err = parseProxyLbPolicy("random")
+	require.ErrorContains(err, "invalid load balancing policy")
+}
+
+func TestProxyNextDest(t *testing.T) {
+	require := require.New(t)
+	client1 := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1000}
+	client2 := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 2000}
+
+	proxy := testProxy(t, ProxyTypeIngress, "tcp:80:10.1.0.1:80", "tcp:80:10.1.0.2:80", "tcp:80:10.1.0.3:80")
+
+	// round robin
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		dest, release := proxy.NextDest(client1)
+		seen[dest.host] = true
+		release()
+	}
+	require.Len(seen, 3)
+
+	// least connections picks the destination with the fewest open connections
+	proxy.lbPolicy = proxyLbLeastConn
+	first, releaseFirst := proxy.NextDest(client1)
+	second, releaseSecond := proxy.NextDest(client1)
+	third, releaseThird := proxy.NextDest(client1)
+	require.ElementsMatch([]string{"10.1.0.1", "10.1.0.2", "10.1.0.3"}, []string{first.host, second.host, third.host})
+	releaseSecond()
+	releaseSecond()
+	dest, release := proxy.NextDest(client1)
+	require.Equal(second, dest)
+	release()
+	releaseFirst()
+	releaseThird()
+
+	// source hash sends all the connections of a client to the same destination
+	proxy.lbPolicy = proxyLbSourceHash
+	dest1, release1 := proxy.NextDest(client1)
+	dest2, release2 := proxy.NextDest(client2)
+	require.Equal(dest1, dest2)
+	release1()
+	release2()
+
+	// failover uses the first healthy destination
+	proxy.lbPolicy = proxyLbFailover
+	dest, release = proxy.NextDest(client1)
+	require.Equal("10.1.0.1", dest.host)
+	release()
+	proxy.destState(proxy.rules[0].dest).unhealthy = true
+	dest, release = proxy.NextDest(client1)
+	require.Equal("10.1.0.2", dest.host)
+	release()
+
+	// without any healthy destination, all of them are tried
+	proxy.destState(proxy.rules[1].dest).unhealthy = true
+	proxy.destState(proxy.rules[2].dest).unhealthy = true
+	dest, release = proxy.NextDest(client1)
+	require.Equal("10.1.0.1", dest.host)
+	release()
+
+	for _, state := range proxy.dests {
+		require.Zero(state.activeConns)
+	}
+}
+
+func TestProxyCheckDests(t *testing.T) {
+	require := require.New(t)
+
+	up, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer up.Close()
+	down, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	require.NoError(down.Close())
+
+	proxy := testProxy(t, ProxyTypeIngress, "tcp:80:"+up.Addr().String(), "tcp:80:"+down.Addr().String())
+	proxy.lbPolicy = proxyLbFailover
+	proxy.checkDests(context.Background())
+	require.True(proxy.destHealthy(proxy.rules[0].dest))
+	require.False(proxy.destHealthy(proxy.rules[1].dest))
+
+	// the down destination is skipped by the other policies as well
+	proxy.lbPolicy = proxyLbRoundRobin
+	for i := 0; i < 4; i++ {
+		dest, release := proxy.NextDest(nil)
+		require.Equal(proxy.rules[0].dest, dest)
+		release()
+	}
+}
+
+func TestStoreProxyLbPolicy(t *testing.T) {
+	require := require.New(t)
+	stateStore := fstore.New(filepath.Join(t.TempDir(), "state.json"))
+	require.NoError(stateStore.Load())
+
+	nx := &Nexodus{logger: zap.NewNop().Sugar(), stateStore: stateStore}
+	nx.proxies = map[ProxyKey]*UsProxy{}
+	for _, r := range []string{"tcp:80:10.1.0.1:80", "tcp:80:10.1.0.2:80"} {
+		rule, err := ParseProxyRule(r, ProxyTypeIngress)
+		require.NoError(err)
+		rule.stored = true
+		_, err = nx.UserspaceProxyAdd(rule)
+		require.NoError(err)
+	}
+	key := ProxyKey{ruleType: ProxyTypeIngress, protocol: proxyProtocolTCP, listenPort: 80}
+	require.NoError(nx.UserspaceProxySetLbPolicy(key, proxyLbLeastConn))
+	require.NoError(nx.StoreProxyRules())
+	require.Equal(map[string]string{"ingress:tcp:80": "least-conn"}, stateStore.State().ProxyRulesConfig.LoadBalancing)
+
+	loaded := &Nexodus{logger: zap.NewNop().Sugar(), stateStore: stateStore}
+	loaded.proxies = map[ProxyKey]*UsProxy{}
+	require.NoError(loaded.LoadProxyRules())
+	require.Len(loaded.proxies[key].rules, 2)
+	require.Equal(proxyLbLeastConn, loaded.proxies[key].lbPolicy)
+}
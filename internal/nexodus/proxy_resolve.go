@@ -0,0 +1,150 @@
+package nexodus
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+)
+
+// proxyDestResolveTTL is how long a DNS name destination keeps the address it resolved to before it is resolved again
+const proxyDestResolveTTL = 30 * time.Second
+
+// proxyResolver turns the destination hosts of the proxy rules into addresses. Hostnames of the peers are looked
+// up in the device cache on every connection so that egress rules follow the tunnel IP changes, other names are
+// resolved with DNS and cached for proxyDestResolveTTL.
+type proxyResolver struct {
+	peerLookup func(hostname string) (net.IP, bool)
+	// peerName returns the hostname of the peer with the tunnel IP
+	peerName func(ip net.IP) (string, bool)
+	lookup   func(ctx context.Context, host string) ([]net.IP, error)
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]proxyResolved
+}
+
+type proxyResolved struct {
+	ip      net.IP
+	expires time.Time
+}
+
+func newProxyResolver(peerLookup func(hostname string) (net.IP, bool), peerName func(ip net.IP) (string, bool)) *proxyResolver {
+	return &proxyResolver{
+		peerLookup: peerLookup,
+		peerName:   peerName,
+		lookup: func(ctx context.Context, host string) ([]net.IP, error) {
+			return net.DefaultResolver.LookupIP(ctx, "ip", host)
+		},
+		now: time.Now,
+	}
+}
+
+// resolve returns the address of the host, looking up the peers first when peers is set
+func (r *proxyResolver) resolve(ctx context.Context, host string, peers bool) (net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip, nil
+	}
+	if peers && r.peerLookup != nil {
+		if ip, found := r.peerLookup(host); found {
+			return ip, nil
+		}
+	}
+
+	key := strings.ToLower(host)
+	r.mu.Lock()
+	cached, found := r.cache[key]
+	r.mu.Unlock()
+	if found && r.now().Before(cached.expires) {
+		return cached.ip, nil
+	}
+
+	ips, err := r.lookup(ctx, host)
+	if err == nil && len(ips) == 0 {
+		err = fmt.Errorf("no addresses found")
+	}
+	if err != nil {
+		if found {
+			// keep using the last address rather than failing every connection while DNS is unavailable
+			return cached.ip, nil
+		}
+		return nil, fmt.Errorf("failed to resolve the proxy destination %s: %w", host, err)
+	}
+
+	ip := ips[0]
+	for _, candidate := range ips {
+		if candidate.To4() != nil {
+			ip = candidate
+			break
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cache == nil {
+		r.cache = map[string]proxyResolved{}
+	}
+	r.cache[key] = proxyResolved{ip: ip, expires: r.now().Add(proxyDestResolveTTL)}
+	return ip, nil
+}
+
+// peerTunnelIP returns the tunnel IP of the peer with the hostname, preferring IPv4
+func (nx *Nexodus) peerTunnelIP(hostname string) (net.IP, bool) {
+	var result net.IP
+	nx.deviceCacheIterRead(func(d deviceCacheEntry) {
+		if result != nil || d.device.GetPublicKey() == nx.wireguardPubKey {
+			return
+		}
+		if !strings.EqualFold(d.device.GetHostname(), hostname) && magicDnsLabel(d.device.GetHostname()) != strings.ToLower(hostname) {
+			return
+		}
+		for _, ip := range d.device.GetIpv4TunnelIps() {
+			if addr := net.ParseIP(ip.GetAddress()); addr != nil {
+				result = addr
+				return
+			}
+		}
+		for _, ip := range d.device.GetIpv6TunnelIps() {
+			if addr := net.ParseIP(ip.GetAddress()); addr != nil {
+				result = addr
+				return
+			}
+		}
+	})
+	return result, result != nil
+}
+
+// peerHostname returns the hostname of the peer with the tunnel IP
+func (nx *Nexodus) peerHostname(ip net.IP) (string, bool) {
+	hostname := ""
+	found := false
+	nx.deviceCacheIterRead(func(d deviceCacheEntry) {
+		if found {
+			return
+		}
+		for _, tunnelIP := range append(append([]client.ModelsTunnelIP{}, d.device.GetIpv4TunnelIps()...), d.device.GetIpv6TunnelIps()...) {
+			if addr := net.ParseIP(tunnelIP.GetAddress()); addr != nil && addr.Equal(ip) {
+				hostname = d.device.GetHostname()
+				found = true
+				return
+			}
+		}
+	})
+	return hostname, found
+}
+
+// resolveDest returns the destination with its host resolved to an address. Peer hostnames are only looked up
+// for egress rules, the destinations of ingress rules are on the networks reachable from this device.
+func (proxy *UsProxy) resolveDest(ctx context.Context, dest HostPort) (HostPort, error) {
+	if proxy.resolver == nil || net.ParseIP(dest.host) != nil {
+		return dest, nil
+	}
+	ip, err := proxy.resolver.resolve(ctx, dest.host, proxy.key.ruleType == ProxyTypeEgress)
+	if err != nil {
+		return HostPort{}, err
+	}
+	return HostPort{host: ip.String(), port: dest.port}, nil
+}
@@ -0,0 +1,118 @@
+package nexodus
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProxyResolver(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	now := time.Unix(1700000000, 0)
+	lookups := 0
+	answer := []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1")}
+	var lookupErr error
+	r := &proxyResolver{
+		peerLookup: func(hostname string) (net.IP, bool) {
+			if hostname == "db-primary" {
+				return net.ParseIP("100.64.0.7"), true
+			}
+			return nil, false
+		},
+		lookup: func(ctx context.Context, host string) ([]net.IP, error) {
+			lookups++
+			return answer, lookupErr
+		},
+		now: func() time.Time { return now },
+	}
+
+	ip, err := r.resolve(ctx, "10.0.0.1", true)
+	require.NoError(err)
+	require.Equal("10.0.0.1", ip.String())
+
+	// peer hostnames are only looked up when asked for
+	ip, err = r.resolve(ctx, "db-primary", true)
+	require.NoError(err)
+	require.Equal("100.64.0.7", ip.String())
+	ip, err = r.resolve(ctx, "db-primary", false)
+	require.NoError(err)
+	require.Equal("192.0.2.1", ip.String())
+	require.Equal(1, lookups)
+
+	// DNS answers are cached until the TTL expires
+	answer = []net.IP{net.ParseIP("192.0.2.2")}
+	ip, err = r.resolve(ctx, "DB-Primary", false)
+	require.NoError(err)
+	require.Equal("192.0.2.1", ip.String())
+	require.Equal(1, lookups)
+	now = now.Add(proxyDestResolveTTL)
+	ip, err = r.resolve(ctx, "db-primary", false)
+	require.NoError(err)
+	require.Equal("192.0.2.2", ip.String())
+	require.Equal(2, lookups)
+
+	// the last answer is kept while DNS fails
+	now = now.Add(proxyDestResolveTTL)
+	lookupErr = errors.New("server misbehaving")
+	ip, err = r.resolve(ctx, "db-primary", false)
+	require.NoError(err)
+	require.Equal("192.0.2.2", ip.String())
+	_, err = r.resolve(ctx, "db.example.com", false)
+	require.ErrorContains(err, "failed to resolve the proxy destination db.example.com")
+}
+
+func TestPeerTunnelIP(t *testing.T) {
+	require := require.New(t)
+
+	nx := &Nexodus{logger: zap.NewNop().Sugar(), wireguardPubKey: "local", deviceCache: map[string]deviceCacheEntry{}}
+	for _, d := range []client.ModelsDevice{
+		{
+			PublicKey:     client.PtrString("local"),
+			Hostname:      client.PtrString("db-primary"),
+			Ipv4TunnelIps: []client.ModelsTunnelIP{{Address: client.PtrString("100.64.0.1")}},
+		},
+		{
+			PublicKey:     client.PtrString("peer1"),
+			Hostname:      client.PtrString("DB-Primary"),
+			Ipv4TunnelIps: []client.ModelsTunnelIP{{Address: client.PtrString("100.64.0.2")}},
+		},
+		{
+			PublicKey:     client.PtrString("peer2"),
+			Hostname:      client.PtrString("web.local"),
+			Ipv6TunnelIps: []client.ModelsTunnelIP{{Address: client.PtrString("200::3")}},
+		},
+	} {
+		nx.deviceCache[d.GetPublicKey()] = deviceCacheEntry{device: d}
+	}
+
+	ip, found := nx.peerTunnelIP("db-primary")
+	require.True(found)
+	require.Equal("100.64.0.2", ip.String())
+	ip, found = nx.peerTunnelIP("web-local")
+	require.True(found)
+	require.Equal("200::3", ip.String())
+	_, found = nx.peerTunnelIP("mail")
+	require.False(found)
+}
+
+func TestParseProxyRuleHostnames(t *testing.T) {
+	require := require.New(t)
+
+	rule, err := ParseProxyRule("tcp:5432:db-primary:5432", ProxyTypeEgress)
+	require.NoError(err)
+	require.Equal("db-primary", rule.dest.host)
+	_, err = ParseProxyRule("udp:53:dns.example.com:53", ProxyTypeIngress)
+	require.NoError(err)
+	_, err = ParseProxyRule("tcp:80:[2001:db8::1]:80", ProxyTypeIngress)
+	require.NoError(err)
+	_, err = ParseProxyRule("tcp:80:bad_host!:80", ProxyTypeIngress)
+	require.ErrorContains(err, "host must be an IP address, a peer hostname or a DNS name")
+}
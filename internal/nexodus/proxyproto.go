@@ -0,0 +1,75 @@
+package nexodus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// proxyProtocolV2Signature starts every PROXY protocol v2 header
+var proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// destProxyProtocolVersion returns the PROXY protocol version of the rule forwarding to dest, 0 if it has none
+func (proxy *UsProxy) destProxyProtocolVersion(dest HostPort) int {
+	proxy.mu.RLock()
+	defer proxy.mu.RUnlock()
+	for _, rule := range proxy.rules {
+		if rule.dest == dest {
+			return rule.proxyProtocolVersion
+		}
+	}
+	return 0
+}
+
+// proxyProtocolHeader returns the PROXY protocol header telling the backend that the connection was made from src
+// to dst. The address family is UNKNOWN when the addresses are not TCP addresses of the same family.
+func proxyProtocolHeader(version int, src net.Addr, dst net.Addr) ([]byte, error) {
+	srcTCP, srcOk := src.(*net.TCPAddr)
+	dstTCP, dstOk := dst.(*net.TCPAddr)
+	var srcIP, dstIP net.IP
+	if srcOk && dstOk {
+		if src4, dst4 := srcTCP.IP.To4(), dstTCP.IP.To4(); src4 != nil && dst4 != nil {
+			srcIP, dstIP = src4, dst4
+		} else if src4 == nil && dst4 == nil {
+			srcIP, dstIP = srcTCP.IP.To16(), dstTCP.IP.To16()
+		}
+	}
+
+	switch version {
+	case 1:
+		switch {
+		case srcIP == nil || dstIP == nil:
+			return []byte("PROXY UNKNOWN\r\n"), nil
+		case len(srcIP) == net.IPv4len:
+			return []byte(fmt.Sprintf("PROXY TCP4 %s %s %d %d\r\n", srcIP, dstIP, srcTCP.Port, dstTCP.Port)), nil
+		default:
+			return []byte(fmt.Sprintf("PROXY TCP6 %s %s %d %d\r\n", srcIP, dstIP, srcTCP.Port, dstTCP.Port)), nil
+		}
+	case 2:
+		header := bytes.NewBuffer(append([]byte{}, proxyProtocolV2Signature...))
+		// version 2, PROXY command
+		header.WriteByte(0x21)
+		switch {
+		case srcIP == nil || dstIP == nil:
+			// UNSPEC family, the backend uses the addresses of the connection
+			header.WriteByte(0x00)
+			_ = binary.Write(header, binary.BigEndian, uint16(0))
+			return header.Bytes(), nil
+		case len(srcIP) == net.IPv4len:
+			// TCP over IPv4
+			header.WriteByte(0x11)
+		default:
+			// TCP over IPv6
+			header.WriteByte(0x21)
+		}
+		_ = binary.Write(header, binary.BigEndian, uint16(2*len(srcIP)+4))
+		header.Write(srcIP)
+		header.Write(dstIP)
+		_ = binary.Write(header, binary.BigEndian, uint16(srcTCP.Port))
+		_ = binary.Write(header, binary.BigEndian, uint16(dstTCP.Port))
+		return header.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported PROXY protocol version %d", version)
+	}
+}
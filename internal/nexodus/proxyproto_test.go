@@ -0,0 +1,92 @@
+package nexodus
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyProtocolHeader(t *testing.T) {
+	require := require.New(t)
+
+	src4 := &net.TCPAddr{IP: net.ParseIP("100.64.0.2"), Port: 51234}
+	dst4 := &net.TCPAddr{IP: net.ParseIP("100.64.0.1"), Port: 443}
+	src6 := &net.TCPAddr{IP: net.ParseIP("200::2"), Port: 51234}
+	dst6 := &net.TCPAddr{IP: net.ParseIP("200::1"), Port: 443}
+
+	header, err := proxyProtocolHeader(1, src4, dst4)
+	require.NoError(err)
+	require.Equal("PROXY TCP4 100.64.0.2 100.64.0.1 51234 443\r\n", string(header))
+	header, err = proxyProtocolHeader(1, src6, dst6)
+	require.NoError(err)
+	require.Equal("PROXY TCP6 200::2 200::1 51234 443\r\n", string(header))
+	header, err = proxyProtocolHeader(1, src4, dst6)
+	require.NoError(err)
+	require.Equal("PROXY UNKNOWN\r\n", string(header))
+
+	header, err = proxyProtocolHeader(2, src4, dst4)
+	require.NoError(err)
+	require.Equal("0d0a0d0a000d0a515549540a"+"21"+"11"+"000c"+"64400002"+"64400001"+"c822"+"01bb", hex.EncodeToString(header))
+	header, err = proxyProtocolHeader(2, src6, dst6)
+	require.NoError(err)
+	require.Len(header, 16+36)
+	require.Equal(byte(0x21), header[13])
+	header, err = proxyProtocolHeader(2, src6, dst4)
+	require.NoError(err)
+	require.Equal("0d0a0d0a000d0a515549540a"+"21"+"00"+"0000", hex.EncodeToString(header))
+
+	_, err = proxyProtocolHeader(3, src4, dst4)
+	require.Error(err)
+}
+
+func TestProxyProtocolRule(t *testing.T) {
+	require := require.New(t)
+
+	_, err := ParseProxyRule("udp:53:10.0.0.1:53,proxy-protocol=v1", ProxyTypeIngress)
+	require.ErrorContains(err, "only tcp rules take the proxy-protocol option")
+	_, err = ParseProxyRule("tcp:80:10.0.0.1:80,proxy-protocol=v3", ProxyTypeIngress)
+	require.ErrorContains(err, "must be v1 or v2")
+
+	backend, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer backend.Close()
+	proxy := testProxy(t, ProxyTypeIngress, "tcp:80:"+backend.Addr().String()+",proxy-protocol=v1")
+	require.Equal("tcp:80:"+backend.Addr().String()+",proxy-protocol=v1", proxy.rules[0].String())
+	require.Equal("v1", proxy.rules[0].toApi().ProxyProtocol)
+
+	// the backend reads the header before the data sent by the caller
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer listener.Close()
+	caller, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(err)
+	defer caller.Close()
+	inConn, err := listener.Accept()
+	require.NoError(err)
+
+	wg := &sync.WaitGroup{}
+	go func() {
+		_ = proxy.handleTCPConnection(context.Background(), wg, inConn)
+	}()
+	_, err = caller.Write([]byte("hello\n"))
+	require.NoError(err)
+
+	outConn, err := backend.Accept()
+	require.NoError(err)
+	defer outConn.Close()
+	lines := bufio.NewReader(outConn)
+	header, err := lines.ReadString('\n')
+	require.NoError(err)
+	callerAddr := caller.LocalAddr().(*net.TCPAddr)
+	listenAddr := listener.Addr().(*net.TCPAddr)
+	require.Equal(fmt.Sprintf("PROXY TCP4 127.0.0.1 127.0.0.1 %d %d\r\n", callerAddr.Port, listenAddr.Port), header)
+	data, err := lines.ReadString('\n')
+	require.NoError(err)
+	require.Equal("hello\n", data)
+}
@@ -5,6 +5,9 @@ import (
 	"net"
 	"strconv"
 	"strings"
+
+	"github.com/nexodus-io/nexodus/internal/api"
+	"github.com/nexodus-io/nexodus/internal/util"
 )
 
 type ProxyType int
@@ -25,11 +28,23 @@ func (ruleType ProxyType) String() string {
 	}
 }
 
+func parseProxyType(ruleType string) (ProxyType, error) {
+	switch strings.ToLower(ruleType) {
+	case "egress":
+		return ProxyTypeEgress, nil
+	case "ingress":
+		return ProxyTypeIngress, nil
+	default:
+		return 0, fmt.Errorf("invalid proxy rule type (%s)", ruleType)
+	}
+}
+
 type ProxyProtocol string
 
 const (
-	proxyProtocolTCP ProxyProtocol = "tcp"
-	proxyProtocolUDP ProxyProtocol = "udp"
+	proxyProtocolTCP  ProxyProtocol = "tcp"
+	proxyProtocolUDP  ProxyProtocol = "udp"
+	proxyProtocolHTTP ProxyProtocol = "http"
 )
 
 func parseProxyProtocol(protocol string) (ProxyProtocol, error) {
@@ -38,11 +53,21 @@ func parseProxyProtocol(protocol string) (ProxyProtocol, error) {
 		return proxyProtocolTCP, nil
 	case "udp":
 		return proxyProtocolUDP, nil
+	case "http":
+		return proxyProtocolHTTP, nil
 	default:
 		return "", fmt.Errorf("invalid protocol (%s)", protocol)
 	}
 }
 
+// network returns the network the listener and the destinations of the protocol use
+func (protocol ProxyProtocol) network() string {
+	if protocol == proxyProtocolHTTP {
+		return "tcp"
+	}
+	return string(protocol)
+}
+
 type ProxyKey struct {
 	ruleType   ProxyType
 	protocol   ProxyProtocol
@@ -57,6 +82,12 @@ type ProxyRule struct {
 	ProxyKey
 	dest   HostPort
 	stored bool
+	// the options of http rules: the request Host and path prefix routed to dest, and TLS termination
+	httpHost string
+	httpPath string
+	tls      bool
+	// proxyProtocolVersion is 1 or 2 when tcp connections to dest start with a PROXY protocol header
+	proxyProtocolVersion int
 }
 
 type HostPort struct {
@@ -70,14 +101,44 @@ func (hp HostPort) String() string {
 }
 
 func (rule ProxyRule) String() string {
-	// protocol:port:destination_ip:destination_port
-	return fmt.Sprintf("%s:%d:%s", rule.protocol, rule.listenPort, rule.dest)
+	// protocol:port:destination_ip:destination_port[,option...]
+	result := fmt.Sprintf("%s:%d:%s", rule.protocol, rule.listenPort, rule.dest)
+	if rule.httpHost != "" {
+		result += ",host=" + rule.httpHost
+	}
+	if rule.httpPath != "" {
+		result += ",path=" + rule.httpPath
+	}
+	if rule.tls {
+		result += ",tls"
+	}
+	if rule.proxyProtocolVersion != 0 {
+		result += fmt.Sprintf(",proxy-protocol=v%d", rule.proxyProtocolVersion)
+	}
+	return result
 }
 
 func (rule ProxyRule) AsFlag() string {
 	return fmt.Sprintf("--%s %s", rule.ruleType, rule)
 }
 
+func (rule ProxyRule) toApi() api.ProxyRule {
+	result := api.ProxyRule{
+		Type:        rule.ruleType.String(),
+		Protocol:    string(rule.protocol),
+		ListenPort:  rule.listenPort,
+		Destination: rule.dest.String(),
+		Rule:        rule.String(),
+		Host:        rule.httpHost,
+		PathPrefix:  rule.httpPath,
+		TLS:         rule.tls,
+	}
+	if rule.proxyProtocolVersion != 0 {
+		result.ProxyProtocol = fmt.Sprintf("v%d", rule.proxyProtocolVersion)
+	}
+	return result
+}
+
 func parsePort(portStr string) (int, error) {
 	port, err := strconv.Atoi(portStr)
 	if err != nil {
@@ -90,7 +151,8 @@ func parsePort(portStr string) (int, error) {
 }
 
 func ParseProxyRule(rule string, ruleType ProxyType) (emptyRule ProxyRule, err error) {
-	// protocol:port:destination_ip:destination_port
+	// protocol:port:destination_ip:destination_port[,option...]
+	rule, options, _ := strings.Cut(rule, ",")
 	parts := strings.Split(rule, ":")
 	if len(parts) < 4 {
 		return emptyRule, fmt.Errorf("invalid proxy rule format, must specify 4 colon-separated values (%s)", rule)
@@ -116,13 +178,17 @@ func ParseProxyRule(rule string, ruleType ProxyType) (emptyRule ProxyRule, err e
 	if destHost == "" {
 		return emptyRule, fmt.Errorf("invalid destination host:port (%s): host cannot be empty", destHostPort)
 	}
+	if net.ParseIP(destHost) == nil && !util.IsValidDomainName(destHost+".local") {
+		// peer hostnames may be a single label, so they are checked as a subdomain
+		return emptyRule, fmt.Errorf("invalid destination host:port (%s): host must be an IP address, a peer hostname or a DNS name", destHostPort)
+	}
 
 	destPort, err := parsePort(destPortStr)
 	if err != nil {
 		return emptyRule, err
 	}
 
-	return ProxyRule{
+	result := ProxyRule{
 		ProxyKey: ProxyKey{
 			ruleType:   ruleType,
 			protocol:   protocol,
@@ -132,5 +198,50 @@ func ParseProxyRule(rule string, ruleType ProxyType) (emptyRule ProxyRule, err e
 			host: destHost,
 			port: destPort,
 		},
-	}, nil
+	}
+	if options == "" {
+		return result, nil
+	}
+	for _, option := range strings.Split(options, ",") {
+		name, value, _ := strings.Cut(option, "=")
+		switch name {
+		case "host", "path", "tls":
+			if protocol != proxyProtocolHTTP {
+				return emptyRule, fmt.Errorf("invalid proxy rule option (%s): only http rules take the %s option", option, name)
+			}
+		case "proxy-protocol":
+			if protocol != proxyProtocolTCP {
+				return emptyRule, fmt.Errorf("invalid proxy rule option (%s): only tcp rules take the %s option", option, name)
+			}
+		}
+		switch name {
+		case "host":
+			if !util.IsValidDomainName(value + ".local") {
+				return emptyRule, fmt.Errorf("invalid host option (%s): must be a hostname", value)
+			}
+			result.httpHost = strings.ToLower(value)
+		case "path":
+			if !strings.HasPrefix(value, "/") {
+				return emptyRule, fmt.Errorf("invalid path option (%s): must start with /", value)
+			}
+			result.httpPath = value
+		case "tls":
+			if value != "" {
+				return emptyRule, fmt.Errorf("invalid tls option (%s): takes no value", option)
+			}
+			result.tls = true
+		case "proxy-protocol":
+			switch value {
+			case "v1":
+				result.proxyProtocolVersion = 1
+			case "v2":
+				result.proxyProtocolVersion = 2
+			default:
+				return emptyRule, fmt.Errorf("invalid proxy-protocol option (%s): must be v1 or v2", value)
+			}
+		default:
+			return emptyRule, fmt.Errorf("invalid proxy rule option (%s): must be host=, path=, tls or proxy-protocol=", option)
+		}
+	}
+	return result, nil
 }
@@ -20,9 +20,8 @@ func (nx *Nexodus) handlePeerRouteOS(wgPeerConfig wgPeerConfig) error {
 	}
 	// If advertised CIDR, split the two prefixes (host /32) and advertised CIDR
 	for _, allowedIP := range wgPeerConfig.AllowedIPs {
-		// if the peer is advertising a default route, append it as an exit origin node, but don't add the route
+		// if the peer is advertising a default route it is an exit origin node, don't add the route
 		if util.IsDefaultIPv4Route(allowedIP) || util.IsDefaultIPv6Route(allowedIP) {
-			nx.updateExitNodeOrigins(wgPeerConfig)
 			continue
 		}
 
@@ -14,9 +14,8 @@ import (
 // handlePeerRoute when a new configuration is deployed, delete/add the peer allowedIPs
 func (nx *Nexodus) handlePeerRouteOS(wgPeerConfig wgPeerConfig) error {
 	for _, allowedIP := range wgPeerConfig.AllowedIPs {
-		// if the peer is advertising a default route, append it as an exit origin node, but don't add the route
+		// if the peer is advertising a default route it is an exit origin node, don't add the route
 		if util.IsDefaultIPv4Route(allowedIP) || util.IsDefaultIPv6Route(allowedIP) {
-			nx.updateExitNodeOrigins(wgPeerConfig)
 			continue
 		}
 
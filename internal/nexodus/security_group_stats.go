@@ -0,0 +1,135 @@
+package nexodus
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+)
+
+const (
+	// securityGroupStatsMetadataKey is the device metadata key the rule counters are published under
+	securityGroupStatsMetadataKey = "security-group-stats"
+	securityGroupStatsInterval    = time.Minute
+)
+
+// securityRuleCounter holds the packets and bytes matched by a security group rule. Only the packets that
+// start a connection are evaluated by the rules, the packets of established connections are not counted.
+type securityRuleCounter struct {
+	packets uint64
+	bytes   uint64
+}
+
+// securityGroupCounters holds the rule counters of each direction by the index of the security group rule
+type securityGroupCounters struct {
+	inbound  map[int]securityRuleCounter
+	outbound map[int]securityRuleCounter
+}
+
+func newSecurityGroupCounters() securityGroupCounters {
+	return securityGroupCounters{
+		inbound:  map[int]securityRuleCounter{},
+		outbound: map[int]securityRuleCounter{},
+	}
+}
+
+// securityGroupStatsReport is the last rule counters published, it is used to skip the
+// update when nothing matched the rules since.
+type securityGroupStatsReport struct {
+	securityGroups []securityGroupRuleStats
+}
+
+// securityGroupRuleStats are the rule counters of one of the security groups of the device
+type securityGroupRuleStats struct {
+	securityGroupId string
+	revision        int32
+	inbound         []client.ModelsSecurityRuleStats
+	outbound        []client.ModelsSecurityRuleStats
+}
+
+// reportSecurityGroupStats publishes the rule counters of the local security groups in the device metadata
+// so that the api server can sum them up over the devices of each group.
+func (nx *Nexodus) reportSecurityGroupStats(ctx context.Context) {
+	var counters *securityGroupCounters
+	if nx.userspaceMode {
+		counters = nx.userspaceFilter.counters()
+	} else {
+		var err error
+		counters, err = nx.securityGroupRuleCounters()
+		if err != nil {
+			nx.logger.Debugf("failed to read the security group rule counters: %v", err)
+			return
+		}
+	}
+
+	if nx.securityGroups == nil || counters == nil {
+		if nx.secGroupStatsReported == nil {
+			return
+		}
+		// the device left its security groups, stop reporting counters for them
+		if _, err := nx.client.DevicesApi.DeleteDeviceMetadataKey(ctx, nx.deviceId, securityGroupStatsMetadataKey).Execute(); err != nil {
+			nx.logger.Debugf("failed to delete the security group stats metadata: %v", err)
+			return
+		}
+		nx.secGroupStatsReported = nil
+		return
+	}
+
+	// the counters are indexed by the position of the rule in the rules of the groups concatenated in order
+	report := securityGroupStatsReport{}
+	inboundOffset, outboundOffset := 0, 0
+	for _, sg := range nx.securityGroups {
+		report.securityGroups = append(report.securityGroups, securityGroupRuleStats{
+			securityGroupId: sg.GetId(),
+			revision:        sg.GetRevision(),
+			inbound:         securityRuleStats(counters.inbound, inboundOffset, len(sg.InboundRules)),
+			outbound:        securityRuleStats(counters.outbound, outboundOffset, len(sg.OutboundRules)),
+		})
+		inboundOffset += len(sg.InboundRules)
+		outboundOffset += len(sg.OutboundRules)
+	}
+	if nx.secGroupStatsReported != nil && reflect.DeepEqual(report, *nx.secGroupStatsReported) {
+		return
+	}
+
+	securityGroups := make([]map[string]interface{}, len(report.securityGroups))
+	for i, stats := range report.securityGroups {
+		securityGroups[i] = map[string]interface{}{
+			"security_group_id": stats.securityGroupId,
+			"revision":          stats.revision,
+			"inbound_rules":     stats.inbound,
+			"outbound_rules":    stats.outbound,
+		}
+	}
+	value := map[string]interface{}{
+		"security_groups": securityGroups,
+		"collected_at":    time.Now().UTC().Format(time.RFC3339),
+	}
+	if _, _, err := nx.client.DevicesApi.UpdateDeviceMetadataKey(ctx, nx.deviceId, securityGroupStatsMetadataKey).Value(value).Execute(); err != nil {
+		nx.logger.Debugf("failed to update the security group stats metadata: %v", err)
+		return
+	}
+	nx.secGroupStatsReported = &report
+}
+
+// securityRuleStats converts the counters of the count rules starting at offset into the api model ordered
+// by the index of the rule in its security group
+func securityRuleStats(counters map[int]securityRuleCounter, offset, count int) []client.ModelsSecurityRuleStats {
+	stats := []client.ModelsSecurityRuleStats{}
+	for index, counter := range counters {
+		if index < offset || index >= offset+count {
+			continue
+		}
+		stats = append(stats, client.ModelsSecurityRuleStats{
+			Rule:    client.PtrInt32(int32(index - offset)),
+			Packets: client.PtrInt64(int64(counter.packets)),
+			Bytes:   client.PtrInt64(int64(counter.bytes)),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].GetRule() < stats[j].GetRule()
+	})
+	return stats
+}
@@ -0,0 +1,256 @@
+package nexodus
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nexodus-io/nexodus/internal/client"
+)
+
+const (
+	securityRuleActionAllow = "allow"
+	securityRuleActionDeny  = "deny"
+)
+
+// securityRules holds the rules of the local security groups as they are rendered into the packet
+// filter. A device in several security groups is allowed the traffic that any of its groups allows,
+// the rules of every group are evaluated on their own and the traffic is dropped only when none of
+// the groups allows it. A direction without any security group allows all traffic.
+type securityRules struct {
+	inbound  []securityGroupRules
+	outbound []securityGroupRules
+}
+
+// securityGroupRules are the rules of a single security group in one direction, ordered by priority
+// and with the security group references resolved. The first matching rule decides whether the group
+// allows the traffic.
+type securityGroupRules struct {
+	rules []client.ModelsSecurityRule
+	// index holds the index of the rule each resolved rule was created from in the rules of the security
+	// groups concatenated in order, it is used to report the rule counters back to the api server.
+	index []int
+	// defaultAllow is set when the group has no allow rules in the direction, the group then allows the
+	// traffic that none of its deny rules match.
+	defaultAllow bool
+}
+
+// ruleIndex returns the index of the security group rule the resolved rule i was created from, -1
+// is returned if it is not known.
+func ruleIndex(index []int, i int) int {
+	if i < 0 || i >= len(index) {
+		return -1
+	}
+	return index[i]
+}
+
+// securityRuleDenies returns true if the rule drops the traffic it matches
+func securityRuleDenies(rule client.ModelsSecurityRule) bool {
+	return strings.ToLower(rule.GetAction()) == securityRuleActionDeny
+}
+
+// securityRulesAllow returns true if any of the rules is an allow rule. The implicit drop at the
+// end of the rules of a group is only added when traffic is explicitly allowed, a group that only
+// has deny rules allows everything else.
+func securityRulesAllow(rules []client.ModelsSecurityRule) bool {
+	for _, rule := range rules {
+		if !securityRuleDenies(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// deviceSecurityGroupIds returns the security groups of a device. Older api servers only set the single
+// security group id of the device.
+func deviceSecurityGroupIds(d client.ModelsDevice) []string {
+	if d.SecurityGroupIds != nil {
+		return d.SecurityGroupIds
+	}
+	if id := d.GetSecurityGroupId(); id != "" && id != uuid.Nil.String() {
+		return []string{id}
+	}
+	return nil
+}
+
+// firstSecurityGroupId returns the first of the security groups, it is sent along with the list for older
+// api servers that only support a single security group per device.
+func firstSecurityGroupId(ids []string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	return ids[0]
+}
+
+// securityGroupIdsOf returns the ids of the security groups
+func securityGroupIdsOf(groups []client.ModelsSecurityGroup) []string {
+	ids := make([]string, len(groups))
+	for i, sg := range groups {
+		ids[i] = sg.GetId()
+	}
+	return ids
+}
+
+// vpcMembersKey is the key of the members of a vpc in the map returned by securityGroupMembers, the default
+// security group of a vpc has the same id as the vpc so the vpc ids are prefixed.
+func vpcMembersKey(vpcId string) string {
+	return "vpc/" + vpcId
+}
+
+// securityGroupMembers maps the security group ids, and the vpc ids keyed with vpcMembersKey, to the tunnel
+// addresses of the devices in the group or vpc.
+func (nx *Nexodus) securityGroupMembers() map[string][]string {
+	members := map[string][]string{}
+	nx.deviceCacheIterRead(func(d deviceCacheEntry) {
+		var addrs []string
+		for _, ip := range d.device.GetIpv4TunnelIps() {
+			if addr := net.ParseIP(ip.GetAddress()); addr != nil && addr.To4() != nil {
+				addrs = append(addrs, addr.String()+"/32")
+			}
+		}
+		for _, ip := range d.device.GetIpv6TunnelIps() {
+			if addr := net.ParseIP(ip.GetAddress()); addr != nil && addr.To4() == nil {
+				addrs = append(addrs, addr.String()+"/128")
+			}
+		}
+		for _, id := range deviceSecurityGroupIds(d.device) {
+			members[id] = append(members[id], addrs...)
+		}
+		if vpcId := d.device.GetVpcId(); vpcId != "" {
+			members[vpcMembersKey(vpcId)] = append(members[vpcMembersKey(vpcId)], addrs...)
+		}
+	})
+	for _, addrs := range members {
+		sort.Strings(addrs)
+	}
+	return members
+}
+
+// resolveSecurityGroupRules returns the rules of the security groups that should be applied locally, one
+// securityGroupRules per group and direction in the order of the groups. A deny rule only applies within its
+// own group, it does not drop the traffic another group of the device allows.
+func (nx *Nexodus) resolveSecurityGroupRules(groups []client.ModelsSecurityGroup) securityRules {
+	if len(groups) == 0 {
+		return securityRules{}
+	}
+	members := nx.securityGroupMembers()
+	rules := securityRules{}
+	inboundOffset, outboundOffset := 0, 0
+	for _, sg := range groups {
+		rules.inbound = append(rules.inbound, resolveGroupSecurityRules(sg.InboundRules, members, inboundOffset))
+		rules.outbound = append(rules.outbound, resolveGroupSecurityRules(sg.OutboundRules, members, outboundOffset))
+		inboundOffset += len(sg.InboundRules)
+		outboundOffset += len(sg.OutboundRules)
+	}
+	return rules
+}
+
+// resolveGroupSecurityRules resolves the rules of a security group in one direction, offset is the index of
+// the first rule of the group in the rules of all the groups concatenated in order. A group without any allow
+// rule in the direction, including one without rules, allows the traffic its deny rules do not match.
+func resolveGroupSecurityRules(rules []client.ModelsSecurityRule, members map[string][]string, offset int) securityGroupRules {
+	resolved, index := resolveIndexedSecurityRules(rules, members)
+	for i := range index {
+		index[i] += offset
+	}
+	return securityGroupRules{
+		rules:        resolved,
+		index:        index,
+		defaultAllow: !securityRulesAllow(rules),
+	}
+}
+
+// resolveSecurityRules orders the rules by priority, keeping the order of rules with the same priority,
+// and replaces the security group references with the tunnel addresses of the group members. Rules
+// that mix IPv4 and IPv6 ranges are split into one rule per address family so that each of them can
+// be rendered into a single packet filter rule.
+func resolveSecurityRules(rules []client.ModelsSecurityRule, members map[string][]string) []client.ModelsSecurityRule {
+	resolved, _ := resolveIndexedSecurityRules(rules, members)
+	return resolved
+}
+
+// resolveIndexedSecurityRules resolves the rules like resolveSecurityRules and also returns the index
+// of the original rule of every resolved rule.
+func resolveIndexedSecurityRules(rules []client.ModelsSecurityRule, members map[string][]string) ([]client.ModelsSecurityRule, []int) {
+	sorted := make([]int, len(rules))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rules[sorted[i]].GetPriority() < rules[sorted[j]].GetPriority()
+	})
+
+	var resolved []client.ModelsSecurityRule
+	var index []int
+	for _, i := range sorted {
+		rule := rules[i]
+		ipRanges := rule.IpRanges
+		if len(rule.SecurityGroupIds) > 0 || len(rule.VpcIds) > 0 {
+			ipRanges = append([]string{}, rule.IpRanges...)
+			for _, id := range rule.SecurityGroupIds {
+				ipRanges = append(ipRanges, members[id]...)
+			}
+			for _, id := range rule.VpcIds {
+				ipRanges = append(ipRanges, members[vpcMembersKey(id)]...)
+			}
+			rule.SecurityGroupIds = nil
+			rule.VpcIds = nil
+			if len(ipRanges) == 0 {
+				// a reference to a group or vpc without members does not match anything, skip the rule
+				// instead of rendering it without addresses which would match any address.
+				continue
+			}
+		}
+
+		var v4Ranges, v6Ranges []string
+		wildcard := len(ipRanges) == 0
+		for _, ipRange := range ipRanges {
+			if ipRange == "" {
+				wildcard = true
+			} else if isIPv4Range(ipRange) {
+				v4Ranges = append(v4Ranges, ipRange)
+			} else {
+				v6Ranges = append(v6Ranges, ipRange)
+			}
+		}
+		if wildcard || len(v4Ranges) == 0 || len(v6Ranges) == 0 {
+			rule.IpRanges = ipRanges
+			if wildcard && len(ipRanges) > 0 {
+				// the rule already matches any address
+				rule.IpRanges = []string{""}
+			}
+			resolved = append(resolved, rule)
+			index = append(index, i)
+			continue
+		}
+
+		switch rule.GetIpProtocol() {
+		case "ipv4", "icmpv4":
+			v6Ranges = nil
+		case "ipv6", "icmpv6":
+			v4Ranges = nil
+		}
+		if len(v4Ranges) > 0 {
+			v4Rule := rule
+			v4Rule.IpRanges = v4Ranges
+			resolved = append(resolved, v4Rule)
+			index = append(index, i)
+		}
+		if len(v6Ranges) > 0 {
+			v6Rule := rule
+			v6Rule.IpRanges = v6Ranges
+			resolved = append(resolved, v6Rule)
+			index = append(index, i)
+		}
+	}
+	return resolved, index
+}
+
+// isIPv4Range returns true if the address, cidr or dash separated range is in the IPv4 family.
+func isIPv4Range(ipRange string) bool {
+	addr, _, _ := strings.Cut(ipRange, "-")
+	addr, _, _ = strings.Cut(addr, "/")
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	return ip != nil && ip.To4() != nil
+}
@@ -0,0 +1,122 @@
+package nexodus
+
+import (
+	"testing"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecurityRules(t *testing.T) {
+	require := require.New(t)
+
+	members := map[string][]string{
+		"web": {"100.64.0.1/32", "100.64.0.2/32", "200::1/128"},
+		"db":  {"100.64.0.3/32"},
+	}
+	rules := []client.ModelsSecurityRule{
+		{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(20), SecurityGroupIds: []string{"web"}},
+		{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(10), IpRanges: []string{"100.64.0.2"}, Action: client.PtrString(securityRuleActionDeny)},
+		// references a group without members and does not match anything
+		{IpProtocol: client.PtrString("udp"), SecurityGroupIds: []string{"empty"}},
+		// the v6 members are dropped from an ipv4 rule
+		{IpProtocol: client.PtrString("ipv4"), Priority: client.PtrInt32(30), IpRanges: []string{"10.0.0.0/8"}, SecurityGroupIds: []string{"db", "web"}},
+		// the wildcard range already matches the members of the group
+		{IpProtocol: client.PtrString("icmp"), Priority: client.PtrInt32(30), IpRanges: []string{""}, SecurityGroupIds: []string{"db"}},
+		{IpProtocol: client.PtrString("ipv6"), Priority: client.PtrInt32(40), IpRanges: []string{"300::/64"}},
+	}
+
+	resolved := resolveSecurityRules(rules, members)
+	require.Equal([]client.ModelsSecurityRule{
+		{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(10), IpRanges: []string{"100.64.0.2"}, Action: client.PtrString(securityRuleActionDeny)},
+		{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(20), IpRanges: []string{"100.64.0.1/32", "100.64.0.2/32"}},
+		{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(20), IpRanges: []string{"200::1/128"}},
+		{IpProtocol: client.PtrString("ipv4"), Priority: client.PtrInt32(30), IpRanges: []string{"10.0.0.0/8", "100.64.0.3/32", "100.64.0.1/32", "100.64.0.2/32"}},
+		{IpProtocol: client.PtrString("icmp"), Priority: client.PtrInt32(30), IpRanges: []string{""}},
+		{IpProtocol: client.PtrString("ipv6"), Priority: client.PtrInt32(40), IpRanges: []string{"300::/64"}},
+	}, resolved)
+
+	_, index := resolveIndexedSecurityRules(rules, members)
+	require.Equal([]int{1, 0, 0, 3, 4, 5}, index)
+	require.Equal(3, ruleIndex(index, 3))
+	require.Equal(-1, ruleIndex(index, 6))
+	require.Equal(-1, ruleIndex(nil, 0))
+
+	require.True(securityRulesAllow(resolved))
+	require.False(securityRulesAllow(resolved[:1]))
+	require.False(securityRulesAllow(nil))
+}
+
+func TestResolveSecurityGroupRules(t *testing.T) {
+	require := require.New(t)
+
+	device := func(ip string, securityGroupIds ...string) deviceCacheEntry {
+		return deviceCacheEntry{device: client.ModelsDevice{
+			Ipv4TunnelIps:    []client.ModelsTunnelIP{{Address: client.PtrString(ip)}},
+			SecurityGroupIds: securityGroupIds,
+		}}
+	}
+	nx := &Nexodus{deviceCache: map[string]deviceCacheEntry{
+		"web": device("100.64.0.1", "base", "web"),
+		"db":  device("100.64.0.2", "base", "db"),
+		// older api servers only set the single security group id
+		"admin": {device: client.ModelsDevice{
+			Ipv4TunnelIps:   []client.ModelsTunnelIP{{Address: client.PtrString("100.64.0.3")}},
+			SecurityGroupId: client.PtrString("admin"),
+			VpcId:           client.PtrString("peer"),
+		}},
+	}}
+
+	members := nx.securityGroupMembers()
+	require.Equal([]string{"100.64.0.1/32", "100.64.0.2/32"}, members["base"])
+	require.Equal([]string{"100.64.0.2/32"}, members["db"])
+	require.Equal([]string{"100.64.0.3/32"}, members["admin"])
+	// the devices of a peered vpc are referenced by the vpc id
+	require.Equal([]string{"100.64.0.3/32"}, members[vpcMembersKey("peer")])
+	require.Equal([]client.ModelsSecurityRule{{IpRanges: []string{"100.64.0.3/32"}}},
+		resolveSecurityRules([]client.ModelsSecurityRule{{VpcIds: []string{"peer"}}, {VpcIds: []string{"empty"}}}, members))
+
+	// the rules of every group are ordered by priority on their own
+	rules := nx.resolveSecurityGroupRules([]client.ModelsSecurityGroup{
+		{Id: client.PtrString("base"), InboundRules: []client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("icmp"), Priority: client.PtrInt32(30)},
+			{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(10), FromPort: client.PtrInt32(22), ToPort: client.PtrInt32(22), SecurityGroupIds: []string{"admin"}},
+		}},
+		{Id: client.PtrString("db"), InboundRules: []client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(30), Action: client.PtrString(securityRuleActionDeny)},
+			{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(20), FromPort: client.PtrInt32(5432), ToPort: client.PtrInt32(5432), SecurityGroupIds: []string{"web"}},
+		}, OutboundRules: []client.ModelsSecurityRule{
+			{IpProtocol: client.PtrString("udp"), Action: client.PtrString(securityRuleActionDeny)},
+		}},
+	})
+	require.Equal([]securityGroupRules{
+		{
+			rules: []client.ModelsSecurityRule{
+				{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(10), FromPort: client.PtrInt32(22), ToPort: client.PtrInt32(22), IpRanges: []string{"100.64.0.3/32"}},
+				{IpProtocol: client.PtrString("icmp"), Priority: client.PtrInt32(30)},
+			},
+			index: []int{1, 0},
+		},
+		{
+			rules: []client.ModelsSecurityRule{
+				{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(20), FromPort: client.PtrInt32(5432), ToPort: client.PtrInt32(5432), IpRanges: []string{"100.64.0.1/32"}},
+				{IpProtocol: client.PtrString("tcp"), Priority: client.PtrInt32(30), Action: client.PtrString(securityRuleActionDeny)},
+			},
+			// the index continues after the rules of the first group
+			index: []int{3, 2},
+		},
+	}, rules.inbound)
+	// a group without rules in a direction allows all the traffic, so does a group with only deny rules
+	require.Len(rules.outbound, 2)
+	require.True(rules.outbound[0].defaultAllow)
+	require.Empty(rules.outbound[0].rules)
+	require.True(rules.outbound[1].defaultAllow)
+	require.Equal([]int{0}, rules.outbound[1].index)
+
+	// a group whose allow rules only reference groups without members does not allow anything
+	require.False(resolveGroupSecurityRules([]client.ModelsSecurityRule{{SecurityGroupIds: []string{"empty"}}}, members, 0).defaultAllow)
+
+	require.Equal(securityRules{}, nx.resolveSecurityGroupRules(nil))
+	require.Nil(deviceSecurityGroupIds(client.ModelsDevice{SecurityGroupId: client.PtrString("00000000-0000-0000-0000-000000000000")}))
+	require.Equal([]string{"base", "db"}, securityGroupIdsOf([]client.ModelsSecurityGroup{{Id: client.PtrString("base")}, {Id: client.PtrString("db")}}))
+}
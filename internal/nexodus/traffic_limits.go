@@ -0,0 +1,247 @@
+package nexodus
+
+import (
+	"net/netip"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+)
+
+const (
+	// trafficLimitsTableName is the nftables table dropping the traffic of the peers above their limits
+	trafficLimitsTableName = "nexodus-traffic-limits"
+	trafficLimitsInput     = "input"
+	trafficLimitsForward   = "forward"
+	// nfPriorityTrafficLimits runs the limits ahead of the security group and relay filter chains
+	nfPriorityTrafficLimits = nfPriorityFilter - 10
+)
+
+// peerTrafficLimit is the traffic limits of a peer, applied to the traffic sent from its tunnel IPs. Zero rates are unlimited.
+type peerTrafficLimit struct {
+	publicKey        string
+	addrs            []netip.Addr
+	bytesPerSecond   uint64
+	packetsPerSecond uint64
+}
+
+// peerTrafficLimits returns the limits of the peers that have any, ordered by public key
+func peerTrafficLimits(peers map[string]client.ModelsDevice, self string) []peerTrafficLimit {
+	var limits []peerTrafficLimit
+	for _, peer := range peers {
+		if peer.GetPublicKey() == self || peer.TrafficLimits == nil {
+			continue
+		}
+		limit := peerTrafficLimit{
+			publicKey:        peer.GetPublicKey(),
+			bytesPerSecond:   uint64(max(peer.TrafficLimits.GetBitsPerSecond(), 0)) / 8,
+			packetsPerSecond: uint64(max(peer.TrafficLimits.GetPacketsPerSecond(), 0)),
+		}
+		if limit.bytesPerSecond == 0 && limit.packetsPerSecond == 0 {
+			continue
+		}
+		for _, ip := range append(append([]client.ModelsTunnelIP{}, peer.GetIpv4TunnelIps()...), peer.GetIpv6TunnelIps()...) {
+			if addr, err := netip.ParseAddr(ip.GetAddress()); err == nil {
+				limit.addrs = append(limit.addrs, addr.Unmap())
+			}
+		}
+		if len(limit.addrs) > 0 {
+			limits = append(limits, limit)
+		}
+	}
+	sort.Slice(limits, func(i, j int) bool {
+		return limits[i].publicKey < limits[j].publicKey
+	})
+	return limits
+}
+
+// reconcileTrafficLimits applies the traffic limits of the peers when they changed. Assumes deviceCacheLock is held.
+func (nx *Nexodus) reconcileTrafficLimits(peers map[string]client.ModelsDevice) {
+	limits := peerTrafficLimits(peers, nx.wireguardPubKey)
+	if nx.trafficLimitsApplied && reflect.DeepEqual(limits, nx.trafficLimits) {
+		return
+	}
+	if err := nx.applyTrafficLimits(limits); err != nil {
+		nx.logger.Errorf("failed to apply the traffic limits of the peers: %v", err)
+		return
+	}
+	nx.trafficLimits = limits
+	nx.trafficLimitsApplied = true
+}
+
+// applyTrafficLimits enforces the limits with the packet filter of the host, or with the token buckets of the
+// userspace wireguard device when running in userspace mode.
+func (nx *Nexodus) applyTrafficLimits(limits []peerTrafficLimit) error {
+	if nx.userspaceMode {
+		nx.userspaceLimiter.setLimits(limits)
+		return nil
+	}
+	return nx.processTrafficLimits(limits)
+}
+
+// nfTrafficLimitsTable drops the packets received from the peers over their limits, both the ones for this device
+// and the ones forwarded by relays, exit nodes and routers. Every address of a peer has its own limit rules.
+func nfTrafficLimitsTable(iface string, limits []peerTrafficLimit) nfTable {
+	var rules []nfRule
+	for _, limit := range limits {
+		for _, addr := range limit.addrs {
+			family := "ip"
+			if addr.Is6() {
+				family = "ip6"
+			}
+			source := nfRule{nfIfname{name: iface}, nfAddr{family: family, value: addr.String()}}
+			if limit.bytesPerSecond > 0 {
+				rules = append(rules, append(append(nfRule{}, source...), nfLimit{rate: limit.bytesPerSecond, bytes: true}, nfCounter{}, nfDrop))
+			}
+			if limit.packetsPerSecond > 0 {
+				rules = append(rules, append(append(nfRule{}, source...), nfLimit{rate: limit.packetsPerSecond}, nfCounter{}, nfDrop))
+			}
+		}
+	}
+	return nfTable{
+		family: tableFamily,
+		name:   trafficLimitsTableName,
+		chains: []nfChain{
+			{
+				name:      trafficLimitsInput,
+				chainType: nfChainTypeFilter,
+				hook:      nfHookInput,
+				priority:  nfPriorityTrafficLimits,
+				rules:     rules,
+			},
+			{
+				name:      trafficLimitsForward,
+				chainType: nfChainTypeFilter,
+				hook:      nfHookForward,
+				priority:  nfPriorityTrafficLimits,
+				rules:     rules,
+			},
+		},
+	}
+}
+
+// trafficLimiter enforces the traffic limits of the peers in userspace mode. Each peer has a token bucket for
+// the bytes and one for the packets, both holding one second worth of traffic.
+type trafficLimiter struct {
+	now     func() time.Time
+	enabled atomic.Bool
+
+	mu sync.Mutex
+	// buckets maps the tunnel IPs of the limited peers to the buckets of the peer
+	buckets map[netip.Addr]*trafficBuckets
+}
+
+type trafficBuckets struct {
+	publicKey string
+	bytes     tokenBucket
+	packets   tokenBucket
+}
+
+// tokenBucket is refilled at rate tokens per second up to rate tokens, a zero rate is unlimited
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newTrafficLimiter() *trafficLimiter {
+	return &trafficLimiter{now: time.Now}
+}
+
+func newTokenBucket(rate uint64, now time.Time) tokenBucket {
+	return tokenBucket{rate: float64(rate), tokens: float64(rate), last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(b.rate, b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+}
+
+func (b *tokenBucket) has(n float64) bool {
+	return b.rate == 0 || b.tokens >= n
+}
+
+func (b *tokenBucket) take(n float64) {
+	if b.rate != 0 {
+		b.tokens -= n
+	}
+}
+
+// setLimits replaces the enforced limits, the buckets of the peers whose limits did not change are kept
+func (l *trafficLimiter) setLimits(limits []peerTrafficLimit) {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	existing := map[string]*trafficBuckets{}
+	for _, b := range l.buckets {
+		existing[b.publicKey] = b
+	}
+	buckets := map[netip.Addr]*trafficBuckets{}
+	for _, limit := range limits {
+		b, found := existing[limit.publicKey]
+		if !found || b.bytes.rate != float64(limit.bytesPerSecond) || b.packets.rate != float64(limit.packetsPerSecond) {
+			b = &trafficBuckets{
+				publicKey: limit.publicKey,
+				bytes:     newTokenBucket(limit.bytesPerSecond, now),
+				packets:   newTokenBucket(limit.packetsPerSecond, now),
+			}
+		}
+		for _, addr := range limit.addrs {
+			buckets[addr] = b
+		}
+	}
+	l.buckets = buckets
+	l.enabled.Store(len(buckets) > 0)
+}
+
+// allow returns true if the packet received from a peer is within the limits of the peer
+func (l *trafficLimiter) allow(packet []byte) bool {
+	if !l.enabled.Load() {
+		return true
+	}
+	src, ok := packetSource(packet)
+	if !ok {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, found := l.buckets[src]
+	if !found {
+		return true
+	}
+	now := l.now()
+	b.bytes.refill(now)
+	b.packets.refill(now)
+	size := float64(len(packet))
+	if !b.bytes.has(size) || !b.packets.has(1) {
+		return false
+	}
+	b.bytes.take(size)
+	b.packets.take(1)
+	return true
+}
+
+// packetSource returns the source address of an IP packet
+func packetSource(packet []byte) (netip.Addr, bool) {
+	if len(packet) < 1 {
+		return netip.Addr{}, false
+	}
+	switch packet[0] >> 4 {
+	case 4:
+		if len(packet) >= 20 {
+			return netip.AddrFrom4([4]byte(packet[12:16])), true
+		}
+	case 6:
+		if len(packet) >= 40 {
+			return netip.AddrFrom16([16]byte(packet[8:24])), true
+		}
+	}
+	return netip.Addr{}, false
+}
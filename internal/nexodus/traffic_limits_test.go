@@ -0,0 +1,108 @@
+package nexodus
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/nexodus-io/nexodus/internal/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerTrafficLimits(t *testing.T) {
+	require := require.New(t)
+
+	device := func(publicKey, ipv4, ipv6 string, limits *client.ModelsTrafficLimits) client.ModelsDevice {
+		return client.ModelsDevice{
+			PublicKey:     client.PtrString(publicKey),
+			Ipv4TunnelIps: []client.ModelsTunnelIP{{Address: client.PtrString(ipv4)}},
+			Ipv6TunnelIps: []client.ModelsTunnelIP{{Address: client.PtrString(ipv6)}},
+			TrafficLimits: limits,
+		}
+	}
+	bandwidth := client.NewModelsTrafficLimits()
+	bandwidth.SetBitsPerSecond(8_000_000)
+	packets := client.NewModelsTrafficLimits()
+	packets.SetPacketsPerSecond(1000)
+
+	limits := peerTrafficLimits(map[string]client.ModelsDevice{
+		"self":      device("self", "100.64.0.1", "200::1", bandwidth),
+		"noisy":     device("noisy", "100.64.0.2", "200::2", bandwidth),
+		"chatty":    device("chatty", "100.64.0.3", "200::3", packets),
+		"unlimited": device("unlimited", "100.64.0.4", "200::4", nil),
+		"empty":     device("empty", "100.64.0.5", "200::5", client.NewModelsTrafficLimits()),
+	}, "self")
+	require.Equal([]peerTrafficLimit{
+		{publicKey: "chatty", addrs: []netip.Addr{netip.MustParseAddr("100.64.0.3"), netip.MustParseAddr("200::3")}, packetsPerSecond: 1000},
+		{publicKey: "noisy", addrs: []netip.Addr{netip.MustParseAddr("100.64.0.2"), netip.MustParseAddr("200::2")}, bytesPerSecond: 1_000_000},
+	}, limits)
+
+	table := nfTrafficLimitsTable("wg0", limits)
+	require.Len(table.chains, 2)
+	var rules []string
+	for _, rule := range table.chains[1].rules {
+		rules = append(rules, rule.String())
+	}
+	require.Equal([]string{
+		`iifname "wg0" ip saddr 100.64.0.3 limit rate over 1000/second counter drop`,
+		`iifname "wg0" ip6 saddr 200::3 limit rate over 1000/second counter drop`,
+		`iifname "wg0" ip saddr 100.64.0.2 limit rate over 1000000 bytes/second counter drop`,
+		`iifname "wg0" ip6 saddr 200::2 limit rate over 1000000 bytes/second counter drop`,
+	}, rules)
+	require.Equal(table.chains[0].rules, table.chains[1].rules)
+}
+
+func TestTrafficLimiter(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	limiter := newTrafficLimiter()
+	limiter.now = func() time.Time { return now }
+
+	packet := testPacket("100.64.0.2", "100.64.0.1", ipProtoUDP, 1000, 53)
+	packet6 := testPacket("200::2", "200::1", ipProtoUDP, 1000, 53)
+	other := testPacket("100.64.0.3", "100.64.0.1", ipProtoUDP, 1000, 53)
+	require.True(limiter.allow(packet))
+
+	limits := []peerTrafficLimit{{
+		publicKey:        "noisy",
+		addrs:            []netip.Addr{netip.MustParseAddr("100.64.0.2"), netip.MustParseAddr("200::2")},
+		bytesPerSecond:   1_000_000,
+		packetsPerSecond: 10,
+	}}
+	limiter.setLimits(limits)
+
+	// the bucket holds one second of packets, shared by the addresses of the peer
+	for i := 0; i < 5; i++ {
+		require.True(limiter.allow(packet))
+		require.True(limiter.allow(packet6))
+	}
+	require.False(limiter.allow(packet))
+	require.False(limiter.allow(packet6))
+	require.True(limiter.allow(other))
+
+	now = now.Add(100 * time.Millisecond)
+	require.True(limiter.allow(packet))
+	require.False(limiter.allow(packet))
+
+	// unchanged limits keep the state of the buckets
+	limiter.setLimits(limits)
+	require.False(limiter.allow(packet))
+
+	// the bandwidth limit drops the packets larger than the tokens left
+	limits[0].bytesPerSecond = 100
+	limits[0].packetsPerSecond = 0
+	limiter.setLimits(limits)
+	require.Len(packet, 28)
+	for i := 0; i < 3; i++ {
+		require.True(limiter.allow(packet))
+	}
+	require.False(limiter.allow(packet))
+	now = now.Add(time.Second)
+	require.True(limiter.allow(packet))
+
+	limiter.setLimits(nil)
+	for i := 0; i < 100; i++ {
+		require.True(limiter.allow(packet))
+	}
+}
@@ -2,6 +2,7 @@ package nexodus
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/nexodus-io/nexodus/internal/state"
@@ -10,7 +11,6 @@ import (
 	"os"
 	"strconv"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/bytedance/gopkg/util/logger"
@@ -22,11 +22,19 @@ import (
 )
 
 type UsProxy struct {
-	key               ProxyKey
-	logger            *zap.SugaredLogger
-	debugTraffic      bool
-	mu                sync.RWMutex
-	rules             []ProxyRule
+	key          ProxyKey
+	logger       *zap.SugaredLogger
+	debugTraffic bool
+	mu           sync.RWMutex
+	rules        []ProxyRule
+	lbPolicy     ProxyLbPolicy
+	resolver     *proxyResolver
+	// certificate returns the certificate of a TLS terminating http proxy for the DNS names
+	certificate       func(ctx context.Context, names []string) (*tls.Certificate, error)
+	certMu            sync.Mutex
+	cert              *tls.Certificate
+	certNames         string
+	dests             map[HostPort]*proxyDestState
 	connectionCounter uint64
 	userspaceNet      *netstack.Net
 	proxyCtx          context.Context
@@ -48,12 +56,24 @@ func (nx *Nexodus) UserspaceProxyAdd(newRule ProxyRule) (*UsProxy, error) {
 	nx.proxyLock.Lock()
 	defer nx.proxyLock.Unlock()
 
+	if newRule.protocol != proxyProtocolUDP {
+		// tcp and http rules cannot share a listener
+		for key := range nx.proxies {
+			if key != newRule.ProxyKey && key.ruleType == newRule.ruleType && key.listenPort == newRule.listenPort && key.protocol != proxyProtocolUDP {
+				return nil, fmt.Errorf("port %d is already used by the %s proxy rules", newRule.listenPort, key.protocol)
+			}
+		}
+	}
+
 	proxy, found := nx.proxies[newRule.ProxyKey]
 	if !found {
 		proxy = &UsProxy{
-			key:    newRule.ProxyKey,
-			logger: nx.logger.With("proxy", newRule.ruleType, "key", newRule.ProxyKey),
+			key:      newRule.ProxyKey,
+			logger:   nx.logger.With("proxy", newRule.ruleType, "key", newRule.ProxyKey),
+			lbPolicy: proxyLbRoundRobin,
+			resolver: nx.proxyResolver,
 		}
+		proxy.certificate = nx.proxyCertificate
 		proxy.debugTraffic, _ = strconv.ParseBool(os.Getenv("NEXD_PROXY_DEBUG_TRAFFIC"))
 		nx.proxies[newRule.ProxyKey] = proxy
 	}
@@ -62,6 +82,9 @@ func (nx *Nexodus) UserspaceProxyAdd(newRule ProxyRule) (*UsProxy, error) {
 		if rule == newRule {
 			return proxy, ProxyExistsError
 		}
+		if rule.tls != newRule.tls {
+			return nil, fmt.Errorf("the rules of the %s proxy on port %d must all terminate tls or none", newRule.protocol, newRule.listenPort)
+		}
 	}
 
 	proxy.mu.Lock()
@@ -89,6 +112,7 @@ func (nx *Nexodus) UserspaceProxyRemove(cmpProxy ProxyRule) (*UsProxy, error) {
 	for i, rule := range proxy.rules {
 		if rule == cmpProxy {
 			proxy.rules = append(proxy.rules[:i], proxy.rules[i+1:]...)
+			delete(proxy.dests, rule.dest)
 			if len(proxy.rules) == 0 {
 				proxy.Stop()
 				delete(nx.proxies, cmpProxy.ProxyKey)
@@ -131,6 +155,21 @@ func (nx *Nexodus) LoadProxyRules() error {
 	if err != nil {
 		return nil
 	}
+
+	for key, policyStr := range rules.LoadBalancing {
+		policy, err := parseProxyLbPolicy(policyStr)
+		if err != nil {
+			nx.logger.Warnf("Ignoring the stored load balancing policy of the %s proxy: %v", key, err)
+			continue
+		}
+		for proxyKey := range nx.proxies {
+			if proxyKey.String() == key {
+				if err := nx.UserspaceProxySetLbPolicy(proxyKey, policy); err != nil {
+					return err
+				}
+			}
+		}
+	}
 	return nil
 }
 
@@ -141,8 +180,10 @@ func (nx *Nexodus) StoreProxyRules() error {
 	rules := state.ProxyRulesConfig{}
 	for _, proxy := range nx.proxies {
 		proxy.mu.Lock()
+		stored := false
 		for _, rule := range proxy.rules {
 			if rule.stored {
+				stored = true
 				if rule.ruleType == ProxyTypeEgress {
 					rules.Egress = append(rules.Egress, rule.String())
 				} else {
@@ -150,6 +191,12 @@ func (nx *Nexodus) StoreProxyRules() error {
 				}
 			}
 		}
+		if stored && proxy.lbPolicy != proxyLbRoundRobin {
+			if rules.LoadBalancing == nil {
+				rules.LoadBalancing = map[string]string{}
+			}
+			rules.LoadBalancing[proxy.key.String()] = string(proxy.lbPolicy)
+		}
 		proxy.mu.Unlock()
 	}
 
@@ -183,6 +230,13 @@ func (proxy *UsProxy) Start(ctx context.Context, wg *sync.WaitGroup, net *netsta
 			time.Sleep(time.Second)
 		}
 	})
+	if proxy.key.protocol != proxyProtocolUDP {
+		proxy.wg.Add(1)
+		util.GoWithWaitGroup(wg, func() {
+			defer proxy.wg.Done()
+			proxy.runHealthChecks(proxy.proxyCtx)
+		})
+	}
 
 }
 
@@ -197,6 +251,8 @@ func (proxy *UsProxy) run(ctx context.Context, proxyWg *sync.WaitGroup) error {
 		return proxy.runTCP(ctx, proxyWg)
 	case proxyProtocolUDP:
 		return proxy.runUDP(ctx, proxyWg)
+	case proxyProtocolHTTP:
+		return proxy.runHTTP(ctx, proxyWg)
 	default:
 		return fmt.Errorf("unexpected proxy protocol: %v", proxy.key.protocol)
 	}
@@ -228,6 +284,8 @@ type udpProxyConn struct {
 	closeChan chan string
 	// track the last time inbound traffic was received
 	lastActivity time.Time
+	// release the destination picked by the load balancing policy
+	release func()
 }
 
 func (udpProxy *udpProxy) setupListener() error {
@@ -413,38 +471,37 @@ func (proxyConn *udpProxyConn) writeBackToOriginator(buffer []byte, n int) error
 	return err
 }
 
-func (proxy *UsProxy) NextDest() HostPort {
-	proxy.mu.RLock()
-	defer proxy.mu.RUnlock()
-
-	counter := atomic.AddUint64(&proxy.connectionCounter, 1)
-
-	index := counter % uint64(len(proxy.rules))
-	return proxy.rules[index].dest
-}
-
 func (proxy *UsProxy) createUDPProxyConn(ctx context.Context, proxyWg *sync.WaitGroup, proxyConn *udpProxyConn) error {
 	var err error
-	dest := proxy.NextDest()
+	dest, release := proxy.NextDest(proxyConn.clientAddr)
 	logger := proxy.logger.With("dest", dest)
 
+	resolved, err := proxy.resolveDest(ctx, dest)
+	if err != nil {
+		release()
+		return err
+	}
 	if proxy.key.ruleType == ProxyTypeEgress {
-		newConn, err := proxy.userspaceNet.DialUDP(nil, &net.UDPAddr{Port: dest.port, IP: net.ParseIP(dest.host)})
+		newConn, err := proxy.userspaceNet.DialUDP(nil, &net.UDPAddr{Port: resolved.port, IP: net.ParseIP(resolved.host)})
 		if err != nil {
+			release()
 			return fmt.Errorf("Error dialing UDP proxy destination: %w", err)
 		}
 		proxyConn.goProxyConn = newConn
 	} else {
-		udpDest := net.JoinHostPort(dest.host, fmt.Sprintf("%d", dest.port))
+		udpDest := resolved.String()
 		addr, err := net.ResolveUDPAddr("udp", udpDest)
 		if err != nil {
+			release()
 			return fmt.Errorf("Failed to resolve UDP address: %w", err)
 		}
 		proxyConn.proxyConn, err = net.DialUDP("udp", nil, addr)
 		if err != nil {
+			release()
 			return fmt.Errorf("Failed to Dial UDP destination %s: %w", udpDest, err)
 		}
 	}
+	proxyConn.release = release
 
 	// Start a goroutine to handle proxying data from the destination back to the client.
 	util.GoWithWaitGroup(proxyWg, func() {
@@ -503,22 +560,32 @@ func (proxy *UsProxy) createUDPProxyConn(ctx context.Context, proxyWg *sync.Wait
 				timer.Reset(udpTimeout)
 			}
 		}
+		proxyConn.release()
 		proxyConn.closeChan <- proxyConn.clientAddr.String()
 	})
 
 	return nil
 }
 
-func (proxy *UsProxy) runTCP(ctx context.Context, proxyWg *sync.WaitGroup) error {
+// listenTCP listens on the local network for an egress proxy, and on the wireguard tunnel for an ingress proxy
+func (proxy *UsProxy) listenTCP() (net.Listener, error) {
 	var l net.Listener
 	var err error
 	if proxy.key.ruleType == ProxyTypeEgress {
-		l, err = net.Listen(fmt.Sprintf("%v", proxy.key.protocol), fmt.Sprintf(":%d", proxy.key.listenPort))
+		l, err = net.Listen(proxy.key.protocol.network(), fmt.Sprintf(":%d", proxy.key.listenPort))
 	} else {
 		l, err = proxy.userspaceNet.ListenTCP(&net.TCPAddr{Port: proxy.key.listenPort})
 	}
 	if err != nil {
 		proxy.logger.Error("Error creating listener: ", err)
+		return nil, err
+	}
+	return l, nil
+}
+
+func (proxy *UsProxy) runTCP(ctx context.Context, proxyWg *sync.WaitGroup) error {
+	l, err := proxy.listenTCP()
+	if err != nil {
 		return err
 	}
 	defer util.IgnoreError(l.Close)
@@ -573,25 +640,28 @@ func (proxy *UsProxy) runTCP(ctx context.Context, proxyWg *sync.WaitGroup) error
 func (proxy *UsProxy) handleTCPConnection(ctx context.Context, proxyWg *sync.WaitGroup, inConn net.Conn) error {
 	defer util.IgnoreError(inConn.Close)
 
-	dest := proxy.NextDest()
+	dest, release := proxy.NextDest(inConn.RemoteAddr())
+	defer release()
 	logger := proxy.logger.With("dest", dest)
 
-	proxyDest := net.JoinHostPort(dest.host, fmt.Sprintf("%d", dest.port))
-	logger.Debugf("Handling connection from %s, proxying to %s", inConn.RemoteAddr().String(), proxyDest)
+	logger.Debugf("Handling connection from %s, proxying to %s", inConn.RemoteAddr().String(), dest)
 
-	var outConn net.Conn
-	var err error
-	protocolStr := fmt.Sprintf("%v", proxy.key.protocol)
-	if proxy.key.ruleType == ProxyTypeEgress {
-		outConn, err = proxy.userspaceNet.DialContext(ctx, protocolStr, proxyDest)
-	} else {
-		outConn, err = net.Dial(protocolStr, proxyDest)
-	}
+	outConn, err := proxy.dialDest(ctx, dest)
 	if err != nil {
 		return err
 	}
 	defer util.IgnoreError(outConn.Close)
 
+	if version := proxy.destProxyProtocolVersion(dest); version != 0 {
+		header, err := proxyProtocolHeader(version, inConn.RemoteAddr(), inConn.LocalAddr())
+		if err != nil {
+			return err
+		}
+		if _, err := outConn.Write(header); err != nil {
+			return fmt.Errorf("failed to send the PROXY protocol header: %w", err)
+		}
+	}
+
 	util.GoWithWaitGroup(proxyWg, func() {
 		_, err := io.Copy(inConn, outConn)
 		if err != nil {
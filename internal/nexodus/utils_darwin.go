@@ -139,6 +139,16 @@ func getDefaultGatewayIPv4() (string, error) {
 	return "", fmt.Errorf("method currently unsupported for darwin")
 }
 
+// getDefaultGatewayIPv6 not currently implemented for darwin
+func getDefaultGatewayIPv6() (string, string, error) {
+	return "", "", fmt.Errorf("method currently unsupported for darwin")
+}
+
+// DefaultRouteIPv6Exists not currently implemented for darwin
+func DefaultRouteIPv6Exists() bool {
+	return false
+}
+
 // isElevatedUnix checks that nexd was started with appropriate permissions for Unix-based OS mode (Linux/macOS)
 func isElevated() (bool, error) {
 	if os.Geteuid() != 0 {
@@ -231,6 +231,36 @@ func getDefaultGatewayIPv4() (string, error) {
 	return "", fmt.Errorf("unable to determine default route")
 }
 
+// getDefaultGatewayIPv6 returns the IPv6 default gateway and the interface it is reached through, the
+// interface is needed since IPv6 gateways are usually link local addresses
+func getDefaultGatewayIPv6() (string, string, error) {
+	routes, err := netlink.RouteList(nil, syscall.AF_INET6)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, route := range routes {
+		if route.Dst == nil || route.Dst.String() == "::/0" {
+			if route.Gw == nil {
+				return "", "", fmt.Errorf("IPv6 default route present, but gateway was not found")
+			}
+			link, err := netlink.LinkByIndex(route.LinkIndex)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to lookup the interface of the IPv6 default route: %w", err)
+			}
+			return route.Gw.String(), link.Attrs().Name, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("unable to determine IPv6 default route")
+}
+
+// DefaultRouteIPv6Exists returns true if the host has an IPv6 default gateway
+func DefaultRouteIPv6Exists() bool {
+	_, _, err := getDefaultGatewayIPv6()
+	return err == nil
+}
+
 // isElevatedUnix checks that nexd was started with appropriate permissions for Unix-based OS mode (Linux/macOS)
 func isElevated() (bool, error) {
 	if os.Geteuid() != 0 {
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"github.com/nexodus-io/nexodus/internal/api"
 	"github.com/nexodus-io/nexodus/internal/client"
 	"net"
 	"strconv"
@@ -163,6 +164,7 @@ func (nx *Nexodus) handlePeerDelete(peerMap map[string]client.ModelsDevice) erro
 		}
 		// remove peer from local peer and key cache
 		delete(nx.deviceCache, p.device.GetPublicKey())
+		nx.publishPeerEvent(api.EventPeerRemoved, p.device)
 	}
 
 	return nil
@@ -4,16 +4,14 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"os/exec"
 	"strings"
 
 	"go.uber.org/zap"
 )
 
 const (
-	fwdFilePathV4  = "/proc/sys/net/ipv4/ip_forward"
-	fwdFilePathV6  = "/proc/sys/net/ipv6/conf/all/forwarding"
-	nftablesBinary = "nft"
+	fwdFilePathV4 = "/proc/sys/net/ipv4/ip_forward"
+	fwdFilePathV6 = "/proc/sys/net/ipv6/conf/all/forwarding"
 )
 
 // ifaceExists returns true if the input matches a net interface
@@ -87,10 +85,6 @@ func (nx *Nexodus) enableForwardingIP() error {
 		}
 	}
 
-	if !IsCommandAvailable(nftablesBinary) {
-		return fmt.Errorf("required relay command %s not found, verify %s is installed", nftablesBinary, nftablesBinary)
-	}
-
 	return nil
 }
 
@@ -129,39 +123,28 @@ func isIPForwardingEnabled(ipForwardFilePath string) (bool, error) {
 	return false, nil
 }
 
-// nfRelayTablesSetup adds v4/v6 nftables rules for the relay node
-func nfRelayTablesSetup(dev string) error {
-	nftV4 := []string{
-		"add table ip filter",
-		"add chain ip filter FORWARD { type filter hook forward priority 0; }",
-		fmt.Sprintf(`add rule ip filter FORWARD iifname "%s" counter accept`, dev),
-	}
-
-	for _, cmd := range nftV4 {
-		if err := runNftCommand(cmd); err != nil {
-			return err
+// nfRelayTablesSetup adds v4/v6 nftables rules for the relay node. The filter tables may also be used
+// by iptables-nft or firewalld, only the rule added by nexd is managed and the other rules are kept.
+func (nx *Nexodus) nfRelayTablesSetup(dev string) error {
+	for _, family := range []string{"ip", "ip6"} {
+		table := nfTable{
+			family: family,
+			name:   "filter",
+			shared: true,
+			chains: []nfChain{
+				{
+					name:      "FORWARD",
+					chainType: nfChainTypeFilter,
+					hook:      nfHookForward,
+					priority:  nfPriorityFilter,
+					rules:     []nfRule{{nfIfname{name: dev}, nfCounter{}, nfAccept}},
+				},
+			},
 		}
-	}
-
-	nftV6 := []string{
-		"add table ip6 filter",
-		"add chain ip6 filter FORWARD { type filter hook forward priority 0; }",
-		fmt.Sprintf(`add rule ip6 filter FORWARD iifname "%s" counter accept`, dev),
-	}
-
-	for _, cmd := range nftV6 {
-		if err := runNftCommand(cmd); err != nil {
+		if err := nfApplyTable(nx.logger, table); err != nil {
 			return err
 		}
 	}
-	return nil
-}
 
-func runNftCommand(cmd string) error {
-	nft := exec.Command("nft", cmd)
-	output, err := nft.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("nft command: %q failed: %w\noutput: %s", cmd, err, output)
-	}
 	return nil
 }
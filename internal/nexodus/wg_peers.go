@@ -2,6 +2,7 @@ package nexodus
 
 import (
 	"fmt"
+	"github.com/nexodus-io/nexodus/internal/api"
 	"github.com/nexodus-io/nexodus/internal/client"
 	"net"
 	"net/netip"
@@ -308,6 +309,9 @@ func (nx *Nexodus) buildPeersConfig() map[string]client.ModelsDevice {
 
 	}
 
+	// pick the exit node origin before building the peers, only that peer gets the default routes
+	nx.updateExitNodeOrigins()
+
 	now := time.Now()
 	wgRelayAvailable := relayAvailable && !isDerpRelay
 	for _, dIter := range nx.deviceCache {
@@ -318,6 +322,8 @@ func (nx *Nexodus) buildPeersConfig() map[string]client.ModelsDevice {
 		}
 
 		peerConfig, chosenMethod, chosenMethodIndex := nx.rebuildPeerConfig(&d, healthyRelay, wgRelayAvailable)
+		peerConfig.AllowedIPs = nx.exitNodeAllowedIPs(d.device.GetPublicKey(), peerConfig.AllowedIPs)
+		peerConfig.AllowedIPsForRelay = nx.exitNodeAllowedIPs(d.device.GetPublicKey(), peerConfig.AllowedIPsForRelay)
 		if len(peerConfig.AllowedIPsForRelay) > 0 {
 			allowedIPsForRelay = append(allowedIPsForRelay, peerConfig.AllowedIPsForRelay...)
 		}
@@ -337,6 +343,16 @@ func (nx *Nexodus) buildPeersConfig() map[string]client.ModelsDevice {
 		} else {
 			nx.wgConfig.Peers[d.device.GetPublicKey()] = peerConfig
 		}
+		if chosenMethod != d.peeringMethod {
+			nx.events.publish(api.Event{
+				Type:                  api.EventPeeringMethodChanged,
+				PublicKey:             d.device.GetPublicKey(),
+				Hostname:              d.device.GetHostname(),
+				DeviceId:              d.device.GetId(),
+				PeeringMethod:         chosenMethod,
+				PreviousPeeringMethod: d.peeringMethod,
+			})
+		}
 		d.peeringMethodIndex = chosenMethodIndex
 		d.peeringMethod = chosenMethod
 		d.peeringTime = now
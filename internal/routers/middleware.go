@@ -35,6 +35,7 @@ func ValidateJWT(ctx context.Context, o APIRouterOptions, jwksURI string, nexodu
 	query, err := rego.New(
 		rego.Query(`result = {
 			"authorized": data.token.valid_token,
+			"api_token": data.token.valid_api_token,
 			"allow": data.token.allow,
 			"user_id": data.token.user_id,
 			"user_name": data.token.user_name,
@@ -162,6 +163,26 @@ func ValidateJWT(ctx context.Context, o APIRouterOptions, jwksURI string, nexodu
 			idpUserName = idpFullName
 		}
 
+		// api tokens act as existing users, and they are rejected once revoked even though their JWT is still valid.
+		if apiToken, _ := result["api_token"].(bool); apiToken {
+			tokenID, _ := claims["jti"].(string)
+			userID, err := o.Api.ApiTokenUserID(c.Request.Context(), tokenID)
+			if err != nil {
+				var apiResponseError *handlers.ApiResponseError
+				if errors.As(err, &apiResponseError) {
+					c.JSON(apiResponseError.Status, apiResponseError.Body)
+				} else {
+					o.Api.SendInternalServerError(c, err)
+				}
+				c.Abort()
+				return
+			}
+			c.Set(gin.AuthUserKey, userID)
+			c.Set(AuthUserName, idpUserName)
+			c.Next()
+			return
+		}
+
 		var limiters *UserLimiters
 		userLimiters.SetIf(idpUserID, func(value *UserLimiters, found bool) (*UserLimiters, bool) {
 			if !found {
@@ -109,6 +109,7 @@ func NewAPIRouter(ctx context.Context, o APIRouterOptions) (*gin.Engine, error)
 		}
 
 		apiGroup.Use(validateJWT)
+		apiGroup.Use(api.AuditMutations)
 
 		// Feature Flags
 		apiGroup.GET("fflags", api.ListFeatureFlags)
@@ -128,8 +129,12 @@ func NewAPIRouter(ctx context.Context, o APIRouterOptions) (*gin.Engine, error)
 
 		apiGroup.GET("/organizations/:id/users", api.ListOrganizationUsers)
 		apiGroup.GET("/organizations/:id/users/:uid", api.GetOrganizationUser)
+		apiGroup.PATCH("/organizations/:id/users/:uid", api.UpdateOrganizationUser)
 		apiGroup.DELETE("/organizations/:id/users/:uid", api.DeleteOrganizationUser)
 
+		apiGroup.GET("/organizations/:id/audit", api.ListOrganizationAudit)
+		apiGroup.GET("/organizations/:id/audit/verify", api.VerifyOrganizationAudit)
+
 		// Invitations
 		apiGroup.GET("/invitations", api.ListInvitations)
 		apiGroup.GET("/invitations/:id", api.GetInvitation)
@@ -144,6 +149,18 @@ func NewAPIRouter(ctx context.Context, o APIRouterOptions) (*gin.Engine, error)
 		apiGroup.PATCH("/reg-keys/:id", api.UpdateRegKey)
 		apiGroup.DELETE("/reg-keys/:id", api.DeleteRegKey)
 
+		// Service Accounts
+		apiGroup.GET("/service-accounts", api.ListServiceAccounts)
+		apiGroup.GET("/service-accounts/:id", api.GetServiceAccount)
+		apiGroup.POST("/service-accounts", api.CreateServiceAccount)
+		apiGroup.DELETE("/service-accounts/:id", api.DeleteServiceAccount)
+
+		// API Tokens
+		apiGroup.GET("/tokens", api.ListApiTokens)
+		apiGroup.GET("/tokens/:id", api.GetApiToken)
+		apiGroup.POST("/tokens", api.CreateApiToken)
+		apiGroup.DELETE("/tokens/:id", api.DeleteApiToken)
+
 		// Events
 		apiGroup.POST("/events", api.WatchEvents)
 
@@ -159,6 +176,13 @@ func NewAPIRouter(ctx context.Context, o APIRouterOptions) (*gin.Engine, error)
 		apiGroup.GET("/vpcs/:id/metadata", api.ListMetadataInVPC)
 		apiGroup.GET("/vpcs/:id/security-groups", api.ListSecurityGroupsInVPC)
 
+		// VPC Peerings
+		apiGroup.GET("/vpc-peerings", api.ListVPCPeerings)
+		apiGroup.GET("/vpc-peerings/:id", api.GetVPCPeering)
+		apiGroup.POST("/vpc-peerings", api.CreateVPCPeering)
+		apiGroup.POST("/vpc-peerings/:id/accept", api.AcceptVPCPeering)
+		apiGroup.DELETE("/vpc-peerings/:id", api.DeleteVPCPeering)
+
 		// Devices
 		apiGroup.GET("/devices", api.ListDevices)
 		apiGroup.GET("/devices/:id", api.GetDevice)
@@ -176,8 +200,10 @@ func NewAPIRouter(ctx context.Context, o APIRouterOptions) (*gin.Engine, error)
 		// Security Groups
 		apiGroup.GET("/security-groups", api.ListSecurityGroups)
 		apiGroup.GET("/security-groups/:id", api.GetSecurityGroup)
+		apiGroup.GET("/security-groups/:id/stats", api.GetSecurityGroupStats)
 		apiGroup.POST("/security-groups", api.CreateSecurityGroup)
 		apiGroup.PATCH("/security-groups/:id", api.UpdateSecurityGroup)
+		apiGroup.POST("/security-groups/:id/simulate", api.SimulateSecurityGroup)
 		apiGroup.DELETE("/security-groups/:id", api.DeleteSecurityGroup)
 
 		// Service Networks
@@ -203,6 +229,7 @@ func NewAPIRouter(ctx context.Context, o APIRouterOptions) (*gin.Engine, error)
 		privateGroup.GET("/gc", o.Api.GarbageCollect, loggerMiddleware)
 		privateGroup.GET("/ready", o.Api.Ready)
 		privateGroup.GET("/live", o.Api.Live)
+		privateGroup.PUT("/organizations/:id/quotas", o.Api.UpdateOrganizationQuotas, loggerMiddleware)
 	}
 
 	return r, nil
@@ -4,6 +4,8 @@ import future.keywords
 
 default valid_keycloak_token := false
 
+default valid_api_token := false
+
 valid_nexodus_token if {
 	[valid, _, _] := io.jwt.decode_verify(input.access_token, {"cert": input.nexodus_jwks})
 	valid == true
@@ -32,6 +34,20 @@ valid_device_token if {
 	contains(token_payload.scope, "device-token")
 }
 
+valid_api_token if {
+	valid_nexodus_token
+	contains(token_payload.scope, "api-token")
+}
+
+# api tokens carry the same scopes as the keycloak tokens of users
+valid_user_token if {
+	valid_keycloak_token
+}
+
+valid_user_token if {
+	valid_api_token
+}
+
 default allow := false
 
 allow if {
@@ -40,11 +56,12 @@ allow if {
 		"invitations",
 		"reg-keys",
 		"vpcs",
+		"vpc-peerings",
 		"service-networks",
 		"security-groups",
 	]
 	action_is_read
-	valid_keycloak_token
+	valid_user_token
 	contains(token_payload.scope, "read:organizations")
 }
 
@@ -56,11 +73,12 @@ allow if {
 		"invitations",
 		"reg-keys",
 		"vpcs",
+		"vpc-peerings",
 		"service-networks",
 		"security-groups",
 	]
 	action_is_write
-	valid_keycloak_token
+	valid_user_token
 	contains(token_payload.scope, "write:organizations")
 }
 
@@ -70,7 +88,7 @@ allow if {
 		"sites",
 	]
 	action_is_read
-	valid_keycloak_token
+	valid_user_token
 	contains(token_payload.scope, "read:devices")
 }
 
@@ -80,27 +98,48 @@ allow if {
 		"sites",
 	]
 	action_is_write
-	valid_keycloak_token
+	valid_user_token
 	contains(token_payload.scope, "write:devices")
 }
 
 allow if {
 	input.path[1] in ["users"]
 	action_is_read
-	valid_keycloak_token
+	valid_user_token
 	contains(token_payload.scope, "read:users")
 }
 
 allow if {
 	input.path[1] in ["users"]
 	action_is_write
-	valid_keycloak_token
+	valid_user_token
 	contains(token_payload.scope, "write:users")
 }
 
 allow if {
 	input.path[1] in ["fflags"]
+	valid_user_token
+}
+
+# only users can manage service accounts and api tokens, an api token cannot mint more tokens
+allow if {
+	input.path[1] in [
+		"service-accounts",
+		"tokens",
+	]
+	action_is_read
 	valid_keycloak_token
+	contains(token_payload.scope, "read:organizations")
+}
+
+allow if {
+	input.path[1] in [
+		"service-accounts",
+		"tokens",
+	]
+	action_is_write
+	valid_keycloak_token
+	contains(token_payload.scope, "write:organizations")
 }
 
 # reg token can get its own token
@@ -191,7 +230,7 @@ allow if {
 allow if {
 	input.path == ["api", "events"]
 	input.method == "POST"
-	valid_keycloak_token
+	valid_user_token
 }
 
 allow if {
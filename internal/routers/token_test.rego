@@ -200,3 +200,60 @@ test_get_fflags_bad_jwt_denied if {
 		with io.jwt.decode_verify as mock_decode_verify
 		with io.jwt.decode as mock_decode
 }
+
+mock_decode_verify("api-read-jwt", {"cert": "nexodus-cert"}) := [true, {}, {}]
+
+mock_decode("api-read-jwt") := [{}, valid_user("api-token read:devices"), {}]
+
+mock_decode_verify("api-write-jwt", {"cert": "nexodus-cert"}) := [true, {}, {}]
+
+mock_decode("api-write-jwt") := [{}, valid_user("api-token write:organizations"), {}]
+
+test_api_token_device_get_allowed if {
+	token.allow with input.path as ["api", "devices"]
+		with input.method as "GET"
+		with input.jwks as "my-cert"
+		with input.nexodus_jwks as "nexodus-cert"
+		with input.access_token as "api-read-jwt"
+		with io.jwt.decode_verify as mock_decode_verify
+		with io.jwt.decode as mock_decode
+}
+
+test_api_token_device_post_with_read_scope_denied if {
+	not token.allow with input.path as ["api", "devices"]
+		with input.method as "POST"
+		with input.jwks as "my-cert"
+		with input.nexodus_jwks as "nexodus-cert"
+		with input.access_token as "api-read-jwt"
+		with io.jwt.decode_verify as mock_decode_verify
+		with io.jwt.decode as mock_decode
+}
+
+test_api_token_org_post_allowed if {
+	token.allow with input.path as ["api", "vpcs"]
+		with input.method as "POST"
+		with input.jwks as "my-cert"
+		with input.nexodus_jwks as "nexodus-cert"
+		with input.access_token as "api-write-jwt"
+		with io.jwt.decode_verify as mock_decode_verify
+		with io.jwt.decode as mock_decode
+}
+
+test_api_token_create_token_denied if {
+	not token.allow with input.path as ["api", "tokens"]
+		with input.method as "POST"
+		with input.jwks as "my-cert"
+		with input.nexodus_jwks as "nexodus-cert"
+		with input.access_token as "api-write-jwt"
+		with io.jwt.decode_verify as mock_decode_verify
+		with io.jwt.decode as mock_decode
+}
+
+test_user_create_token_allowed if {
+	token.allow with input.path as ["api", "tokens"]
+		with input.method as "POST"
+		with input.jwks as "my-cert"
+		with input.access_token as "org-write-jwt"
+		with io.jwt.decode_verify as mock_decode_verify
+		with io.jwt.decode as mock_decode
+}
@@ -18,6 +18,9 @@ type State struct {
 type ProxyRulesConfig struct {
 	Egress  []string `json:"egress"`
 	Ingress []string `json:"ingress"`
+	// LoadBalancing maps the proxy listeners, in the form type:protocol:port, to their load balancing policy
+	// when it is not round-robin
+	LoadBalancing map[string]string `json:"load-balancing,omitempty"`
 }
 
 type Store interface {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/netip"
 	"strings"
 )
 
@@ -121,6 +122,30 @@ func IsValidPrefix(prefix string) bool {
 	return IsIPv4Prefix(prefix) || IsIPv6Prefix(prefix)
 }
 
+// IsValidDomainName checks if the given name is a fully qualified domain name made of dot separated labels
+// of letters, digits and hyphens, a trailing dot is allowed.
+func IsValidDomainName(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	if len(name) == 0 || len(name) > 253 || net.ParseIP(name) != nil {
+		return false
+	}
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // ContainsValidCustomIPv4Ranges matches the following custom IPv4 patterns usable by netfilter userspace utils:
 // Cidr notation 100.100.0.0/16
 // Individual address 10.100.0.2
@@ -191,3 +216,36 @@ func ContainsValidCustomIPv6Ranges(ipRanges []string) bool {
 
 	return true
 }
+
+// IPRangeContains checks if the address is in the cidr, individual address or dash-separated range. The
+// address never matches a range of the other address family.
+func IPRangeContains(ipRange string, addr netip.Addr) (bool, error) {
+	addr = addr.Unmap()
+	if from, to, found := strings.Cut(ipRange, "-"); found {
+		fromAddr, err := netip.ParseAddr(strings.TrimSpace(from))
+		if err != nil {
+			return false, fmt.Errorf("invalid IP range %s: %w", ipRange, err)
+		}
+		toAddr, err := netip.ParseAddr(strings.TrimSpace(to))
+		if err != nil {
+			return false, fmt.Errorf("invalid IP range %s: %w", ipRange, err)
+		}
+		fromAddr, toAddr = fromAddr.Unmap(), toAddr.Unmap()
+		if fromAddr.Is4() != toAddr.Is4() {
+			return false, fmt.Errorf("invalid IP range %s: mixed address families", ipRange)
+		}
+		return fromAddr.Is4() == addr.Is4() && fromAddr.Compare(addr) <= 0 && addr.Compare(toAddr) <= 0, nil
+	}
+	if strings.Contains(ipRange, "/") {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(ipRange))
+		if err != nil {
+			return false, fmt.Errorf("invalid IP range %s: %w", ipRange, err)
+		}
+		return prefix.Contains(addr), nil
+	}
+	ip, err := netip.ParseAddr(strings.TrimSpace(ipRange))
+	if err != nil {
+		return false, fmt.Errorf("invalid IP range %s: %w", ipRange, err)
+	}
+	return ip.Unmap() == addr, nil
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"net/netip"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -191,3 +192,56 @@ func TestContainsValidCustomIPv6Ranges(t *testing.T) {
 	assert.False(ContainsValidCustomIPv6Ranges([]string{"making_biscuits"}))
 	assert.False(ContainsValidCustomIPv6Ranges([]string{"2001:db8::zzz"}))
 }
+
+func TestIPRangeContains(t *testing.T) {
+	testCases := []struct {
+		ipRange  string
+		addr     string
+		expected bool
+	}{
+		{"192.168.1.0/24", "192.168.1.10", true},
+		{"192.168.1.0/24", "192.168.2.10", false},
+		{"192.168.1.1", "192.168.1.1", true},
+		{"192.168.1.1", "::ffff:192.168.1.1", true},
+		{"192.168.1.1-192.168.1.9", "192.168.1.5", true},
+		{"192.168.1.1 - 192.168.1.9", "192.168.1.10", false},
+		{"2001:db8::/32", "2001:db8::1", true},
+		{"2001:db8::1-2001:db8::8", "2001:db8::9", false},
+		{"0.0.0.0/0", "2001:db8::1", false},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.ipRange+" "+tt.addr, func(t *testing.T) {
+			result, err := IPRangeContains(tt.ipRange, netip.MustParseAddr(tt.addr))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	_, err := IPRangeContains("kitten_loaf", netip.MustParseAddr("192.168.1.1"))
+	assert.Error(t, err)
+	_, err = IPRangeContains("192.168.1.1-2001:db8::1", netip.MustParseAddr("192.168.1.1"))
+	assert.Error(t, err)
+}
+
+func TestIsValidDomainName(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected bool
+	}{
+		{"example.com", true},
+		{"api.corp.example.com.", true},
+		{"my-host.example.com", true},
+		{"localhost", false},
+		{"-bad.example.com", false},
+		{"bad..example.com", false},
+		{"under_score.example.com", false},
+		{"10.0.0.1", false},
+		{"10.0.0.0/8", false},
+		{"", false},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsValidDomainName(tt.input))
+		})
+	}
+}
@@ -33,6 +33,10 @@ import { StringListField } from "../components/StringListField";
 export const roleChoices = [
   { id: "owner", name: "Owner" },
   { id: "member", name: "Member" },
+  { id: "viewer", name: "Viewer" },
+  { id: "network-admin", name: "Network Admin" },
+  { id: "security-admin", name: "Security Admin" },
+  { id: "device-operator", name: "Device Operator" },
 ];
 
 export function choiceMapper(choices: { id: string; name: string }[]) {
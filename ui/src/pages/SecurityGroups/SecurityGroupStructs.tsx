@@ -22,6 +22,11 @@ interface SecurityRule {
   to_port: number;
   from_port: number;
   ip_protocol: string;
+  action?: "allow" | "deny";
+  priority?: number;
+  icmp_type?: number;
+  icmp_code?: number;
+  security_group_ids?: string[];
 }
 
 // Represents a security group containing security rules and a group owner